@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
+	"io/fs"
 	stdhttp "net/http"
 	"os"
 	"os/signal"
@@ -11,20 +13,31 @@ import (
 	"time"
 
 	appconfig "kbfood/internal/config"
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
 	"kbfood/internal/domain/service"
 	dbinfra "kbfood/internal/infra/db"
+	"kbfood/internal/infra/external"
 	"kbfood/internal/infra/platform"
 	repoimpl "kbfood/internal/infra/repository"
 	schedulerinfra "kbfood/internal/infra/scheduler"
 	httpiface "kbfood/internal/interface/http"
 	"kbfood/internal/interface/http/handler"
+	"kbfood/internal/interface/http/middleware"
 	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/tracing"
+	"kbfood/web"
 
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
 	configPath := flag.String("config", "", "path to config file")
+	grantAdmin := flag.String("grant-admin", "", "grant the admin role to an existing account and exit")
+	migrateLegacy := flag.Bool("migrate-legacy-users", false, "merge legacy Bark-key user data into current users, print a report and exit")
+	dryRun := flag.Bool("dry-run", false, "with -migrate-legacy-users, print the report without changing data")
+	staticDir := flag.String("static-dir", "", "serve the frontend from this directory instead of the embedded build, e.g. frontend/dist")
+	migrationsDir := flag.String("migrations-dir", "", "run the .sql migrations of this directory instead of the embedded ones")
 	flag.Parse()
 
 	cfg, err := appconfig.Load(*configPath)
@@ -41,19 +54,66 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to initialize logger")
 	}
 
+	// Deferred first, so spans of the scheduler and server shutdown are still exported
+	shutdownTracing, err := tracing.Init(tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize tracing")
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("failed to flush spans")
+		}
+	}()
+
 	ctx := context.Background()
-	database, err := dbinfra.NewPool(ctx, &dbinfra.Config{
+	dbConfig := &dbinfra.Config{
 		Path:            cfg.Database.Path,
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
 		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
-	})
+		MigrationsDir:   *migrationsDir,
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(ctx, dbConfig, dbinfra.MigrationsFS(*migrationsDir), flag.Args()[1:]); err != nil {
+			log.Fatal().Err(err).Msg("migrate failed")
+		}
+		return
+	}
+
+	database, err := dbinfra.NewPool(ctx, dbConfig)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to initialize database")
 	}
 	defer database.Close()
 
+	if *migrateLegacy {
+		report, err := repoimpl.MigrateLegacyUsers(ctx, database, *dryRun)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to migrate legacy users")
+		}
+		log.Info().
+			Bool("dryRun", report.DryRun).
+			Int("migrated", report.Migrated).
+			Int("conflicts", report.Conflicts).
+			Int("orphaned", report.Orphaned).
+			Msg("Legacy user migration finished")
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatal().Err(err).Msg("failed to write legacy migration report")
+		}
+		return
+	}
+
 	queries := database.Queries()
 
 	productRepo := repoimpl.NewProductRepository(queries)
@@ -63,27 +123,75 @@ func main() {
 	trendRepo := repoimpl.NewTrendRepository(queries)
 	candidateRepo := repoimpl.NewCandidateRepository(queries)
 	userSettingsRepo := repoimpl.NewUserSettingsRepository(queries)
+	savedSearchRepo := repoimpl.NewSavedSearchRepository(queries)
+	deliveryRepo := repoimpl.NewNotificationDeliveryRepository(queries)
 	syncStatusRepo := repoimpl.NewSyncStatusRepository(database)
+	userRepo := repoimpl.NewUserRepository(queries)
+	authTokenRepo := repoimpl.NewAuthTokenRepository(queries)
+	clientClaimRepo := repoimpl.NewClientClaimRepository(database)
+	deviceRepo := repoimpl.NewDeviceRepository(database)
+	watchlistRepo := repoimpl.NewWatchlistRepository(database)
+	auditLogRepo := repoimpl.NewAuditLogRepository(queries)
+	annotationRepo := repoimpl.NewProductAnnotationRepository(queries)
+	listingRepo := repoimpl.NewProductListingRepository(database)
+	searchRepo := repoimpl.NewProductSearchRepository(database)
+	titleVoteRepo := repoimpl.NewTitleVoteRepository(queries)
+	masterShopRepo := repoimpl.NewMasterShopRepository(database)
+	productChangeRepo := repoimpl.NewProductChangeRepository(database)
+	feedTokenRepo := repoimpl.NewFeedTokenRepository(database)
+
+	authService := service.NewAuthService(userRepo, authTokenRepo, clientClaimRepo, cfg.Auth.SessionTTL, cfg.Auth.AdminUsers)
+	deviceService := service.NewDeviceService(deviceRepo)
+	if *grantAdmin != "" {
+		if err := authService.SetRole(ctx, *grantAdmin, entity.RoleAdmin); err != nil {
+			log.Fatal().Err(err).Str("username", *grantAdmin).Msg("failed to grant admin role")
+		}
+		return
+	}
+	if err := authService.BootstrapAdmins(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to grant admin roles from config")
+	}
 
-	cleaningService := service.NewDataCleaningService(masterProductRepo, candidateRepo, trendRepo)
 	notificationService := service.NewNotificationService(
 		notificationRepo,
 		productRepo,
 		masterProductRepo,
 		userSettingsRepo,
-		cfg.BarkURL,
+		trendRepo,
+		deliveryRepo,
+		watchlistRepo,
+		external.NewBarkSender(cfg.BarkURL),
+		cfg.PublicURL,
 	)
+	events := event.NewBus()
+	// Runs after the scheduler has stopped so that the last sync's events are delivered
+	defer events.Close()
+	notificationService.Subscribe(events)
+	streamService := service.NewStreamService(cfg.Stream.BufferSize)
+	streamService.Subscribe(events)
+	feedService := service.NewFeedService(productChangeRepo, feedTokenRepo, notificationRepo, watchlistRepo, cfg.PublicURL)
+	feedService.Subscribe(events)
+	digestService := service.NewDigestService(notificationRepo, masterProductRepo, trendRepo, userSettingsRepo, notificationService)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo, masterProductRepo, notificationService)
+	watchlistService := service.NewWatchlistService(watchlistRepo, cfg.PublicURL)
+	userDataService := service.NewUserDataService(userSettingsRepo, notificationRepo, blockedRepo, savedSearchRepo, annotationRepo, repoimpl.NewUserDataTransactor(database), savedSearchService)
+	cleaningService := service.NewDataCleaningService(masterProductRepo, candidateRepo, trendRepo, titleVoteRepo, masterShopRepo, savedSearchService, events)
 
 	tttClient := platform.NewTanTanTangClient(&cfg.Platforms.TanTanTang)
 
 	syncJob := schedulerinfra.NewSyncJob(cfg, tttClient, cleaningService, syncStatusRepo)
 	promoteCandidatesJob := schedulerinfra.NewPromoteCandidatesJob(cleaningService)
 	priceCheckJob := schedulerinfra.NewPriceCheckJob(notificationService)
+	deliverHeldJob := schedulerinfra.NewDeliverHeldNotificationsJob(notificationService)
+	digestJob := schedulerinfra.NewDigestJob(digestService)
 	recordTrendsJob := schedulerinfra.NewRecordTrendsJob(cleaningService)
 
 	scheduler := schedulerinfra.NewScheduler(nil)
 	registerJob(scheduler, syncJob, "0 */5 * * * *")
-	registerJob(scheduler, priceCheckJob, "0 */5 * * * *")
+	// Alerts are event-driven; the full scan only catches unpublished changes
+	registerJob(scheduler, priceCheckJob, "0 0 * * * *")
+	registerJob(scheduler, deliverHeldJob, "30 * * * * *")
+	registerJob(scheduler, digestJob, "0 * * * * *")
 	registerJob(scheduler, promoteCandidatesJob, "*/30 * * * * *")
 	registerJob(scheduler, recordTrendsJob, "0 5 0 * * *")
 	scheduler.Start()
@@ -95,11 +203,36 @@ func main() {
 		}
 	}()
 
-	productHandler := handler.NewProductHandler(productRepo, masterProductRepo, notificationRepo, blockedRepo, trendRepo)
+	productHandler := handler.NewProductHandler(productRepo, masterProductRepo, notificationRepo, blockedRepo, trendRepo, userSettingsRepo, annotationRepo, listingRepo, searchRepo, titleVoteRepo)
 	externalHandler := handler.NewExternalHandler(cleaningService)
 	syncHandler := handler.NewSyncHandler(syncJob, tttClient)
 	statusHandler := handler.NewStatusHandler(syncStatusRepo)
-	userHandler := handler.NewUserHandler(userSettingsRepo)
+	userHandler := handler.NewUserHandler(userSettingsRepo, digestService, userDataService, cfg.BarkURL)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService)
+	authHandler := handler.NewAuthHandler(authService)
+	auditHandler := handler.NewAuditHandler(auditLogRepo)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistService, productRepo, masterProductRepo)
+	streamHandler := handler.NewStreamHandler(streamService, notificationRepo, cfg.Stream.Heartbeat)
+	feedHandler := handler.NewFeedHandler(feedService, cfg.PublicURL)
+
+	var rateLimits httpiface.RateLimits
+	if cfg.RateLimit.Enabled {
+		rateLimits = httpiface.RateLimits{
+			Default:  middleware.RateLimitRule(cfg.RateLimit.Default),
+			Admin:    middleware.RateLimitRule(cfg.RateLimit.Admin),
+			External: middleware.RateLimitRule(cfg.RateLimit.External),
+			Pairing:  middleware.RateLimitRule(cfg.RateLimit.Pairing),
+		}
+	}
+
+	frontend := web.Dist()
+	if *staticDir != "" {
+		frontend = os.DirFS(*staticDir)
+	}
+	if _, err := fs.Stat(frontend, "index.html"); err != nil {
+		log.Warn().Str("staticDir", *staticDir).Msg("Frontend not found, run make frontend or pass -static-dir; only the API is served")
+	}
 
 	router := httpiface.Router(
 		productHandler,
@@ -107,7 +240,21 @@ func main() {
 		syncHandler,
 		statusHandler,
 		userHandler,
+		savedSearchHandler,
+		authHandler,
+		auditHandler,
+		deviceHandler,
+		watchlistHandler,
+		streamHandler,
+		feedHandler,
+		authService,
+		deviceService,
+		cfg.Auth.AllowAnonymous,
+		cfg.Auth.LegacyMigration,
+		auditLogRepo,
+		rateLimits,
 		database,
+		frontend,
 	)
 
 	server := &stdhttp.Server{
@@ -117,6 +264,8 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.ReadTimeout,
 	}
+	// Live update streams never end on their own; close them so shutdown does not wait
+	server.RegisterOnShutdown(streamService.Close)
 
 	go func() {
 		log.Info().
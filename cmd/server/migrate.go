@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	dbinfra "kbfood/internal/infra/db"
+)
+
+const migrateUsage = "usage: server [flags] migrate status|up|down|to <version>"
+
+// runMigrate runs the migrate subcommand against the database of cfg, without
+// the automatic migration done on startup
+func runMigrate(ctx context.Context, cfg *dbinfra.Config, migrations fs.FS, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	pool, err := dbinfra.Open(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := dbinfra.NewMigrator(pool, migrations)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, status.AppliedAt)
+		}
+		return w.Flush()
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", count)
+	case "down":
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if !reverted {
+			fmt.Println("no migration to revert")
+			return nil
+		}
+		fmt.Println("reverted 1 migration")
+	case "to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid migration version %q", args[1])
+		}
+		count, err := migrator.To(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("migrated to version %d, %d migration(s) applied or reverted\n", version, count)
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
@@ -25,15 +25,15 @@ const queryClient = new QueryClient({
 });
 
 function AppContent() {
-  // Filter state
-  const [filters, setFilters] = useState<TProductFilters>({
-    keyword: "",
+  // Filter state, seeded from alert deep links (/?keyword=...&activityId=...)
+  const [filters, setFilters] = useState<TProductFilters>(() => ({
+    keyword: new URLSearchParams(window.location.search).get("keyword") ?? "",
     platform: "",
     region: "",
     salesStatus: "",
     monitorStatus: "",
     recentSevenDays: false,
-  });
+  }));
 
   // UI state
   const [isSettingsOpen, setIsSettingsOpen] = useState(false);
@@ -72,7 +72,7 @@ export function SettingsModal({ open, onClose, onSave }: SettingsModalProps) {
     setTestResult(null);
 
     try {
-      const response = await api.post("/admin/test-notification", {
+      const response = await api.post("/user/test-notification", {
         barkKey: barkKey.trim(),
       });
 
@@ -47,7 +47,7 @@ export function WelcomeModal({ open, onClose, onComplete }: WelcomeModalProps) {
     setTestResult(null);
 
     try {
-      const response = await api.post("/admin/test-notification", {
+      const response = await api.post("/user/test-notification", {
         barkKey: normalizeBarkKey(barkKey.trim()),
       });
 
@@ -174,7 +174,7 @@ describe("WelcomeModal", () => {
       await user.click(testButton);
 
       await waitFor(() => {
-        expect(api.post).toHaveBeenCalledWith("/admin/test-notification", {
+        expect(api.post).toHaveBeenCalledWith("/user/test-notification", {
           barkKey: "ABC123",
         });
       });
@@ -348,7 +348,7 @@ describe("normalizeBarkKey utility", () => {
     await user.click(testButton);
 
     await waitFor(() => {
-      expect(api.post).toHaveBeenCalledWith("/admin/test-notification", {
+      expect(api.post).toHaveBeenCalledWith("/user/test-notification", {
         barkKey: "MYKEY123",
       });
     });
@@ -369,7 +369,7 @@ describe("normalizeBarkKey utility", () => {
     await user.click(testButton);
 
     await waitFor(() => {
-      expect(api.post).toHaveBeenCalledWith("/admin/test-notification", {
+      expect(api.post).toHaveBeenCalledWith("/user/test-notification", {
         barkKey: "SIMPLEKEY",
       });
     });
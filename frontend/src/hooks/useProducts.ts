@@ -14,12 +14,20 @@ export function useProducts(filters: ProductFilters) {
       salesStatus: filters.salesStatus,
       monitorStatus: filters.monitorStatus,
       recentSevenDays: filters.recentSevenDays,
+      favorite: filters.favorite ? '1' : '',
+      tag: filters.tag ?? '',
+      minPrice: filters.minPrice ?? '',
+      maxPrice: filters.maxPrice ?? '',
+      sort: filters.sort ?? '',
+      order: filters.order ?? '',
     }),
     staleTime: 30000, // 30 seconds
   });
 
-  // Sort products: monitored first, then by price gap
-  const sortedProducts: Product[] = query.data
+  // Sort products: monitored first, then by price gap, unless the server sorted them
+  const sortedProducts: Product[] = filters.sort
+    ? query.data ?? []
+    : query.data
     ? [...query.data].sort((a, b) => {
         const monitorA = a.hasNotification ? 1 : 0;
         const monitorB = b.hasNotification ? 1 : 0;
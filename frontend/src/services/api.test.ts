@@ -7,6 +7,7 @@ describe("api request headers", () => {
   afterEach(() => {
     settingsService.clearBarkKey();
     settingsService.clearClientId();
+    settingsService.clearAuthToken();
     delete api.defaults.adapter;
   });
 
@@ -53,4 +54,24 @@ describe("api request headers", () => {
     expect(capturedHeaders?.["X-User-ID"]).toBe(settingsService.getClientId());
     expect(capturedHeaders?.["X-Legacy-User-ID"]).toBeUndefined();
   });
+
+  it("sends the session token when logged in", async () => {
+    settingsService.setAuthToken("kbf_TOKEN");
+
+    let capturedHeaders: Record<string, unknown> | undefined;
+    api.defaults.adapter = async (config) => {
+      capturedHeaders = config.headers as Record<string, unknown>;
+      return {
+        data: { ok: true },
+        status: 200,
+        statusText: "OK",
+        headers: {},
+        config,
+      };
+    };
+
+    await api.get("/status");
+
+    expect(capturedHeaders?.["Authorization"]).toBe("Bearer kbf_TOKEN");
+  });
 });
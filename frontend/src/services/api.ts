@@ -51,18 +51,32 @@ export const api = axios.create({
   },
 });
 
-// Request interceptor - add X-User-ID header
+// Headers identifying the user: the session token and X-User-ID
+export const identityHeaders = (): Record<string, string> => {
+  const headers: Record<string, string> = {};
+  const authToken = settingsService.getAuthToken();
+  if (authToken) {
+    headers["Authorization"] = `Bearer ${authToken}`;
+  }
+
+  const clientId = settingsService.getOrCreateClientId();
+  const legacyUserId = settingsService.getLegacyUserId();
+
+  if (clientId) {
+    headers["X-User-ID"] = clientId;
+  }
+  if (legacyUserId && legacyUserId !== clientId) {
+    headers["X-Legacy-User-ID"] = legacyUserId;
+  }
+  return headers;
+};
+
+// Request interceptor - add the session token and X-User-ID header
 api.interceptors.request.use(
   (config) => {
-    const clientId = settingsService.getOrCreateClientId();
-    const legacyUserId = settingsService.getLegacyUserId();
-
-    if (clientId) {
-      config.headers["X-User-ID"] = clientId;
-    }
-    if (legacyUserId && legacyUserId !== clientId) {
-      config.headers["X-Legacy-User-ID"] = legacyUserId;
-    }
+    Object.entries(identityHeaders()).forEach(([name, value]) => {
+      config.headers[name] = value;
+    });
     return config;
   },
   (error) => {
@@ -0,0 +1,48 @@
+import { api } from './api';
+import { settingsService } from './settingsService';
+import type { ApiResponse, AuthSession, User } from '@/types';
+
+export const authService = {
+  // Create an account and keep its session token
+  signUp: async (username: string, password: string): Promise<User> => {
+    const response = await api.post<ApiResponse<AuthSession>>('/auth/signup', { username, password });
+    const session = response.data.data!;
+    settingsService.setAuthToken(session.token);
+    return session.user;
+  },
+
+  // Log in and keep the session token
+  login: async (username: string, password: string): Promise<User> => {
+    const response = await api.post<ApiResponse<AuthSession>>('/auth/login', { username, password });
+    const session = response.data.data!;
+    settingsService.setAuthToken(session.token);
+    return session.user;
+  },
+
+  // Revoke the session token
+  logout: async (): Promise<void> => {
+    try {
+      await api.post<ApiResponse<null>>('/auth/logout');
+    } finally {
+      settingsService.clearAuthToken();
+    }
+  },
+
+  // Get the logged-in account, or null when not logged in
+  me: async (): Promise<User | null> => {
+    if (!settingsService.getAuthToken()) {
+      return null;
+    }
+    const response = await api.get<ApiResponse<User>>('/auth/me');
+    return response.data.data || null;
+  },
+
+  // Move the data of this browser's anonymous client ID onto the account
+  claimClient: async (): Promise<void> => {
+    await api.post<ApiResponse<null>>('/auth/claim', {
+      clientId: settingsService.getClientId(),
+    });
+    // A claimed client ID is rejected without a token, start over with a fresh one
+    settingsService.clearClientId();
+  },
+};
@@ -0,0 +1,27 @@
+import { api } from './api';
+import type { ApiResponse, Device, PairingCode } from '@/types';
+
+export const deviceService = {
+  // Create a pairing code for another device to enter
+  createPairingCode: async (): Promise<PairingCode> => {
+    const response = await api.post<ApiResponse<PairingCode>>('/devices/pairing-code');
+    return response.data.data!;
+  },
+
+  // Pair this browser with the user who created the code
+  pair: async (code: string, name?: string): Promise<Device> => {
+    const response = await api.post<ApiResponse<Device>>('/devices/pair', { code, name });
+    return response.data.data!;
+  },
+
+  // List the devices paired to this user
+  listDevices: async (): Promise<Device[]> => {
+    const response = await api.get<ApiResponse<Device[]>>('/devices');
+    return response.data.data || [];
+  },
+
+  // Unpair a device
+  revokeDevice: async (id: number): Promise<void> => {
+    await api.delete<ApiResponse<null>>(`/devices/${id}`);
+  },
+};
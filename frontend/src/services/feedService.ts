@@ -0,0 +1,18 @@
+import { api } from './api';
+import type { ApiResponse, FeedToken } from '@/types';
+
+// Public feeds, for feed readers: /feeds/drops.atom and /feeds/new.atom,
+// filtered by the region, platform and keyword query parameters
+export const feedService = {
+  // Get the private feed token, created on first use
+  getToken: async (): Promise<FeedToken> => {
+    const response = await api.get<ApiResponse<FeedToken>>('/user/feed');
+    return response.data.data!;
+  },
+
+  // Issue a new private feed token; the earlier feed URL stops working
+  rotateToken: async (): Promise<FeedToken> => {
+    const response = await api.post<ApiResponse<FeedToken>>('/user/feed');
+    return response.data.data!;
+  },
+};
@@ -1,5 +1,14 @@
 import { api } from './api';
-import type { ApiResponse, Product, PriceTrend } from '@/types';
+import type {
+  ApiResponse,
+  Product,
+  ProductPage,
+  ProductDetail,
+  PriceTrend,
+  ProductAnnotation,
+  ProductTag,
+  UpdateAnnotationParams,
+} from '@/types';
 
 export const productService = {
   // Get products with filters
@@ -17,6 +26,49 @@ export const productService = {
     return response.data.data || [];
   },
 
+  // Get one page of products; pass the previous page's nextCursor to continue
+  getProductPage: async (
+    params: Record<string, string | boolean>,
+    limit: number,
+    cursor?: string
+  ): Promise<ProductPage> => {
+    const searchParams = new URLSearchParams();
+    Object.entries(params).forEach(([key, value]) => {
+      if (value !== '' && value !== false) {
+        searchParams.append(key, String(value));
+      }
+    });
+    searchParams.append('limit', String(limit));
+    if (cursor) {
+      searchParams.append('cursor', cursor);
+    }
+
+    const response = await api.get<ApiResponse<Product[]>>(
+      `/products?${searchParams.toString()}`
+    );
+    return {
+      products: response.data.data || [],
+      total: response.data.meta?.total ?? 0,
+      nextCursor: response.data.meta?.nextCursor,
+    };
+  },
+
+  // Full-text search over titles and shop names, best match first
+  search: async (q: string, limit?: number): Promise<Product[]> => {
+    const searchParams = new URLSearchParams({ q });
+    if (limit) {
+      searchParams.append('limit', String(limit));
+    }
+    const response = await api.get<ApiResponse<Product[]>>(`/search?${searchParams.toString()}`);
+    return response.data.data || [];
+  },
+
+  // Get a product with its trend summary, title votes and related products
+  getProduct: async (activityId: string): Promise<ProductDetail> => {
+    const response = await api.get<ApiResponse<ProductDetail>>(`/products/${activityId}`);
+    return response.data.data!;
+  },
+
   // Get price trend for a product
   getPriceTrend: async (activityId: string): Promise<PriceTrend[]> => {
     const response = await api.get<ApiResponse<PriceTrend[]>>(
@@ -40,4 +92,35 @@ export const productService = {
     const response = await api.get<ApiResponse<Product[]>>('/products/blocked');
     return response.data.data || [];
   },
+
+  // Star a product
+  favoriteProduct: async (activityId: string): Promise<void> => {
+    await api.post(`/products/${activityId}/favorite`);
+  },
+
+  // Remove the star from a product
+  unfavoriteProduct: async (activityId: string): Promise<void> => {
+    await api.delete(`/products/${activityId}/favorite`);
+  },
+
+  // Get the favorite star, tags and note of a product
+  getAnnotation: async (activityId: string): Promise<ProductAnnotation> => {
+    const response = await api.get<ApiResponse<ProductAnnotation>>(`/products/${activityId}/annotation`);
+    return response.data.data!;
+  },
+
+  // Change the favorite star, tags or note of a product
+  updateAnnotation: async (activityId: string, params: UpdateAnnotationParams): Promise<ProductAnnotation> => {
+    const response = await api.put<ApiResponse<ProductAnnotation>>(
+      `/products/${activityId}/annotation`,
+      params
+    );
+    return response.data.data!;
+  },
+
+  // Get the user's tags, most used first
+  getTags: async (): Promise<ProductTag[]> => {
+    const response = await api.get<ApiResponse<ProductTag[]>>('/products/tags');
+    return response.data.data || [];
+  },
 };
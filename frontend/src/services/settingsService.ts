@@ -1,5 +1,6 @@
 const BARK_KEY_STORAGE_KEY = "barkKey";
 const CLIENT_ID_STORAGE_KEY = "clientId";
+const AUTH_TOKEN_STORAGE_KEY = "authToken";
 
 function normalizeBarkKey(input: string): string {
   const trimmed = input.trim();
@@ -89,6 +90,25 @@ export const settingsService = {
     localStorage.removeItem(CLIENT_ID_STORAGE_KEY);
   },
 
+  // Get the session token of the logged-in account
+  getAuthToken: (): string => {
+    return localStorage.getItem(AUTH_TOKEN_STORAGE_KEY) || "";
+  },
+
+  // Save the session token after login
+  setAuthToken: (token: string): void => {
+    if (token) {
+      localStorage.setItem(AUTH_TOKEN_STORAGE_KEY, token);
+    } else {
+      localStorage.removeItem(AUTH_TOKEN_STORAGE_KEY);
+    }
+  },
+
+  // Clear the session token on logout
+  clearAuthToken: (): void => {
+    localStorage.removeItem(AUTH_TOKEN_STORAGE_KEY);
+  },
+
   // Get the legacy Bark-key-based user ID for one-time backend migration
   getLegacyUserId: (): string => {
     const barkKey = localStorage.getItem(BARK_KEY_STORAGE_KEY) || "";
@@ -0,0 +1,92 @@
+import { api, identityHeaders } from './api';
+import type { StreamEvent, StreamFilters } from '@/types';
+
+const DEFAULT_RETRY_MS = 3000;
+
+// Splits a Server-Sent Events buffer into complete events and the unfinished rest
+export const parseEventStream = (
+  buffer: string
+): { events: { id?: string; data: string }[]; rest: string; retry?: number } => {
+  const events: { id?: string; data: string }[] = [];
+  let retry: number | undefined;
+  const blocks = buffer.replace(/\r\n?/g, '\n').split('\n\n');
+  const rest = blocks.pop() ?? '';
+
+  for (const block of blocks) {
+    let id: string | undefined;
+    const data: string[] = [];
+    for (const line of block.split('\n')) {
+      if (line === '' || line.startsWith(':')) continue;
+      const colon = line.indexOf(':');
+      const field = colon === -1 ? line : line.slice(0, colon);
+      const value = colon === -1 ? '' : line.slice(colon + 1).replace(/^ /, '');
+      if (field === 'data') data.push(value);
+      else if (field === 'id') id = value;
+      else if (field === 'retry' && /^\d+$/.test(value)) retry = Number(value);
+    }
+    events.push({ id, data: data.join('\n') });
+  }
+  return { events, rest, retry };
+};
+
+export const streamService = {
+  // Subscribe to live price, status and new product updates. Uses fetch instead
+  // of EventSource so the user headers are sent; reconnects with Last-Event-ID.
+  // Returns a function that closes the stream.
+  subscribe: (
+    filters: StreamFilters,
+    onEvent: (event: StreamEvent) => void,
+    onError?: (error: unknown) => void
+  ): (() => void) => {
+    const controller = new AbortController();
+    const searchParams = new URLSearchParams();
+    if (filters.region) searchParams.append('region', filters.region);
+    if (filters.platform) searchParams.append('platform', filters.platform);
+    if (filters.watchedOnly) searchParams.append('watched', '1');
+    const url = `${api.defaults.baseURL}/stream?${searchParams.toString()}`;
+
+    let lastEventId = '';
+    let retryMs = DEFAULT_RETRY_MS;
+
+    const connect = async (): Promise<void> => {
+      const headers: Record<string, string> = { Accept: 'text/event-stream', ...identityHeaders() };
+      if (lastEventId) headers['Last-Event-ID'] = lastEventId;
+
+      const response = await fetch(url, { headers, signal: controller.signal });
+      if (!response.ok || !response.body) {
+        throw new Error(`stream request failed: ${response.status}`);
+      }
+
+      const reader = response.body.getReader();
+      const decoder = new TextDecoder();
+      let buffer = '';
+      for (;;) {
+        const { done, value } = await reader.read();
+        if (done) return;
+        buffer += decoder.decode(value, { stream: true });
+        const parsed = parseEventStream(buffer);
+        buffer = parsed.rest;
+        if (parsed.retry) retryMs = parsed.retry;
+        for (const event of parsed.events) {
+          if (event.id !== undefined) lastEventId = event.id;
+          if (event.data) onEvent(JSON.parse(event.data) as StreamEvent);
+        }
+      }
+    };
+
+    const run = async () => {
+      while (!controller.signal.aborted) {
+        try {
+          await connect();
+        } catch (error) {
+          if (controller.signal.aborted) return;
+          onError?.(error);
+        }
+        await new Promise((resolve) => setTimeout(resolve, retryMs));
+      }
+    };
+    void run();
+
+    return () => controller.abort();
+  },
+};
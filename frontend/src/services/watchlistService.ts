@@ -0,0 +1,81 @@
+import { api } from './api';
+import type {
+  ApiResponse,
+  SaveWatchlistItemParams,
+  Watchlist,
+  WatchlistDetail,
+  WatchlistItem,
+} from '@/types';
+
+export const watchlistService = {
+  // List the watchlists this user is a member of
+  listWatchlists: async (): Promise<Watchlist[]> => {
+    const response = await api.get<ApiResponse<Watchlist[]>>('/watchlists');
+    return response.data.data || [];
+  },
+
+  // Create a watchlist owned by this user
+  createWatchlist: async (name: string): Promise<Watchlist> => {
+    const response = await api.post<ApiResponse<Watchlist>>('/watchlists', { name });
+    return response.data.data!;
+  },
+
+  // Join a watchlist through the code of its invite link
+  joinWatchlist: async (inviteCode: string): Promise<Watchlist> => {
+    const response = await api.post<ApiResponse<Watchlist>>('/watchlists/join', { inviteCode });
+    return response.data.data!;
+  },
+
+  // Get a watchlist with its items and members
+  getWatchlist: async (id: number): Promise<WatchlistDetail> => {
+    const response = await api.get<ApiResponse<WatchlistDetail>>(`/watchlists/${id}`);
+    return response.data.data!;
+  },
+
+  // Rename a watchlist (owner only)
+  renameWatchlist: async (id: number, name: string): Promise<Watchlist> => {
+    const response = await api.put<ApiResponse<Watchlist>>(`/watchlists/${id}`, { name });
+    return response.data.data!;
+  },
+
+  // Delete a watchlist (owner only)
+  deleteWatchlist: async (id: number): Promise<void> => {
+    await api.delete<ApiResponse<null>>(`/watchlists/${id}`);
+  },
+
+  // Replace the invite link; earlier links stop working (owner only)
+  rotateInvite: async (id: number): Promise<Watchlist> => {
+    const response = await api.post<ApiResponse<Watchlist>>(`/watchlists/${id}/invite`);
+    return response.data.data!;
+  },
+
+  // Leave a watchlist
+  leaveWatchlist: async (id: number): Promise<void> => {
+    await api.post<ApiResponse<null>>(`/watchlists/${id}/leave`);
+  },
+
+  // Remove a member from a watchlist (owner only)
+  removeMember: async (id: number, memberId: number): Promise<void> => {
+    await api.delete<ApiResponse<null>>(`/watchlists/${id}/members/${memberId}`);
+  },
+
+  // Add a product to a watchlist
+  saveItem: async (id: number, params: SaveWatchlistItemParams): Promise<WatchlistItem> => {
+    const response = await api.post<ApiResponse<WatchlistItem>>(`/watchlists/${id}/items`, params);
+    return response.data.data!;
+  },
+
+  // Change the target price of a product on a watchlist
+  updateItem: async (id: number, activityId: string, targetPrice: number): Promise<WatchlistItem> => {
+    const response = await api.put<ApiResponse<WatchlistItem>>(
+      `/watchlists/${id}/items/${activityId}`,
+      { targetPrice }
+    );
+    return response.data.data!;
+  },
+
+  // Remove a product from a watchlist
+  deleteItem: async (id: number, activityId: string): Promise<void> => {
+    await api.delete<ApiResponse<null>>(`/watchlists/${id}/items/${activityId}`);
+  },
+};
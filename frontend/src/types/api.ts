@@ -4,8 +4,9 @@ export interface ApiResponse<T> {
   data?: T;
   message?: string;
   meta?: {
-    total: number;
-    page: number;
-    limit: number;
+    total?: number;
+    page?: number;
+    limit?: number;
+    nextCursor?: string;
   };
 }
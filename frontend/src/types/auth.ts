@@ -0,0 +1,13 @@
+// Registered account
+export interface User {
+  id: string;
+  username: string;
+  createTime: string;
+  updateTime: string;
+}
+
+// Result of sign-up and login
+export interface AuthSession {
+  user: User;
+  token: string;
+}
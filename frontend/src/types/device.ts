@@ -0,0 +1,15 @@
+// Six-digit code another device enters to share this user
+export interface PairingCode {
+  code: string;
+  expireTime: string;
+  createTime: string;
+}
+
+// Device paired to this user
+export interface Device {
+  id: number;
+  name: string;
+  lastSeenTime?: string;
+  createTime: string;
+  current: boolean;
+}
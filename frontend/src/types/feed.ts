@@ -0,0 +1,6 @@
+// Private Atom feed of price drops on the user's watched products
+export interface FeedToken {
+  token: string;
+  url?: string;
+  createTime: string;
+}
@@ -1,5 +1,10 @@
 export type { ApiResponse } from './api';
-export type { Product, ProductFilters } from './product';
+export type { Product, ProductFilters, ProductAnnotation, UpdateAnnotationParams, ProductTag, ProductSort, ProductPage, ProductHighlight, ProductDetail, TrendSummary, TitleVote } from './product';
 export { PLATFORMS, REGIONS, SALES_STATUS, MONITOR_STATUS } from './product';
 export type { NotificationConfig, CreateNotificationParams, UpdateNotificationParams } from './notification';
 export type { PriceTrend } from './priceTrend';
+export type { User, AuthSession } from './auth';
+export type { PairingCode, Device } from './device';
+export type { Watchlist, WatchlistMember, WatchlistItem, WatchlistDetail, SaveWatchlistItemParams } from './watchlist';
+export type { StreamEvent, StreamEventType, StreamFilters } from './stream';
+export type { FeedToken } from './feed';
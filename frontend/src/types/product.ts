@@ -17,6 +17,68 @@ export interface Product {
   dropRate?: number;
   hasNotification?: boolean;
   targetPrice?: number | null;
+  favorite?: boolean;
+  tags?: string[];
+  note?: string;
+  highlight?: ProductHighlight;
+}
+
+// HTML-escaped title and shop name with search matches wrapped in <mark>
+export interface ProductHighlight {
+  title: string;
+  shopName?: string;
+}
+
+// Price history summary on the product detail page
+export interface TrendSummary {
+  points: number;
+  firstPrice: number;
+  firstDate: string;
+  latestPrice: number;
+  latestDate: string;
+  lowestPrice: number;
+  lowestDate: string;
+  highestPrice: number;
+  changeRate: number;
+}
+
+// Raw platform title merged into a catalog product and how often it was seen
+export interface TitleVote {
+  title: string;
+  votes: number;
+}
+
+// Everything known about one product
+export interface ProductDetail {
+  product: Product;
+  source: 'master' | 'product';
+  blocked: boolean;
+  trustScore: number;
+  lastSeenTime: string;
+  trendSummary?: TrendSummary;
+  titleVotes: TitleVote[];
+  related: Product[];
+}
+
+// Favorite star, tags and private note of a product
+export interface ProductAnnotation {
+  activityId: string;
+  favorite: boolean;
+  tags: string[];
+  note: string;
+}
+
+// Fields to change on a product annotation; omitted fields are kept
+export interface UpdateAnnotationParams {
+  favorite?: boolean;
+  tags?: string[];
+  note?: string;
+}
+
+// User's tag with the number of products carrying it
+export interface ProductTag {
+  name: string;
+  count: number;
 }
 
 // Product filter parameters
@@ -27,6 +89,22 @@ export interface ProductFilters {
   salesStatus: string;
   monitorStatus: string;
   recentSevenDays: boolean;
+  favorite?: boolean;
+  tag?: string;
+  minPrice?: string;
+  maxPrice?: string;
+  sort?: ProductSort;
+  order?: 'asc' | 'desc';
+}
+
+// Server-side sort keys for the product list
+export type ProductSort = 'update_time' | 'price' | 'discount' | 'drop_rate' | 'relevance';
+
+// One page of a cursor-paginated product list
+export interface ProductPage {
+  products: Product[];
+  total: number;
+  nextCursor?: string;
 }
 
 // Platform options for filter dropdown
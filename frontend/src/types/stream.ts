@@ -0,0 +1,24 @@
+// Live update event types pushed by /api/stream
+export type StreamEventType = 'price' | 'status' | 'new_product' | 'reset';
+
+// Live product update; 'reset' means events were missed and data should be reloaded
+export interface StreamEvent {
+  id: string;
+  type: StreamEventType;
+  activityId?: string;
+  region?: string;
+  platform?: string;
+  title?: string;
+  price?: number;
+  oldPrice?: number;
+  status?: number;
+  oldStatus?: number;
+  occurredAt: string;
+}
+
+// Filters of the live update stream
+export interface StreamFilters {
+  region?: string;
+  platform?: string;
+  watchedOnly?: boolean;
+}
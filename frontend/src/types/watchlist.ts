@@ -0,0 +1,39 @@
+import type { NotificationConfig } from './notification';
+
+// Watchlist shared with the members who joined through its invite link
+export interface Watchlist {
+  id: number;
+  name: string;
+  inviteCode: string;
+  inviteUrl?: string;
+  role: 'owner' | 'member';
+  memberCount: number;
+  createTime: string;
+  updateTime: string;
+}
+
+// Member of a shared watchlist
+export interface WatchlistMember {
+  id: number;
+  username?: string;
+  role: 'owner' | 'member';
+  createTime: string;
+  current: boolean;
+}
+
+// Product on a watchlist; its rule alerts every member
+export interface WatchlistItem extends NotificationConfig {
+  watchlistId: number;
+}
+
+// Watchlist with its items and members
+export interface WatchlistDetail extends Watchlist {
+  items: WatchlistItem[];
+  members: WatchlistMember[];
+}
+
+// Parameters for adding a product to a watchlist
+export interface SaveWatchlistItemParams {
+  activityId: string;
+  targetPrice: number;
+}
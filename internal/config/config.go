@@ -15,7 +15,12 @@ type Config struct {
 	Database  DatabaseConfig  `envconfig:"DB"`
 	Platforms PlatformsConfig `envconfig:"PLATFORM"`
 	Log       LogConfig       `envconfig:"LOG"`
+	Auth      AuthConfig      `envconfig:"AUTH"`
+	RateLimit RateLimitConfig `envconfig:"RATE_LIMIT" mapstructure:"rate_limit"`
+	Stream    StreamConfig    `envconfig:"STREAM" mapstructure:"stream"`
+	Tracing   TracingConfig   `envconfig:"TRACING" mapstructure:"tracing"`
 	BarkURL   string          `envconfig:"BARK_URL"`
+	PublicURL string          `envconfig:"PUBLIC_URL" mapstructure:"public_url"` // frontend URL used for deep links in alerts
 }
 
 // ServerConfig holds HTTP server configuration
@@ -54,6 +59,48 @@ type LogConfig struct {
 	TimeFormat string `envconfig:"TIME_FORMAT" mapstructure:"time_format" default:""`
 }
 
+// AuthConfig holds account authentication configuration
+type AuthConfig struct {
+	// AllowAnonymous keeps accepting the X-User-ID header from clients without an account
+	AllowAnonymous bool          `envconfig:"ALLOW_ANONYMOUS" mapstructure:"allow_anonymous" default:"true"`
+	SessionTTL     time.Duration `envconfig:"SESSION_TTL" mapstructure:"session_ttl" default:"720h"`
+	// AdminUsers are usernames that get the admin role, whether they sign up before or after startup
+	AdminUsers []string `envconfig:"ADMIN_USERS" mapstructure:"admin_users"`
+	// LegacyMigration merges legacy Bark-key user data on every request that sends
+	// X-Legacy-User-ID. Prefer running -migrate-legacy-users once instead.
+	LegacyMigration bool `envconfig:"LEGACY_MIGRATION" mapstructure:"legacy_migration"`
+}
+
+// RateLimitConfig holds per-client token bucket rate limits of the API route groups
+type RateLimitConfig struct {
+	Enabled  bool          `envconfig:"ENABLED" mapstructure:"enabled" default:"true"`
+	Default  RateLimitRule `mapstructure:"default"`
+	Admin    RateLimitRule `mapstructure:"admin"`
+	External RateLimitRule `mapstructure:"external"`
+	Pairing  RateLimitRule `mapstructure:"pairing"`
+}
+
+// RateLimitRule allows Burst requests at once, refilled at RequestsPerMinute
+type RateLimitRule struct {
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	Burst             int `mapstructure:"burst"`
+}
+
+// StreamConfig holds live update stream configuration
+type StreamConfig struct {
+	// BufferSize is the number of recent events kept for clients resuming with Last-Event-ID
+	BufferSize int           `mapstructure:"buffer_size"`
+	Heartbeat  time.Duration `mapstructure:"heartbeat"`
+}
+
+// TracingConfig holds span export configuration
+type TracingConfig struct {
+	// Exporter is "none", "stdout" (one OTLP JSON span per line) or "otlp" (OTLP/HTTP collector)
+	Exporter    string `mapstructure:"exporter"`
+	Endpoint    string `mapstructure:"endpoint"`
+	ServiceName string `mapstructure:"service_name"`
+}
+
 // PlatformsConfig holds platform-specific configuration
 type PlatformsConfig struct {
 	TanTanTang TanTanTangConfig `envconfig:"TANTANTANG"`
@@ -119,14 +166,22 @@ func Load(configPath string) (*Config, error) {
 	// 2. Merge non-empty values into cfg
 
 	type EnvConfig struct {
-		ServerPort      int    `envconfig:"SERVER_PORT"`
-		DBPath          string `envconfig:"DB_PATH"`
-		LogLevel        string `envconfig:"LOG_LEVEL"`
-		TantantangToken string `envconfig:"PLATFORM_TANTANTANG_TOKEN"`
-		TantantangSK    string `envconfig:"PLATFORM_TANTANTANG_SECRET_KEY"`
-		TantantangURL   string `envconfig:"PLATFORM_TANTANTANG_BASE_URL"`
-		DTToken         string `envconfig:"PLATFORM_DT_TOKEN"`
-		BarkURL         string `envconfig:"BARK_URL"`
+		ServerPort      int      `envconfig:"SERVER_PORT"`
+		DBPath          string   `envconfig:"DB_PATH"`
+		LogLevel        string   `envconfig:"LOG_LEVEL"`
+		TantantangToken string   `envconfig:"PLATFORM_TANTANTANG_TOKEN"`
+		TantantangSK    string   `envconfig:"PLATFORM_TANTANTANG_SECRET_KEY"`
+		TantantangURL   string   `envconfig:"PLATFORM_TANTANTANG_BASE_URL"`
+		DTToken         string   `envconfig:"PLATFORM_DT_TOKEN"`
+		BarkURL         string   `envconfig:"BARK_URL"`
+		PublicURL       string   `envconfig:"PUBLIC_URL"`
+		AllowAnonymous  *bool    `envconfig:"AUTH_ALLOW_ANONYMOUS"`
+		SessionTTL      string   `envconfig:"AUTH_SESSION_TTL"`
+		AdminUsers      []string `envconfig:"AUTH_ADMIN_USERS"`
+		LegacyMigration *bool    `envconfig:"AUTH_LEGACY_MIGRATION"`
+		RateLimit       *bool    `envconfig:"RATE_LIMIT_ENABLED"`
+		TraceExporter   string   `envconfig:"TRACING_EXPORTER"`
+		TraceEndpoint   string   `envconfig:"TRACING_ENDPOINT"`
 	}
 
 	var envCfg EnvConfig
@@ -159,6 +214,34 @@ func Load(configPath string) (*Config, error) {
 	if envCfg.BarkURL != "" {
 		cfg.BarkURL = envCfg.BarkURL
 	}
+	if envCfg.PublicURL != "" {
+		cfg.PublicURL = envCfg.PublicURL
+	}
+	if envCfg.AllowAnonymous != nil {
+		cfg.Auth.AllowAnonymous = *envCfg.AllowAnonymous
+	}
+	if envCfg.SessionTTL != "" {
+		ttl, err := time.ParseDuration(envCfg.SessionTTL)
+		if err != nil {
+			return nil, fmt.Errorf("parse auth session ttl: %w", err)
+		}
+		cfg.Auth.SessionTTL = ttl
+	}
+	if len(envCfg.AdminUsers) > 0 {
+		cfg.Auth.AdminUsers = envCfg.AdminUsers
+	}
+	if envCfg.LegacyMigration != nil {
+		cfg.Auth.LegacyMigration = *envCfg.LegacyMigration
+	}
+	if envCfg.RateLimit != nil {
+		cfg.RateLimit.Enabled = *envCfg.RateLimit
+	}
+	if envCfg.TraceExporter != "" {
+		cfg.Tracing.Exporter = envCfg.TraceExporter
+	}
+	if envCfg.TraceEndpoint != "" {
+		cfg.Tracing.Endpoint = envCfg.TraceEndpoint
+	}
 
 	// Validate
 	if err := validate(&cfg); err != nil {
@@ -182,6 +265,31 @@ func setDefaults() {
 	// Log defaults
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
+
+	// Auth defaults
+	viper.SetDefault("auth.allow_anonymous", true)
+	viper.SetDefault("auth.session_ttl", "720h")
+	viper.SetDefault("auth.legacy_migration", false)
+
+	// Rate limit defaults, stricter for admin actions and webhooks
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.default.requests_per_minute", 300)
+	viper.SetDefault("rate_limit.default.burst", 60)
+	viper.SetDefault("rate_limit.admin.requests_per_minute", 6)
+	viper.SetDefault("rate_limit.admin.burst", 2)
+	viper.SetDefault("rate_limit.external.requests_per_minute", 60)
+	viper.SetDefault("rate_limit.external.burst", 10)
+	viper.SetDefault("rate_limit.pairing.requests_per_minute", 10)
+	viper.SetDefault("rate_limit.pairing.burst", 5)
+
+	// Live update stream defaults
+	viper.SetDefault("stream.buffer_size", 1000)
+	viper.SetDefault("stream.heartbeat", "15s")
+
+	// Tracing is off unless an exporter is chosen
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.endpoint", "http://localhost:4318")
+	viper.SetDefault("tracing.service_name", "kbfood")
 }
 
 func validate(cfg *Config) error {
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// AuditLog records an admin action and who triggered it
+type AuditLog struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     string    `json:"userId" db:"user_id"`
+	Username   string    `json:"username" db:"username"`
+	Action     string    `json:"action" db:"action"` // method and route, e.g. POST /api/admin/sync
+	Params     string    `json:"params" db:"params"` // JSON of path params, query and body
+	Status     int       `json:"status" db:"status"` // HTTP status of the response
+	CreateTime time.Time `json:"createTime" db:"create_time"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+// BarkMessage is the payload of a Bark push
+type BarkMessage struct {
+	Title     string `json:"title,omitempty"`
+	Body      string `json:"body"`
+	Level     string `json:"level,omitempty"`
+	Volume    int    `json:"volume,omitempty"`
+	Sound     string `json:"sound,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Icon      string `json:"icon,omitempty"`
+	URL       string `json:"url,omitempty"`
+	IsArchive string `json:"isArchive,omitempty"` // "1" keeps the push in the Bark history
+}
+
+// BarkDevice is the Bark device a push is sent to
+type BarkDevice struct {
+	Key string
+
+	// AES-CBC key and IV configured on the device, empty sends plaintext
+	EncryptKey string
+	EncryptIV  string
+}
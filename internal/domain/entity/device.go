@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+)
+
+// PairingCodeTTL is how long a pairing code can be redeemed
+const PairingCodeTTL = 10 * time.Minute
+
+var pairingCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// PairingCode is a short-lived six-digit code that binds another device to a user
+type PairingCode struct {
+	Code       string    `json:"code" db:"code"`
+	UserID     string    `json:"-" db:"user_id"`
+	ExpireTime time.Time `json:"expireTime" db:"expire_time"`
+	CreateTime time.Time `json:"createTime" db:"create_time"`
+}
+
+// IsExpired returns true if the code has expired at t
+func (p *PairingCode) IsExpired(at time.Time) bool {
+	return !at.Before(p.ExpireTime)
+}
+
+// IsValidPairingCode returns true if code has the format of a pairing code
+func IsValidPairingCode(code string) bool {
+	return pairingCodePattern.MatchString(code)
+}
+
+// Device is an anonymous client ID paired to a user. Requests sent with the
+// client ID act as that user until the device is revoked.
+type Device struct {
+	ID           int64      `json:"id" db:"id"`
+	ClientID     string     `json:"-" db:"client_id"`
+	UserID       string     `json:"-" db:"user_id"`
+	Name         string     `json:"name" db:"name"`
+	LastSeenTime *time.Time `json:"lastSeenTime,omitempty" db:"last_seen_time"`
+	CreateTime   time.Time  `json:"createTime" db:"create_time"`
+	Current      bool       `json:"current" db:"-"` // true for the device that sent the request
+}
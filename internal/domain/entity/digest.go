@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// digestTextMaxLines limits the number of product lines in the push text
+const digestTextMaxLines = 10
+
+// DigestItem summarizes one watched product in a daily digest
+type DigestItem struct {
+	ActivityID   string   `json:"activityId"`
+	Platform     string   `json:"platform"`
+	Region       string   `json:"region"`
+	Title        string   `json:"title"`
+	CurrentPrice float64  `json:"currentPrice"`
+	TodayLow     float64  `json:"todayLow"`
+	RuleType     string   `json:"ruleType"`
+	TargetPrice  *float64 `json:"targetPrice,omitempty"`
+	SalesStatus  int      `json:"salesStatus"`
+	Triggered    bool     `json:"triggered"` // whether the watch's rule condition currently holds
+	Watchlist    string   `json:"watchlist,omitempty"` // name of the shared watchlist the watch belongs to
+}
+
+// DigestProduct summarizes a master product promoted on the digest day
+type DigestProduct struct {
+	ActivityID string  `json:"activityId"`
+	Platform   string  `json:"platform"`
+	Region     string  `json:"region"`
+	Title      string  `json:"title"`
+	Price      float64 `json:"price"`
+}
+
+// Digest is a daily summary of a user's watched items and newly promoted products
+type Digest struct {
+	UserID      string          `json:"userId"`
+	Date        string          `json:"date"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Items       []DigestItem    `json:"items"`
+	NewProducts []DigestProduct `json:"newProducts"`
+}
+
+// IsEmpty returns true if there is nothing to report
+func (d *Digest) IsEmpty() bool {
+	return len(d.Items) == 0 && len(d.NewProducts) == 0
+}
+
+// Text renders the digest as a compact push message
+func (d *Digest) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "【每日摘要 %s】关注%d件，今日上新%d件", d.Date, len(d.Items), len(d.NewProducts))
+
+	lines := 0
+	for _, item := range d.Items {
+		if lines == digestTextMaxLines {
+			break
+		}
+		b.WriteString("\n")
+		if item.Triggered {
+			b.WriteString("★")
+		}
+		fmt.Fprintf(&b, "%s ¥%.2f", item.Title, item.CurrentPrice)
+		if item.TodayLow > 0 && item.TodayLow < item.CurrentPrice {
+			fmt.Fprintf(&b, " 低¥%.2f", item.TodayLow)
+		}
+		if item.TargetPrice != nil {
+			fmt.Fprintf(&b, " 目标¥%.2f", *item.TargetPrice)
+		}
+		if item.SalesStatus != SalesStatusOnSale {
+			b.WriteString(" 售罄")
+		}
+		if item.Watchlist != "" {
+			fmt.Fprintf(&b, " 清单「%s」", item.Watchlist)
+		}
+		lines++
+	}
+
+	for _, product := range d.NewProducts {
+		if lines == digestTextMaxLines {
+			break
+		}
+		fmt.Fprintf(&b, "\n新 %s %s ¥%.2f", product.Region, product.Title, product.Price)
+		lines++
+	}
+
+	if remaining := len(d.Items) + len(d.NewProducts) - lines; remaining > 0 {
+		fmt.Fprintf(&b, "\n…另有%d条", remaining)
+	}
+
+	return b.String()
+}
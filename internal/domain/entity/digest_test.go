@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDigest_TextTruncatesLongDigests(t *testing.T) {
+	digest := Digest{Date: "2024-03-02"}
+	for i := 0; i < 8; i++ {
+		digest.Items = append(digest.Items, DigestItem{
+			Title:        fmt.Sprintf("商品%d", i),
+			CurrentPrice: 30,
+			TodayLow:     25,
+			SalesStatus:  SalesStatusOnSale,
+			Triggered:    i == 0,
+		})
+	}
+	for i := 0; i < 5; i++ {
+		digest.NewProducts = append(digest.NewProducts, DigestProduct{Region: "广州", Title: fmt.Sprintf("新品%d", i), Price: 9.9})
+	}
+
+	text := digest.Text()
+	lines := strings.Split(text, "\n")
+
+	if lines[0] != "【每日摘要 2024-03-02】关注8件，今日上新5件" {
+		t.Fatalf("unexpected header: %q", lines[0])
+	}
+	if lines[1] != "★商品0 ¥30.00 低¥25.00" {
+		t.Fatalf("unexpected item line: %q", lines[1])
+	}
+	if len(lines) != 1+digestTextMaxLines+1 || lines[len(lines)-1] != "…另有3条" {
+		t.Fatalf("expected truncated digest, got %q", text)
+	}
+}
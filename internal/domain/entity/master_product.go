@@ -32,3 +32,11 @@ func (m *MasterProduct) IsOnSale() bool {
 func (m *MasterProduct) IncrementTrustScore() {
 	m.TrustScore++
 }
+
+// TitleVote counts how often a raw platform title was seen for a master product
+// while it was still a candidate
+type TitleVote struct {
+	MasterID string `json:"masterId" db:"master_id"`
+	Title    string `json:"title" db:"title"`
+	Votes    int    `json:"votes" db:"votes"`
+}
@@ -1,34 +1,163 @@
 package entity
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
+// Notification rule types
+const (
+	RuleTypeTargetPrice        = "target_price"
+	RuleTypePercentDrop        = "percent_drop"
+	RuleTypeAllTimeLow         = "all_time_low"
+	RuleTypeBackInStock        = "back_in_stock"
+	RuleTypeDropSinceLastAlert = "drop_since_last_alert"
+)
+
 // NotificationConfig represents a price notification configuration
 type NotificationConfig struct {
 	ActivityID     string     `json:"activityId" db:"activity_id"`
 	UserID         string     `json:"userId" db:"user_id"`
+	RuleType       string     `json:"ruleType" db:"rule_type"`
 	TargetPrice    float64    `json:"targetPrice" db:"target_price"`
+	DropPercent    float64    `json:"dropPercent" db:"drop_percent"`
+	DropAmount     float64    `json:"dropAmount" db:"drop_amount"`
+	ReferencePrice *float64   `json:"referencePrice" db:"reference_price"` // price at the last alert (or at creation)
+	LastStatus     *int       `json:"lastStatus" db:"last_status"`         // last observed sales status
 	LastNotifyTime *time.Time `json:"lastNotifyTime" db:"last_notify_time"`
+	SnoozeUntil    *time.Time `json:"snoozeUntil" db:"snooze_until"` // alerts are suppressed until this time
 	CreateTime     time.Time  `json:"createTime" db:"create_time"`
 	UpdateTime     time.Time  `json:"updateTime" db:"update_time"`
 }
 
+// PriceSnapshot holds the observed state of a product used to evaluate a rule
+type PriceSnapshot struct {
+	CurrentPrice  float64
+	SalesStatus   int
+	PreviousClose *float64 // recorded price of the previous day
+	HistoricalLow *float64 // lowest recorded price before today
+}
+
+// IsValidRuleType returns true if ruleType is a supported rule type
+func IsValidRuleType(ruleType string) bool {
+	switch ruleType {
+	case RuleTypeTargetPrice, RuleTypePercentDrop, RuleTypeAllTimeLow,
+		RuleTypeBackInStock, RuleTypeDropSinceLastAlert:
+		return true
+	default:
+		return false
+	}
+}
+
+// EffectiveRuleType returns the rule type, treating empty as the legacy target price rule
+func (n *NotificationConfig) EffectiveRuleType() string {
+	if n.RuleType == "" {
+		return RuleTypeTargetPrice
+	}
+	return n.RuleType
+}
+
+// Validate checks that the parameters required by the rule type are present
+func (n *NotificationConfig) Validate() error {
+	switch n.EffectiveRuleType() {
+	case RuleTypeTargetPrice:
+		if n.TargetPrice <= 0 {
+			return errors.New("targetPrice must be positive")
+		}
+	case RuleTypePercentDrop:
+		if n.DropPercent <= 0 || n.DropPercent >= 100 {
+			return errors.New("dropPercent must be between 0 and 100")
+		}
+	case RuleTypeDropSinceLastAlert:
+		if n.DropAmount <= 0 {
+			return errors.New("dropAmount must be positive")
+		}
+	case RuleTypeAllTimeLow, RuleTypeBackInStock:
+		// No parameters required
+	default:
+		return fmt.Errorf("unsupported ruleType: %s", n.RuleType)
+	}
+	return nil
+}
+
 // ShouldNotify checks if a notification should be sent
-func (n *NotificationConfig) ShouldNotify(currentPrice float64) bool {
-	// Check if price condition is met
-	if currentPrice > n.TargetPrice {
+func (n *NotificationConfig) ShouldNotify(snapshot PriceSnapshot) bool {
+	// Check if the watch is snoozed
+	if n.IsSnoozed(time.Now()) {
+		return false
+	}
+
+	// Check if rule condition is met
+	if !n.Matches(snapshot) {
 		return false
 	}
 
 	// Check if already notified today (local time)
-	if n.LastNotifyTime != nil && sameCalendarDayInLocation(time.Now(), *n.LastNotifyTime, time.Now().Location()) {
-		return false // Already notified today (local time)
+	if n.HasNotifiedToday() {
+		return false
 	}
 
 	return true
 }
 
+// Matches checks whether the rule condition holds for the given snapshot
+func (n *NotificationConfig) Matches(snapshot PriceSnapshot) bool {
+	switch n.EffectiveRuleType() {
+	case RuleTypeTargetPrice:
+		return snapshot.SalesStatus == SalesStatusOnSale && snapshot.CurrentPrice <= n.TargetPrice
+	case RuleTypePercentDrop:
+		if snapshot.SalesStatus != SalesStatusOnSale || snapshot.PreviousClose == nil || *snapshot.PreviousClose <= 0 {
+			return false
+		}
+		drop := (*snapshot.PreviousClose - snapshot.CurrentPrice) / *snapshot.PreviousClose * 100
+		return drop >= n.DropPercent
+	case RuleTypeAllTimeLow:
+		if snapshot.SalesStatus != SalesStatusOnSale || snapshot.HistoricalLow == nil {
+			return false
+		}
+		return snapshot.CurrentPrice < *snapshot.HistoricalLow
+	case RuleTypeBackInStock:
+		return snapshot.SalesStatus == SalesStatusOnSale &&
+			n.LastStatus != nil && *n.LastStatus != SalesStatusOnSale
+	case RuleTypeDropSinceLastAlert:
+		if snapshot.SalesStatus != SalesStatusOnSale || n.ReferencePrice == nil {
+			return false
+		}
+		return *n.ReferencePrice-snapshot.CurrentPrice >= n.DropAmount
+	default:
+		return false
+	}
+}
+
+// Reason returns a short human-readable description of why the rule fired
+func (n *NotificationConfig) Reason(snapshot PriceSnapshot) string {
+	switch n.EffectiveRuleType() {
+	case RuleTypePercentDrop:
+		if snapshot.PreviousClose != nil && *snapshot.PreviousClose > 0 {
+			drop := (*snapshot.PreviousClose - snapshot.CurrentPrice) / *snapshot.PreviousClose * 100
+			return fmt.Sprintf("较昨日下降%.0f%%", drop)
+		}
+		return "较昨日下降"
+	case RuleTypeAllTimeLow:
+		return "历史新低"
+	case RuleTypeBackInStock:
+		return "重新开售"
+	case RuleTypeDropSinceLastAlert:
+		if n.ReferencePrice != nil {
+			return fmt.Sprintf("较上次提醒下降¥%.2f", *n.ReferencePrice-snapshot.CurrentPrice)
+		}
+		return "较上次提醒下降"
+	default:
+		return fmt.Sprintf("低于目标价¥%.2f", n.TargetPrice)
+	}
+}
+
+// IsSnoozed returns true if alerts for this watch are suppressed at t
+func (n *NotificationConfig) IsSnoozed(t time.Time) bool {
+	return n.SnoozeUntil != nil && t.Before(*n.SnoozeUntil)
+}
+
 // MarkNotified marks the notification as sent
 func (n *NotificationConfig) MarkNotified() {
 	now := time.Now()
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Notification delivery status constants
+const (
+	DeliveryStatusHeld = "held"
+	DeliveryStatusSent = "sent"
+)
+
+// NotificationDelivery records an alert that was sent or held for later delivery
+type NotificationDelivery struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     string     `json:"userId" db:"user_id"`
+	ActivityID string     `json:"activityId" db:"activity_id"`
+	Message    string     `json:"message" db:"message"`
+	Status     string     `json:"status" db:"status"`
+	CreateTime time.Time  `json:"createTime" db:"create_time"`
+	SentTime   *time.Time `json:"sentTime" db:"sent_time"`
+}
@@ -0,0 +1,159 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Bark notification levels
+const (
+	BarkLevelActive        = "active"
+	BarkLevelTimeSensitive = "timeSensitive"
+	BarkLevelPassive       = "passive"
+	BarkLevelCritical      = "critical"
+)
+
+// Bark alert grouping
+const (
+	BarkGroupPlatform = "platform"
+	BarkGroupRegion   = "region"
+)
+
+// NotificationPreferences controls when and how alerts are delivered to a user
+type NotificationPreferences struct {
+	Timezone      string `json:"timezone" db:"timezone"`       // IANA name, empty means server local time
+	QuietStart    string `json:"quietStart" db:"quiet_start"`  // HH:MM, empty disables quiet hours
+	QuietEnd      string `json:"quietEnd" db:"quiet_end"`      // HH:MM
+	MaxPerHour    int    `json:"maxPerHour" db:"max_per_hour"` // 0 means unlimited
+	MaxPerDay     int    `json:"maxPerDay" db:"max_per_day"`   // 0 means unlimited
+	BarkLevel     string `json:"barkLevel" db:"bark_level"`    // empty means critical
+	BarkSound     string `json:"barkSound" db:"bark_sound"`
+	DigestEnabled bool   `json:"digestEnabled" db:"digest_enabled"` // replaces instant alerts with a daily summary
+	DigestTime    string `json:"digestTime" db:"digest_time"`       // HH:MM, empty means DefaultDigestTime
+
+	BarkGroup      string `json:"barkGroup" db:"bark_group"`            // platform or region, empty disables grouping
+	BarkIcon       string `json:"barkIcon" db:"bark_icon"`              // icon URL
+	BarkArchive    bool   `json:"barkArchive" db:"bark_archive"`        // keep alerts in the Bark history
+	BarkEncryptKey string `json:"barkEncryptKey" db:"bark_encrypt_key"` // AES-CBC key configured on the device, empty sends plaintext
+	BarkEncryptIV  string `json:"barkEncryptIv" db:"bark_encrypt_iv"`   // 16-byte IV configured on the device
+}
+
+// DefaultDigestTime is the local delivery time of the daily digest when none is configured
+const DefaultDigestTime = "09:00"
+
+// Validate checks that the preferences are well formed
+func (p *NotificationPreferences) Validate() error {
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %s", p.Timezone)
+		}
+	}
+	if (p.QuietStart == "") != (p.QuietEnd == "") {
+		return errors.New("quietStart and quietEnd must be set together")
+	}
+	if p.QuietStart != "" {
+		if _, err := parseClock(p.QuietStart); err != nil {
+			return fmt.Errorf("invalid quietStart: %s", p.QuietStart)
+		}
+		if _, err := parseClock(p.QuietEnd); err != nil {
+			return fmt.Errorf("invalid quietEnd: %s", p.QuietEnd)
+		}
+	}
+	if p.MaxPerHour < 0 || p.MaxPerDay < 0 {
+		return errors.New("frequency caps must not be negative")
+	}
+	if p.DigestTime != "" {
+		if _, err := parseClock(p.DigestTime); err != nil {
+			return fmt.Errorf("invalid digestTime: %s", p.DigestTime)
+		}
+	}
+	switch p.BarkLevel {
+	case "", BarkLevelActive, BarkLevelTimeSensitive, BarkLevelPassive, BarkLevelCritical:
+	default:
+		return fmt.Errorf("unsupported barkLevel: %s", p.BarkLevel)
+	}
+	switch p.BarkGroup {
+	case "", BarkGroupPlatform, BarkGroupRegion:
+	default:
+		return fmt.Errorf("unsupported barkGroup: %s", p.BarkGroup)
+	}
+	if p.BarkEncryptKey != "" {
+		switch len(p.BarkEncryptKey) {
+		case 16, 24, 32:
+		default:
+			return errors.New("barkEncryptKey must be 16, 24 or 32 characters")
+		}
+		if len(p.BarkEncryptIV) != 16 {
+			return errors.New("barkEncryptIv must be 16 characters")
+		}
+	}
+	return nil
+}
+
+// Location returns the user's timezone, falling back to the server local time
+func (p *NotificationPreferences) Location() *time.Location {
+	if p.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// EffectiveBarkLevel returns the Bark level, defaulting to critical
+func (p *NotificationPreferences) EffectiveBarkLevel() string {
+	if p.BarkLevel == "" {
+		return BarkLevelCritical
+	}
+	return p.BarkLevel
+}
+
+// DigestDueTime returns the digest delivery time on t's calendar day in the user's timezone
+func (p *NotificationPreferences) DigestDueTime(t time.Time) time.Time {
+	minutes, err := parseClock(p.DigestTime)
+	if err != nil {
+		minutes, _ = parseClock(DefaultDigestTime)
+	}
+	return p.StartOfDay(t).Add(time.Duration(minutes) * time.Minute)
+}
+
+// InQuietHours returns true if t falls within the user's quiet hours
+func (p *NotificationPreferences) InQuietHours(t time.Time) bool {
+	if p.QuietStart == "" || p.QuietEnd == "" {
+		return false
+	}
+	start, err := parseClock(p.QuietStart)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(p.QuietEnd)
+	if err != nil || start == end {
+		return false
+	}
+
+	local := t.In(p.Location())
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// Quiet hours wrap around midnight, e.g. 22:00-08:00
+	return minute >= start || minute < end
+}
+
+// StartOfDay returns midnight of t's calendar day in the user's timezone
+func (p *NotificationPreferences) StartOfDay(t time.Time) time.Time {
+	local := t.In(p.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// parseClock parses HH:MM into minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationPreferences_InQuietHours(t *testing.T) {
+	prefs := NotificationPreferences{Timezone: "Asia/Shanghai", QuietStart: "22:00", QuietEnd: "08:00"}
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "just after midnight", at: time.Date(2026, 3, 28, 0, 10, 0, 0, loc), want: true},
+		{name: "late evening", at: time.Date(2026, 3, 28, 22, 0, 0, 0, loc), want: true},
+		{name: "end is exclusive", at: time.Date(2026, 3, 28, 8, 0, 0, 0, loc), want: false},
+		{name: "daytime", at: time.Date(2026, 3, 28, 12, 0, 0, 0, loc), want: false},
+		{name: "converted from UTC", at: time.Date(2026, 3, 27, 16, 30, 0, 0, time.UTC), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prefs.InQuietHours(tt.at); got != tt.want {
+				t.Fatalf("InQuietHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationPreferences_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefs   NotificationPreferences
+		wantErr bool
+	}{
+		{name: "empty", prefs: NotificationPreferences{}},
+		{name: "quiet hours", prefs: NotificationPreferences{QuietStart: "23:00", QuietEnd: "07:30"}},
+		{name: "quiet end missing", prefs: NotificationPreferences{QuietStart: "23:00"}, wantErr: true},
+		{name: "bad clock", prefs: NotificationPreferences{QuietStart: "25:00", QuietEnd: "07:00"}, wantErr: true},
+		{name: "bad timezone", prefs: NotificationPreferences{Timezone: "Mars/Base"}, wantErr: true},
+		{name: "negative cap", prefs: NotificationPreferences{MaxPerDay: -1}, wantErr: true},
+		{name: "bad level", prefs: NotificationPreferences{BarkLevel: "loud"}, wantErr: true},
+		{name: "encrypted push", prefs: NotificationPreferences{BarkEncryptKey: "1234567890abcdef", BarkEncryptIV: "fedcba0987654321"}, wantErr: false},
+		{name: "bad encrypt key", prefs: NotificationPreferences{BarkEncryptKey: "short", BarkEncryptIV: "fedcba0987654321"}, wantErr: true},
+		{name: "missing encrypt iv", prefs: NotificationPreferences{BarkEncryptKey: "1234567890abcdef"}, wantErr: true},
+		{name: "bad group", prefs: NotificationPreferences{BarkGroup: "shop"}, wantErr: true},
+		{name: "bad digest time", prefs: NotificationPreferences{DigestEnabled: true, DigestTime: "9am"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.prefs.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotificationConfig_IsSnoozed(t *testing.T) {
+	now := time.Now()
+	until := now.Add(time.Hour)
+	config := NotificationConfig{TargetPrice: 100, SnoozeUntil: &until}
+
+	if !config.IsSnoozed(now) {
+		t.Fatal("expected watch to be snoozed")
+	}
+	if config.ShouldNotify(PriceSnapshot{CurrentPrice: 50, SalesStatus: SalesStatusOnSale}) {
+		t.Fatal("expected snoozed watch not to notify")
+	}
+	if config.IsSnoozed(until) {
+		t.Fatal("expected snooze to end at the deadline")
+	}
+}
+
+func TestNotificationPreferences_DigestDueTime(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	prefs := NotificationPreferences{Timezone: "Asia/Shanghai", DigestTime: "20:30"}
+	now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC) // 2024-03-02 07:00 in Shanghai
+
+	want := time.Date(2024, 3, 2, 20, 30, 0, 0, loc)
+	if got := prefs.DigestDueTime(now); !got.Equal(want) {
+		t.Fatalf("DigestDueTime() = %v, want %v", got, want)
+	}
+
+	prefs.DigestTime = ""
+	want = time.Date(2024, 3, 2, 9, 0, 0, 0, loc)
+	if got := prefs.DigestDueTime(now); !got.Equal(want) {
+		t.Fatalf("DigestDueTime() default = %v, want %v", got, want)
+	}
+}
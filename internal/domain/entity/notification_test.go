@@ -26,3 +26,115 @@ func TestSameCalendarDayInLocation_DetectsDifferentLocalDays(t *testing.T) {
 		t.Fatal("expected different local calendar days")
 	}
 }
+
+func TestNotificationConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  NotificationConfig
+		wantErr bool
+	}{
+		{name: "legacy target price", config: NotificationConfig{TargetPrice: 50}},
+		{name: "target price must be positive", config: NotificationConfig{RuleType: RuleTypeTargetPrice}, wantErr: true},
+		{name: "percent drop", config: NotificationConfig{RuleType: RuleTypePercentDrop, DropPercent: 10}},
+		{name: "percent drop out of range", config: NotificationConfig{RuleType: RuleTypePercentDrop, DropPercent: 120}, wantErr: true},
+		{name: "drop amount required", config: NotificationConfig{RuleType: RuleTypeDropSinceLastAlert}, wantErr: true},
+		{name: "all time low needs no params", config: NotificationConfig{RuleType: RuleTypeAllTimeLow}},
+		{name: "unknown rule type", config: NotificationConfig{RuleType: "price_rise"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotificationConfig_Matches(t *testing.T) {
+	price := func(v float64) *float64 { return &v }
+	status := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		config   NotificationConfig
+		snapshot PriceSnapshot
+		want     bool
+	}{
+		{
+			name:     "target price reached",
+			config:   NotificationConfig{TargetPrice: 50},
+			snapshot: PriceSnapshot{CurrentPrice: 49.9, SalesStatus: SalesStatusOnSale},
+			want:     true,
+		},
+		{
+			name:     "target price ignored when sold out",
+			config:   NotificationConfig{TargetPrice: 50},
+			snapshot: PriceSnapshot{CurrentPrice: 40, SalesStatus: SalesStatusSold},
+			want:     false,
+		},
+		{
+			name:     "percent drop from yesterday",
+			config:   NotificationConfig{RuleType: RuleTypePercentDrop, DropPercent: 10},
+			snapshot: PriceSnapshot{CurrentPrice: 88, SalesStatus: SalesStatusOnSale, PreviousClose: price(100)},
+			want:     true,
+		},
+		{
+			name:     "percent drop too small",
+			config:   NotificationConfig{RuleType: RuleTypePercentDrop, DropPercent: 20},
+			snapshot: PriceSnapshot{CurrentPrice: 88, SalesStatus: SalesStatusOnSale, PreviousClose: price(100)},
+			want:     false,
+		},
+		{
+			name:     "percent drop without history",
+			config:   NotificationConfig{RuleType: RuleTypePercentDrop, DropPercent: 10},
+			snapshot: PriceSnapshot{CurrentPrice: 10, SalesStatus: SalesStatusOnSale},
+			want:     false,
+		},
+		{
+			name:     "new all time low",
+			config:   NotificationConfig{RuleType: RuleTypeAllTimeLow},
+			snapshot: PriceSnapshot{CurrentPrice: 59, SalesStatus: SalesStatusOnSale, HistoricalLow: price(60)},
+			want:     true,
+		},
+		{
+			name:     "equal to historical low is not new",
+			config:   NotificationConfig{RuleType: RuleTypeAllTimeLow},
+			snapshot: PriceSnapshot{CurrentPrice: 60, SalesStatus: SalesStatusOnSale, HistoricalLow: price(60)},
+			want:     false,
+		},
+		{
+			name:     "back in stock after sold out",
+			config:   NotificationConfig{RuleType: RuleTypeBackInStock, LastStatus: status(SalesStatusSold)},
+			snapshot: PriceSnapshot{CurrentPrice: 60, SalesStatus: SalesStatusOnSale},
+			want:     true,
+		},
+		{
+			name:     "still on sale is not a transition",
+			config:   NotificationConfig{RuleType: RuleTypeBackInStock, LastStatus: status(SalesStatusOnSale)},
+			snapshot: PriceSnapshot{CurrentPrice: 60, SalesStatus: SalesStatusOnSale},
+			want:     false,
+		},
+		{
+			name:     "dropped enough since last alert",
+			config:   NotificationConfig{RuleType: RuleTypeDropSinceLastAlert, DropAmount: 5, ReferencePrice: price(70)},
+			snapshot: PriceSnapshot{CurrentPrice: 65, SalesStatus: SalesStatusOnSale},
+			want:     true,
+		},
+		{
+			name:     "not dropped enough since last alert",
+			config:   NotificationConfig{RuleType: RuleTypeDropSinceLastAlert, DropAmount: 5, ReferencePrice: price(70)},
+			snapshot: PriceSnapshot{CurrentPrice: 66, SalesStatus: SalesStatusOnSale},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Matches(tt.snapshot); got != tt.want {
+				t.Fatalf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -37,6 +37,7 @@ type PlatformProductDTO struct {
 // DTInputDTO represents an input from DT platform
 type DTInputDTO struct {
 	Title     string
+	ShopName  string
 	Price     float64
 	Status    int
 	CrawlTime int64
@@ -67,3 +68,56 @@ func NewPriceTrend(activityID string, price float64, recordDate time.Time) (*Pri
 func (p *PriceTrend) IsLowerThan(other float64) bool {
 	return p.Price < other
 }
+
+// TrendSummary condenses the recorded price history of a product
+type TrendSummary struct {
+	Points       int
+	FirstPrice   float64
+	FirstDate    time.Time
+	LatestPrice  float64
+	LatestDate   time.Time
+	LowestPrice  float64
+	LowestDate   time.Time // most recent day at the lowest price
+	HighestPrice float64
+	ChangeRate   float64 // percent change from the first to the latest price
+}
+
+// SummarizeTrends summarizes price trend records in any order. It returns nil without records.
+func SummarizeTrends(trends []*PriceTrend) *TrendSummary {
+	var summary *TrendSummary
+	for _, t := range trends {
+		if t == nil {
+			continue
+		}
+		if summary == nil {
+			summary = &TrendSummary{
+				FirstPrice:   t.Price,
+				FirstDate:    t.RecordDate,
+				LatestPrice:  t.Price,
+				LatestDate:   t.RecordDate,
+				LowestPrice:  t.Price,
+				LowestDate:   t.RecordDate,
+				HighestPrice: t.Price,
+			}
+		}
+		summary.Points++
+
+		if t.RecordDate.Before(summary.FirstDate) {
+			summary.FirstPrice, summary.FirstDate = t.Price, t.RecordDate
+		}
+		if t.RecordDate.After(summary.LatestDate) {
+			summary.LatestPrice, summary.LatestDate = t.Price, t.RecordDate
+		}
+		if t.Price < summary.LowestPrice || (t.Price == summary.LowestPrice && t.RecordDate.After(summary.LowestDate)) {
+			summary.LowestPrice, summary.LowestDate = t.Price, t.RecordDate
+		}
+		if t.Price > summary.HighestPrice {
+			summary.HighestPrice = t.Price
+		}
+	}
+
+	if summary != nil && summary.FirstPrice > 0 {
+		summary.ChangeRate = (summary.LatestPrice/summary.FirstPrice - 1) * 100
+	}
+	return summary
+}
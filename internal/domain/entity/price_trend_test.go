@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeTrends(t *testing.T) {
+	if SummarizeTrends(nil) != nil {
+		t.Fatal("expected no summary without trend records")
+	}
+
+	day := func(d int) time.Time {
+		return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	summary := SummarizeTrends([]*PriceTrend{
+		{Price: 60, RecordDate: day(3)},
+		{Price: 80, RecordDate: day(1)},
+		{Price: 60, RecordDate: day(5)},
+		{Price: 72, RecordDate: day(7)},
+	})
+
+	if summary.Points != 4 || summary.FirstPrice != 80 || !summary.FirstDate.Equal(day(1)) ||
+		summary.LatestPrice != 72 || !summary.LatestDate.Equal(day(7)) {
+		t.Fatalf("unexpected first or latest point: %+v", summary)
+	}
+	if summary.LowestPrice != 60 || !summary.LowestDate.Equal(day(5)) || summary.HighestPrice != 80 {
+		t.Fatalf("unexpected lowest or highest point: %+v", summary)
+	}
+	if summary.ChangeRate < -10.0001 || summary.ChangeRate > -9.9999 {
+		t.Fatalf("ChangeRate = %v, want -10", summary.ChangeRate)
+	}
+}
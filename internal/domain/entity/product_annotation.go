@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Product annotation limits
+const (
+	MaxProductTags       = 10
+	MaxProductTagLength  = 20
+	MaxProductNoteLength = 500
+)
+
+// ProductAnnotation holds a user's own marks on a product: a favorite star,
+// free-form tags and a private note
+type ProductAnnotation struct {
+	ActivityID string    `json:"activityId" db:"activity_id"`
+	UserID     string    `json:"-" db:"user_id"`
+	Favorite   bool      `json:"favorite" db:"favorite"`
+	Tags       []string  `json:"tags" db:"tags"` // stored as a JSON array
+	Note       string    `json:"note" db:"note"`
+	CreateTime time.Time `json:"createTime" db:"create_time"`
+	UpdateTime time.Time `json:"updateTime" db:"update_time"`
+}
+
+// Normalize trims the note and tags, dropping empty and duplicate tags
+func (a *ProductAnnotation) Normalize() {
+	a.ActivityID = strings.TrimSpace(a.ActivityID)
+	a.Note = strings.TrimSpace(a.Note)
+
+	tags := make([]string, 0, len(a.Tags))
+	seen := make(map[string]bool, len(a.Tags))
+	for _, tag := range a.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	a.Tags = tags
+}
+
+// Validate checks the tag and note limits
+func (a *ProductAnnotation) Validate() error {
+	if len(a.Tags) > MaxProductTags {
+		return fmt.Errorf("最多只能添加 %d 个标签", MaxProductTags)
+	}
+	for _, tag := range a.Tags {
+		if utf8.RuneCountInString(tag) > MaxProductTagLength {
+			return fmt.Errorf("标签不能超过 %d 个字", MaxProductTagLength)
+		}
+	}
+	if utf8.RuneCountInString(a.Note) > MaxProductNoteLength {
+		return fmt.Errorf("备注不能超过 %d 个字", MaxProductNoteLength)
+	}
+	return nil
+}
+
+// IsEmpty returns true if the annotation marks nothing and need not be stored
+func (a *ProductAnnotation) IsEmpty() bool {
+	return !a.Favorite && len(a.Tags) == 0 && a.Note == ""
+}
+
+// HasTag checks whether the product carries the tag
+func (a *ProductAnnotation) HasTag(tag string) bool {
+	tag = strings.TrimSpace(tag)
+	for _, t := range a.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProductAnnotation_Normalize(t *testing.T) {
+	annotation := ProductAnnotation{
+		ActivityID: " DT_1 ",
+		Tags:       []string{" 午餐 ", "", "周末", "午餐"},
+		Note:       "  份量偏小  ",
+	}
+	annotation.Normalize()
+
+	if annotation.ActivityID != "DT_1" {
+		t.Fatalf("ActivityID = %q, want DT_1", annotation.ActivityID)
+	}
+	if strings.Join(annotation.Tags, ",") != "午餐,周末" {
+		t.Fatalf("Tags = %v, want [午餐 周末]", annotation.Tags)
+	}
+	if annotation.Note != "份量偏小" {
+		t.Fatalf("Note = %q, want trimmed note", annotation.Note)
+	}
+	if !annotation.HasTag(" 周末") || annotation.HasTag("晚餐") {
+		t.Fatalf("HasTag() mismatch for tags %v", annotation.Tags)
+	}
+	if annotation.IsEmpty() {
+		t.Fatal("expected annotation with tags not to be empty")
+	}
+
+	cleared := ProductAnnotation{Tags: []string{" "}, Note: " "}
+	cleared.Normalize()
+	if !cleared.IsEmpty() {
+		t.Fatal("expected annotation with blank tags and note to be empty")
+	}
+}
+
+func TestProductAnnotation_Validate(t *testing.T) {
+	tooManyTags := make([]string, MaxProductTags+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = string(rune('a' + i))
+	}
+
+	tests := []struct {
+		name       string
+		annotation ProductAnnotation
+		wantErr    bool
+	}{
+		{name: "favorite only", annotation: ProductAnnotation{Favorite: true}},
+		{name: "tags and note", annotation: ProductAnnotation{Tags: []string{"午餐"}, Note: "份量偏小"}},
+		{name: "too many tags", annotation: ProductAnnotation{Tags: tooManyTags}, wantErr: true},
+		{name: "tag too long", annotation: ProductAnnotation{Tags: []string{strings.Repeat("餐", MaxProductTagLength+1)}}, wantErr: true},
+		{name: "note too long", annotation: ProductAnnotation{Note: strings.Repeat("好", MaxProductNoteLength+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.annotation.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Product change kinds
+const (
+	ProductChangePrice = "price" // the price of a product changed
+	ProductChangeNew   = "new"   // a product was promoted from the candidate pool
+)
+
+// ProductChange is a recorded price change or new product, the entries of the
+// Atom feeds. Its ID never changes, so it identifies the feed entry.
+type ProductChange struct {
+	ID         int64     `json:"id" db:"id"`
+	Kind       string    `json:"kind" db:"kind"`
+	ActivityID string    `json:"activityId" db:"activity_id"`
+	Region     string    `json:"region" db:"region"`
+	Platform   string    `json:"platform" db:"platform"`
+	Title      string    `json:"title" db:"title"`
+	Price      float64   `json:"price" db:"price"`
+	OldPrice   *float64  `json:"oldPrice,omitempty" db:"old_price"` // price before the change, nil for new products
+	CreateTime time.Time `json:"createTime" db:"create_time"`
+}
+
+// IsDrop returns true if the change lowered the price
+func (c *ProductChange) IsDrop() bool {
+	return c.Kind == ProductChangePrice && c.OldPrice != nil && c.Price < *c.OldPrice
+}
+
+// FeedToken is the secret in the URL of a user's private feed
+type FeedToken struct {
+	UserID     string    `json:"-" db:"user_id"`
+	Token      string    `json:"token" db:"token"`
+	URL        string    `json:"url,omitempty" db:"-"` // private drops feed, set if a public URL is configured
+	CreateTime time.Time `json:"createTime" db:"create_time"`
+}
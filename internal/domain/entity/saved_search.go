@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DefaultPlatform is the platform shown for master products without an explicit platform
+const DefaultPlatform = "探探糖"
+
+// SavedSearch represents a user's saved product search that alerts on new matches
+type SavedSearch struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     string    `json:"userId" db:"user_id"`
+	Name       string    `json:"name" db:"name"`
+	Keyword    string    `json:"keyword" db:"keyword"`
+	Region     string    `json:"region" db:"region"`
+	Platform   string    `json:"platform" db:"platform"`
+	MaxPrice   float64   `json:"maxPrice" db:"max_price"` // 0 means no price limit
+	CreateTime time.Time `json:"createTime" db:"create_time"`
+	UpdateTime time.Time `json:"updateTime" db:"update_time"`
+}
+
+// Normalize trims the criteria and fills in a default name
+func (s *SavedSearch) Normalize() {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Keyword = strings.TrimSpace(s.Keyword)
+	s.Region = strings.TrimSpace(s.Region)
+	s.Platform = strings.TrimSpace(s.Platform)
+	if s.Name == "" {
+		s.Name = s.Keyword
+	}
+}
+
+// Validate checks that the search has at least one criterion
+func (s *SavedSearch) Validate() error {
+	if s.MaxPrice < 0 {
+		return errors.New("maxPrice must not be negative")
+	}
+	if s.Keyword == "" && s.Region == "" && s.Platform == "" && s.MaxPrice == 0 {
+		return errors.New("at least one of keyword, region, platform or maxPrice is required")
+	}
+	return nil
+}
+
+// Matches checks whether an on-sale master product satisfies every criterion of the search
+func (s *SavedSearch) Matches(product *MasterProduct) bool {
+	if product == nil || !product.IsOnSale() {
+		return false
+	}
+	if s.Region != "" && product.Region != s.Region {
+		return false
+	}
+	if s.Platform != "" {
+		platform := product.Platform
+		if platform == "" {
+			platform = DefaultPlatform
+		}
+		if platform != s.Platform {
+			return false
+		}
+	}
+	if s.MaxPrice > 0 && product.Price > s.MaxPrice {
+		return false
+	}
+	if s.Keyword != "" && !strings.Contains(strings.ToLower(product.StandardTitle), strings.ToLower(s.Keyword)) {
+		return false
+	}
+	return true
+}
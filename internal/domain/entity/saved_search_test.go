@@ -0,0 +1,58 @@
+package entity
+
+import "testing"
+
+func TestSavedSearch_Matches(t *testing.T) {
+	product := &MasterProduct{
+		ID:            "DT_1",
+		Region:        "上海",
+		StandardTitle: "Manner 拿铁套餐",
+		Price:         9.9,
+		Status:        SalesStatusOnSale,
+	}
+
+	tests := []struct {
+		name   string
+		search SavedSearch
+		want   bool
+	}{
+		{name: "keyword is case insensitive", search: SavedSearch{Keyword: "manner"}, want: true},
+		{name: "keyword mismatch", search: SavedSearch{Keyword: "瑞幸"}, want: false},
+		{name: "region mismatch", search: SavedSearch{Keyword: "拿铁", Region: "北京"}, want: false},
+		{name: "empty platform defaults to 探探糖", search: SavedSearch{Platform: DefaultPlatform}, want: true},
+		{name: "platform mismatch", search: SavedSearch{Platform: "小蚕"}, want: false},
+		{name: "within max price", search: SavedSearch{Region: "上海", MaxPrice: 10}, want: true},
+		{name: "above max price", search: SavedSearch{Region: "上海", MaxPrice: 5}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.search.Matches(product); got != tt.want {
+				t.Fatalf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	sold := *product
+	sold.Status = SalesStatusSold
+	if (&SavedSearch{Keyword: "拿铁"}).Matches(&sold) {
+		t.Fatal("expected sold out products not to match")
+	}
+}
+
+func TestSavedSearch_Validate(t *testing.T) {
+	search := SavedSearch{Name: "  "}
+	search.Normalize()
+	if err := search.Validate(); err == nil {
+		t.Fatal("expected error for search without criteria")
+	}
+
+	search = SavedSearch{Keyword: " 拿铁 "}
+	search.Normalize()
+	if err := search.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if search.Name != "拿铁" {
+		t.Fatalf("expected name to default to keyword, got %q", search.Name)
+	}
+}
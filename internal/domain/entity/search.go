@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Search query limits
+const (
+	MaxSearchTerms      = 5
+	MaxSearchTermLength = 30
+)
+
+// Search highlight markers, wrapped around matched characters of HTML-escaped text
+const (
+	HighlightOpen  = "<mark>"
+	HighlightClose = "</mark>"
+)
+
+// ParseSearchTerms splits a search query into lower-cased, de-duplicated terms.
+// Extra terms beyond MaxSearchTerms are dropped and long terms are truncated.
+func ParseSearchTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if utf8.RuneCountInString(term) > MaxSearchTermLength {
+			term = string([]rune(term)[:MaxSearchTermLength])
+		}
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+		if len(terms) == MaxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// HighlightSearchTerms HTML-escapes text and marks where the terms match it.
+// A term found as a whole is marked at every occurrence; otherwise its characters
+// are marked where they appear in order, as in 烤鱼 matching 烤全鱼.
+// The second result is false if nothing matched.
+func HighlightSearchTerms(text string, terms []string) (string, bool) {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	matched := false
+	for _, term := range terms {
+		termRunes := []rune(term)
+		if len(termRunes) == 0 {
+			continue
+		}
+		if markOccurrences(lower, termRunes, marked) || markSubsequence(lower, termRunes, marked) {
+			matched = true
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(HighlightOpen)
+		}
+		b.WriteString(html.EscapeString(string(r)))
+		if marked[i] && (i == len(runes)-1 || !marked[i+1]) {
+			b.WriteString(HighlightClose)
+		}
+	}
+	return b.String(), matched
+}
+
+// markOccurrences marks every whole occurrence of term in text
+func markOccurrences(text, term []rune, marked []bool) bool {
+	found := false
+	for i := 0; i+len(term) <= len(text); i++ {
+		if string(text[i:i+len(term)]) != string(term) {
+			continue
+		}
+		for j := range term {
+			marked[i+j] = true
+		}
+		found = true
+	}
+	return found
+}
+
+// markSubsequence marks the first in-order appearance of the characters of term
+func markSubsequence(text, term []rune, marked []bool) bool {
+	positions := make([]int, 0, len(term))
+	next := 0
+	for i := 0; i < len(text) && next < len(term); i++ {
+		if text[i] == term[next] {
+			positions = append(positions, i)
+			next++
+		}
+	}
+	if next < len(term) {
+		return false
+	}
+	for _, pos := range positions {
+		marked[pos] = true
+	}
+	return true
+}
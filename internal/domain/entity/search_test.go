@@ -0,0 +1,68 @@
+package entity
+
+import "testing"
+
+func TestParseSearchTerms(t *testing.T) {
+	terms := ParseSearchTerms("  烤鱼 KFC 烤鱼  a b c d e")
+	want := []string{"烤鱼", "kfc", "a", "b", "c"}
+	if len(terms) != len(want) {
+		t.Fatalf("ParseSearchTerms() = %v, want %v", terms, want)
+	}
+	for i := range want {
+		if terms[i] != want[i] {
+			t.Fatalf("ParseSearchTerms() = %v, want %v", terms, want)
+		}
+	}
+
+	if terms := ParseSearchTerms("   "); len(terms) != 0 {
+		t.Fatalf("expected no terms for blank query, got %v", terms)
+	}
+}
+
+func TestHighlightSearchTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		terms   []string
+		want    string
+		matched bool
+	}{
+		{
+			name:    "whole term at every occurrence",
+			text:    "烤鱼配烤鱼",
+			terms:   []string{"烤鱼"},
+			want:    "<mark>烤鱼</mark>配<mark>烤鱼</mark>",
+			matched: true,
+		},
+		{
+			name:    "characters in order",
+			text:    "烤全鱼双人餐",
+			terms:   []string{"烤鱼"},
+			want:    "<mark>烤</mark>全<mark>鱼</mark>双人餐",
+			matched: true,
+		},
+		{
+			name:    "case-insensitive and escaped",
+			text:    "KFC <Big> 套餐",
+			terms:   []string{"kfc", "套餐"},
+			want:    "<mark>KFC</mark> &lt;Big&gt; <mark>套餐</mark>",
+			matched: true,
+		},
+		{
+			name:    "no match",
+			text:    "奶茶",
+			terms:   []string{"烤鱼"},
+			want:    "奶茶",
+			matched: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := HighlightSearchTerms(tt.text, tt.terms)
+			if got != tt.want || matched != tt.matched {
+				t.Fatalf("HighlightSearchTerms() = %q, %v; want %q, %v", got, matched, tt.want, tt.matched)
+			}
+		})
+	}
+}
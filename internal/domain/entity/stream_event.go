@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Live update event types
+const (
+	StreamEventPrice      = "price"
+	StreamEventStatus     = "status"
+	StreamEventNewProduct = "new_product"
+	// StreamEventReset tells a resuming client that events were missed and it
+	// should reload instead
+	StreamEventReset = "reset"
+)
+
+// StreamEvent is a live product update pushed to connected clients
+type StreamEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	ActivityID string    `json:"activityId,omitempty"`
+	Region     string    `json:"region,omitempty"`
+	Platform   string    `json:"platform,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Price      *float64  `json:"price,omitempty"`
+	OldPrice   *float64  `json:"oldPrice,omitempty"`
+	Status     *int      `json:"status,omitempty"`
+	OldStatus  *int      `json:"oldStatus,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// StreamFilter selects the live updates a client receives
+type StreamFilter struct {
+	Region      string
+	Platform    string
+	WatchedOnly bool
+	// Watched holds the activity IDs the client watches, used with WatchedOnly
+	Watched map[string]bool
+}
+
+// Matches returns true if the event passes the filter. Reset events always pass.
+func (f StreamFilter) Matches(e StreamEvent) bool {
+	if e.Type == StreamEventReset {
+		return true
+	}
+	if f.Region != "" && e.Region != f.Region {
+		return false
+	}
+	if f.Platform != "" && e.Platform != f.Platform {
+		return false
+	}
+	if f.WatchedOnly && !f.Watched[e.ActivityID] {
+		return false
+	}
+	return true
+}
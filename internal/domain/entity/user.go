@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Auth token kinds
+const (
+	TokenKindSession = "session" // issued by login, expires
+	TokenKindAPI     = "api"     // personal API token, valid until revoked
+)
+
+// Account roles
+const (
+	RoleAdmin = "admin" // may trigger syncs, test pushes and clear platform data
+	RoleUser  = "user"
+)
+
+// UserIDPrefix prefixes account IDs so they cannot collide with anonymous client IDs
+const UserIDPrefix = "u_"
+
+// MinPasswordLength is the minimum length of an account password
+const MinPasswordLength = 8
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{3,32}$`)
+
+// User is a registered account
+type User struct {
+	ID           string    `json:"id" db:"id"`
+	Username     string    `json:"username" db:"username"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	Role         string    `json:"role" db:"role"`
+	CreateTime   time.Time `json:"createTime" db:"create_time"`
+	UpdateTime   time.Time `json:"updateTime" db:"update_time"`
+}
+
+// IsAdmin returns true if the account has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// AuthToken is a session or personal API token. Only the hash of the token is stored.
+type AuthToken struct {
+	ID           int64      `json:"id" db:"id"`
+	TokenHash    string     `json:"-" db:"token_hash"`
+	UserID       string     `json:"-" db:"user_id"`
+	Kind         string     `json:"kind" db:"kind"`
+	Name         string     `json:"name" db:"name"`
+	ExpireTime   *time.Time `json:"expireTime,omitempty" db:"expire_time"`
+	LastUsedTime *time.Time `json:"lastUsedTime,omitempty" db:"last_used_time"`
+	CreateTime   time.Time  `json:"createTime" db:"create_time"`
+}
+
+// IsExpired returns true if the token has expired at t
+func (t *AuthToken) IsExpired(at time.Time) bool {
+	return t.ExpireTime != nil && !at.Before(*t.ExpireTime)
+}
+
+// IsValidRole returns true if role is a known account role
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleUser
+}
+
+// ValidateCredentials checks the username and password of a new account
+func ValidateCredentials(username, password string) error {
+	if !usernamePattern.MatchString(username) {
+		return errors.New("用户名需为 3-32 位字母、数字或 _ . -")
+	}
+	if len(password) < MinPasswordLength {
+		return errors.New("密码至少 8 位")
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserDataVersion is the version of the user data export format written by this server
+const UserDataVersion = 1
+
+// User data import modes
+const (
+	ImportModeMerge   = "merge"   // keep existing data, imported entries win on conflict
+	ImportModeReplace = "replace" // delete existing data before importing
+)
+
+// UserDataExport is a portable copy of everything a user has configured, used to
+// move data between browsers or accounts
+type UserDataExport struct {
+	Version         int                    `json:"version"`
+	ExportedAt      time.Time              `json:"exportedAt"`
+	Settings        UserDataSettings       `json:"settings"`
+	Notifications   []UserDataNotification `json:"notifications"`
+	BlockedProducts []string               `json:"blockedProducts"`
+	SavedSearches   []UserDataSavedSearch  `json:"savedSearches"`
+	Annotations     []UserDataAnnotation   `json:"annotations"`
+}
+
+// UserDataSettings holds the Bark key and notification preferences.
+// A nil Preferences leaves the current preferences untouched on merge.
+type UserDataSettings struct {
+	BarkKey     string                   `json:"barkKey"`
+	Preferences *NotificationPreferences `json:"preferences,omitempty"`
+}
+
+// UserDataNotification is an exported notification rule including its rule state
+type UserDataNotification struct {
+	ActivityID     string     `json:"activityId"`
+	RuleType       string     `json:"ruleType"`
+	TargetPrice    float64    `json:"targetPrice,omitempty"`
+	DropPercent    float64    `json:"dropPercent,omitempty"`
+	DropAmount     float64    `json:"dropAmount,omitempty"`
+	ReferencePrice *float64   `json:"referencePrice,omitempty"`
+	LastStatus     *int       `json:"lastStatus,omitempty"`
+	SnoozeUntil    *time.Time `json:"snoozeUntil,omitempty"`
+}
+
+// UserDataSavedSearch is an exported saved search
+type UserDataSavedSearch struct {
+	Name     string  `json:"name"`
+	Keyword  string  `json:"keyword"`
+	Region   string  `json:"region"`
+	Platform string  `json:"platform"`
+	MaxPrice float64 `json:"maxPrice,omitempty"`
+}
+
+// UserDataAnnotation is an exported favorite star, tag list and note of a product
+type UserDataAnnotation struct {
+	ActivityID string   `json:"activityId"`
+	Favorite   bool     `json:"favorite,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Note       string   `json:"note,omitempty"`
+}
+
+// UserDataImportSummary reports what an import changed
+type UserDataImportSummary struct {
+	Mode            string `json:"mode"`
+	Settings        bool   `json:"settings"`        // true if the Bark key or preferences were written
+	Notifications   int    `json:"notifications"`   // rules created or overwritten
+	BlockedProducts int    `json:"blockedProducts"` // products newly blocked
+	SavedSearches   int    `json:"savedSearches"`   // searches created
+	Annotations     int    `json:"annotations"`     // annotations created or overwritten
+	Skipped         int    `json:"skipped"`         // entries already present
+}
+
+// IsValidImportMode returns true if mode is a supported import mode
+func IsValidImportMode(mode string) bool {
+	return mode == ImportModeMerge || mode == ImportModeReplace
+}
+
+// ToConfig converts the exported rule to a notification config of the user
+func (n UserDataNotification) ToConfig(userID string) *NotificationConfig {
+	config := &NotificationConfig{
+		ActivityID:     strings.TrimSpace(n.ActivityID),
+		UserID:         userID,
+		RuleType:       n.RuleType,
+		TargetPrice:    n.TargetPrice,
+		DropPercent:    n.DropPercent,
+		DropAmount:     n.DropAmount,
+		ReferencePrice: n.ReferencePrice,
+		LastStatus:     n.LastStatus,
+		SnoozeUntil:    n.SnoozeUntil,
+	}
+	config.RuleType = config.EffectiveRuleType()
+	return config
+}
+
+// ToSavedSearch converts the exported search to a normalized saved search of the user
+func (s UserDataSavedSearch) ToSavedSearch(userID string) *SavedSearch {
+	search := &SavedSearch{
+		UserID:   userID,
+		Name:     s.Name,
+		Keyword:  s.Keyword,
+		Region:   s.Region,
+		Platform: s.Platform,
+		MaxPrice: s.MaxPrice,
+	}
+	search.Normalize()
+	return search
+}
+
+// ToAnnotation converts the exported annotation to a normalized annotation of the user
+func (a UserDataAnnotation) ToAnnotation(userID string) *ProductAnnotation {
+	annotation := &ProductAnnotation{
+		ActivityID: a.ActivityID,
+		UserID:     userID,
+		Favorite:   a.Favorite,
+		Tags:       a.Tags,
+		Note:       a.Note,
+	}
+	annotation.Normalize()
+	return annotation
+}
+
+// Validate checks the whole document so that an import either applies fully or not at all
+func (d *UserDataExport) Validate() error {
+	if d.Version < 1 || d.Version > UserDataVersion {
+		return fmt.Errorf("不支持的导出版本: %d", d.Version)
+	}
+
+	if d.Settings.Preferences != nil {
+		if err := d.Settings.Preferences.Validate(); err != nil {
+			return fmt.Errorf("settings.preferences: %w", err)
+		}
+	}
+
+	for i, n := range d.Notifications {
+		config := n.ToConfig("")
+		if config.ActivityID == "" {
+			return fmt.Errorf("notifications[%d]: activityId is required", i)
+		}
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("notifications[%d]: %w", i, err)
+		}
+	}
+
+	for i, activityID := range d.BlockedProducts {
+		if strings.TrimSpace(activityID) == "" {
+			return fmt.Errorf("blockedProducts[%d]: activityId is required", i)
+		}
+	}
+
+	for i, s := range d.SavedSearches {
+		if err := s.ToSavedSearch("").Validate(); err != nil {
+			return fmt.Errorf("savedSearches[%d]: %w", i, err)
+		}
+	}
+
+	for i, a := range d.Annotations {
+		annotation := a.ToAnnotation("")
+		if annotation.ActivityID == "" {
+			return fmt.Errorf("annotations[%d]: activityId is required", i)
+		}
+		if err := annotation.Validate(); err != nil {
+			return fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
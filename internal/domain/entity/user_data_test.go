@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUserDataExport_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     UserDataExport
+		wantErr string
+	}{
+		{
+			name: "valid document",
+			doc: UserDataExport{
+				Version:         UserDataVersion,
+				Settings:        UserDataSettings{BarkKey: "KEY", Preferences: &NotificationPreferences{QuietStart: "22:00", QuietEnd: "08:00"}},
+				Notifications:   []UserDataNotification{{ActivityID: "DT_1", TargetPrice: 9.9}},
+				BlockedProducts: []string{"DT_2"},
+				SavedSearches:   []UserDataSavedSearch{{Keyword: "拿铁"}},
+			},
+		},
+		{name: "missing version", doc: UserDataExport{}, wantErr: "版本"},
+		{name: "future version", doc: UserDataExport{Version: UserDataVersion + 1}, wantErr: "版本"},
+		{
+			name:    "bad preferences",
+			doc:     UserDataExport{Version: 1, Settings: UserDataSettings{Preferences: &NotificationPreferences{QuietStart: "25:00"}}},
+			wantErr: "settings.preferences",
+		},
+		{
+			name:    "notification without activity",
+			doc:     UserDataExport{Version: 1, Notifications: []UserDataNotification{{TargetPrice: 1}}},
+			wantErr: "notifications[0]",
+		},
+		{
+			name:    "notification with bad rule",
+			doc:     UserDataExport{Version: 1, Notifications: []UserDataNotification{{ActivityID: "DT_1", RuleType: RuleTypePercentDrop}}},
+			wantErr: "notifications[0]",
+		},
+		{
+			name:    "empty blocked product",
+			doc:     UserDataExport{Version: 1, BlockedProducts: []string{"DT_1", " "}},
+			wantErr: "blockedProducts[1]",
+		},
+		{
+			name:    "saved search without criteria",
+			doc:     UserDataExport{Version: 1, SavedSearches: []UserDataSavedSearch{{Name: "空"}}},
+			wantErr: "savedSearches[0]",
+		},
+		{
+			name:    "annotation without activity",
+			doc:     UserDataExport{Version: 1, Annotations: []UserDataAnnotation{{Favorite: true}}},
+			wantErr: "annotations[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.doc.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
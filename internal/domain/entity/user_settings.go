@@ -1,11 +1,55 @@
 package entity
 
-import "time"
+import (
+	"net/url"
+	"strings"
+	"time"
+)
 
 // UserSettings stores user-specific settings including Bark key
 type UserSettings struct {
-	UserID     string     `json:"userId" db:"user_id"`
-	BarkKey    string     `json:"barkKey" db:"bark_key"`
-	CreateTime time.Time  `json:"createTime" db:"create_time"`
-	UpdateTime time.Time  `json:"updateTime" db:"update_time"`
+	UserID      string                  `json:"userId" db:"user_id"`
+	BarkKey     string                  `json:"barkKey" db:"bark_key"`
+	Preferences NotificationPreferences `json:"preferences"`
+	LastDigest  *time.Time              `json:"lastDigest" db:"last_digest_time"`
+	CreateTime  time.Time               `json:"createTime" db:"create_time"`
+	UpdateTime  time.Time               `json:"updateTime" db:"update_time"`
+}
+
+// NormalizeBarkKey extracts the device key from a full Bark URL or returns the key as-is.
+// Legacy clients used the normalized key as their user ID.
+func NormalizeBarkKey(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return ""
+	}
+
+	// If full URL, extract the last non-empty path segment as device key.
+	if strings.HasPrefix(strings.ToLower(input), "http") {
+		parsed, err := url.Parse(input)
+		if err == nil {
+			path := strings.Trim(parsed.Path, "/")
+			if path == "" {
+				return ""
+			}
+
+			parts := strings.Split(path, "/")
+			for i := len(parts) - 1; i >= 0; i-- {
+				if seg := strings.TrimSpace(parts[i]); seg != "" {
+					return seg
+				}
+			}
+			return ""
+		}
+
+		trimmed := strings.Trim(input, "/")
+		parts := strings.Split(trimmed, "/")
+		for i := len(parts) - 1; i >= 0; i-- {
+			if seg := strings.TrimSpace(parts[i]); seg != "" {
+				return seg
+			}
+		}
+		return ""
+	}
+	return input
 }
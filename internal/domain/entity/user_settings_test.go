@@ -1,4 +1,4 @@
-package service
+package entity
 
 import "testing"
 
@@ -37,10 +37,9 @@ func TestNormalizeBarkKey(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := normalizeBarkKey(tt.input); got != tt.want {
-				t.Fatalf("normalizeBarkKey(%q) = %q, want %q", tt.input, got, tt.want)
+			if got := NormalizeBarkKey(tt.input); got != tt.want {
+				t.Fatalf("NormalizeBarkKey(%q) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
 }
-
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Watchlist member roles
+const (
+	WatchlistRoleOwner  = "owner"  // created the list, may rename, delete and manage members
+	WatchlistRoleMember = "member" // joined through the invite link
+)
+
+// maxWatchlistNameLength is the maximum length of a watchlist name in runes
+const maxWatchlistNameLength = 50
+
+// Watchlist is a named list of notification rules shared by its members.
+// An alert on one of its items is sent to every member.
+type Watchlist struct {
+	ID          int64     `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	InviteCode  string    `json:"inviteCode" db:"invite_code"`
+	InviteURL   string    `json:"inviteUrl,omitempty" db:"-"`
+	Role        string    `json:"role" db:"-"` // role of the requesting user
+	MemberCount int       `json:"memberCount" db:"-"`
+	CreateTime  time.Time `json:"createTime" db:"create_time"`
+	UpdateTime  time.Time `json:"updateTime" db:"update_time"`
+}
+
+// WatchlistMember is a user who receives the alerts of a watchlist
+type WatchlistMember struct {
+	ID          int64     `json:"id" db:"id"`
+	WatchlistID int64     `json:"-" db:"watchlist_id"`
+	UserID      string    `json:"-" db:"user_id"`
+	Username    string    `json:"username" db:"-"` // account name, empty for anonymous members
+	Role        string    `json:"role" db:"role"`
+	CreateTime  time.Time `json:"createTime" db:"create_time"`
+	Current     bool      `json:"current" db:"-"` // true for the requesting user
+}
+
+// WatchlistItem is a product on a watchlist. Its rule works like a personal
+// notification config, but alerts are sent to every member of the list.
+// The embedded config has no user ID.
+type WatchlistItem struct {
+	WatchlistID int64 `json:"watchlistId" db:"watchlist_id"`
+	NotificationConfig
+}
+
+// Normalize trims the name
+func (w *Watchlist) Normalize() {
+	w.Name = strings.TrimSpace(w.Name)
+}
+
+// Validate checks the name of the list
+func (w *Watchlist) Validate() error {
+	if w.Name == "" {
+		return errors.New("清单名称不能为空")
+	}
+	if utf8.RuneCountInString(w.Name) > maxWatchlistNameLength {
+		return errors.New("清单名称不能超过 50 个字")
+	}
+	return nil
+}
+
+// IsOwner returns true if the member created the list
+func (m *WatchlistMember) IsOwner() bool {
+	return m.Role == WatchlistRoleOwner
+}
@@ -0,0 +1,214 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Event names
+const (
+	NamePriceChanged   = "price_changed"
+	NameStatusChanged  = "status_changed"
+	NameProductCreated = "product_created"
+)
+
+// Event is a domain event published on the bus
+type Event interface {
+	// Name returns the event name subscribers register for
+	Name() string
+}
+
+// PriceChanged is published when the price of a product changes
+type PriceChanged struct {
+	ActivityID string
+	Region     string
+	Platform   string
+	Title      string
+	OldPrice   float64
+	NewPrice   float64
+	OccurredAt time.Time
+}
+
+// Name returns the event name
+func (e PriceChanged) Name() string {
+	return NamePriceChanged
+}
+
+// StatusChanged is published when the sales status of a product changes
+type StatusChanged struct {
+	ActivityID string
+	Region     string
+	Platform   string
+	Title      string
+	OldStatus  int
+	NewStatus  int
+	OccurredAt time.Time
+}
+
+// Name returns the event name
+func (e StatusChanged) Name() string {
+	return NameStatusChanged
+}
+
+// ProductCreated is published when a new product is promoted from the candidate pool
+type ProductCreated struct {
+	ActivityID string
+	Region     string
+	Platform   string
+	Title      string
+	Price      float64
+	Status     int
+	OccurredAt time.Time
+}
+
+// Name returns the event name
+func (e ProductCreated) Name() string {
+	return NameProductCreated
+}
+
+// Handler handles a published event
+type Handler func(ctx context.Context, e Event)
+
+// queueSize is how many published events may wait for delivery
+const queueSize = 4096
+
+// Bus is an in-process domain event bus. Published events are queued and
+// delivered in publish order by a single worker goroutine, so slow
+// subscribers never hold up the publisher.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	queue chan queuedEvent
+	done  chan struct{}
+
+	// stateMu guards pending and closed; idle is signaled when pending drops to zero
+	stateMu sync.Mutex
+	idle    *sync.Cond
+	pending int
+	closed  bool
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	event Event
+}
+
+// NewBus creates a new event bus and starts its delivery worker
+func NewBus() *Bus {
+	b := &Bus{
+		handlers: make(map[string][]Handler),
+		queue:    make(chan queuedEvent, queueSize),
+		done:     make(chan struct{}),
+	}
+	b.idle = sync.NewCond(&b.stateMu)
+	go b.run()
+	return b
+}
+
+// Subscribe registers a handler for the named event
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish queues the event for delivery to its subscribers and returns
+// without waiting for them. Handlers get the publisher's context values but
+// not its cancellation. The event is dropped with a warning once the queue
+// is full or the bus is closed. Publishing on a nil bus is a no-op.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+
+	if b.closed {
+		log.Warn().Str("event", e.Name()).Msg("event bus closed, dropping event")
+		return
+	}
+
+	b.pending++
+	select {
+	case b.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: e}:
+	default:
+		b.pending--
+		log.Warn().Str("event", e.Name()).Msg("event queue full, dropping event")
+	}
+}
+
+// Wait blocks until every event published so far has been delivered
+func (b *Bus) Wait() {
+	if b == nil {
+		return
+	}
+
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+	for b.pending > 0 {
+		b.idle.Wait()
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.stateMu.Lock()
+	if b.closed {
+		b.stateMu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.stateMu.Unlock()
+
+	<-b.done
+}
+
+// run delivers queued events until the bus is closed
+func (b *Bus) run() {
+	defer close(b.done)
+
+	for queued := range b.queue {
+		b.deliver(queued.ctx, queued.event)
+
+		b.stateMu.Lock()
+		b.pending--
+		if b.pending == 0 {
+			b.idle.Broadcast()
+		}
+		b.stateMu.Unlock()
+	}
+}
+
+// deliver passes the event to its subscribers in registration order.
+// A panicking handler is logged and does not affect the others.
+func (b *Bus) deliver(ctx context.Context, e Event) {
+	b.mu.RLock()
+	handlers := b.handlers[e.Name()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.dispatch(ctx, handler, e)
+	}
+}
+
+func (b *Bus) dispatch(ctx context.Context, handler Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Interface("panic", r).
+				Str("event", e.Name()).
+				Msg("event handler panicked")
+		}
+	}()
+	handler(ctx, e)
+}
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBus_PublishDeliversToSubscribersInOrder(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	var calls []string
+	bus.Subscribe(NamePriceChanged, func(ctx context.Context, e Event) {
+		panic("boom")
+	})
+	bus.Subscribe(NamePriceChanged, func(ctx context.Context, e Event) {
+		calls = append(calls, "price:"+e.(PriceChanged).ActivityID)
+	})
+	bus.Subscribe(NameStatusChanged, func(ctx context.Context, e Event) {
+		calls = append(calls, "status:"+e.(StatusChanged).ActivityID)
+	})
+
+	bus.Publish(context.Background(), PriceChanged{ActivityID: "DT_1", OldPrice: 10, NewPrice: 8})
+	bus.Publish(context.Background(), StatusChanged{ActivityID: "DT_2", OldStatus: 0, NewStatus: 1})
+	bus.Wait()
+
+	if len(calls) != 2 || calls[0] != "price:DT_1" || calls[1] != "status:DT_2" {
+		t.Fatalf("unexpected handler calls: %v", calls)
+	}
+
+	var nilBus *Bus
+	nilBus.Publish(context.Background(), PriceChanged{ActivityID: "DT_1"})
+	nilBus.Wait()
+	nilBus.Close()
+}
+
+func TestBus_SlowHandlerDoesNotBlockPublish(t *testing.T) {
+	bus := NewBus()
+
+	release := make(chan struct{})
+	var delivered []string
+	bus.Subscribe(NamePriceChanged, func(ctx context.Context, e Event) {
+		<-release
+		if ctx.Err() != nil {
+			t.Errorf("handler context canceled: %v", ctx.Err())
+		}
+		delivered = append(delivered, e.(PriceChanged).ActivityID)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(ctx, PriceChanged{ActivityID: "DT_1"})
+		bus.Publish(ctx, PriceChanged{ActivityID: "DT_2"})
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Publish() waited for a blocked handler")
+	}
+
+	// Handlers outlive the publisher's context
+	cancel()
+	close(release)
+	bus.Close()
+
+	if len(delivered) != 2 || delivered[0] != "DT_1" || delivered[1] != "DT_2" {
+		t.Fatalf("delivered = %v, want [DT_1 DT_2]", delivered)
+	}
+
+	// Events published after Close are dropped
+	bus.Publish(context.Background(), PriceChanged{ActivityID: "DT_3"})
+	bus.Wait()
+	if len(delivered) != 2 {
+		t.Errorf("delivered after Close = %v", delivered)
+	}
+}
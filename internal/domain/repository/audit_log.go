@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// AuditLogRepository defines the interface for admin audit log data access
+type AuditLogRepository interface {
+	// Create records an admin action
+	Create(ctx context.Context, log *entity.AuditLog) error
+
+	// ListRecent lists the most recent admin actions, newest first
+	ListRecent(ctx context.Context, limit int) ([]*entity.AuditLog, error)
+}
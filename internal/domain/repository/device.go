@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+// DeviceRepository defines the interface for pairing code and paired device data access
+type DeviceRepository interface {
+	// CreatePairingCode stores a pairing code and deletes the earlier codes of the
+	// user. Returns false if the code is already taken.
+	CreatePairingCode(ctx context.Context, code *entity.PairingCode) (bool, error)
+
+	// TakePairingCode deletes a pairing code and returns it, or nil if it does not exist
+	TakePairingCode(ctx context.Context, code string) (*entity.PairingCode, error)
+
+	// DeleteExpiredPairingCodes deletes codes that expired before the given time
+	DeleteExpiredPairingCodes(ctx context.Context, before time.Time) error
+
+	// Pair binds the client ID of the device to its user ID and sets the device ID.
+	// If the client ID was not paired yet, its data is merged into the user's data.
+	Pair(ctx context.Context, device *entity.Device) error
+
+	// FindByClientID finds the device paired with a client ID
+	FindByClientID(ctx context.Context, clientID string) (*entity.Device, error)
+
+	// ListByUser lists the devices paired to a user, newest first
+	ListByUser(ctx context.Context, userID string) ([]*entity.Device, error)
+
+	// Touch records the last time a device was used
+	Touch(ctx context.Context, id int64, seenAt time.Time) error
+
+	// Delete unpairs a device of the user, returning false if it did not exist
+	Delete(ctx context.Context, id int64, userID string) (bool, error)
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// MasterShopRepository defines the interface for the shops selling master products
+type MasterShopRepository interface {
+	// AddShop records that a shop sells a master product
+	AddShop(ctx context.Context, masterID, shopName string) error
+}
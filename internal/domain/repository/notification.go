@@ -14,6 +14,9 @@ type NotificationRepository interface {
 	// ListByUser lists all notification configs for a user
 	ListByUser(ctx context.Context, userID string) ([]*entity.NotificationConfig, error)
 
+	// ListByActivityID lists all notification configs watching a product
+	ListByActivityID(ctx context.Context, activityID string) ([]*entity.NotificationConfig, error)
+
 	// ListAll lists all notification configs (for background notification checker)
 	ListAll(ctx context.Context) ([]*entity.NotificationConfig, error)
 
@@ -23,6 +26,9 @@ type NotificationRepository interface {
 	// Delete deletes a notification config by activity ID and user ID
 	Delete(ctx context.Context, activityID string, userID string) error
 
-	// UpdateNotifyTime updates the last notification time
-	UpdateNotifyTime(ctx context.Context, activityID string, userID string) error
+	// UpdateNotifyTime updates the last notification time and records the alerted price
+	UpdateNotifyTime(ctx context.Context, activityID string, userID string, price float64) error
+
+	// UpdateLastStatus records the last observed sales status of the watched product
+	UpdateLastStatus(ctx context.Context, activityID string, userID string, status int) error
 }
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+// NotificationDeliveryRepository defines the interface for sent and held alert records
+type NotificationDeliveryRepository interface {
+	// Create records a sent or held alert
+	Create(ctx context.Context, delivery *entity.NotificationDelivery) error
+
+	// CountSentSince counts the alerts sent to a user since the given time
+	CountSentSince(ctx context.Context, userID string, since time.Time) (int, error)
+
+	// ListHeld lists all held alerts, oldest first
+	ListHeld(ctx context.Context) ([]*entity.NotificationDelivery, error)
+
+	// MarkSent marks a held alert as sent now
+	MarkSent(ctx context.Context, id int64) error
+
+	// DeleteSentBefore deletes sent alert records older than the given time
+	DeleteSentBefore(ctx context.Context, before time.Time) error
+}
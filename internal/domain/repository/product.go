@@ -27,6 +27,9 @@ type ProductRepository interface {
 	// FindByFilter finds products by filter criteria
 	FindByFilter(ctx context.Context, filter ProductFilter) ([]*entity.Product, error)
 
+	// FindByShop finds up to limit other products of the same shop, newest first
+	FindByShop(ctx context.Context, shopName, excludeActivityID string, limit int) ([]*entity.Product, error)
+
 	// Create creates a new product
 	Create(ctx context.Context, product *entity.Product) error
 
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// ProductAnnotationRepository defines the interface for favorites, tags and notes data access
+type ProductAnnotationRepository interface {
+	// Find finds the annotation of a product for a user, or nil if there is none
+	Find(ctx context.Context, activityID string, userID string) (*entity.ProductAnnotation, error)
+
+	// ListByUser lists all annotations of a user, most recently changed first
+	ListByUser(ctx context.Context, userID string) ([]*entity.ProductAnnotation, error)
+
+	// Upsert creates or replaces an annotation
+	Upsert(ctx context.Context, annotation *entity.ProductAnnotation) error
+
+	// Delete deletes the annotation of a product for a user
+	Delete(ctx context.Context, activityID string, userID string) error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// MaxFeedEntries caps the number of entries of a feed
+const MaxFeedEntries = 50
+
+// ProductChangeQuery selects recorded product changes. Empty fields do not filter.
+type ProductChangeQuery struct {
+	Kind      string // entity.ProductChangePrice or entity.ProductChangeNew
+	DropsOnly bool   // only price changes that lowered the price
+	Region    string
+	Platform  string
+	Keyword   string // substring of the title
+	// ActivityIDs restricts the changes to these products when not nil
+	ActivityIDs []string
+	Limit       int
+}
+
+// ProductChangeRepository defines the interface for recorded product change data access
+type ProductChangeRepository interface {
+	// Create records a change and sets its ID and creation time
+	Create(ctx context.Context, change *entity.ProductChange) error
+
+	// List lists the changes matching the query, newest first
+	List(ctx context.Context, query ProductChangeQuery) ([]*entity.ProductChange, error)
+}
+
+// FeedTokenRepository defines the interface for private feed token data access
+type FeedTokenRepository interface {
+	// FindByUser finds the feed token of a user, or nil if it has none
+	FindByUser(ctx context.Context, userID string) (*entity.FeedToken, error)
+
+	// FindByToken finds a feed token by its secret, or nil if it does not exist
+	FindByToken(ctx context.Context, token string) (*entity.FeedToken, error)
+
+	// Save stores the feed token of a user, replacing the previous one
+	Save(ctx context.Context, token *entity.FeedToken) error
+}
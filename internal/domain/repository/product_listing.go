@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// Product listing sort keys
+const (
+	ListingSortUpdateTime = "update_time"
+	ListingSortPrice      = "price"
+	ListingSortDiscount   = "discount"
+	ListingSortDropRate   = "drop_rate"
+	ListingSortRelevance  = "relevance" // search relevance of the keyword
+)
+
+// MaxListingLimit caps the page size of a product listing
+const MaxListingLimit = 200
+
+// ProductListingQuery holds filter, sort and page parameters for listing master products.
+// Monitor, block, favorite and tag filters are scoped to UserID.
+type ProductListingQuery struct {
+	UserID        string
+	Region        string
+	Platform      string
+	Keyword       string // full-text search over titles and shop names
+	SharedShopOf  string // master ID, limits to the other masters sold by its shops
+	SalesStatus   *int
+	MonitorStatus *int
+	FavoriteOnly  bool
+	Tag           string
+	MinPrice      *float64
+	MaxPrice      *float64
+	Sort          string // one of the ListingSort keys, defaults to update time
+	Descending    bool
+	Cursor        *ListingCursor
+	Limit         int // 0 returns every match
+}
+
+// ListingCursor marks the last product of a page by its sort value and ID
+type ListingCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// ProductListing is a master product with its reference price, which is the
+// highest price ever recorded for it
+type ProductListing struct {
+	*entity.MasterProduct
+	OriginalPrice float64
+	Relevance     float64 // search relevance, zero without a keyword
+}
+
+// ProductListingPage is one page of a product listing
+type ProductListingPage struct {
+	Products   []*ProductListing
+	Total      int            // matches across all pages
+	NextCursor *ListingCursor // nil on the last page
+}
+
+// IsValidListingSort returns true if sort is a supported listing sort key
+func IsValidListingSort(sort string) bool {
+	switch sort {
+	case ListingSortUpdateTime, ListingSortPrice, ListingSortDiscount, ListingSortDropRate, ListingSortRelevance:
+		return true
+	}
+	return false
+}
+
+// ProductListingRepository defines the interface for filtered product listings
+type ProductListingRepository interface {
+	// List returns one page of master products matching the query
+	List(ctx context.Context, query ProductListingQuery) (*ProductListingPage, error)
+
+	// OriginalPrice returns the stored original price of a master product,
+	// 0 if the product does not exist
+	OriginalPrice(ctx context.Context, masterID string) (float64, error)
+}
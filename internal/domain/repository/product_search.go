@@ -0,0 +1,27 @@
+package repository
+
+import "context"
+
+// Product search sources
+const (
+	SearchSourceMaster  = "master"  // standard products in the master catalog
+	SearchSourceProduct = "product" // raw platform products
+)
+
+// MaxSearchLimit caps the number of search results
+const MaxSearchLimit = 50
+
+// SearchHit is a product whose title or shop name matched a search
+type SearchHit struct {
+	ActivityID string
+	Source     string
+	Title      string
+	ShopName   string
+	Relevance  float64 // higher is more relevant
+}
+
+// ProductSearchRepository defines the interface for full-text product search
+type ProductSearchRepository interface {
+	// Search finds products matching every term, most relevant first, one hit per activity ID
+	Search(ctx context.Context, terms []string, limit int) ([]*SearchHit, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// SavedSearchRepository defines the interface for saved search data access
+type SavedSearchRepository interface {
+	// FindByID finds a saved search by ID and user ID
+	FindByID(ctx context.Context, id int64, userID string) (*entity.SavedSearch, error)
+
+	// ListByUser lists all saved searches for a user
+	ListByUser(ctx context.Context, userID string) ([]*entity.SavedSearch, error)
+
+	// ListAll lists all saved searches (for matching new products)
+	ListAll(ctx context.Context) ([]*entity.SavedSearch, error)
+
+	// Create creates a saved search and fills in its ID and timestamps
+	Create(ctx context.Context, search *entity.SavedSearch) error
+
+	// Update updates the criteria of a saved search
+	Update(ctx context.Context, search *entity.SavedSearch) error
+
+	// Delete deletes a saved search and its match history
+	Delete(ctx context.Context, id int64, userID string) error
+
+	// RecordMatch records that a product matched a saved search
+	// Returns false if the match was already recorded
+	RecordMatch(ctx context.Context, searchID int64, activityID string) (bool, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// TitleVoteRepository defines the interface for the title votes of master products
+type TitleVoteRepository interface {
+	// AddVotes adds the vote counts of raw titles to a master product
+	AddVotes(ctx context.Context, masterID string, votes map[string]int) error
+
+	// ListByMaster lists the title votes of a master product, most votes first
+	ListByMaster(ctx context.Context, masterID string) ([]*entity.TitleVote, error)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+// UserRepository defines the interface for account data access
+type UserRepository interface {
+	// FindByID finds an account by ID
+	FindByID(ctx context.Context, id string) (*entity.User, error)
+
+	// FindByUsername finds an account by username
+	FindByUsername(ctx context.Context, username string) (*entity.User, error)
+
+	// Create creates an account
+	Create(ctx context.Context, user *entity.User) error
+
+	// UpdateRole sets the role of an account, returning false if it does not exist
+	UpdateRole(ctx context.Context, username, role string) (bool, error)
+}
+
+// AuthTokenRepository defines the interface for session and API token data access
+type AuthTokenRepository interface {
+	// FindByHash finds a token by the hash of its secret
+	FindByHash(ctx context.Context, tokenHash string) (*entity.AuthToken, error)
+
+	// ListByUser lists the tokens of an account, newest first
+	ListByUser(ctx context.Context, userID string) ([]*entity.AuthToken, error)
+
+	// Create creates a token and sets its ID
+	Create(ctx context.Context, token *entity.AuthToken) error
+
+	// Touch records the last time a token was used
+	Touch(ctx context.Context, id int64, usedAt time.Time) error
+
+	// Delete deletes a token owned by the user, returning false if it did not exist
+	Delete(ctx context.Context, id int64, userID string) (bool, error)
+
+	// DeleteByHash deletes a token by the hash of its secret
+	DeleteByHash(ctx context.Context, tokenHash string) error
+
+	// DeleteExpired deletes tokens that expired before the given time
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// ClientClaimRepository moves the data of anonymous client IDs onto accounts
+type ClientClaimRepository interface {
+	// IsClaimed returns true if the anonymous client ID has been claimed
+	IsClaimed(ctx context.Context, clientID string) (bool, error)
+
+	// Claim moves all data owned by the client ID onto the account and marks the
+	// client ID as claimed. Returns false if the client ID was already claimed.
+	Claim(ctx context.Context, clientID, userID string) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// UserDataRepositories are the repositories holding the data a user can export and import
+type UserDataRepositories struct {
+	Settings      UserSettingsRepository
+	Notifications NotificationRepository
+	Blocked       BlockedRepository
+	SavedSearches SavedSearchRepository
+	Annotations   ProductAnnotationRepository
+}
+
+// UserDataTransactor runs writes to the data of a user in one transaction
+type UserDataTransactor interface {
+	// InTx calls fn with repositories bound to a transaction, committed if fn
+	// returns nil and rolled back otherwise
+	InTx(ctx context.Context, fn func(repos UserDataRepositories) error) error
+}
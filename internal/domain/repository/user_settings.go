@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"kbfood/internal/domain/entity"
 )
@@ -13,4 +14,13 @@ type UserSettingsRepository interface {
 
 	// Upsert creates or updates user settings
 	Upsert(ctx context.Context, settings *entity.UserSettings) error
+
+	// UpdatePreferences creates or updates the notification preferences of a user
+	UpdatePreferences(ctx context.Context, userID string, prefs entity.NotificationPreferences) error
+
+	// ListDigestSubscribers lists the settings of users with digest mode enabled
+	ListDigestSubscribers(ctx context.Context) ([]*entity.UserSettings, error)
+
+	// MarkDigestSent records when the daily digest was last sent to a user
+	MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error
 }
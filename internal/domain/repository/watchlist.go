@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"kbfood/internal/domain/entity"
+)
+
+// WatchlistRepository defines the interface for shared watchlist data access
+type WatchlistRepository interface {
+	// Create creates a watchlist with the user as its owner and sets its ID
+	Create(ctx context.Context, watchlist *entity.Watchlist, ownerUserID string) error
+
+	// FindByID finds a watchlist by ID
+	FindByID(ctx context.Context, id int64) (*entity.Watchlist, error)
+
+	// FindByInviteCode finds a watchlist by its invite code
+	FindByInviteCode(ctx context.Context, inviteCode string) (*entity.Watchlist, error)
+
+	// ListByMember lists the watchlists of a user with the user's role, newest first
+	ListByMember(ctx context.Context, userID string) ([]*entity.Watchlist, error)
+
+	// Update updates the name and invite code of a watchlist
+	Update(ctx context.Context, watchlist *entity.Watchlist) error
+
+	// Delete deletes a watchlist with its members and items
+	Delete(ctx context.Context, id int64) error
+
+	// FindMember finds the membership of a user, or nil if the user is not a member
+	FindMember(ctx context.Context, watchlistID int64, userID string) (*entity.WatchlistMember, error)
+
+	// ListMembers lists the members of a watchlist, owner first
+	ListMembers(ctx context.Context, watchlistID int64) ([]*entity.WatchlistMember, error)
+
+	// AddMember adds a member and sets its ID. Returns false if the user is already a member.
+	AddMember(ctx context.Context, member *entity.WatchlistMember) (bool, error)
+
+	// RemoveMember removes a member, returning false if it did not exist
+	RemoveMember(ctx context.Context, watchlistID, memberID int64) (bool, error)
+
+	// ListItems lists the items of a watchlist, oldest first
+	ListItems(ctx context.Context, watchlistID int64) ([]*entity.WatchlistItem, error)
+
+	// ListItemsByMember lists the items of every watchlist the user is a member of
+	ListItemsByMember(ctx context.Context, userID string) ([]*entity.WatchlistItem, error)
+
+	// ListItemsByActivityID lists the items watching a product on any watchlist
+	ListItemsByActivityID(ctx context.Context, activityID string) ([]*entity.WatchlistItem, error)
+
+	// ListAllItems lists the items of every watchlist
+	ListAllItems(ctx context.Context) ([]*entity.WatchlistItem, error)
+
+	// FindItem finds an item by product, or nil if the product is not on the list
+	FindItem(ctx context.Context, watchlistID int64, activityID string) (*entity.WatchlistItem, error)
+
+	// SaveItem adds a product to a watchlist or replaces its rule
+	SaveItem(ctx context.Context, item *entity.WatchlistItem) error
+
+	// DeleteItem removes a product from a watchlist
+	DeleteItem(ctx context.Context, watchlistID int64, activityID string) error
+
+	// UpdateItemNotifyTime records an alert on an item and the alerted price
+	UpdateItemNotifyTime(ctx context.Context, watchlistID int64, activityID string, price float64) error
+
+	// UpdateItemLastStatus records the last observed sales status of an item
+	UpdateItemLastStatus(ctx context.Context, watchlistID int64, activityID string, status int) error
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Authentication errors
+var (
+	ErrUsernameTaken      = apperrors.New(apperrors.Conflict, "用户名已被占用")
+	ErrInvalidCredentials = apperrors.New(apperrors.Unauthorized, "用户名或密码错误")
+	ErrInvalidToken       = apperrors.New(apperrors.Unauthorized, "登录已失效，请重新登录")
+	ErrClientClaimed      = apperrors.New(apperrors.Conflict, "该匿名标识已被认领")
+	ErrUserNotFound       = apperrors.New(apperrors.NotFound, "用户不存在")
+)
+
+// DefaultSessionTTL is how long a login session stays valid
+const DefaultSessionTTL = 30 * 24 * time.Hour
+
+// tokenPrefix marks kbFood tokens so they are recognizable in configs and leaks
+const tokenPrefix = "kbf_"
+
+// tokenTouchInterval limits how often the last used time of a token is written
+const tokenTouchInterval = time.Minute
+
+// AuthService manages accounts, sessions and personal API tokens
+type AuthService struct {
+	users      repository.UserRepository
+	tokens     repository.AuthTokenRepository
+	claims     repository.ClientClaimRepository
+	sessionTTL time.Duration
+	adminUsers map[string]bool // usernames that are always admins
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(
+	users repository.UserRepository,
+	tokens repository.AuthTokenRepository,
+	claims repository.ClientClaimRepository,
+	sessionTTL time.Duration,
+	adminUsers []string,
+) *AuthService {
+	if sessionTTL <= 0 {
+		sessionTTL = DefaultSessionTTL
+	}
+	admins := make(map[string]bool, len(adminUsers))
+	for _, username := range adminUsers {
+		if username = strings.TrimSpace(username); username != "" {
+			admins[username] = true
+		}
+	}
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		claims:     claims,
+		sessionTTL: sessionTTL,
+		adminUsers: admins,
+	}
+}
+
+// BootstrapAdmins grants the admin role to the configured admin usernames that
+// already have an account. Accounts signed up later get the role on sign-up.
+func (s *AuthService) BootstrapAdmins(ctx context.Context) error {
+	for username := range s.adminUsers {
+		updated, err := s.users.UpdateRole(ctx, username, entity.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		if updated {
+			log.Info().Str("username", username).Msg("Admin role granted from config")
+		}
+	}
+	return nil
+}
+
+// SetRole sets the role of an account
+func (s *AuthService) SetRole(ctx context.Context, username, role string) error {
+	if !entity.IsValidRole(role) {
+		return fmt.Errorf("无效的角色: %s", role)
+	}
+
+	updated, err := s.users.UpdateRole(ctx, strings.TrimSpace(username), role)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrUserNotFound
+	}
+
+	log.Info().
+		Str("username", username).
+		Str("role", role).
+		Msg("Account role changed")
+	return nil
+}
+
+// SignUp creates an account and returns it with a new session token
+func (s *AuthService) SignUp(ctx context.Context, username, password string) (*entity.User, string, error) {
+	username = strings.TrimSpace(username)
+	if err := entity.ValidateCredentials(username, password); err != nil {
+		return nil, "", err
+	}
+
+	existing, err := s.users.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing != nil {
+		return nil, "", ErrUsernameTaken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, "", fmt.Errorf("hash password: %w", err)
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user := &entity.User{
+		ID:           entity.UserIDPrefix + id,
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         entity.RoleUser,
+	}
+	if s.adminUsers[username] {
+		user.Role = entity.RoleAdmin
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, "", err
+	}
+
+	token, _, err := s.issue(ctx, user.ID, entity.TokenKindSession, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// Login verifies the credentials and returns the account with a new session token
+func (s *AuthService) Login(ctx context.Context, username, password string) (*entity.User, string, error) {
+	user, err := s.users.FindByUsername(ctx, strings.TrimSpace(username))
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil {
+		return nil, "", ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, "", ErrInvalidCredentials
+	}
+
+	if err := s.tokens.DeleteExpired(ctx, time.Now()); err != nil {
+		log.Error().Err(err).Msg("failed to prune expired sessions")
+	}
+
+	token, _, err := s.issue(ctx, user.ID, entity.TokenKindSession, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// Authenticate resolves a session or API token to its account
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*entity.User, error) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	record, err := s.tokens.FindByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if record == nil || record.IsExpired(now) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.users.FindByID(ctx, record.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+
+	if record.LastUsedTime == nil || now.Sub(*record.LastUsedTime) >= tokenTouchInterval {
+		if err := s.tokens.Touch(ctx, record.ID, now); err != nil {
+			log.Error().Err(err).
+				Int64("tokenId", record.ID).
+				Msg("failed to record token use")
+		}
+	}
+
+	return user, nil
+}
+
+// GetUser returns the account with the given ID, or nil if it does not exist
+func (s *AuthService) GetUser(ctx context.Context, userID string) (*entity.User, error) {
+	return s.users.FindByID(ctx, userID)
+}
+
+// Logout revokes a session or API token
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	return s.tokens.DeleteByHash(ctx, hashToken(token))
+}
+
+// CreateAPIToken creates a personal API token that is valid until revoked.
+// The secret is only returned here and cannot be retrieved later.
+func (s *AuthService) CreateAPIToken(ctx context.Context, userID, name string) (string, *entity.AuthToken, error) {
+	return s.issue(ctx, userID, entity.TokenKindAPI, strings.TrimSpace(name))
+}
+
+// ListTokens lists the sessions and API tokens of an account
+func (s *AuthService) ListTokens(ctx context.Context, userID string) ([]*entity.AuthToken, error) {
+	return s.tokens.ListByUser(ctx, userID)
+}
+
+// RevokeToken revokes a token of an account, returning false if it did not exist
+func (s *AuthService) RevokeToken(ctx context.Context, userID string, id int64) (bool, error) {
+	return s.tokens.Delete(ctx, id, userID)
+}
+
+// ClaimClient moves the data of an anonymous client ID onto the account.
+// Each client ID can only be claimed once.
+func (s *AuthService) ClaimClient(ctx context.Context, userID, clientID string) error {
+	clientID = strings.TrimSpace(clientID)
+	if clientID == "" || strings.HasPrefix(clientID, entity.UserIDPrefix) {
+		return apperrors.New(apperrors.InvalidInput, "无效的匿名标识")
+	}
+
+	claimed, err := s.claims.Claim(ctx, clientID, userID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrClientClaimed
+	}
+
+	log.Info().
+		Str("userId", userID).
+		Str("clientId", clientID).
+		Msg("Anonymous client data claimed")
+	return nil
+}
+
+// IsClientClaimed returns true if the anonymous client ID has been claimed by an account
+func (s *AuthService) IsClientClaimed(ctx context.Context, clientID string) (bool, error) {
+	return s.claims.IsClaimed(ctx, clientID)
+}
+
+// issue creates a token of the given kind and returns its secret
+func (s *AuthService) issue(ctx context.Context, userID, kind, name string) (string, *entity.AuthToken, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("generate token: %w", err)
+	}
+	token := tokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	record := &entity.AuthToken{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		Kind:      kind,
+		Name:      name,
+	}
+	if kind == entity.TokenKindSession {
+		expireTime := time.Now().Add(s.sessionTTL)
+		record.ExpireTime = &expireTime
+	}
+
+	if err := s.tokens.Create(ctx, record); err != nil {
+		return "", nil, err
+	}
+	return token, record, nil
+}
+
+// hashToken returns the stored form of a token secret
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+type stubUserRepository struct {
+	users []*entity.User
+}
+
+func (s *stubUserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	for _, user := range s.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubUserRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubUserRepository) Create(ctx context.Context, user *entity.User) error {
+	s.users = append(s.users, user)
+	return nil
+}
+
+func (s *stubUserRepository) UpdateRole(ctx context.Context, username, role string) (bool, error) {
+	for _, user := range s.users {
+		if user.Username == username {
+			user.Role = role
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type stubAuthTokenRepository struct {
+	tokens []*entity.AuthToken
+}
+
+func (s *stubAuthTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.AuthToken, error) {
+	for _, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubAuthTokenRepository) ListByUser(ctx context.Context, userID string) ([]*entity.AuthToken, error) {
+	var result []*entity.AuthToken
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubAuthTokenRepository) Create(ctx context.Context, token *entity.AuthToken) error {
+	token.ID = int64(len(s.tokens) + 1)
+	s.tokens = append(s.tokens, token)
+	return nil
+}
+
+func (s *stubAuthTokenRepository) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	for _, token := range s.tokens {
+		if token.ID == id {
+			token.LastUsedTime = &usedAt
+		}
+	}
+	return nil
+}
+
+func (s *stubAuthTokenRepository) Delete(ctx context.Context, id int64, userID string) (bool, error) {
+	for i, token := range s.tokens {
+		if token.ID == id && token.UserID == userID {
+			s.tokens = append(s.tokens[:i], s.tokens[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubAuthTokenRepository) DeleteByHash(ctx context.Context, tokenHash string) error {
+	for i, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			s.tokens = append(s.tokens[:i], s.tokens[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *stubAuthTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+type stubClientClaimRepository struct {
+	claims map[string]string
+}
+
+func (s *stubClientClaimRepository) IsClaimed(ctx context.Context, clientID string) (bool, error) {
+	_, ok := s.claims[clientID]
+	return ok, nil
+}
+
+func (s *stubClientClaimRepository) Claim(ctx context.Context, clientID, userID string) (bool, error) {
+	if s.claims == nil {
+		s.claims = make(map[string]string)
+	}
+	if _, ok := s.claims[clientID]; ok {
+		return false, nil
+	}
+	s.claims[clientID] = userID
+	return true, nil
+}
+
+func newTestAuthService() (*AuthService, *stubAuthTokenRepository) {
+	tokens := &stubAuthTokenRepository{}
+	return NewAuthService(&stubUserRepository{}, tokens, &stubClientClaimRepository{}, time.Hour, nil), tokens
+}
+
+func TestAuthService_SignUpLoginAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens := newTestAuthService()
+
+	user, token, err := svc.SignUp(ctx, " alice ", "correct-horse")
+	if err != nil {
+		t.Fatalf("SignUp() error = %v", err)
+	}
+	if user.Username != "alice" || !strings.HasPrefix(user.ID, entity.UserIDPrefix) {
+		t.Fatalf("unexpected user: %+v", user)
+	}
+	if user.PasswordHash == "correct-horse" {
+		t.Fatal("password stored in plain text")
+	}
+	if tokens.tokens[0].TokenHash == token {
+		t.Fatal("token stored in plain text")
+	}
+
+	if _, _, err := svc.SignUp(ctx, "alice", "another-password"); !errors.Is(err, ErrUsernameTaken) {
+		t.Fatalf("duplicate SignUp() error = %v, want ErrUsernameTaken", err)
+	}
+
+	if _, _, err := svc.Login(ctx, "alice", "wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() with wrong password error = %v, want ErrInvalidCredentials", err)
+	}
+
+	_, session, err := svc.Login(ctx, "alice", "correct-horse")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	authenticated, err := svc.Authenticate(ctx, session)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if authenticated.ID != user.ID {
+		t.Fatalf("Authenticate() user = %s, want %s", authenticated.ID, user.ID)
+	}
+
+	if err := svc.Logout(ctx, session); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, session); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Authenticate() after logout error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthService_RejectsExpiredSessionsButNotAPITokens(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens := newTestAuthService()
+
+	user, session, err := svc.SignUp(ctx, "bob", "correct-horse")
+	if err != nil {
+		t.Fatalf("SignUp() error = %v", err)
+	}
+	apiToken, record, err := svc.CreateAPIToken(ctx, user.ID, "cli")
+	if err != nil {
+		t.Fatalf("CreateAPIToken() error = %v", err)
+	}
+	if record.ExpireTime != nil {
+		t.Fatal("API token should not expire")
+	}
+
+	past := time.Now().Add(-time.Minute)
+	tokens.tokens[0].ExpireTime = &past
+
+	if _, err := svc.Authenticate(ctx, session); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Authenticate() expired session error = %v, want ErrInvalidToken", err)
+	}
+	if _, err := svc.Authenticate(ctx, apiToken); err != nil {
+		t.Fatalf("Authenticate() API token error = %v", err)
+	}
+
+	revoked, err := svc.RevokeToken(ctx, "u_other", record.ID)
+	if err != nil || revoked {
+		t.Fatalf("RevokeToken() of another account = %v, %v, want false", revoked, err)
+	}
+	if revoked, _ := svc.RevokeToken(ctx, user.ID, record.ID); !revoked {
+		t.Fatal("RevokeToken() = false, want true")
+	}
+	if _, err := svc.Authenticate(ctx, apiToken); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Authenticate() revoked token error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthService_ClaimClientOnlyOnce(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestAuthService()
+
+	if err := svc.ClaimClient(ctx, "u_alice", "client-123"); err != nil {
+		t.Fatalf("ClaimClient() error = %v", err)
+	}
+	if err := svc.ClaimClient(ctx, "u_bob", "client-123"); !errors.Is(err, ErrClientClaimed) {
+		t.Fatalf("second ClaimClient() error = %v, want ErrClientClaimed", err)
+	}
+	if err := svc.ClaimClient(ctx, "u_bob", "u_alice"); err == nil {
+		t.Fatal("ClaimClient() of an account ID should fail")
+	}
+
+	claimed, err := svc.IsClientClaimed(ctx, "client-123")
+	if err != nil || !claimed {
+		t.Fatalf("IsClientClaimed() = %v, %v, want true", claimed, err)
+	}
+}
+
+func TestAuthService_AdminRoles(t *testing.T) {
+	ctx := context.Background()
+	users := &stubUserRepository{}
+	svc := NewAuthService(users, &stubAuthTokenRepository{}, &stubClientClaimRepository{}, time.Hour, []string{"root", "ops"})
+
+	// Existing accounts are promoted on startup
+	if err := users.Create(ctx, &entity.User{ID: "u_ops", Username: "ops", Role: entity.RoleUser}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := svc.BootstrapAdmins(ctx); err != nil {
+		t.Fatalf("BootstrapAdmins() error = %v", err)
+	}
+	if ops, _ := users.FindByUsername(ctx, "ops"); !ops.IsAdmin() {
+		t.Fatal("configured admin was not promoted")
+	}
+
+	// Later sign-ups get the role directly
+	root, _, err := svc.SignUp(ctx, "root", "correct-horse")
+	if err != nil {
+		t.Fatalf("SignUp() error = %v", err)
+	}
+	if !root.IsAdmin() {
+		t.Fatal("configured admin signed up without admin role")
+	}
+	carol, _, err := svc.SignUp(ctx, "carol", "correct-horse")
+	if err != nil {
+		t.Fatalf("SignUp() error = %v", err)
+	}
+	if carol.Role != entity.RoleUser {
+		t.Fatalf("Role = %q, want %q", carol.Role, entity.RoleUser)
+	}
+
+	if err := svc.SetRole(ctx, "carol", entity.RoleAdmin); err != nil {
+		t.Fatalf("SetRole() error = %v", err)
+	}
+	if !carol.IsAdmin() {
+		t.Fatal("SetRole() did not grant admin role")
+	}
+	if err := svc.SetRole(ctx, "nobody", entity.RoleAdmin); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("SetRole() unknown user error = %v, want ErrUserNotFound", err)
+	}
+	if err := svc.SetRole(ctx, "carol", "superuser"); err == nil {
+		t.Fatal("SetRole() with unknown role should fail")
+	}
+}
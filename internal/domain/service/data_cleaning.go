@@ -6,9 +6,10 @@ import (
 	"time"
 
 	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
 	"kbfood/internal/domain/repository"
-
-	"github.com/rs/zerolog/log"
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/metrics"
 )
 
 // truncateToDay truncates a time to the start of the day (midnight)
@@ -22,6 +23,10 @@ type DataCleaningService struct {
 	masterRepo     repository.MasterProductRepository
 	candidateRepo  repository.CandidateRepository
 	trendRepo      repository.TrendRepository
+	voteRepo       repository.TitleVoteRepository
+	shopRepo       repository.MasterShopRepository
+	savedSearches  *SavedSearchService
+	events         *event.Bus
 	priceValidator *PriceValidator
 	titleCleaner   *TitleCleaner
 }
@@ -31,11 +36,19 @@ func NewDataCleaningService(
 	masterRepo repository.MasterProductRepository,
 	candidateRepo repository.CandidateRepository,
 	trendRepo repository.TrendRepository,
+	voteRepo repository.TitleVoteRepository,
+	shopRepo repository.MasterShopRepository,
+	savedSearches *SavedSearchService,
+	events *event.Bus,
 ) *DataCleaningService {
 	return &DataCleaningService{
 		masterRepo:     masterRepo,
 		candidateRepo:  candidateRepo,
 		trendRepo:      trendRepo,
+		voteRepo:       voteRepo,
+		shopRepo:       shopRepo,
+		savedSearches:  savedSearches,
+		events:         events,
 		priceValidator: NewPriceValidator(),
 		titleCleaner:   NewTitleCleaner(),
 	}
@@ -48,8 +61,49 @@ func (s *DataCleaningService) ProcessIncomingItem(
 	item *entity.DTInputDTO,
 	region string,
 ) (*entity.PlatformProductDTO, error) {
+	promoted, changed, err := s.processItem(ctx, item, region)
+	if changed != nil {
+		s.evaluateSavedSearches(ctx, changed)
+	}
+	return promoted, err
+}
+
+// ProcessIncomingItems processes a batch of incoming items in their own regions.
+// The saved searches are evaluated once for the batch, against the masters whose
+// price or status changed. The returned DTOs and errors are indexed like items.
+func (s *DataCleaningService) ProcessIncomingItems(
+	ctx context.Context,
+	items []*entity.DTInputDTO,
+) ([]*entity.PlatformProductDTO, []error) {
+	promoted := make([]*entity.PlatformProductDTO, len(items))
+	errs := make([]error, len(items))
+
+	var changed []*entity.MasterProduct
+	for i, item := range items {
+		var master *entity.MasterProduct
+		region := ""
+		if item != nil {
+			region = item.Region
+		}
+		promoted[i], master, errs[i] = s.processItem(ctx, item, region)
+		if master != nil {
+			changed = append(changed, master)
+		}
+	}
+	s.evaluateSavedSearches(ctx, changed...)
+
+	return promoted, errs
+}
+
+// processItem matches an item to a master product or adds it to the candidate
+// pool. It also returns the matched master if its price or status changed.
+func (s *DataCleaningService) processItem(
+	ctx context.Context,
+	item *entity.DTInputDTO,
+	region string,
+) (*entity.PlatformProductDTO, *entity.MasterProduct, error) {
 	if item == nil {
-		return nil, fmt.Errorf("item cannot be nil")
+		return nil, nil, fmt.Errorf("item cannot be nil")
 	}
 
 	rawTitle := item.Title
@@ -58,12 +112,13 @@ func (s *DataCleaningService) ProcessIncomingItem(
 	// Try to match with existing master products
 	masters, err := s.masterRepo.FindByRegion(ctx, region)
 	if err != nil {
-		return nil, fmt.Errorf("find masters: %w", err)
+		return nil, nil, fmt.Errorf("find masters: %w", err)
 	}
 
 	// Strategy A: High confidence title match
 	for _, master := range masters {
 		if s.titleCleaner.IsHighSimilarity(rawTitle, master.StandardTitle) {
+			s.recordShop(ctx, master.ID, item.ShopName)
 			return s.handleMasterMatch(ctx, master, item.Price, item.Status)
 		}
 	}
@@ -72,25 +127,28 @@ func (s *DataCleaningService) ProcessIncomingItem(
 	for _, master := range masters {
 		if s.titleCleaner.IsMidSimilarity(rawTitle, master.StandardTitle) &&
 			s.titleCleaner.IsPriceMatch(item.Price, master.Price) {
+			s.recordShop(ctx, master.ID, item.ShopName)
 			return s.handleMasterMatch(ctx, master, item.Price, item.Status)
 		}
 	}
 
 	// No match - add to candidate pool
 	if err := s.handleCandidateLogic(ctx, region, rawTitle, cleanKey, item); err != nil {
-		return nil, fmt.Errorf("handle candidate: %w", err)
+		return nil, nil, fmt.Errorf("handle candidate: %w", err)
 	}
 
-	return nil, nil
+	return nil, nil, nil
 }
 
-// handleMasterMatch handles when an item matches a master product
+// handleMasterMatch handles when an item matches a master product. The master
+// is returned as changed only if its price or status changed, since saved
+// searches cannot start matching an unchanged master.
 func (s *DataCleaningService) handleMasterMatch(
 	ctx context.Context,
 	master *entity.MasterProduct,
 	price float64,
 	status int,
-) (*entity.PlatformProductDTO, error) {
+) (*entity.PlatformProductDTO, *entity.MasterProduct, error) {
 	// Validate price update using Dutch auction model
 	finalPrice, err := s.priceValidator.ValidateUpdate(
 		master.Price,
@@ -99,16 +157,24 @@ func (s *DataCleaningService) handleMasterMatch(
 	)
 	if err != nil {
 		// Price anomaly detected - block update
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// Update master
+	oldPrice, oldStatus := master.Price, master.Status
 	master.Price = finalPrice
 	master.Status = status
 	master.IncrementTrustScore()
 
 	if err := s.masterRepo.Update(ctx, master); err != nil {
-		return nil, fmt.Errorf("update master: %w", err)
+		return nil, nil, fmt.Errorf("update master: %w", err)
+	}
+
+	s.publishChanges(ctx, master, oldPrice, oldStatus)
+
+	var changed *entity.MasterProduct
+	if master.Price != oldPrice || master.Status != oldStatus {
+		changed = master
 	}
 
 	// Return DTO
@@ -122,7 +188,7 @@ func (s *DataCleaningService) handleMasterMatch(
 		CurrentPrice:       finalPrice,
 		SalesStatus:        status,
 		ActivityCreateTime: master.UpdateTime,
-	}, nil
+	}, changed, nil
 }
 
 // handleCandidateLogic handles the candidate pool logic
@@ -179,6 +245,8 @@ func (s *DataCleaningService) handleCandidateLogic(
 func (s *DataCleaningService) PromoteCandidates(
 	ctx context.Context,
 ) (map[string][]*entity.PlatformProductDTO, error) {
+	logger := applog.LoggerFromContext(ctx)
+
 	promotedData := make(map[string][]*entity.PlatformProductDTO)
 
 	candidates, err := s.candidateRepo.ListAll(ctx)
@@ -187,6 +255,7 @@ func (s *DataCleaningService) PromoteCandidates(
 	}
 
 	var toDeleteIDs []int64
+	var promotedMasters []*entity.MasterProduct
 
 	for _, candidate := range candidates {
 		// Check for nil candidate
@@ -201,7 +270,7 @@ func (s *DataCleaningService) PromoteCandidates(
 		// Elect standard title
 		winnerTitle := s.titleCleaner.ElectStandardTitle(candidate.TitleVotes)
 		if winnerTitle == "" {
-			log.Warn().
+			logger.Warn().
 				Int64("id", candidate.ID).
 				Msg("Skipping candidate with empty title")
 			continue
@@ -233,16 +302,19 @@ func (s *DataCleaningService) PromoteCandidates(
 
 			// Record initial price trend
 			s.recordPriceTrend(ctx, master.ID, master.Price)
+
+			s.publishCreated(ctx, master)
+			metrics.Promotions.With(metrics.PromotionCreated).Inc()
 		} else {
 			// Update existing master
-			oldPrice := master.Price
+			oldPrice, oldStatus := master.Price, master.Status
 			finalPrice, err := s.priceValidator.ValidateUpdate(
 				master.Price,
 				candidate.LastPrice,
 				master.UpdateTime,
 			)
 			if err != nil {
-				log.Error().Err(err).
+				logger.Error().Err(err).
 					Str("masterId", master.ID).
 					Msg("Failed to validate price during promotion")
 				finalPrice = master.Price // Keep existing price on validation failure
@@ -258,8 +330,14 @@ func (s *DataCleaningService) PromoteCandidates(
 			if finalPrice != oldPrice {
 				s.recordPriceTrend(ctx, master.ID, finalPrice)
 			}
+
+			s.publishChanges(ctx, master, oldPrice, oldStatus)
+			metrics.Promotions.With(metrics.PromotionMerged).Inc()
 		}
 
+		// Keep the title votes, the candidate is deleted after promotion
+		s.recordTitleVotes(ctx, master.ID, candidate.TitleVotes)
+
 		// Add to promoted data
 		dto := &entity.PlatformProductDTO{
 			ActivityID:         master.ID,
@@ -275,6 +353,7 @@ func (s *DataCleaningService) PromoteCandidates(
 
 		promotedData[candidate.Region] = append(promotedData[candidate.Region], dto)
 		toDeleteIDs = append(toDeleteIDs, candidate.ID)
+		promotedMasters = append(promotedMasters, master)
 	}
 
 	// Delete promoted candidates
@@ -283,12 +362,85 @@ func (s *DataCleaningService) PromoteCandidates(
 			return nil, fmt.Errorf("delete candidates: %w", err)
 		}
 	}
+	metrics.CandidatePoolSize.Set(float64(len(candidates) - len(toDeleteIDs)))
+
+	s.evaluateSavedSearches(ctx, promotedMasters...)
 
 	return promotedData, nil
 }
 
+// publishChanges publishes price and status change events for an updated master
+func (s *DataCleaningService) publishChanges(
+	ctx context.Context,
+	master *entity.MasterProduct,
+	oldPrice float64,
+	oldStatus int,
+) {
+	if s.events == nil {
+		return
+	}
+
+	now := time.Now()
+	if master.Price != oldPrice {
+		s.events.Publish(ctx, event.PriceChanged{
+			ActivityID: master.ID,
+			Region:     master.Region,
+			Platform:   master.Platform,
+			Title:      master.StandardTitle,
+			OldPrice:   oldPrice,
+			NewPrice:   master.Price,
+			OccurredAt: now,
+		})
+	}
+	if master.Status != oldStatus {
+		s.events.Publish(ctx, event.StatusChanged{
+			ActivityID: master.ID,
+			Region:     master.Region,
+			Platform:   master.Platform,
+			Title:      master.StandardTitle,
+			OldStatus:  oldStatus,
+			NewStatus:  master.Status,
+			OccurredAt: now,
+		})
+	}
+}
+
+// publishCreated publishes the creation of a promoted master
+func (s *DataCleaningService) publishCreated(ctx context.Context, master *entity.MasterProduct) {
+	if s.events == nil {
+		return
+	}
+
+	s.events.Publish(ctx, event.ProductCreated{
+		ActivityID: master.ID,
+		Region:     master.Region,
+		Platform:   master.Platform,
+		Title:      master.StandardTitle,
+		Price:      master.Price,
+		Status:     master.Status,
+		OccurredAt: time.Now(),
+	})
+}
+
+// evaluateSavedSearches alerts saved search subscribers about matching masters
+func (s *DataCleaningService) evaluateSavedSearches(ctx context.Context, masters ...*entity.MasterProduct) {
+	logger := applog.LoggerFromContext(ctx)
+
+	if s.savedSearches == nil || len(masters) == 0 {
+		return
+	}
+
+	if sent := s.savedSearches.Evaluate(ctx, masters); sent > 0 {
+		logger.Info().
+			Int("sent", sent).
+			Msg("Sent saved search notifications")
+	}
+}
+
 // recordPriceTrend records a price trend for a master product
 func (s *DataCleaningService) recordPriceTrend(ctx context.Context, activityID string, price float64) {
+	logger := applog.LoggerFromContext(ctx)
+
 	if s.trendRepo == nil {
 		return
 	}
@@ -296,7 +448,7 @@ func (s *DataCleaningService) recordPriceTrend(ctx context.Context, activityID s
 	// Truncate to day to ensure consistent date for ON CONFLICT clause
 	trend, err := entity.NewPriceTrend(activityID, price, truncateToDay(time.Now()))
 	if err != nil {
-		log.Error().Err(err).
+		logger.Error().Err(err).
 			Str("activityId", activityID).
 			Float64("price", price).
 			Msg("Failed to create price trend")
@@ -304,16 +456,50 @@ func (s *DataCleaningService) recordPriceTrend(ctx context.Context, activityID s
 	}
 
 	if err := s.trendRepo.Upsert(ctx, trend); err != nil {
-		log.Error().Err(err).
+		logger.Error().Err(err).
 			Str("activityId", activityID).
 			Float64("price", price).
 			Msg("Failed to record price trend")
 	}
 }
 
+// recordTitleVotes adds the title votes of a promoted candidate to its master product
+func (s *DataCleaningService) recordTitleVotes(ctx context.Context, masterID string, votes map[string]int) {
+	logger := applog.LoggerFromContext(ctx)
+
+	if s.voteRepo == nil || len(votes) == 0 {
+		return
+	}
+
+	if err := s.voteRepo.AddVotes(ctx, masterID, votes); err != nil {
+		logger.Error().Err(err).
+			Str("masterId", masterID).
+			Msg("Failed to record title votes")
+	}
+}
+
+// recordShop records the shop of an item matched to a master product. Promoted
+// candidates get their shops as their items are matched by later syncs.
+func (s *DataCleaningService) recordShop(ctx context.Context, masterID, shopName string) {
+	logger := applog.LoggerFromContext(ctx)
+
+	if s.shopRepo == nil || shopName == "" {
+		return
+	}
+
+	if err := s.shopRepo.AddShop(ctx, masterID, shopName); err != nil {
+		logger.Error().Err(err).
+			Str("masterId", masterID).
+			Str("shopName", shopName).
+			Msg("Failed to record master shop")
+	}
+}
+
 // RecordDailyTrends records price trends for all master products
 // This should be called daily to track price history
 func (s *DataCleaningService) RecordDailyTrends(ctx context.Context) (int, error) {
+	logger := applog.LoggerFromContext(ctx)
+
 	if s.trendRepo == nil {
 		return 0, nil
 	}
@@ -335,7 +521,7 @@ func (s *DataCleaningService) RecordDailyTrends(ctx context.Context) (int, error
 
 		trend, err := entity.NewPriceTrend(master.ID, master.Price, now)
 		if err != nil {
-			log.Error().Err(err).
+			logger.Error().Err(err).
 				Str("masterId", master.ID).
 				Float64("price", master.Price).
 				Msg("Failed to create price trend entity")
@@ -343,7 +529,7 @@ func (s *DataCleaningService) RecordDailyTrends(ctx context.Context) (int, error
 		}
 
 		if err := s.trendRepo.Upsert(ctx, trend); err != nil {
-			log.Error().Err(err).
+			logger.Error().Err(err).
 				Str("masterId", master.ID).
 				Float64("price", master.Price).
 				Msg("Failed to record price trend")
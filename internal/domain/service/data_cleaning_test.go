@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"kbfood/internal/domain/entity"
 )
 
 func TestTruncateToDay(t *testing.T) {
@@ -80,3 +83,34 @@ func TestTruncateToDay_Consistency(t *testing.T) {
 		}
 	}
 }
+
+func TestDataCleaningService_ProcessIncomingItemRecordsShop(t *testing.T) {
+	ctx := context.Background()
+	masterRepo := &regionMasterProductRepository{
+		stubMasterProductRepository{
+			product: &entity.MasterProduct{ID: "DT_1", Region: "上海", StandardTitle: "拿铁套餐", Price: 9.9, Status: entity.SalesStatusOnSale},
+		},
+	}
+	shopRepo := &stubMasterShopRepository{}
+	cleaning := NewDataCleaningService(masterRepo, nil, nil, nil, shopRepo, nil, nil)
+
+	for _, shopName := range []string{"瑞幸咖啡", ""} {
+		item := &entity.DTInputDTO{Title: "拿铁套餐", ShopName: shopName, Price: 9.9, Status: entity.SalesStatusOnSale}
+		if _, err := cleaning.ProcessIncomingItem(ctx, item, "上海"); err != nil {
+			t.Fatalf("ProcessIncomingItem() error = %v", err)
+		}
+	}
+
+	if len(shopRepo.shops) != 1 || shopRepo.shops[0] != "DT_1/瑞幸咖啡" {
+		t.Fatalf("recorded shops = %v, want only the named shop of DT_1", shopRepo.shops)
+	}
+}
+
+type stubMasterShopRepository struct {
+	shops []string
+}
+
+func (r *stubMasterShopRepository) AddShop(_ context.Context, masterID, shopName string) error {
+	r.shops = append(r.shops, masterID+"/"+shopName)
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Pairing errors
+var (
+	ErrInvalidPairingCode = apperrors.New(apperrors.InvalidInput, "配对码无效或已过期")
+	ErrPairWithSelf       = apperrors.New(apperrors.InvalidInput, "不能与本设备配对")
+)
+
+// pairingCodeAttempts limits how often a colliding pairing code is regenerated
+const pairingCodeAttempts = 5
+
+// deviceTouchInterval limits how often the last seen time of a device is written
+const deviceTouchInterval = time.Minute
+
+// maxDeviceNameLength is the maximum length of a device name in runes
+const maxDeviceNameLength = 64
+
+// DeviceService pairs anonymous client IDs with a shared user ID so that several
+// devices see the same notifications and settings
+type DeviceService struct {
+	devices repository.DeviceRepository
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(devices repository.DeviceRepository) *DeviceService {
+	return &DeviceService{devices: devices}
+}
+
+// CreatePairingCode creates a six-digit code another device can redeem within
+// entity.PairingCodeTTL to act as userID. Earlier codes of the user stop working.
+func (s *DeviceService) CreatePairingCode(ctx context.Context, userID string) (*entity.PairingCode, error) {
+	now := time.Now()
+	if err := s.devices.DeleteExpiredPairingCodes(ctx, now); err != nil {
+		log.Error().Err(err).Msg("failed to prune expired pairing codes")
+	}
+
+	for i := 0; i < pairingCodeAttempts; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+		if err != nil {
+			return nil, fmt.Errorf("generate pairing code: %w", err)
+		}
+
+		code := &entity.PairingCode{
+			Code:       fmt.Sprintf("%06d", n.Int64()),
+			UserID:     userID,
+			ExpireTime: now.Add(entity.PairingCodeTTL),
+		}
+		created, err := s.devices.CreatePairingCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			return code, nil
+		}
+	}
+	return nil, errors.New("generate pairing code: too many collisions")
+}
+
+// Pair redeems a pairing code for an anonymous client ID. The client's existing
+// data is merged into the data of the user who created the code, and requests
+// with the client ID act as that user until the device is revoked.
+func (s *DeviceService) Pair(ctx context.Context, clientID, code, name string) (*entity.Device, error) {
+	code = strings.TrimSpace(code)
+	if !entity.IsValidPairingCode(code) {
+		return nil, ErrInvalidPairingCode
+	}
+
+	pairing, err := s.devices.TakePairingCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	// Codes of accounts issued before they were refused cannot be redeemed either
+	if pairing == nil || pairing.IsExpired(time.Now()) || strings.HasPrefix(pairing.UserID, entity.UserIDPrefix) {
+		return nil, ErrInvalidPairingCode
+	}
+	if pairing.UserID == clientID {
+		return nil, ErrPairWithSelf
+	}
+
+	device := &entity.Device{
+		ClientID: clientID,
+		UserID:   pairing.UserID,
+		Name:     truncateRunes(strings.TrimSpace(name), maxDeviceNameLength),
+	}
+	if err := s.devices.Pair(ctx, device); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("userId", device.UserID).
+		Int64("deviceId", device.ID).
+		Msg("Device paired")
+	return device, nil
+}
+
+// ResolveDevice returns the user ID an anonymous client ID is paired to, or an
+// empty string if the client ID is not paired
+func (s *DeviceService) ResolveDevice(ctx context.Context, clientID string) (string, error) {
+	device, err := s.devices.FindByClientID(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+	if device == nil {
+		return "", nil
+	}
+
+	now := time.Now()
+	if device.LastSeenTime == nil || now.Sub(*device.LastSeenTime) >= deviceTouchInterval {
+		if err := s.devices.Touch(ctx, device.ID, now); err != nil {
+			log.Error().Err(err).
+				Int64("deviceId", device.ID).
+				Msg("failed to record device use")
+		}
+	}
+	return device.UserID, nil
+}
+
+// ListDevices lists the devices paired to a user and marks the one using clientID
+func (s *DeviceService) ListDevices(ctx context.Context, userID, clientID string) ([]*entity.Device, error) {
+	devices, err := s.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		device.Current = clientID != "" && device.ClientID == clientID
+	}
+	return devices, nil
+}
+
+// RevokeDevice unpairs a device of the user, returning false if it did not exist.
+// The device falls back to its own, empty client ID.
+func (s *DeviceService) RevokeDevice(ctx context.Context, userID string, id int64) (bool, error) {
+	revoked, err := s.devices.Delete(ctx, id, userID)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		log.Info().
+			Str("userId", userID).
+			Int64("deviceId", id).
+			Msg("Device revoked")
+	}
+	return revoked, nil
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
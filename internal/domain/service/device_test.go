@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+type stubDeviceRepository struct {
+	codes   map[string]*entity.PairingCode
+	devices []*entity.Device
+	touched int
+}
+
+func (s *stubDeviceRepository) CreatePairingCode(ctx context.Context, code *entity.PairingCode) (bool, error) {
+	if s.codes == nil {
+		s.codes = make(map[string]*entity.PairingCode)
+	}
+	if _, ok := s.codes[code.Code]; ok {
+		return false, nil
+	}
+	for key, existing := range s.codes {
+		if existing.UserID == code.UserID {
+			delete(s.codes, key)
+		}
+	}
+	s.codes[code.Code] = code
+	return true, nil
+}
+
+func (s *stubDeviceRepository) TakePairingCode(ctx context.Context, code string) (*entity.PairingCode, error) {
+	pairing := s.codes[code]
+	delete(s.codes, code)
+	return pairing, nil
+}
+
+func (s *stubDeviceRepository) DeleteExpiredPairingCodes(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func (s *stubDeviceRepository) Pair(ctx context.Context, device *entity.Device) error {
+	device.ID = int64(len(s.devices) + 1)
+	s.devices = append(s.devices, device)
+	return nil
+}
+
+func (s *stubDeviceRepository) FindByClientID(ctx context.Context, clientID string) (*entity.Device, error) {
+	for _, device := range s.devices {
+		if device.ClientID == clientID {
+			return device, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubDeviceRepository) ListByUser(ctx context.Context, userID string) ([]*entity.Device, error) {
+	var result []*entity.Device
+	for _, device := range s.devices {
+		if device.UserID == userID {
+			result = append(result, device)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubDeviceRepository) Touch(ctx context.Context, id int64, seenAt time.Time) error {
+	s.touched++
+	for _, device := range s.devices {
+		if device.ID == id {
+			device.LastSeenTime = &seenAt
+		}
+	}
+	return nil
+}
+
+func (s *stubDeviceRepository) Delete(ctx context.Context, id int64, userID string) (bool, error) {
+	for i, device := range s.devices {
+		if device.ID == id && device.UserID == userID {
+			s.devices = append(s.devices[:i], s.devices[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestDeviceService_PairResolveAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubDeviceRepository{}
+	svc := NewDeviceService(repo)
+
+	code, err := svc.CreatePairingCode(ctx, "client-laptop")
+	if err != nil {
+		t.Fatalf("CreatePairingCode() error = %v", err)
+	}
+	if !entity.IsValidPairingCode(code.Code) {
+		t.Fatalf("code = %q, want six digits", code.Code)
+	}
+
+	device, err := svc.Pair(ctx, "client-phone", code.Code, "iPhone")
+	if err != nil {
+		t.Fatalf("Pair() error = %v", err)
+	}
+	if device.UserID != "client-laptop" || device.Name != "iPhone" {
+		t.Fatalf("unexpected device: %+v", device)
+	}
+
+	if _, err := svc.Pair(ctx, "client-tablet", code.Code, ""); !errors.Is(err, ErrInvalidPairingCode) {
+		t.Fatalf("reusing a code: error = %v, want ErrInvalidPairingCode", err)
+	}
+
+	userID, err := svc.ResolveDevice(ctx, "client-phone")
+	if err != nil || userID != "client-laptop" {
+		t.Fatalf("ResolveDevice() = %q, %v, want client-laptop", userID, err)
+	}
+	if _, err := svc.ResolveDevice(ctx, "client-phone"); err != nil {
+		t.Fatalf("ResolveDevice() error = %v", err)
+	}
+	if repo.touched != 1 {
+		t.Fatalf("touched = %d, want 1 within the touch interval", repo.touched)
+	}
+
+	devices, err := svc.ListDevices(ctx, "client-laptop", "client-phone")
+	if err != nil || len(devices) != 1 || !devices[0].Current {
+		t.Fatalf("ListDevices() = %+v, %v", devices, err)
+	}
+
+	if revoked, err := svc.RevokeDevice(ctx, "someone-else", device.ID); err != nil || revoked {
+		t.Fatalf("revoking another user's device: revoked = %v, err = %v", revoked, err)
+	}
+	if revoked, err := svc.RevokeDevice(ctx, "client-laptop", device.ID); err != nil || !revoked {
+		t.Fatalf("RevokeDevice() = %v, %v", revoked, err)
+	}
+	if userID, _ := svc.ResolveDevice(ctx, "client-phone"); userID != "" {
+		t.Fatalf("revoked device still resolves to %q", userID)
+	}
+}
+
+func TestDeviceService_PairRejectsBadCodes(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubDeviceRepository{codes: map[string]*entity.PairingCode{
+		"111111": {Code: "111111", UserID: "client-laptop", ExpireTime: time.Now().Add(-time.Second)},
+		"222222": {Code: "222222", UserID: "client-laptop", ExpireTime: time.Now().Add(time.Minute)},
+		"333333": {Code: "333333", UserID: entity.UserIDPrefix + "alice", ExpireTime: time.Now().Add(time.Minute)},
+	}}
+	svc := NewDeviceService(repo)
+
+	tests := []struct {
+		name     string
+		clientID string
+		code     string
+		wantErr  error
+	}{
+		{name: "malformed", clientID: "client-phone", code: "12ab", wantErr: ErrInvalidPairingCode},
+		{name: "unknown", clientID: "client-phone", code: "999999", wantErr: ErrInvalidPairingCode},
+		{name: "expired", clientID: "client-phone", code: "111111", wantErr: ErrInvalidPairingCode},
+		{name: "own code", clientID: "client-laptop", code: "222222", wantErr: ErrPairWithSelf},
+		{name: "account code", clientID: "client-phone", code: "333333", wantErr: ErrInvalidPairingCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Pair(ctx, tt.clientID, tt.code, ""); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Pair() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(repo.devices) != 0 {
+		t.Fatalf("devices = %d, want none", len(repo.devices))
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	applog "kbfood/internal/pkg/logger"
+)
+
+// DigestService compiles and sends daily digests of users' watched items
+type DigestService struct {
+	notiRepo         repository.NotificationRepository
+	masterRepo       repository.MasterProductRepository
+	trendRepo        repository.TrendRepository
+	userSettingsRepo repository.UserSettingsRepository
+	notifications    *NotificationService
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(
+	notiRepo repository.NotificationRepository,
+	masterRepo repository.MasterProductRepository,
+	trendRepo repository.TrendRepository,
+	userSettingsRepo repository.UserSettingsRepository,
+	notifications *NotificationService,
+) *DigestService {
+	return &DigestService{
+		notiRepo:         notiRepo,
+		masterRepo:       masterRepo,
+		trendRepo:        trendRepo,
+		userSettingsRepo: userSettingsRepo,
+		notifications:    notifications,
+	}
+}
+
+// Build compiles the digest of a user for the day containing now
+func (s *DigestService) Build(ctx context.Context, userID string, now time.Time) (*entity.Digest, error) {
+	prefs := entity.NotificationPreferences{}
+	settings, err := s.userSettingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user settings: %w", err)
+	}
+	if settings != nil {
+		prefs = settings.Preferences
+	}
+	return s.build(ctx, userID, prefs, now)
+}
+
+// SendDue sends the digest to every digest subscriber whose delivery time has
+// passed today and who has not received today's digest yet.
+// Returns the number of digests sent.
+func (s *DigestService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	logger := applog.LoggerFromContext(ctx)
+
+	subscribers, err := s.userSettingsRepo.ListDigestSubscribers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, settings := range subscribers {
+		dueAt := settings.Preferences.DigestDueTime(now)
+		if now.Before(dueAt) {
+			continue
+		}
+		if settings.LastDigest != nil && !settings.LastDigest.Before(dueAt) {
+			continue
+		}
+
+		digest, err := s.build(ctx, settings.UserID, settings.Preferences, now)
+		if err != nil {
+			logger.Error().Err(err).
+				Str("userId", settings.UserID).
+				Msg("failed to build digest")
+			continue
+		}
+
+		if !digest.IsEmpty() {
+			logger.Info().
+				Str("userId", settings.UserID).
+				Int("items", len(digest.Items)).
+				Int("newProducts", len(digest.NewProducts)).
+				Msg("Sending daily digest")
+
+			if !s.notifications.sendBark(ctx, settings, "", digest.Text()) {
+				continue
+			}
+			sent++
+		}
+
+		if err := s.userSettingsRepo.MarkDigestSent(ctx, settings.UserID, now); err != nil {
+			logger.Error().Err(err).
+				Str("userId", settings.UserID).
+				Msg("failed to mark digest sent")
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *DigestService) build(
+	ctx context.Context,
+	userID string,
+	prefs entity.NotificationPreferences,
+	now time.Time,
+) (*entity.Digest, error) {
+	startOfDay := prefs.StartOfDay(now)
+	digest := &entity.Digest{
+		UserID:      userID,
+		Date:        startOfDay.Format("2006-01-02"),
+		GeneratedAt: now,
+		Items:       []entity.DigestItem{},
+		NewProducts: []entity.DigestProduct{},
+	}
+
+	configs, err := s.notiRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+
+	items, watchlists, err := s.watchlistItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := make(map[string]bool)
+	add := func(config *entity.NotificationConfig, watchlist string) error {
+		product, err := s.notifications.findNotificationProduct(ctx, config.ActivityID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return nil
+		}
+		regions[product.Region] = true
+
+		item := entity.DigestItem{
+			ActivityID:   product.ActivityID,
+			Platform:     product.Platform,
+			Region:       product.Region,
+			Title:        product.Title,
+			CurrentPrice: product.CurrentPrice,
+			TodayLow:     s.todayLow(ctx, product, now),
+			RuleType:     config.EffectiveRuleType(),
+			SalesStatus:  product.SalesStatus,
+			Triggered:    config.Matches(s.notifications.buildSnapshot(ctx, config, product)),
+			Watchlist:    watchlist,
+		}
+		if item.RuleType == entity.RuleTypeTargetPrice {
+			targetPrice := config.TargetPrice
+			item.TargetPrice = &targetPrice
+		}
+		digest.Items = append(digest.Items, item)
+		return nil
+	}
+
+	for _, config := range configs {
+		if err := add(config, ""); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range items {
+		if err := add(&item.NotificationConfig, watchlists[item.WatchlistID]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(regions) == 0 || s.masterRepo == nil {
+		return digest, nil
+	}
+
+	masters, err := s.masterRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list masters: %w", err)
+	}
+	for _, master := range masters {
+		if !regions[master.Region] || master.CreateTime.Before(startOfDay) {
+			continue
+		}
+		platform := master.Platform
+		if platform == "" {
+			platform = entity.DefaultPlatform
+		}
+		digest.NewProducts = append(digest.NewProducts, entity.DigestProduct{
+			ActivityID: master.ID,
+			Platform:   platform,
+			Region:     master.Region,
+			Title:      master.StandardTitle,
+			Price:      master.Price,
+		})
+	}
+
+	return digest, nil
+}
+
+// watchlistItems returns the items of the shared watchlists the user is a
+// member of, with the list names by ID
+func (s *DigestService) watchlistItems(ctx context.Context, userID string) ([]*entity.WatchlistItem, map[int64]string, error) {
+	watchlistRepo := s.notifications.watchlistRepo
+	if watchlistRepo == nil {
+		return nil, nil, nil
+	}
+
+	watchlists, err := watchlistRepo.ListByMember(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list watchlists: %w", err)
+	}
+	items, err := watchlistRepo.ListItemsByMember(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list watchlist items: %w", err)
+	}
+
+	names := make(map[int64]string, len(watchlists))
+	for _, watchlist := range watchlists {
+		names[watchlist.ID] = watchlist.Name
+	}
+	return items, names, nil
+}
+
+// todayLow returns the lowest price recorded today, including the current price
+func (s *DigestService) todayLow(ctx context.Context, product *notificationProduct, now time.Time) float64 {
+	logger := applog.LoggerFromContext(ctx)
+
+	low := product.CurrentPrice
+	if s.trendRepo == nil {
+		return low
+	}
+
+	// Trends are recorded per server-local day, see DataCleaningService.recordPriceTrend
+	trend, err := s.trendRepo.FindByActivityIDAndDate(ctx, product.ActivityID, truncateToDay(now))
+	if err != nil {
+		logger.Error().Err(err).
+			Str("activityId", product.ActivityID).
+			Msg("failed to load today's price trend")
+		return low
+	}
+	if trend != nil && trend.Price > 0 && trend.Price < low {
+		low = trend.Price
+	}
+	return low
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/infra/external"
+)
+
+func TestDigestService_SendDueSendsOncePerDay(t *testing.T) {
+	ctx := context.Background()
+
+	notiRepo := &stubNotificationRepository{
+		configs: []*entity.NotificationConfig{
+			{ActivityID: "DT_digest", UserID: "client-123", TargetPrice: 60},
+		},
+	}
+	masterRepo := &stubMasterProductRepository{
+		product: &entity.MasterProduct{
+			ID:            "DT_digest",
+			Region:        "广州",
+			Platform:      "DT",
+			StandardTitle: "火锅四人餐",
+			Price:         68.7,
+			Status:        entity.SalesStatusOnSale,
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{
+			UserID:  "client-123",
+			BarkKey: "DEVICE123",
+			Preferences: entity.NotificationPreferences{
+				DigestEnabled: true,
+				DigestTime:    "08:00",
+			},
+		},
+	}
+
+	var messages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		messages = append(messages, decodeBarkMessage(t, r).Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifications := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
+	digests := NewDigestService(notiRepo, masterRepo, nil, userSettingsRepo, notifications)
+
+	today := time.Now()
+	beforeDue := time.Date(today.Year(), today.Month(), today.Day(), 7, 0, 0, 0, time.Local)
+	afterDue := time.Date(today.Year(), today.Month(), today.Day(), 8, 30, 0, 0, time.Local)
+
+	if sent, err := digests.SendDue(ctx, beforeDue); err != nil || sent != 0 {
+		t.Fatalf("SendDue() before delivery time = %d, %v; want 0, nil", sent, err)
+	}
+	if sent, err := digests.SendDue(ctx, afterDue); err != nil || sent != 1 {
+		t.Fatalf("SendDue() = %d, %v; want 1, nil", sent, err)
+	}
+	if sent, err := digests.SendDue(ctx, afterDue.Add(time.Hour)); err != nil || sent != 0 {
+		t.Fatalf("SendDue() again = %d, %v; want 0, nil", sent, err)
+	}
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "火锅四人餐 ¥68.70") || !strings.Contains(messages[0], "目标¥60.00") {
+		t.Fatalf("unexpected digest messages: %q", messages)
+	}
+
+	// Digest mode suppresses instant alerts
+	if err := notifications.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() error = %v", err)
+	}
+	notiRepo.configs[0].TargetPrice = 70
+	if err := notifications.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected no instant alerts in digest mode, got %d messages", len(messages))
+	}
+}
+
+func TestDigestService_BuildIncludesWatchlistItems(t *testing.T) {
+	ctx := context.Background()
+
+	masterRepo := &stubMasterProductRepository{
+		product: &entity.MasterProduct{
+			ID:            "DT_shared",
+			Region:        "广州",
+			Platform:      "DT",
+			StandardTitle: "烤鱼双人餐",
+			Price:         59.9,
+			Status:        entity.SalesStatusOnSale,
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{}
+	watchlistRepo := &stubWatchlistRepository{
+		watchlists: []*entity.Watchlist{{ID: 1, Name: "午饭"}},
+		members:    []*entity.WatchlistMember{{ID: 1, WatchlistID: 1, UserID: "client-123", Role: entity.WatchlistRoleMember}},
+		items: []*entity.WatchlistItem{{
+			WatchlistID:        1,
+			NotificationConfig: entity.NotificationConfig{ActivityID: "DT_shared", TargetPrice: 60},
+		}},
+	}
+
+	notifications := NewNotificationService(&stubNotificationRepository{}, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, watchlistRepo, nil, "")
+	digests := NewDigestService(&stubNotificationRepository{}, masterRepo, nil, userSettingsRepo, notifications)
+
+	digest, err := digests.Build(ctx, "client-123", time.Now())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(digest.Items) != 1 {
+		t.Fatalf("digest items = %+v, want the watchlist item", digest.Items)
+	}
+	item := digest.Items[0]
+	if item.ActivityID != "DT_shared" || item.Watchlist != "午饭" || !item.Triggered || item.TargetPrice == nil || *item.TargetPrice != 60 {
+		t.Errorf("watchlist digest item = %+v", item)
+	}
+	if text := digest.Text(); !strings.Contains(text, "烤鱼双人餐 ¥59.90 目标¥60.00 清单「午饭」") {
+		t.Errorf("digest text = %q", text)
+	}
+}
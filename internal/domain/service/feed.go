@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
+	"kbfood/internal/domain/repository"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrFeedTokenNotFound is returned for private feed URLs with an unknown token
+var ErrFeedTokenNotFound = apperrors.New(apperrors.NotFound, "订阅地址无效或已失效")
+
+// FeedService records price changes and promoted products, and lists them as
+// the entries of the public Atom feeds and of each user's private feed of
+// watched products.
+type FeedService struct {
+	changes    repository.ProductChangeRepository
+	tokens     repository.FeedTokenRepository
+	notiRepo   repository.NotificationRepository
+	watchlists repository.WatchlistRepository
+	publicURL  string
+}
+
+// NewFeedService creates a new feed service
+func NewFeedService(
+	changes repository.ProductChangeRepository,
+	tokens repository.FeedTokenRepository,
+	notiRepo repository.NotificationRepository,
+	watchlists repository.WatchlistRepository,
+	publicURL string,
+) *FeedService {
+	return &FeedService{
+		changes:    changes,
+		tokens:     tokens,
+		notiRepo:   notiRepo,
+		watchlists: watchlists,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Subscribe registers the service for price change and new product events on the bus
+func (s *FeedService) Subscribe(bus *event.Bus) {
+	bus.Subscribe(event.NamePriceChanged, s.HandleProductEvent)
+	bus.Subscribe(event.NameProductCreated, s.HandleProductEvent)
+}
+
+// HandleProductEvent records a price change or new product as a feed entry
+func (s *FeedService) HandleProductEvent(ctx context.Context, e event.Event) {
+	var change *entity.ProductChange
+	switch ev := e.(type) {
+	case event.PriceChanged:
+		oldPrice := ev.OldPrice
+		change = &entity.ProductChange{
+			Kind:       entity.ProductChangePrice,
+			ActivityID: ev.ActivityID,
+			Region:     ev.Region,
+			Platform:   streamPlatform(ev.Platform),
+			Title:      ev.Title,
+			Price:      ev.NewPrice,
+			OldPrice:   &oldPrice,
+		}
+	case event.ProductCreated:
+		change = &entity.ProductChange{
+			Kind:       entity.ProductChangeNew,
+			ActivityID: ev.ActivityID,
+			Region:     ev.Region,
+			Platform:   streamPlatform(ev.Platform),
+			Title:      ev.Title,
+			Price:      ev.Price,
+		}
+	default:
+		return
+	}
+
+	if err := s.changes.Create(ctx, change); err != nil {
+		log.Error().Err(err).
+			Str("activityId", change.ActivityID).
+			Str("kind", change.Kind).
+			Msg("failed to record product change")
+	}
+}
+
+// Drops lists the latest price drops matching the region, platform and keyword of the query
+func (s *FeedService) Drops(ctx context.Context, query repository.ProductChangeQuery) ([]*entity.ProductChange, error) {
+	query.Kind, query.DropsOnly, query.ActivityIDs = "", true, nil
+	return s.changes.List(ctx, query)
+}
+
+// NewArrivals lists the latest promoted products matching the region, platform and keyword of the query
+func (s *FeedService) NewArrivals(ctx context.Context, query repository.ProductChangeQuery) ([]*entity.ProductChange, error) {
+	query.Kind, query.DropsOnly, query.ActivityIDs = entity.ProductChangeNew, false, nil
+	return s.changes.List(ctx, query)
+}
+
+// WatchedDrops lists the latest price drops of the products watched by the
+// owner of a private feed token, personally or through a shared watchlist
+func (s *FeedService) WatchedDrops(ctx context.Context, token string, query repository.ProductChangeQuery) ([]*entity.ProductChange, error) {
+	feedToken, err := s.tokens.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if feedToken == nil {
+		return nil, ErrFeedTokenNotFound
+	}
+
+	watched, err := s.watchedActivityIDs(ctx, feedToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+	query.Kind, query.DropsOnly, query.ActivityIDs = "", true, watched
+	return s.changes.List(ctx, query)
+}
+
+// Token returns the private feed token of the user, creating it on first use
+func (s *FeedService) Token(ctx context.Context, userID string) (*entity.FeedToken, error) {
+	token, err := s.tokens.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return s.RotateToken(ctx, userID)
+	}
+	return s.withFeedURL(token), nil
+}
+
+// RotateToken replaces the private feed token of the user, so that the
+// earlier feed URL stops working
+func (s *FeedService) RotateToken(ctx context.Context, userID string) (*entity.FeedToken, error) {
+	secret, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &entity.FeedToken{UserID: userID, Token: secret}
+	if err := s.tokens.Save(ctx, token); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("userId", userID).
+		Msg("Feed token issued")
+	return s.withFeedURL(token), nil
+}
+
+// watchedActivityIDs returns the products the user has alerts for, including
+// the items of the shared watchlists the user is a member of
+func (s *FeedService) watchedActivityIDs(ctx context.Context, userID string) ([]string, error) {
+	configs, err := s.notiRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.watchlists.ListItemsByMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	ids := []string{}
+	add := func(activityID string) {
+		if !seen[activityID] {
+			seen[activityID] = true
+			ids = append(ids, activityID)
+		}
+	}
+	for _, config := range configs {
+		add(config.ActivityID)
+	}
+	for _, item := range items {
+		add(item.ActivityID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// withFeedURL sets the private feed URL of a token if a public URL is configured
+func (s *FeedService) withFeedURL(token *entity.FeedToken) *entity.FeedToken {
+	if s.publicURL != "" {
+		token.URL = s.publicURL + "/feeds/user/" + token.Token + "/drops.atom"
+	}
+	return token
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
+	"kbfood/internal/domain/repository"
+)
+
+type memoryProductChangeRepository struct {
+	changes []*entity.ProductChange
+	queries []repository.ProductChangeQuery
+}
+
+func (s *memoryProductChangeRepository) Create(ctx context.Context, change *entity.ProductChange) error {
+	change.ID = int64(len(s.changes) + 1)
+	s.changes = append(s.changes, change)
+	return nil
+}
+
+func (s *memoryProductChangeRepository) List(ctx context.Context, query repository.ProductChangeQuery) ([]*entity.ProductChange, error) {
+	s.queries = append(s.queries, query)
+	return s.changes, nil
+}
+
+type memoryFeedTokenRepository struct {
+	tokens map[string]*entity.FeedToken
+}
+
+func (s *memoryFeedTokenRepository) FindByUser(ctx context.Context, userID string) (*entity.FeedToken, error) {
+	return s.tokens[userID], nil
+}
+
+func (s *memoryFeedTokenRepository) FindByToken(ctx context.Context, token string) (*entity.FeedToken, error) {
+	for _, t := range s.tokens {
+		if t.Token == token {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryFeedTokenRepository) Save(ctx context.Context, token *entity.FeedToken) error {
+	copied := *token
+	s.tokens[token.UserID] = &copied
+	return nil
+}
+
+func TestFeedService_RecordsBusEvents(t *testing.T) {
+	changes := &memoryProductChangeRepository{}
+	svc := NewFeedService(changes, nil, nil, nil, "")
+	bus := event.NewBus()
+	defer bus.Close()
+	svc.Subscribe(bus)
+
+	ctx := context.Background()
+	bus.Publish(ctx, event.PriceChanged{ActivityID: "DT_1", Region: "广州", Title: "双人套餐", OldPrice: 39.9, NewPrice: 29.9})
+	bus.Publish(ctx, event.StatusChanged{ActivityID: "DT_1", OldStatus: 1, NewStatus: 0})
+	bus.Publish(ctx, event.ProductCreated{ActivityID: "DT_2", Region: "佛山", Platform: "小蚕", Title: "烤鱼", Price: 59})
+	bus.Wait()
+
+	if len(changes.changes) != 2 {
+		t.Fatalf("recorded %d changes, want price and new product only", len(changes.changes))
+	}
+	drop := changes.changes[0]
+	if drop.Kind != entity.ProductChangePrice || !drop.IsDrop() || *drop.OldPrice != 39.9 || drop.Platform != entity.DefaultPlatform {
+		t.Errorf("price change = %+v", drop)
+	}
+	created := changes.changes[1]
+	if created.Kind != entity.ProductChangeNew || created.OldPrice != nil || created.Platform != "小蚕" || created.IsDrop() {
+		t.Errorf("new product = %+v", created)
+	}
+}
+
+func TestFeedService_Queries(t *testing.T) {
+	changes := &memoryProductChangeRepository{}
+	svc := NewFeedService(changes, nil, nil, nil, "")
+	ctx := context.Background()
+
+	filter := repository.ProductChangeQuery{Region: "广州", Keyword: "烤鱼", ActivityIDs: []string{"DT_1"}}
+	if _, err := svc.Drops(ctx, filter); err != nil {
+		t.Fatalf("Drops() error = %v", err)
+	}
+	if _, err := svc.NewArrivals(ctx, filter); err != nil {
+		t.Fatalf("NewArrivals() error = %v", err)
+	}
+
+	drops, arrivals := changes.queries[0], changes.queries[1]
+	if !drops.DropsOnly || drops.Region != "广州" || drops.Keyword != "烤鱼" || drops.ActivityIDs != nil {
+		t.Errorf("drops query = %+v", drops)
+	}
+	if arrivals.Kind != entity.ProductChangeNew || arrivals.DropsOnly || arrivals.ActivityIDs != nil {
+		t.Errorf("new arrivals query = %+v", arrivals)
+	}
+}
+
+func TestFeedService_WatchedDrops(t *testing.T) {
+	ctx := context.Background()
+	changes := &memoryProductChangeRepository{}
+	tokens := &memoryFeedTokenRepository{tokens: map[string]*entity.FeedToken{}}
+	notiRepo := &stubNotificationRepository{configs: []*entity.NotificationConfig{
+		{ActivityID: "DT_2", UserID: "user-1"},
+		{ActivityID: "DT_3", UserID: "user-2"},
+	}}
+	watchlists := &stubWatchlistRepository{items: []*entity.WatchlistItem{
+		{WatchlistID: 1, NotificationConfig: entity.NotificationConfig{ActivityID: "DT_1"}},
+		{WatchlistID: 1, NotificationConfig: entity.NotificationConfig{ActivityID: "DT_2"}},
+		{WatchlistID: 2, NotificationConfig: entity.NotificationConfig{ActivityID: "DT_4"}},
+	}}
+	if err := watchlists.Create(ctx, &entity.Watchlist{Name: "午餐"}, "user-2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := watchlists.AddMember(ctx, &entity.WatchlistMember{WatchlistID: 1, UserID: "user-1", Role: entity.WatchlistRoleMember}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewFeedService(changes, tokens, notiRepo, watchlists, "https://kbfood.example.com/")
+
+	token, err := svc.Token(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if len(token.Token) != 32 || token.URL != "https://kbfood.example.com/feeds/user/"+token.Token+"/drops.atom" {
+		t.Errorf("token = %+v", token)
+	}
+	again, err := svc.Token(ctx, "user-1")
+	if err != nil || again.Token != token.Token {
+		t.Fatalf("Token() = %+v, %v, want the existing token", again, err)
+	}
+
+	if _, err := svc.WatchedDrops(ctx, token.Token, repository.ProductChangeQuery{Platform: "探探糖"}); err != nil {
+		t.Fatalf("WatchedDrops() error = %v", err)
+	}
+	query := changes.queries[0]
+	if !query.DropsOnly || query.Platform != "探探糖" || strings.Join(query.ActivityIDs, ",") != "DT_1,DT_2" {
+		t.Errorf("watched drops query = %+v", query)
+	}
+
+	rotated, err := svc.RotateToken(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("RotateToken() error = %v", err)
+	}
+	if rotated.Token == token.Token {
+		t.Fatal("RotateToken() kept the earlier token")
+	}
+	if _, err := svc.WatchedDrops(ctx, token.Token, repository.ProductChangeQuery{}); !errors.Is(err, ErrFeedTokenNotFound) {
+		t.Errorf("WatchedDrops(earlier token) error = %v, want ErrFeedTokenNotFound", err)
+	}
+}
@@ -3,40 +3,68 @@ package service
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
 	"kbfood/internal/domain/repository"
-
-	"github.com/rs/zerolog/log"
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/metrics"
+	"kbfood/internal/pkg/tracing"
 )
 
+// BarkSender delivers pushes to Bark devices
+type BarkSender interface {
+	Push(ctx context.Context, device entity.BarkDevice, msg *entity.BarkMessage) error
+}
+
 // NotificationService handles price notifications
 type NotificationService struct {
 	notiRepo         repository.NotificationRepository
 	prodRepo         repository.ProductRepository
 	masterRepo       repository.MasterProductRepository
 	userSettingsRepo repository.UserSettingsRepository
-	barkURL          string
+	trendRepo        repository.TrendRepository
+	deliveryRepo     repository.NotificationDeliveryRepository
+	watchlistRepo    repository.WatchlistRepository
+	bark             BarkSender
+	publicURL        string
+
+	// checkMu serializes rule matching; sending holds the rules it is
+	// alerting on so that an event and the polling safety net cannot alert
+	// the same watch twice
+	checkMu sync.Mutex
+	sending map[string]bool
 }
 
+// deliveryRetention is how long sent alert records are kept for frequency caps
+const deliveryRetention = 7 * 24 * time.Hour
+
 // NewNotificationService creates a new notification service
 func NewNotificationService(
 	notiRepo repository.NotificationRepository,
 	prodRepo repository.ProductRepository,
 	masterRepo repository.MasterProductRepository,
 	userSettingsRepo repository.UserSettingsRepository,
-	barkURL string,
+	trendRepo repository.TrendRepository,
+	deliveryRepo repository.NotificationDeliveryRepository,
+	watchlistRepo repository.WatchlistRepository,
+	bark BarkSender,
+	publicURL string,
 ) *NotificationService {
 	return &NotificationService{
 		notiRepo:         notiRepo,
 		prodRepo:         prodRepo,
 		masterRepo:       masterRepo,
 		userSettingsRepo: userSettingsRepo,
-		barkURL:          barkURL,
+		trendRepo:        trendRepo,
+		deliveryRepo:     deliveryRepo,
+		watchlistRepo:    watchlistRepo,
+		bark:             bark,
+		publicURL:        strings.TrimRight(publicURL, "/"),
 	}
 }
 
@@ -78,143 +106,667 @@ func (s *NotificationService) Delete(ctx context.Context, userID, activityID str
 	return s.notiRepo.Delete(ctx, activityID, userID)
 }
 
-// CheckAndNotify checks all notifications and sends notifications for matching products
+// CheckAndNotify checks all notifications and sends notifications for matching products.
+// Alerts are normally raised by HandleProductEvent as soon as a product changes;
+// this full scan is kept as a safety net for changes that were not published.
 func (s *NotificationService) CheckAndNotify(ctx context.Context) error {
 	configs, err := s.notiRepo.ListAll(ctx)
 	if err != nil {
 		return err
 	}
+	var items []*entity.WatchlistItem
+	if s.watchlistRepo != nil {
+		if items, err = s.watchlistRepo.ListAllItems(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.evaluate(ctx, configs, items)
+	return nil
+}
+
+// Subscribe registers the service for product change events on the bus
+func (s *NotificationService) Subscribe(bus *event.Bus) {
+	bus.Subscribe(event.NamePriceChanged, s.HandleProductEvent)
+	bus.Subscribe(event.NameStatusChanged, s.HandleProductEvent)
+}
+
+// HandleProductEvent evaluates the notification configs and watchlist items
+// watching the changed product
+func (s *NotificationService) HandleProductEvent(ctx context.Context, e event.Event) {
+	logger := applog.LoggerFromContext(ctx)
+
+	var activityID string
+	switch ev := e.(type) {
+	case event.PriceChanged:
+		activityID = ev.ActivityID
+	case event.StatusChanged:
+		activityID = ev.ActivityID
+	default:
+		return
+	}
+
+	configs, err := s.notiRepo.ListByActivityID(ctx, activityID)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("activityId", activityID).
+			Msg("failed to list notifications for product")
+		return
+	}
+	var items []*entity.WatchlistItem
+	if s.watchlistRepo != nil {
+		if items, err = s.watchlistRepo.ListItemsByActivityID(ctx, activityID); err != nil {
+			logger.Error().Err(err).
+				Str("activityId", activityID).
+				Msg("failed to list watchlist items for product")
+			return
+		}
+	}
+
+	s.evaluate(ctx, configs, items)
+}
+
+// pendingAlert is a matched rule whose alert is being sent
+type pendingAlert struct {
+	key     string
+	config  *entity.NotificationConfig // personal rule, nil for watchlist items
+	item    *entity.WatchlistItem
+	product *notificationProduct
+	reason  string
+}
+
+// evaluate checks the given notification configs and watchlist items, sends
+// the alerts of the met rules and records them. Pushes are sent outside
+// checkMu so that a slow Bark server does not hold up other evaluations.
+func (s *NotificationService) evaluate(
+	ctx context.Context,
+	configs []*entity.NotificationConfig,
+	items []*entity.WatchlistItem,
+) {
+	for _, alert := range s.matchAlerts(ctx, configs, items) {
+		s.sendAlert(ctx, alert)
+	}
+}
+
+// matchAlerts returns the met rules and holds them until their alert is
+// sent. Rules whose alert is already being sent are skipped.
+func (s *NotificationService) matchAlerts(
+	ctx context.Context,
+	configs []*entity.NotificationConfig,
+	items []*entity.WatchlistItem,
+) []*pendingAlert {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	if s.sending == nil {
+		s.sending = make(map[string]bool)
+	}
 
+	var alerts []*pendingAlert
 	for _, config := range configs {
-		if s.checkAndNotifySingle(ctx, config) {
-			// Update last notify time
-			if err := s.notiRepo.UpdateNotifyTime(ctx, config.ActivityID, config.UserID); err != nil {
-				log.Error().Err(err).
-					Str("activityId", config.ActivityID).
-					Str("userId", config.UserID).
-					Msg("failed to update notification time")
-			}
+		key := "config:" + config.UserID + ":" + config.ActivityID
+		if s.sending[key] {
+			continue
+		}
+		product, reason, ok := s.matchRule(ctx, config, func(status int) error {
+			return s.notiRepo.UpdateLastStatus(ctx, config.ActivityID, config.UserID, status)
+		})
+		if !ok {
+			continue
 		}
+		s.sending[key] = true
+		alerts = append(alerts, &pendingAlert{key: key, config: config, product: product, reason: reason})
 	}
 
-	return nil
+	for _, item := range items {
+		key := fmt.Sprintf("watchlist:%d:%s", item.WatchlistID, item.ActivityID)
+		if s.sending[key] {
+			continue
+		}
+		product, reason, ok := s.matchRule(ctx, &item.NotificationConfig, func(status int) error {
+			return s.watchlistRepo.UpdateItemLastStatus(ctx, item.WatchlistID, item.ActivityID, status)
+		})
+		if !ok {
+			continue
+		}
+		s.sending[key] = true
+		alerts = append(alerts, &pendingAlert{key: key, item: item, product: product, reason: reason})
+	}
+
+	return alerts
 }
 
-// checkAndNotifySingle checks a single notification and sends if conditions are met
-func (s *NotificationService) checkAndNotifySingle(ctx context.Context, config *entity.NotificationConfig) bool {
-	// Check if already notified today
-	if config.HasNotifiedToday() {
-		return false
+// sendAlert sends a matched rule's alert, records the notify time if anyone
+// was notified and releases the rule
+func (s *NotificationService) sendAlert(ctx context.Context, alert *pendingAlert) {
+	logger := applog.LoggerFromContext(ctx)
+
+	defer func() {
+		s.checkMu.Lock()
+		delete(s.sending, alert.key)
+		s.checkMu.Unlock()
+	}()
+
+	price := alert.product.CurrentPrice
+	if alert.item != nil {
+		item := alert.item
+		if !s.notifyWatchlist(ctx, item.WatchlistID, alert.product, alert.reason) {
+			return
+		}
+		if err := s.watchlistRepo.UpdateItemNotifyTime(ctx, item.WatchlistID, item.ActivityID, price); err != nil {
+			logger.Error().Err(err).
+				Str("activityId", item.ActivityID).
+				Int64("watchlistId", item.WatchlistID).
+				Msg("failed to update watchlist item notification time")
+		}
+		return
+	}
+
+	config := alert.config
+	if !s.notifyOwner(ctx, config, alert.product, alert.reason) {
+		return
+	}
+	// Update last notify time and remember the alerted price
+	if err := s.notiRepo.UpdateNotifyTime(ctx, config.ActivityID, config.UserID, price); err != nil {
+		logger.Error().Err(err).
+			Str("activityId", config.ActivityID).
+			Str("userId", config.UserID).
+			Msg("failed to update notification time")
 	}
+}
+
+// notifyOwner sends a personal rule's alert to its owner. Returns true if
+// the alert was sent or held.
+func (s *NotificationService) notifyOwner(
+	ctx context.Context,
+	config *entity.NotificationConfig,
+	product *notificationProduct,
+	reason string,
+) bool {
+	logger := applog.LoggerFromContext(ctx)
 
 	// Get user's Bark Key
 	userSettings, err := s.userSettingsRepo.Get(ctx, config.UserID)
 	if err != nil || userSettings == nil {
-		log.Error().Err(err).
+		logger.Error().Err(err).
 			Str("userId", config.UserID).
 			Msg("failed to get user settings")
 		return false
 	}
 
+	// Send (or hold) notification according to the user's preferences
+	return s.sendNotification(ctx, product, reason, userSettings)
+}
+
+// matchRule evaluates a rule against the current state of its product and
+// returns the product with the alert reason if the rule is met. saveStatus
+// records the observed sales status of back-in-stock rules.
+func (s *NotificationService) matchRule(
+	ctx context.Context,
+	config *entity.NotificationConfig,
+	saveStatus func(status int) error,
+) (*notificationProduct, string, bool) {
+	logger := applog.LoggerFromContext(ctx)
+
+	// Check if already notified today. Back-in-stock rules still need the
+	// product status observed so that a sell-out today is not missed.
+	if config.HasNotifiedToday() && config.EffectiveRuleType() != entity.RuleTypeBackInStock {
+		return nil, "", false
+	}
+
 	product, err := s.findNotificationProduct(ctx, config.ActivityID)
 	if err != nil {
-		log.Error().Err(err).
+		logger.Error().Err(err).
 			Str("activityId", config.ActivityID).
 			Msg("failed to find product")
-		return false
+		return nil, "", false
 	}
 
 	// Check if product is nil
 	if product == nil {
-		log.Warn().
+		logger.Warn().
 			Str("activityId", config.ActivityID).
 			Msg("Product not found for notification")
-		return false
+		return nil, "", false
 	}
 
-	// Check if product is on sale
-	if !product.IsOnSale() {
-		return false
+	snapshot := s.buildSnapshot(ctx, config, product)
+	shouldNotify := config.ShouldNotify(snapshot)
+	// A restock that the daily limit or a snooze keeps from alerting is not
+	// tracked, so it is alerted once the rule can notify again
+	if shouldNotify || !config.Matches(snapshot) {
+		s.trackStatus(ctx, config, product.SalesStatus, saveStatus)
 	}
 
-	// Check if price condition is met
-	if !config.ShouldNotify(product.CurrentPrice) {
-		return false
+	// Check if rule condition is met
+	if !shouldNotify {
+		return nil, "", false
+	}
+	return product, config.Reason(snapshot), true
+}
+
+// buildSnapshot collects the price history a rule needs to be evaluated
+func (s *NotificationService) buildSnapshot(
+	ctx context.Context,
+	config *entity.NotificationConfig,
+	product *notificationProduct,
+) entity.PriceSnapshot {
+	logger := applog.LoggerFromContext(ctx)
+
+	snapshot := entity.PriceSnapshot{
+		CurrentPrice: product.CurrentPrice,
+		SalesStatus:  product.SalesStatus,
+	}
+
+	switch config.EffectiveRuleType() {
+	case entity.RuleTypePercentDrop, entity.RuleTypeAllTimeLow:
+	default:
+		return snapshot
+	}
+	if s.trendRepo == nil {
+		return snapshot
+	}
+
+	trends, err := s.trendRepo.FindByActivityID(ctx, config.ActivityID)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("activityId", config.ActivityID).
+			Msg("failed to load price trends")
+		return snapshot
+	}
+
+	today := truncateToDay(time.Now())
+	todayKey := today.Format("2006-01-02")
+	yesterdayKey := today.AddDate(0, 0, -1).Format("2006-01-02")
+
+	for _, trend := range trends {
+		if trend == nil {
+			continue
+		}
+		key := trend.RecordDate.Format("2006-01-02")
+		if key >= todayKey {
+			continue
+		}
+		if key == yesterdayKey {
+			price := trend.Price
+			snapshot.PreviousClose = &price
+		}
+		if snapshot.HistoricalLow == nil || trend.Price < *snapshot.HistoricalLow {
+			price := trend.Price
+			snapshot.HistoricalLow = &price
+		}
 	}
 
-	// Send notification using user's Bark Key
-	return s.sendNotification(product, config.TargetPrice, userSettings.BarkKey)
+	return snapshot
 }
 
-// sendNotification sends a push notification
-func (s *NotificationService) sendNotification(product *notificationProduct, targetPrice float64, barkKey string) bool {
-	message := fmt.Sprintf("【%s %s ¥%.2f】%s",
+// trackStatus records the observed sales status for back-in-stock rules
+func (s *NotificationService) trackStatus(
+	ctx context.Context,
+	config *entity.NotificationConfig,
+	status int,
+	saveStatus func(status int) error,
+) {
+	logger := applog.LoggerFromContext(ctx)
+
+	if config.EffectiveRuleType() != entity.RuleTypeBackInStock {
+		return
+	}
+	if config.LastStatus != nil && *config.LastStatus == status {
+		return
+	}
+
+	if err := saveStatus(status); err != nil {
+		logger.Error().Err(err).
+			Str("activityId", config.ActivityID).
+			Msg("failed to update last observed status")
+		return
+	}
+	config.LastStatus = &status
+}
+
+// sendNotification sends a price notification
+func (s *NotificationService) sendNotification(
+	ctx context.Context,
+	product *notificationProduct,
+	reason string,
+	settings *entity.UserSettings,
+) bool {
+	logger := applog.LoggerFromContext(ctx)
+
+	message := fmt.Sprintf("【%s %s ¥%.2f】%s（%s）",
 		product.Platform,
 		product.Region,
 		product.CurrentPrice,
 		product.Title,
+		reason,
 	)
 
-	log.Info().
+	logger.Info().
 		Str("activityId", product.ActivityID).
 		Str("message", message).
 		Msg("Sending price notification")
 
-	if barkKey == "" {
-		log.Info().Msg("Bark key not configured, skipping actual send")
-		return true
+	return s.deliver(ctx, settings, product.ActivityID, message)
+}
+
+// notifyWatchlist sends an alert on a watchlist item to every member of the
+// list, honoring each member's preferences. Returns true if any member was notified.
+func (s *NotificationService) notifyWatchlist(
+	ctx context.Context,
+	watchlistID int64,
+	product *notificationProduct,
+	reason string,
+) bool {
+	logger := applog.LoggerFromContext(ctx)
+
+	if s.watchlistRepo == nil {
+		return false
 	}
 
-	// Build Bark URL with user's bark key
-	barkBaseURL := s.barkURL
-	if barkBaseURL == "" {
-		barkBaseURL = "https://api.day.app"
+	watchlist, err := s.watchlistRepo.FindByID(ctx, watchlistID)
+	if err != nil || watchlist == nil {
+		logger.Error().Err(err).
+			Int64("watchlistId", watchlistID).
+			Msg("failed to get watchlist")
+		return false
+	}
+	members, err := s.watchlistRepo.ListMembers(ctx, watchlistID)
+	if err != nil {
+		logger.Error().Err(err).
+			Int64("watchlistId", watchlistID).
+			Msg("failed to list watchlist members")
+		return false
 	}
 
-	// Normalize barkKey - extract device key from URL if needed
-	deviceKey := normalizeBarkKey(barkKey)
+	reason = fmt.Sprintf("%s，清单「%s」", reason, watchlist.Name)
+	notified := false
+	for _, member := range members {
+		settings, err := s.userSettingsRepo.Get(ctx, member.UserID)
+		if err != nil || settings == nil {
+			logger.Error().Err(err).
+				Str("userId", member.UserID).
+				Int64("watchlistId", watchlistID).
+				Msg("failed to get watchlist member settings")
+			continue
+		}
+		if s.sendNotification(ctx, product, reason, settings) {
+			notified = true
+		}
+	}
+	return notified
+}
 
-	// Send to Bark using HTTP client
-	encodedMsg := url.QueryEscape(message)
-	fullURL := fmt.Sprintf("%s/%s/%s?level=critical&volume=5", barkBaseURL, deviceKey, encodedMsg)
+// NotifyUser sends a free-form message to a user's Bark device
+func (s *NotificationService) NotifyUser(ctx context.Context, userID, message string) bool {
+	logger := applog.LoggerFromContext(ctx)
 
-	// Create HTTP request with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	userSettings, err := s.userSettingsRepo.Get(ctx, userID)
+	if err != nil || userSettings == nil {
+		logger.Error().Err(err).
+			Str("userId", userID).
+			Msg("failed to get user settings")
+		return false
+	}
+
+	logger.Info().
+		Str("userId", userID).
+		Str("message", message).
+		Msg("Sending user notification")
+
+	return s.deliver(ctx, userSettings, "", message)
+}
+
+// DeliverHeld sends alerts held back by quiet hours or frequency caps once
+// the user's preferences allow it. Returns the number of alerts sent.
+func (s *NotificationService) DeliverHeld(ctx context.Context) (int, error) {
+	logger := applog.LoggerFromContext(ctx)
+
+	if s.deliveryRepo == nil {
+		return 0, nil
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, fullURL, nil)
+	held, err := s.deliveryRepo.ListHeld(ctx)
 	if err != nil {
-		log.Error().Err(err).
-			Str("url", fullURL).
-			Msg("Failed to create bark request")
+		return 0, err
+	}
+
+	now := time.Now()
+	settingsByUser := make(map[string]*entity.UserSettings)
+	waiting := make(map[string]bool)
+	sent := 0
+
+	for _, delivery := range held {
+		if waiting[delivery.UserID] {
+			continue
+		}
+
+		settings, ok := settingsByUser[delivery.UserID]
+		if !ok {
+			settings, err = s.userSettingsRepo.Get(ctx, delivery.UserID)
+			if err != nil {
+				logger.Error().Err(err).
+					Str("userId", delivery.UserID).
+					Msg("failed to get user settings")
+				waiting[delivery.UserID] = true
+				continue
+			}
+			if settings == nil {
+				settings = &entity.UserSettings{UserID: delivery.UserID}
+			}
+			settingsByUser[delivery.UserID] = settings
+		}
+
+		// Keep the remaining alerts of this user held, in order
+		if s.holdReason(ctx, settings, now) != "" || !s.sendBark(ctx, settings, delivery.ActivityID, delivery.Message) {
+			waiting[delivery.UserID] = true
+			continue
+		}
+
+		if err := s.deliveryRepo.MarkSent(ctx, delivery.ID); err != nil {
+			logger.Error().Err(err).
+				Int64("deliveryId", delivery.ID).
+				Msg("failed to mark held notification as sent")
+		}
+		sent++
+	}
+
+	if err := s.deliveryRepo.DeleteSentBefore(ctx, now.Add(-deliveryRetention)); err != nil {
+		logger.Error().Err(err).Msg("failed to prune notification deliveries")
+	}
+
+	return sent, nil
+}
+
+// deliver sends a message honoring the user's notification preferences.
+// Users in digest mode only receive the daily digest. During quiet hours or once a frequency cap is reached the message is held
+// and delivered later by DeliverHeld.
+func (s *NotificationService) deliver(ctx context.Context, settings *entity.UserSettings, activityID, message string) bool {
+	logger := applog.LoggerFromContext(ctx)
+
+	if settings.Preferences.DigestEnabled {
+		logger.Debug().
+			Str("userId", settings.UserID).
+			Msg("Digest mode enabled, skipping instant notification")
 		return false
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Error().Err(err).
-			Str("url", fullURL).
-			Msg("Failed to send bark notification")
+	if s.deliveryRepo == nil {
+		return s.sendBark(ctx, settings, activityID, message)
+	}
+
+	now := time.Now()
+	if reason := s.holdReason(ctx, settings, now); reason != "" {
+		err := s.deliveryRepo.Create(ctx, &entity.NotificationDelivery{
+			UserID:     settings.UserID,
+			ActivityID: activityID,
+			Message:    message,
+			Status:     entity.DeliveryStatusHeld,
+		})
+		if err != nil {
+			logger.Error().Err(err).
+				Str("userId", settings.UserID).
+				Msg("failed to hold notification")
+			return false
+		}
+
+		logger.Info().
+			Str("userId", settings.UserID).
+			Str("reason", reason).
+			Msg("Notification held")
+		return true
+	}
+
+	if !s.sendBark(ctx, settings, activityID, message) {
 		return false
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		log.Error().
-			Int("status", resp.StatusCode).
-			Str("url", fullURL).
-			Msg("Bark notification failed")
+	err := s.deliveryRepo.Create(ctx, &entity.NotificationDelivery{
+		UserID:     settings.UserID,
+		ActivityID: activityID,
+		Message:    message,
+		Status:     entity.DeliveryStatusSent,
+		SentTime:   &now,
+	})
+	if err != nil {
+		logger.Error().Err(err).
+			Str("userId", settings.UserID).
+			Msg("failed to record sent notification")
+	}
+	return true
+}
+
+// holdReason returns why an alert must be held for the user at t, or "" if it can be sent
+func (s *NotificationService) holdReason(ctx context.Context, settings *entity.UserSettings, t time.Time) string {
+	prefs := &settings.Preferences
+	if prefs.InQuietHours(t) {
+		return "quiet hours"
+	}
+
+	if prefs.MaxPerHour > 0 && s.sentSince(ctx, settings.UserID, t.Add(-time.Hour)) >= prefs.MaxPerHour {
+		return "hourly cap"
+	}
+	if prefs.MaxPerDay > 0 && s.sentSince(ctx, settings.UserID, prefs.StartOfDay(t)) >= prefs.MaxPerDay {
+		return "daily cap"
+	}
+	return ""
+}
+
+// sentSince counts the alerts sent to a user since the given time
+func (s *NotificationService) sentSince(ctx context.Context, userID string, since time.Time) int {
+	logger := applog.LoggerFromContext(ctx)
+
+	count, err := s.deliveryRepo.CountSentSince(ctx, userID, since)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("userId", userID).
+			Msg("failed to count sent notifications")
+		return 0
+	}
+	return count
+}
+
+// sendBark delivers a message to the user's Bark device. Alerts about a
+// product are grouped and deep-linked according to the user's preferences.
+func (s *NotificationService) sendBark(ctx context.Context, settings *entity.UserSettings, activityID, message string) bool {
+	logger := applog.LoggerFromContext(ctx)
+
+	barkKey := settings.BarkKey
+	if barkKey == "" {
+		logger.Info().Msg("Bark key not configured, skipping actual send")
+		return true
+	}
+
+	// Normalize barkKey - extract device key from URL if needed
+	prefs := settings.Preferences
+	device := entity.BarkDevice{
+		Key:        entity.NormalizeBarkKey(barkKey),
+		EncryptKey: prefs.BarkEncryptKey,
+		EncryptIV:  prefs.BarkEncryptIV,
+	}
+
+	var product *notificationProduct
+	if activityID != "" {
+		var err error
+		if product, err = s.findNotificationProduct(ctx, activityID); err != nil {
+			logger.Warn().Err(err).
+				Str("activityId", activityID).
+				Msg("failed to load product for Bark options")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "notification.bark", tracing.KindClient,
+		tracing.String("user.id", settings.UserID),
+		tracing.String("activity.id", activityID),
+	)
+	defer span.End()
+
+	if err := s.bark.Push(ctx, device, s.barkMessage(message, prefs, product)); err != nil {
+		span.RecordError(err)
+		logger.Error().Err(err).
+			Str("userId", settings.UserID).
+			Str("activityId", activityID).
+			Msg("Failed to send bark notification")
+		metrics.Notifications.With("bark", metrics.StatusFailed).Inc()
 		return false
 	}
+	metrics.Notifications.With("bark", metrics.StatusSent).Inc()
 
-	log.Info().
-		Str("url", fullURL).
+	logger.Info().
+		Str("userId", settings.UserID).
+		Str("activityId", activityID).
+		Bool("encrypted", prefs.BarkEncryptKey != "").
 		Msg("Bark notification sent successfully")
 
 	return true
 }
 
+// barkMessage builds the Bark payload for the user's preferences
+func (s *NotificationService) barkMessage(
+	message string,
+	prefs entity.NotificationPreferences,
+	product *notificationProduct,
+) *entity.BarkMessage {
+	msg := &entity.BarkMessage{
+		Body:  message,
+		Level: prefs.EffectiveBarkLevel(),
+		Sound: prefs.BarkSound,
+		Icon:  prefs.BarkIcon,
+	}
+	if msg.Level == entity.BarkLevelCritical {
+		msg.Volume = 5
+	}
+	if prefs.BarkArchive {
+		msg.IsArchive = "1"
+	}
+	if product == nil {
+		return msg
+	}
+
+	switch prefs.BarkGroup {
+	case entity.BarkGroupPlatform:
+		msg.Group = product.Platform
+	case entity.BarkGroupRegion:
+		msg.Group = product.Region
+	}
+	msg.URL = s.productLink(product)
+	return msg
+}
+
+// productLink returns the frontend link that opens the product, or "" if no public URL is configured
+func (s *NotificationService) productLink(product *notificationProduct) string {
+	if s.publicURL == "" {
+		return ""
+	}
+	query := url.Values{}
+	query.Set("keyword", product.Title)
+	query.Set("activityId", product.ActivityID)
+	return s.publicURL + "/?" + query.Encode()
+}
+
 type notificationProduct struct {
 	ActivityID   string
 	Platform     string
@@ -277,43 +829,6 @@ func notificationPlatform(product *entity.MasterProduct) string {
 	return "探探糖"
 }
 
-// normalizeBarkKey extracts device key from full URL or returns key as-is
-func normalizeBarkKey(input string) string {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return ""
-	}
-
-	// If full URL, extract the last non-empty path segment as device key.
-	if strings.HasPrefix(strings.ToLower(input), "http") {
-		parsed, err := url.Parse(input)
-		if err == nil {
-			path := strings.Trim(parsed.Path, "/")
-			if path == "" {
-				return ""
-			}
-
-			parts := strings.Split(path, "/")
-			for i := len(parts) - 1; i >= 0; i-- {
-				if seg := strings.TrimSpace(parts[i]); seg != "" {
-					return seg
-				}
-			}
-			return ""
-		}
-
-		trimmed := strings.Trim(input, "/")
-		parts := strings.Split(trimmed, "/")
-		for i := len(parts) - 1; i >= 0; i-- {
-			if seg := strings.TrimSpace(parts[i]); seg != "" {
-				return seg
-			}
-		}
-		return ""
-	}
-	return input
-}
-
 // GetByActivityID returns notification config for a product
 func (s *NotificationService) GetByActivityID(ctx context.Context, userID, activityID string) (*entity.NotificationConfig, error) {
 	return s.notiRepo.FindByActivityID(ctx, activityID, userID)
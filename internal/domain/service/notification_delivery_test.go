@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/infra/external"
+)
+
+type stubNotificationDeliveryRepository struct {
+	deliveries []*entity.NotificationDelivery
+}
+
+func (s *stubNotificationDeliveryRepository) Create(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	delivery.ID = int64(len(s.deliveries) + 1)
+	s.deliveries = append(s.deliveries, delivery)
+	return nil
+}
+
+func (s *stubNotificationDeliveryRepository) CountSentSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	count := 0
+	for _, d := range s.deliveries {
+		if d.UserID == userID && d.Status == entity.DeliveryStatusSent && d.SentTime != nil && !d.SentTime.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *stubNotificationDeliveryRepository) ListHeld(ctx context.Context) ([]*entity.NotificationDelivery, error) {
+	var held []*entity.NotificationDelivery
+	for _, d := range s.deliveries {
+		if d.Status == entity.DeliveryStatusHeld {
+			held = append(held, d)
+		}
+	}
+	return held, nil
+}
+
+func (s *stubNotificationDeliveryRepository) MarkSent(ctx context.Context, id int64) error {
+	now := time.Now()
+	for _, d := range s.deliveries {
+		if d.ID == id {
+			d.Status = entity.DeliveryStatusSent
+			d.SentTime = &now
+		}
+	}
+	return nil
+}
+
+func (s *stubNotificationDeliveryRepository) DeleteSentBefore(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func (s *stubNotificationDeliveryRepository) countStatus(status string) int {
+	count := 0
+	for _, d := range s.deliveries {
+		if d.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// decodeBarkMessage decodes the JSON payload of a plaintext Bark push
+func decodeBarkMessage(t *testing.T, r *http.Request) entity.BarkMessage {
+	t.Helper()
+	var msg entity.BarkMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		t.Fatalf("decode Bark payload: %v", err)
+	}
+	return msg
+}
+
+func TestNotificationService_HoldsDuringQuietHoursAndDeliversLater(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{
+			UserID:  "client-123",
+			BarkKey: "DEVICE123",
+			Preferences: entity.NotificationPreferences{
+				QuietStart: now.Add(-time.Hour).Format("15:04"),
+				QuietEnd:   now.Add(time.Hour).Format("15:04"),
+			},
+		},
+	}
+	deliveryRepo := &stubNotificationDeliveryRepository{}
+
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := NewNotificationService(&stubNotificationRepository{}, &stubProductRepository{}, nil, userSettingsRepo, nil, deliveryRepo, nil, external.NewBarkSender(server.URL), "")
+
+	if !service.NotifyUser(ctx, "client-123", "降价啦") {
+		t.Fatal("expected notification to be held successfully")
+	}
+	if requestCount != 0 {
+		t.Fatalf("expected no Bark request during quiet hours, got %d", requestCount)
+	}
+	if deliveryRepo.countStatus(entity.DeliveryStatusHeld) != 1 {
+		t.Fatalf("expected 1 held notification, got %d", deliveryRepo.countStatus(entity.DeliveryStatusHeld))
+	}
+
+	// Still quiet: nothing is delivered
+	if sent, err := service.DeliverHeld(ctx); err != nil || sent != 0 {
+		t.Fatalf("DeliverHeld() = %d, %v; want 0, nil", sent, err)
+	}
+
+	userSettingsRepo.settings.Preferences = entity.NotificationPreferences{}
+	if sent, err := service.DeliverHeld(ctx); err != nil || sent != 1 {
+		t.Fatalf("DeliverHeld() = %d, %v; want 1, nil", sent, err)
+	}
+	if requestCount != 1 {
+		t.Fatalf("expected held notification to be sent, got %d requests", requestCount)
+	}
+	if deliveryRepo.countStatus(entity.DeliveryStatusHeld) != 0 {
+		t.Fatal("expected no held notifications after delivery")
+	}
+}
+
+func TestNotificationService_HoldsBeyondHourlyCapAndUsesBarkPreferences(t *testing.T) {
+	ctx := context.Background()
+
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{
+			UserID:  "client-123",
+			BarkKey: "DEVICE123",
+			Preferences: entity.NotificationPreferences{
+				MaxPerHour: 1,
+				BarkLevel:  entity.BarkLevelActive,
+				BarkSound:  "bell",
+			},
+		},
+	}
+	deliveryRepo := &stubNotificationDeliveryRepository{}
+
+	var pushes []entity.BarkMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushes = append(pushes, decodeBarkMessage(t, r))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := NewNotificationService(&stubNotificationRepository{}, &stubProductRepository{}, nil, userSettingsRepo, nil, deliveryRepo, nil, external.NewBarkSender(server.URL), "")
+
+	service.NotifyUser(ctx, "client-123", "第一条")
+	service.NotifyUser(ctx, "client-123", "第二条")
+
+	if len(pushes) != 1 {
+		t.Fatalf("expected 1 Bark request under hourly cap, got %d", len(pushes))
+	}
+	if got := pushes[0]; got.Body != "第一条" || got.Level != "active" || got.Sound != "bell" || got.Volume != 0 {
+		t.Fatalf("unexpected Bark payload %+v", got)
+	}
+	if deliveryRepo.countStatus(entity.DeliveryStatusHeld) != 1 {
+		t.Fatalf("expected second notification to be held, got %d held", deliveryRepo.countStatus(entity.DeliveryStatusHeld))
+	}
+}
+
+func TestNotificationService_GroupsAndDeepLinksProductAlerts(t *testing.T) {
+	ctx := context.Background()
+
+	masterRepo := &stubMasterProductRepository{
+		product: &entity.MasterProduct{
+			ID:            "DT_link",
+			Region:        "广州",
+			Platform:      "DT",
+			StandardTitle: "火锅四人餐",
+			Price:         68.7,
+			Status:        entity.SalesStatusOnSale,
+		},
+	}
+	notiRepo := &stubNotificationRepository{
+		configs: []*entity.NotificationConfig{
+			{ActivityID: "DT_link", UserID: "client-123", TargetPrice: 70},
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{
+			UserID:  "client-123",
+			BarkKey: "DEVICE123",
+			Preferences: entity.NotificationPreferences{
+				BarkGroup:   entity.BarkGroupRegion,
+				BarkIcon:    "https://food.example.com/icon.png",
+				BarkArchive: true,
+			},
+		},
+	}
+
+	var pushes []entity.BarkMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushes = append(pushes, decodeBarkMessage(t, r))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "https://food.example.com/")
+
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() error = %v", err)
+	}
+
+	if len(pushes) != 1 {
+		t.Fatalf("expected 1 Bark request, got %d", len(pushes))
+	}
+	got := pushes[0]
+	if got.Group != "广州" || got.Icon != "https://food.example.com/icon.png" || got.IsArchive != "1" {
+		t.Fatalf("unexpected Bark options %+v", got)
+	}
+	if got.Level != entity.BarkLevelCritical || got.Volume != 5 {
+		t.Fatalf("expected critical level with volume, got %+v", got)
+	}
+	wantURL := "https://food.example.com/?activityId=DT_link&keyword=%E7%81%AB%E9%94%85%E5%9B%9B%E4%BA%BA%E9%A4%90"
+	if got.URL != wantURL {
+		t.Fatalf("URL = %q, want %q", got.URL, wantURL)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
+	"kbfood/internal/infra/external"
+)
+
+type regionMasterProductRepository struct {
+	stubMasterProductRepository
+}
+
+func (s *regionMasterProductRepository) FindByRegion(ctx context.Context, region string) ([]*entity.MasterProduct, error) {
+	if s.product != nil && s.product.Region == region {
+		return []*entity.MasterProduct{s.product}, nil
+	}
+	return nil, nil
+}
+
+func TestDataCleaningService_PriceChangeAlertsWatchersImmediately(t *testing.T) {
+	ctx := context.Background()
+
+	notiRepo := &stubNotificationRepository{
+		configs: []*entity.NotificationConfig{
+			{ActivityID: "DT_event", UserID: "client-123", TargetPrice: 60},
+			// Matches too, but its product did not change
+			{ActivityID: "DT_other", UserID: "client-123", TargetPrice: 1000},
+		},
+	}
+	masterRepo := &regionMasterProductRepository{
+		stubMasterProductRepository{
+			product: &entity.MasterProduct{
+				ID:            "DT_event",
+				Region:        "广州",
+				Platform:      "DT",
+				StandardTitle: "火锅四人餐",
+				Price:         68.7,
+				Status:        entity.SalesStatusOnSale,
+			},
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{UserID: "client-123", BarkKey: "DEVICE123"},
+	}
+
+	var messages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		messages = append(messages, decodeBarkMessage(t, r).Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bus := event.NewBus()
+	defer bus.Close()
+	notifications := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
+	notifications.Subscribe(bus)
+	cleaning := NewDataCleaningService(masterRepo, nil, nil, nil, nil, nil, bus)
+
+	item := &entity.DTInputDTO{Title: "火锅四人餐", Price: 55, Status: entity.SalesStatusOnSale}
+	if _, err := cleaning.ProcessIncomingItem(ctx, item, "广州"); err != nil {
+		t.Fatalf("ProcessIncomingItem() error = %v", err)
+	}
+	bus.Wait()
+
+	if len(messages) != 1 || !strings.Contains(messages[0], "火锅四人餐") {
+		t.Fatalf("expected one alert for the changed product, got %q", messages)
+	}
+	if notiRepo.updatedActivityID != "DT_event" {
+		t.Fatalf("expected notify time to be recorded for DT_event, got %q", notiRepo.updatedActivityID)
+	}
+
+	// An unchanged price publishes nothing
+	if _, err := cleaning.ProcessIncomingItem(ctx, item, "广州"); err != nil {
+		t.Fatalf("ProcessIncomingItem() error = %v", err)
+	}
+	bus.Wait()
+	if len(messages) != 1 {
+		t.Fatalf("expected no alert without a change, got %d messages", len(messages))
+	}
+}
+
+func TestNotificationService_SlowBarkDoesNotBlockPublish(t *testing.T) {
+	ctx := context.Background()
+
+	notiRepo := &stubNotificationRepository{
+		configs: []*entity.NotificationConfig{
+			{ActivityID: "DT_event", UserID: "client-123", TargetPrice: 60},
+		},
+	}
+	masterRepo := &regionMasterProductRepository{
+		stubMasterProductRepository{
+			product: &entity.MasterProduct{
+				ID:            "DT_event",
+				Region:        "广州",
+				Platform:      "DT",
+				StandardTitle: "火锅四人餐",
+				Price:         68.7,
+				Status:        entity.SalesStatusOnSale,
+			},
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{UserID: "client-123", BarkKey: "DEVICE123"},
+	}
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bus := event.NewBus()
+	defer bus.Close()
+	notifications := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
+	notifications.Subscribe(bus)
+	cleaning := NewDataCleaningService(masterRepo, nil, nil, nil, nil, nil, bus)
+
+	processed := make(chan error, 1)
+	go func() {
+		_, err := cleaning.ProcessIncomingItem(ctx, &entity.DTInputDTO{Title: "火锅四人餐", Price: 55, Status: entity.SalesStatusOnSale}, "广州")
+		processed <- err
+	}()
+	select {
+	case err := <-processed:
+		if err != nil {
+			t.Fatalf("ProcessIncomingItem() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ProcessIncomingItem() waited for the Bark push")
+	}
+
+	<-started
+	// The safety net neither waits for the push in flight nor repeats it
+	checked := make(chan error, 1)
+	go func() { checked <- notifications.CheckAndNotify(ctx) }()
+	select {
+	case err := <-checked:
+		if err != nil {
+			t.Fatalf("CheckAndNotify() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CheckAndNotify() waited for the Bark push")
+	}
+
+	close(release)
+	bus.Wait()
+
+	if len(started) != 0 {
+		t.Errorf("expected one Bark push, got %d", 1+len(started))
+	}
+	if notiRepo.updatedActivityID != "DT_event" {
+		t.Errorf("expected notify time to be recorded for DT_event, got %q", notiRepo.updatedActivityID)
+	}
+}
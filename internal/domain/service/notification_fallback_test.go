@@ -4,12 +4,12 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 	"time"
 
 	"kbfood/internal/domain/entity"
 	"kbfood/internal/domain/repository"
+	"kbfood/internal/infra/external"
 )
 
 type stubNotificationRepository struct {
@@ -37,6 +37,16 @@ func (s *stubNotificationRepository) ListByUser(ctx context.Context, userID stri
 	return result, nil
 }
 
+func (s *stubNotificationRepository) ListByActivityID(ctx context.Context, activityID string) ([]*entity.NotificationConfig, error) {
+	var result []*entity.NotificationConfig
+	for _, config := range s.configs {
+		if config.ActivityID == activityID {
+			result = append(result, config)
+		}
+	}
+	return result, nil
+}
+
 func (s *stubNotificationRepository) ListAll(ctx context.Context) ([]*entity.NotificationConfig, error) {
 	return s.configs, nil
 }
@@ -49,13 +59,23 @@ func (s *stubNotificationRepository) Delete(ctx context.Context, activityID stri
 	return nil
 }
 
-func (s *stubNotificationRepository) UpdateNotifyTime(ctx context.Context, activityID string, userID string) error {
+func (s *stubNotificationRepository) UpdateNotifyTime(ctx context.Context, activityID string, userID string, price float64) error {
 	s.updatedActivityID = activityID
 	s.updatedUserID = userID
 	now := time.Now()
 	for _, config := range s.configs {
 		if config.ActivityID == activityID && config.UserID == userID {
 			config.LastNotifyTime = &now
+			config.ReferencePrice = &price
+		}
+	}
+	return nil
+}
+
+func (s *stubNotificationRepository) UpdateLastStatus(ctx context.Context, activityID string, userID string, status int) error {
+	for _, config := range s.configs {
+		if config.ActivityID == activityID && config.UserID == userID {
+			config.LastStatus = &status
 		}
 	}
 	return nil
@@ -75,6 +95,10 @@ func (s *stubProductRepository) FindByFilter(ctx context.Context, filter reposit
 	return nil, nil
 }
 
+func (s *stubProductRepository) FindByShop(ctx context.Context, shopName, excludeActivityID string, limit int) ([]*entity.Product, error) {
+	return nil, nil
+}
+
 func (s *stubProductRepository) Create(ctx context.Context, product *entity.Product) error {
 	return nil
 }
@@ -162,6 +186,27 @@ func (s *stubUserSettingsRepository) Upsert(ctx context.Context, settings *entit
 	return nil
 }
 
+func (s *stubUserSettingsRepository) ListDigestSubscribers(ctx context.Context) ([]*entity.UserSettings, error) {
+	if s.settings != nil && s.settings.Preferences.DigestEnabled {
+		return []*entity.UserSettings{s.settings}, nil
+	}
+	return nil, nil
+}
+
+func (s *stubUserSettingsRepository) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	if s.settings != nil && s.settings.UserID == userID {
+		s.settings.LastDigest = &sentAt
+	}
+	return nil
+}
+
+func (s *stubUserSettingsRepository) UpdatePreferences(ctx context.Context, userID string, prefs entity.NotificationPreferences) error {
+	if s.settings != nil && s.settings.UserID == userID {
+		s.settings.Preferences = prefs
+	}
+	return nil
+}
+
 func TestNotificationService_CheckAndNotifyFallsBackToMasterProduct(t *testing.T) {
 	ctx := context.Background()
 
@@ -197,14 +242,14 @@ func TestNotificationService_CheckAndNotifyFallsBackToMasterProduct(t *testing.T
 	requestCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestCount++
-		if !strings.Contains(r.URL.Path, "/DEVICE123/") {
+		if r.URL.Path != "/DEVICE123" {
 			t.Fatalf("expected normalized Bark key in request path, got %s", r.URL.Path)
 		}
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
 
-	service := NewNotificationService(notiRepo, prodRepo, masterRepo, userSettingsRepo, server.URL)
+	service := NewNotificationService(notiRepo, prodRepo, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
 
 	if err := service.CheckAndNotify(ctx); err != nil {
 		t.Fatalf("CheckAndNotify() error = %v", err)
@@ -257,7 +302,7 @@ func TestNotificationService_CheckAndNotifyOnlyNotifiesOncePerDay(t *testing.T)
 	}))
 	defer server.Close()
 
-	service := NewNotificationService(notiRepo, prodRepo, masterRepo, userSettingsRepo, server.URL)
+	service := NewNotificationService(notiRepo, prodRepo, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
 
 	if err := service.CheckAndNotify(ctx); err != nil {
 		t.Fatalf("first CheckAndNotify() error = %v", err)
@@ -270,3 +315,127 @@ func TestNotificationService_CheckAndNotifyOnlyNotifiesOncePerDay(t *testing.T)
 		t.Fatalf("expected only 1 Bark request in a single day, got %d", requestCount)
 	}
 }
+
+func TestNotificationService_CheckAndNotifyBackInStock(t *testing.T) {
+	ctx := context.Background()
+
+	soldOut := entity.SalesStatusSold
+	notiRepo := &stubNotificationRepository{
+		configs: []*entity.NotificationConfig{
+			{
+				ActivityID: "DT_restock",
+				UserID:     "client-123",
+				RuleType:   entity.RuleTypeBackInStock,
+				LastStatus: &soldOut,
+			},
+		},
+	}
+	masterRepo := &stubMasterProductRepository{
+		product: &entity.MasterProduct{
+			ID:            "DT_restock",
+			Region:        "广州",
+			Platform:      "DT",
+			StandardTitle: "烤鱼双人餐",
+			Price:         59.9,
+			Status:        entity.SalesStatusSold,
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{
+			UserID:  "client-123",
+			BarkKey: "DEVICE123",
+		},
+	}
+
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
+
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() while sold out error = %v", err)
+	}
+	if requestCount != 0 {
+		t.Fatalf("expected no Bark request while sold out, got %d", requestCount)
+	}
+
+	masterRepo.product.Status = entity.SalesStatusOnSale
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() after restock error = %v", err)
+	}
+	if requestCount != 1 {
+		t.Fatalf("expected 1 Bark request after restock, got %d", requestCount)
+	}
+	if got := notiRepo.configs[0].LastStatus; got == nil || *got != entity.SalesStatusOnSale {
+		t.Fatalf("expected last status to be tracked as on sale, got %v", got)
+	}
+}
+
+func TestNotificationService_BackInStockAlertsSuppressedRestockNextDay(t *testing.T) {
+	ctx := context.Background()
+
+	onSale := entity.SalesStatusOnSale
+	notifiedAt := time.Now()
+	config := &entity.NotificationConfig{
+		ActivityID:     "DT_restock",
+		UserID:         "client-123",
+		RuleType:       entity.RuleTypeBackInStock,
+		LastStatus:     &onSale,
+		LastNotifyTime: &notifiedAt,
+	}
+	notiRepo := &stubNotificationRepository{configs: []*entity.NotificationConfig{config}}
+	masterRepo := &stubMasterProductRepository{
+		product: &entity.MasterProduct{
+			ID:            "DT_restock",
+			Region:        "广州",
+			Platform:      "DT",
+			StandardTitle: "烤鱼双人餐",
+			Price:         59.9,
+			Status:        entity.SalesStatusSold,
+		},
+	}
+	userSettingsRepo := &stubUserSettingsRepository{
+		settings: &entity.UserSettings{UserID: "client-123", BarkKey: "DEVICE123"},
+	}
+
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, nil, external.NewBarkSender(server.URL), "")
+
+	// Sold out and restocked again on the day it already alerted
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() while sold out error = %v", err)
+	}
+	masterRepo.product.Status = entity.SalesStatusOnSale
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() after restock error = %v", err)
+	}
+	if requestCount != 0 {
+		t.Fatalf("expected no Bark request after the daily alert, got %d", requestCount)
+	}
+	if config.LastStatus == nil || *config.LastStatus != entity.SalesStatusSold {
+		t.Fatalf("expected the suppressed restock to stay untracked, got %v", config.LastStatus)
+	}
+
+	// Once the daily limit resets the restock is alerted
+	yesterday := notifiedAt.AddDate(0, 0, -1)
+	config.LastNotifyTime = &yesterday
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() next day error = %v", err)
+	}
+	if requestCount != 1 {
+		t.Fatalf("expected 1 Bark request once the daily limit resets, got %d", requestCount)
+	}
+	if config.LastStatus == nil || *config.LastStatus != entity.SalesStatusOnSale {
+		t.Fatalf("expected last status to be tracked as on sale, got %v", config.LastStatus)
+	}
+}
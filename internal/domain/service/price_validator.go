@@ -1,10 +1,13 @@
 package service
 
 import (
+	stderrors "errors"
 	"math"
+	"strconv"
 	"time"
 
 	"kbfood/internal/pkg/errors"
+	"kbfood/internal/pkg/metrics"
 )
 
 // PriceValidator implements Dutch auction model price validation
@@ -44,6 +47,18 @@ func NewPriceValidatorWithConfig(maxDrop, maxRise, min float64) *PriceValidator
 func (v *PriceValidator) ValidateUpdate(
 	oldPrice, newPrice float64,
 	lastUpdateTime time.Time,
+) (float64, error) {
+	price, err := v.validateUpdate(oldPrice, newPrice, lastUpdateTime)
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		metrics.PriceRejections.With(strconv.Itoa(int(appErr.Code))).Inc()
+	}
+	return price, err
+}
+
+func (v *PriceValidator) validateUpdate(
+	oldPrice, newPrice float64,
+	lastUpdateTime time.Time,
 ) (float64, error) {
 	// Validate inputs
 	if math.IsNaN(oldPrice) || math.IsNaN(newPrice) {
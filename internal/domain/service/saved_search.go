@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	applog "kbfood/internal/pkg/logger"
+)
+
+// UserNotifier delivers a message to a user
+type UserNotifier interface {
+	NotifyUser(ctx context.Context, userID, message string) bool
+}
+
+// SavedSearchService manages saved searches and alerts users on new matching products
+type SavedSearchService struct {
+	searchRepo repository.SavedSearchRepository
+	masterRepo repository.MasterProductRepository
+	notifier   UserNotifier
+}
+
+// NewSavedSearchService creates a new saved search service
+func NewSavedSearchService(
+	searchRepo repository.SavedSearchRepository,
+	masterRepo repository.MasterProductRepository,
+	notifier UserNotifier,
+) *SavedSearchService {
+	return &SavedSearchService{
+		searchRepo: searchRepo,
+		masterRepo: masterRepo,
+		notifier:   notifier,
+	}
+}
+
+// List lists the saved searches of a user
+func (s *SavedSearchService) List(ctx context.Context, userID string) ([]*entity.SavedSearch, error) {
+	return s.searchRepo.ListByUser(ctx, userID)
+}
+
+// Get finds a saved search owned by the user
+func (s *SavedSearchService) Get(ctx context.Context, userID string, id int64) (*entity.SavedSearch, error) {
+	return s.searchRepo.FindByID(ctx, id, userID)
+}
+
+// Create creates a saved search. Products that already match are recorded
+// without alerting so that only products appearing later trigger a push.
+func (s *SavedSearchService) Create(ctx context.Context, search *entity.SavedSearch) error {
+	if err := s.searchRepo.Create(ctx, search); err != nil {
+		return err
+	}
+	return s.seedMatches(ctx, s.searchRepo, search)
+}
+
+// Update updates the criteria of a saved search and records the products
+// already matching the new criteria
+func (s *SavedSearchService) Update(ctx context.Context, search *entity.SavedSearch) error {
+	if err := s.searchRepo.Update(ctx, search); err != nil {
+		return err
+	}
+	return s.seedMatches(ctx, s.searchRepo, search)
+}
+
+// Delete deletes a saved search
+func (s *SavedSearchService) Delete(ctx context.Context, userID string, id int64) error {
+	return s.searchRepo.Delete(ctx, id, userID)
+}
+
+// Evaluate checks the given master products against all saved searches and
+// notifies the owner the first time a product matches a search.
+// Returns the number of notifications sent.
+func (s *SavedSearchService) Evaluate(ctx context.Context, masters []*entity.MasterProduct) int {
+	logger := applog.LoggerFromContext(ctx)
+
+	if len(masters) == 0 {
+		return 0
+	}
+
+	searches, err := s.searchRepo.ListAll(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list saved searches")
+		return 0
+	}
+
+	sent := 0
+	for _, search := range searches {
+		for _, master := range masters {
+			if !search.Matches(master) {
+				continue
+			}
+
+			isNew, err := s.searchRepo.RecordMatch(ctx, search.ID, master.ID)
+			if err != nil {
+				logger.Error().Err(err).
+					Int64("searchId", search.ID).
+					Str("activityId", master.ID).
+					Msg("failed to record saved search match")
+				continue
+			}
+			if !isNew {
+				continue
+			}
+
+			if s.notifier != nil && s.notifier.NotifyUser(ctx, search.UserID, savedSearchMessage(search, master)) {
+				sent++
+			}
+		}
+	}
+
+	return sent
+}
+
+// seedMatches records the current matches of a search without notifying,
+// through searchRepo so that it can join the transaction of the caller
+func (s *SavedSearchService) seedMatches(ctx context.Context, searchRepo repository.SavedSearchRepository, search *entity.SavedSearch) error {
+	if s.masterRepo == nil {
+		return nil
+	}
+
+	masters, err := s.masterRepo.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("list masters: %w", err)
+	}
+
+	for _, master := range masters {
+		if !search.Matches(master) {
+			continue
+		}
+		if _, err := searchRepo.RecordMatch(ctx, search.ID, master.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func savedSearchMessage(search *entity.SavedSearch, master *entity.MasterProduct) string {
+	platform := master.Platform
+	if platform == "" {
+		platform = entity.DefaultPlatform
+	}
+	return fmt.Sprintf("【订阅：%s】%s %s ¥%.2f %s",
+		search.Name,
+		platform,
+		master.Region,
+		master.Price,
+		master.StandardTitle,
+	)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+)
+
+type stubSavedSearchRepository struct {
+	searches  []*entity.SavedSearch
+	matches   map[string]bool
+	listCalls int
+}
+
+func (s *stubSavedSearchRepository) FindByID(ctx context.Context, id int64, userID string) (*entity.SavedSearch, error) {
+	for _, search := range s.searches {
+		if search.ID == id && search.UserID == userID {
+			return search, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubSavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*entity.SavedSearch, error) {
+	return s.searches, nil
+}
+
+func (s *stubSavedSearchRepository) ListAll(ctx context.Context) ([]*entity.SavedSearch, error) {
+	s.listCalls++
+	return s.searches, nil
+}
+
+func (s *stubSavedSearchRepository) Create(ctx context.Context, search *entity.SavedSearch) error {
+	search.ID = int64(len(s.searches) + 1)
+	s.searches = append(s.searches, search)
+	return nil
+}
+
+func (s *stubSavedSearchRepository) Update(ctx context.Context, search *entity.SavedSearch) error {
+	return nil
+}
+
+func (s *stubSavedSearchRepository) Delete(ctx context.Context, id int64, userID string) error {
+	return nil
+}
+
+func (s *stubSavedSearchRepository) RecordMatch(ctx context.Context, searchID int64, activityID string) (bool, error) {
+	if s.matches == nil {
+		s.matches = make(map[string]bool)
+	}
+	key := fmt.Sprintf("%d/%s", searchID, activityID)
+	if s.matches[key] {
+		return false, nil
+	}
+	s.matches[key] = true
+	return true, nil
+}
+
+type stubUserNotifier struct {
+	messages map[string][]string
+}
+
+func (s *stubUserNotifier) NotifyUser(ctx context.Context, userID, message string) bool {
+	if s.messages == nil {
+		s.messages = make(map[string][]string)
+	}
+	s.messages[userID] = append(s.messages[userID], message)
+	return true
+}
+
+type listingMasterProductRepository struct {
+	stubMasterProductRepository
+	products []*entity.MasterProduct
+}
+
+func (s *listingMasterProductRepository) ListAll(ctx context.Context) ([]*entity.MasterProduct, error) {
+	return s.products, nil
+}
+
+func TestSavedSearchService_EvaluateNotifiesOncePerProduct(t *testing.T) {
+	ctx := context.Background()
+	searchRepo := &stubSavedSearchRepository{
+		searches: []*entity.SavedSearch{
+			{ID: 1, UserID: "user-a", Name: "拿铁", Keyword: "拿铁"},
+			{ID: 2, UserID: "user-b", Name: "北京", Region: "北京"},
+		},
+	}
+	notifier := &stubUserNotifier{}
+	svc := NewSavedSearchService(searchRepo, nil, notifier)
+
+	masters := []*entity.MasterProduct{
+		{ID: "DT_1", Region: "上海", StandardTitle: "拿铁套餐", Price: 9.9, Status: entity.SalesStatusOnSale},
+		{ID: "DT_2", Region: "上海", StandardTitle: "美式", Price: 8.8, Status: entity.SalesStatusOnSale},
+	}
+
+	if sent := svc.Evaluate(ctx, masters); sent != 1 {
+		t.Fatalf("expected 1 notification, got %d", sent)
+	}
+	if len(notifier.messages["user-a"]) != 1 {
+		t.Fatalf("expected user-a to be notified once, got %v", notifier.messages)
+	}
+	if len(notifier.messages["user-b"]) != 0 {
+		t.Fatalf("expected user-b not to be notified, got %v", notifier.messages["user-b"])
+	}
+
+	if sent := svc.Evaluate(ctx, masters); sent != 0 {
+		t.Fatalf("expected repeated evaluation to be deduplicated, got %d", sent)
+	}
+}
+
+func TestSavedSearchService_CreateSkipsExistingMatches(t *testing.T) {
+	ctx := context.Background()
+	existing := &entity.MasterProduct{ID: "DT_1", Region: "上海", StandardTitle: "拿铁套餐", Price: 9.9, Status: entity.SalesStatusOnSale}
+	searchRepo := &stubSavedSearchRepository{}
+	notifier := &stubUserNotifier{}
+	svc := NewSavedSearchService(searchRepo, &listingMasterProductRepository{
+		products: []*entity.MasterProduct{existing},
+	}, notifier)
+
+	if err := svc.Create(ctx, &entity.SavedSearch{UserID: "user-a", Keyword: "拿铁"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	newcomer := &entity.MasterProduct{ID: "DT_2", Region: "上海", StandardTitle: "燕麦拿铁", Price: 12, Status: entity.SalesStatusOnSale}
+	if sent := svc.Evaluate(ctx, []*entity.MasterProduct{existing, newcomer}); sent != 1 {
+		t.Fatalf("expected only the new product to notify, got %d", sent)
+	}
+}
+
+func TestDataCleaningService_ProcessIncomingItemsEvaluatesSavedSearchesOncePerBatch(t *testing.T) {
+	ctx := context.Background()
+	searchRepo := &stubSavedSearchRepository{
+		searches: []*entity.SavedSearch{{ID: 1, UserID: "user-a", Keyword: "拿铁", MaxPrice: 9}},
+	}
+	notifier := &stubUserNotifier{}
+	masterRepo := &regionMasterProductRepository{
+		stubMasterProductRepository{
+			product: &entity.MasterProduct{ID: "DT_1", Region: "上海", StandardTitle: "拿铁套餐", Price: 9.9, Status: entity.SalesStatusOnSale},
+		},
+	}
+	cleaning := NewDataCleaningService(masterRepo, nil, nil, nil, nil, NewSavedSearchService(searchRepo, nil, notifier), nil)
+
+	items := []*entity.DTInputDTO{
+		{Title: "拿铁套餐", Price: 8.8, Status: entity.SalesStatusOnSale, Region: "上海"},
+		{Title: "拿铁套餐", Price: 8.8, Status: entity.SalesStatusOnSale, Region: "上海"},
+		nil,
+	}
+	promoted, errs := cleaning.ProcessIncomingItems(ctx, items)
+	if errs[0] != nil || errs[1] != nil || errs[2] == nil {
+		t.Fatalf("ProcessIncomingItems() errors = %v, want only the nil item to fail", errs)
+	}
+	if promoted[0] == nil || promoted[1] == nil {
+		t.Fatalf("expected both items to match the master, got %v", promoted)
+	}
+	if searchRepo.listCalls != 1 {
+		t.Errorf("expected saved searches to be listed once per batch, got %d", searchRepo.listCalls)
+	}
+	if len(notifier.messages["user-a"]) != 1 {
+		t.Errorf("expected user-a to be notified once, got %v", notifier.messages)
+	}
+
+	// A batch without price or status changes does not evaluate saved searches
+	cleaning.ProcessIncomingItems(ctx, items[:2])
+	if searchRepo.listCalls != 1 {
+		t.Errorf("expected unchanged masters to skip saved searches, got %d list calls", searchRepo.listCalls)
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
+)
+
+// DefaultStreamBufferSize is the number of recent events kept for resuming clients
+const DefaultStreamBufferSize = 1000
+
+// streamSubscriberBuffer is the number of events queued for a subscriber
+// before it counts as too slow and is disconnected
+const streamSubscriberBuffer = 64
+
+// StreamService fans out product events to live update subscribers. It keeps
+// the most recent events in a bounded buffer so that reconnecting clients can
+// resume after the last event they received.
+//
+// Event IDs are "<epoch>-<sequence>", where the epoch identifies this process,
+// so IDs from before a restart are recognized as not resumable.
+type StreamService struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         int64
+	buffer      []entity.StreamEvent // ring buffer of the most recent events
+	start       int                  // index of the oldest buffered event
+	count       int
+	subscribers map[*StreamSubscription]struct{}
+	closed      bool
+}
+
+// NewStreamService creates a stream that buffers up to bufferSize events
+func NewStreamService(bufferSize int) *StreamService {
+	if bufferSize <= 0 {
+		bufferSize = DefaultStreamBufferSize
+	}
+	return &StreamService{
+		epoch:       strconv.FormatInt(time.Now().UnixMilli(), 36),
+		buffer:      make([]entity.StreamEvent, bufferSize),
+		subscribers: make(map[*StreamSubscription]struct{}),
+	}
+}
+
+// Subscribe registers the stream for product events on the bus
+func (s *StreamService) Subscribe(bus *event.Bus) {
+	bus.Subscribe(event.NamePriceChanged, s.HandleProductEvent)
+	bus.Subscribe(event.NameStatusChanged, s.HandleProductEvent)
+	bus.Subscribe(event.NameProductCreated, s.HandleProductEvent)
+}
+
+// HandleProductEvent publishes a price, status or new product event to subscribers
+func (s *StreamService) HandleProductEvent(ctx context.Context, e event.Event) {
+	var update entity.StreamEvent
+	switch ev := e.(type) {
+	case event.PriceChanged:
+		update = entity.StreamEvent{
+			Type:       entity.StreamEventPrice,
+			ActivityID: ev.ActivityID,
+			Region:     ev.Region,
+			Platform:   streamPlatform(ev.Platform),
+			Title:      ev.Title,
+			Price:      &ev.NewPrice,
+			OldPrice:   &ev.OldPrice,
+			OccurredAt: ev.OccurredAt,
+		}
+	case event.StatusChanged:
+		update = entity.StreamEvent{
+			Type:       entity.StreamEventStatus,
+			ActivityID: ev.ActivityID,
+			Region:     ev.Region,
+			Platform:   streamPlatform(ev.Platform),
+			Title:      ev.Title,
+			Status:     &ev.NewStatus,
+			OldStatus:  &ev.OldStatus,
+			OccurredAt: ev.OccurredAt,
+		}
+	case event.ProductCreated:
+		update = entity.StreamEvent{
+			Type:       entity.StreamEventNewProduct,
+			ActivityID: ev.ActivityID,
+			Region:     ev.Region,
+			Platform:   streamPlatform(ev.Platform),
+			Title:      ev.Title,
+			Price:      &ev.Price,
+			Status:     &ev.Status,
+			OccurredAt: ev.OccurredAt,
+		}
+	default:
+		return
+	}
+
+	s.Publish(update)
+}
+
+// Publish assigns the next ID to the event, buffers it and delivers it to
+// subscribers. Subscribers whose queue is full are disconnected; they resume
+// from the buffer when they reconnect.
+func (s *StreamService) Publish(e entity.StreamEvent) entity.StreamEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	e.ID = s.eventID(s.seq)
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+
+	if s.count < len(s.buffer) {
+		s.buffer[(s.start+s.count)%len(s.buffer)] = e
+		s.count++
+	} else {
+		s.buffer[s.start] = e
+		s.start = (s.start + 1) % len(s.buffer)
+	}
+
+	for sub := range s.subscribers {
+		select {
+		case sub.events <- e:
+		default:
+			s.removeLocked(sub)
+		}
+	}
+	return e
+}
+
+// Listen subscribes to new events. Buffered events after lastEventID are
+// returned to be sent first. If lastEventID is set but the events after it are
+// no longer buffered or it is from another process, a single reset event is
+// returned instead.
+func (s *StreamService) Listen(lastEventID string) ([]entity.StreamEvent, *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &StreamSubscription{service: s, events: make(chan entity.StreamEvent, streamSubscriberBuffer)}
+	if s.closed {
+		close(sub.events)
+	} else {
+		s.subscribers[sub] = struct{}{}
+	}
+
+	if lastEventID == "" {
+		return nil, sub
+	}
+
+	seq, ok := s.parseEventID(lastEventID)
+	oldest := s.seq - int64(s.count) + 1
+	if !ok || seq > s.seq || seq < oldest-1 {
+		reset := entity.StreamEvent{
+			ID:         s.eventID(s.seq),
+			Type:       entity.StreamEventReset,
+			OccurredAt: time.Now(),
+		}
+		return []entity.StreamEvent{reset}, sub
+	}
+
+	backlog := make([]entity.StreamEvent, 0, s.seq-seq)
+	for i := seq - oldest + 1; i < int64(s.count); i++ {
+		backlog = append(backlog, s.buffer[(s.start+int(i))%len(s.buffer)])
+	}
+	return backlog, sub
+}
+
+// Close disconnects every subscriber, e.g. when the server shuts down
+func (s *StreamService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subscribers {
+		s.removeLocked(sub)
+	}
+}
+
+// SubscriberCount returns the number of connected subscribers
+func (s *StreamService) SubscriberCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers)
+}
+
+func (s *StreamService) removeLocked(sub *StreamSubscription) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
+
+func (s *StreamService) eventID(seq int64) string {
+	return s.epoch + "-" + strconv.FormatInt(seq, 10)
+}
+
+// parseEventID returns the sequence of an event ID issued by this process
+func (s *StreamService) parseEventID(id string) (int64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != s.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(seq, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// streamPlatform returns the platform shown for a product, defaulting like saved searches do
+func streamPlatform(platform string) string {
+	if platform == "" {
+		return entity.DefaultPlatform
+	}
+	return platform
+}
+
+// StreamSubscription receives the events published after it was created
+type StreamSubscription struct {
+	service *StreamService
+	events  chan entity.StreamEvent
+}
+
+// Events returns the subscriber's events. The channel is closed when the
+// subscriber falls behind, the stream closes or the subscription is closed.
+func (sub *StreamSubscription) Events() <-chan entity.StreamEvent {
+	return sub.events
+}
+
+// Close stops the subscription
+func (sub *StreamSubscription) Close() {
+	sub.service.mu.Lock()
+	defer sub.service.mu.Unlock()
+	sub.service.removeLocked(sub)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/event"
+)
+
+func TestStreamService_DeliversBusEvents(t *testing.T) {
+	stream := NewStreamService(10)
+	bus := event.NewBus()
+	defer bus.Close()
+	stream.Subscribe(bus)
+
+	_, sub := stream.Listen("")
+	defer sub.Close()
+
+	bus.Publish(context.Background(), event.PriceChanged{ActivityID: "DT_1", Region: "广州", OldPrice: 30, NewPrice: 25})
+	bus.Publish(context.Background(), event.StatusChanged{ActivityID: "DT_1", Region: "广州", Platform: "探探糖", OldStatus: 1, NewStatus: 0})
+	bus.Publish(context.Background(), event.ProductCreated{ActivityID: "DT_2", Region: "佛山", Price: 9.9, Status: 1})
+
+	price := <-sub.Events()
+	if price.Type != entity.StreamEventPrice || *price.Price != 25 || *price.OldPrice != 30 || price.Platform != entity.DefaultPlatform {
+		t.Errorf("price event = %+v", price)
+	}
+	status := <-sub.Events()
+	if status.Type != entity.StreamEventStatus || *status.Status != 0 || *status.OldStatus != 1 || status.Price != nil {
+		t.Errorf("status event = %+v", status)
+	}
+	created := <-sub.Events()
+	if created.Type != entity.StreamEventNewProduct || created.ActivityID != "DT_2" || *created.Price != 9.9 || *created.Status != 1 {
+		t.Errorf("new product event = %+v", created)
+	}
+	if price.ID == status.ID || status.ID == created.ID || price.OccurredAt.IsZero() {
+		t.Errorf("event IDs = %s, %s, %s", price.ID, status.ID, created.ID)
+	}
+}
+
+func TestStreamService_ListenResumesFromBuffer(t *testing.T) {
+	stream := NewStreamService(3)
+	var published []entity.StreamEvent
+	for _, id := range []string{"DT_1", "DT_2", "DT_3", "DT_4", "DT_5"} {
+		published = append(published, stream.Publish(entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: id}))
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string // activity IDs, or "reset"
+	}{
+		{"new client", "", nil},
+		{"up to date", published[4].ID, []string{}},
+		{"missed two", published[2].ID, []string{"DT_4", "DT_5"}},
+		{"oldest buffered", published[1].ID, []string{"DT_3", "DT_4", "DT_5"}},
+		{"fell out of buffer", published[0].ID, []string{"reset"}},
+		{"before restart", "abc-4", []string{"reset"}},
+		{"ahead of stream", published[4].ID + "0", []string{"reset"}},
+		{"malformed", "garbage", []string{"reset"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backlog, sub := stream.Listen(tt.lastEventID)
+			defer sub.Close()
+
+			var got []string
+			for _, e := range backlog {
+				if e.Type == entity.StreamEventReset {
+					got = append(got, "reset")
+					if e.ID != published[4].ID {
+						t.Errorf("reset ID = %s, want the latest %s", e.ID, published[4].ID)
+					}
+					continue
+				}
+				got = append(got, e.ActivityID)
+			}
+			if len(got) != len(tt.want) || (tt.want == nil) != (backlog == nil) {
+				t.Fatalf("backlog = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("backlog = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamService_DisconnectsSlowSubscribers(t *testing.T) {
+	stream := NewStreamService(10)
+	_, slow := stream.Listen("")
+	_, fast := stream.Listen("")
+	defer fast.Close()
+
+	for i := 0; i < streamSubscriberBuffer+1; i++ {
+		stream.Publish(entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: "DT_1"})
+		<-fast.Events()
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received != streamSubscriberBuffer {
+		t.Errorf("slow subscriber received %d events before disconnecting, want %d", received, streamSubscriberBuffer)
+	}
+	if n := stream.SubscriberCount(); n != 1 {
+		t.Errorf("SubscriberCount() = %d, want 1", n)
+	}
+	slow.Close()
+
+	stream.Close()
+	if _, ok := <-fast.Events(); ok {
+		t.Error("subscription still open after Close")
+	}
+	_, late := stream.Listen("")
+	if _, ok := <-late.Events(); ok {
+		t.Error("subscription opened after Close is not closed")
+	}
+	late.Close()
+}
+
+func TestStreamFilter_Matches(t *testing.T) {
+	price := entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: "DT_1", Region: "广州", Platform: "探探糖"}
+	tests := []struct {
+		name   string
+		filter entity.StreamFilter
+		event  entity.StreamEvent
+		want   bool
+	}{
+		{"no filter", entity.StreamFilter{}, price, true},
+		{"region", entity.StreamFilter{Region: "广州"}, price, true},
+		{"other region", entity.StreamFilter{Region: "佛山"}, price, false},
+		{"other platform", entity.StreamFilter{Platform: "小蚕"}, price, false},
+		{"watched", entity.StreamFilter{WatchedOnly: true, Watched: map[string]bool{"DT_1": true}}, price, true},
+		{"not watched", entity.StreamFilter{WatchedOnly: true}, price, false},
+		{"reset", entity.StreamFilter{Region: "佛山", WatchedOnly: true}, entity.StreamEvent{Type: entity.StreamEventReset}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
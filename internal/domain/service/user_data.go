@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// UserDataService exports and imports everything a user has configured
+type UserDataService struct {
+	settingsRepo       repository.UserSettingsRepository
+	notificationRepo   repository.NotificationRepository
+	blockedRepo        repository.BlockedRepository
+	savedSearchRepo    repository.SavedSearchRepository
+	annotationRepo     repository.ProductAnnotationRepository
+	transactor         repository.UserDataTransactor
+	savedSearchService *SavedSearchService
+}
+
+// NewUserDataService creates a new user data service
+func NewUserDataService(
+	settingsRepo repository.UserSettingsRepository,
+	notificationRepo repository.NotificationRepository,
+	blockedRepo repository.BlockedRepository,
+	savedSearchRepo repository.SavedSearchRepository,
+	annotationRepo repository.ProductAnnotationRepository,
+	transactor repository.UserDataTransactor,
+	savedSearchService *SavedSearchService,
+) *UserDataService {
+	return &UserDataService{
+		settingsRepo:       settingsRepo,
+		notificationRepo:   notificationRepo,
+		blockedRepo:        blockedRepo,
+		savedSearchRepo:    savedSearchRepo,
+		annotationRepo:     annotationRepo,
+		transactor:         transactor,
+		savedSearchService: savedSearchService,
+	}
+}
+
+// Export returns the data of a user as a versioned document
+func (s *UserDataService) Export(ctx context.Context, userID string) (*entity.UserDataExport, error) {
+	doc := &entity.UserDataExport{
+		Version:         entity.UserDataVersion,
+		ExportedAt:      time.Now(),
+		Notifications:   []entity.UserDataNotification{},
+		BlockedProducts: []string{},
+		SavedSearches:   []entity.UserDataSavedSearch{},
+		Annotations:     []entity.UserDataAnnotation{},
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings != nil {
+		prefs := settings.Preferences
+		doc.Settings = entity.UserDataSettings{
+			BarkKey:     settings.BarkKey,
+			Preferences: &prefs,
+		}
+	}
+
+	configs, err := s.notificationRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		doc.Notifications = append(doc.Notifications, entity.UserDataNotification{
+			ActivityID:     config.ActivityID,
+			RuleType:       config.EffectiveRuleType(),
+			TargetPrice:    config.TargetPrice,
+			DropPercent:    config.DropPercent,
+			DropAmount:     config.DropAmount,
+			ReferencePrice: config.ReferencePrice,
+			LastStatus:     config.LastStatus,
+			SnoozeUntil:    config.SnoozeUntil,
+		})
+	}
+
+	blocked, err := s.blockedRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	doc.BlockedProducts = append(doc.BlockedProducts, blocked...)
+
+	searches, err := s.savedSearchRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, search := range searches {
+		doc.SavedSearches = append(doc.SavedSearches, entity.UserDataSavedSearch{
+			Name:     search.Name,
+			Keyword:  search.Keyword,
+			Region:   search.Region,
+			Platform: search.Platform,
+			MaxPrice: search.MaxPrice,
+		})
+	}
+
+	annotations, err := s.annotationRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, annotation := range annotations {
+		doc.Annotations = append(doc.Annotations, entity.UserDataAnnotation{
+			ActivityID: annotation.ActivityID,
+			Favorite:   annotation.Favorite,
+			Tags:       annotation.Tags,
+			Note:       annotation.Note,
+		})
+	}
+
+	return doc, nil
+}
+
+// Import writes an exported document under the user ID. In merge mode existing
+// data is kept and imported entries win on conflict; in replace mode the existing
+// data is deleted first. The document must have been validated. All writes run
+// in one transaction, so a failed import leaves the data of the user unchanged.
+func (s *UserDataService) Import(ctx context.Context, userID string, doc *entity.UserDataExport, mode string) (*entity.UserDataImportSummary, error) {
+	var summary *entity.UserDataImportSummary
+	err := s.transactor.InTx(ctx, func(repos repository.UserDataRepositories) error {
+		var err error
+		summary, err = s.importData(ctx, repos, userID, doc, mode)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("userId", userID).
+		Str("mode", mode).
+		Int("notifications", summary.Notifications).
+		Int("blockedProducts", summary.BlockedProducts).
+		Int("savedSearches", summary.SavedSearches).
+		Int("annotations", summary.Annotations).
+		Int("skipped", summary.Skipped).
+		Msg("User data imported")
+
+	return summary, nil
+}
+
+// importData writes the document through the repositories of the import transaction
+func (s *UserDataService) importData(ctx context.Context, repos repository.UserDataRepositories, userID string, doc *entity.UserDataExport, mode string) (*entity.UserDataImportSummary, error) {
+	summary := &entity.UserDataImportSummary{Mode: mode}
+
+	if mode == entity.ImportModeReplace {
+		if err := clearUserData(ctx, repos, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := importSettings(ctx, repos.Settings, userID, doc.Settings, mode, summary); err != nil {
+		return nil, err
+	}
+
+	for _, n := range doc.Notifications {
+		if err := repos.Notifications.Upsert(ctx, n.ToConfig(userID)); err != nil {
+			return nil, err
+		}
+		summary.Notifications++
+	}
+
+	for _, activityID := range doc.BlockedProducts {
+		activityID = strings.TrimSpace(activityID)
+		exists, err := repos.Blocked.Exists(ctx, activityID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			summary.Skipped++
+			continue
+		}
+		if err := repos.Blocked.Create(ctx, activityID, userID); err != nil {
+			return nil, err
+		}
+		summary.BlockedProducts++
+	}
+
+	existing, err := repos.SavedSearches.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, imported := range doc.SavedSearches {
+		search := imported.ToSavedSearch(userID)
+		if hasSameCriteria(existing, search) {
+			summary.Skipped++
+			continue
+		}
+		if err := repos.SavedSearches.Create(ctx, search); err != nil {
+			return nil, err
+		}
+		if err := s.savedSearchService.seedMatches(ctx, repos.SavedSearches, search); err != nil {
+			return nil, err
+		}
+		existing = append(existing, search)
+		summary.SavedSearches++
+	}
+
+	for _, a := range doc.Annotations {
+		annotation := a.ToAnnotation(userID)
+		if annotation.IsEmpty() {
+			continue
+		}
+		if err := repos.Annotations.Upsert(ctx, annotation); err != nil {
+			return nil, err
+		}
+		summary.Annotations++
+	}
+
+	return summary, nil
+}
+
+// importSettings writes the Bark key and preferences. On merge an empty Bark key
+// or missing preferences keep the current values.
+func importSettings(ctx context.Context, settingsRepo repository.UserSettingsRepository, userID string, settings entity.UserDataSettings, mode string, summary *entity.UserDataImportSummary) error {
+	barkKey := strings.TrimSpace(settings.BarkKey)
+	if barkKey != "" || mode == entity.ImportModeReplace {
+		if err := settingsRepo.Upsert(ctx, &entity.UserSettings{UserID: userID, BarkKey: barkKey}); err != nil {
+			return err
+		}
+		summary.Settings = true
+	}
+
+	prefs := settings.Preferences
+	if prefs == nil && mode == entity.ImportModeReplace {
+		prefs = &entity.NotificationPreferences{}
+	}
+	if prefs != nil {
+		if err := settingsRepo.UpdatePreferences(ctx, userID, *prefs); err != nil {
+			return err
+		}
+		summary.Settings = true
+	}
+	return nil
+}
+
+// clearUserData deletes the notification rules, blocked products, saved searches
+// and annotations of a user
+func clearUserData(ctx context.Context, repos repository.UserDataRepositories, userID string) error {
+	configs, err := repos.Notifications.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		if err := repos.Notifications.Delete(ctx, config.ActivityID, userID); err != nil {
+			return err
+		}
+	}
+
+	blocked, err := repos.Blocked.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, activityID := range blocked {
+		if err := repos.Blocked.Delete(ctx, activityID, userID); err != nil {
+			return err
+		}
+	}
+
+	searches, err := repos.SavedSearches.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, search := range searches {
+		if err := repos.SavedSearches.Delete(ctx, search.ID, userID); err != nil {
+			return err
+		}
+	}
+
+	annotations, err := repos.Annotations.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, annotation := range annotations {
+		if err := repos.Annotations.Delete(ctx, annotation.ActivityID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasSameCriteria returns true if one of the searches has the same criteria as search
+func hasSameCriteria(searches []*entity.SavedSearch, search *entity.SavedSearch) bool {
+	for _, s := range searches {
+		if s.Keyword == search.Keyword && s.Region == search.Region &&
+			s.Platform == search.Platform && s.MaxPrice == search.MaxPrice {
+			return true
+		}
+	}
+	return false
+}
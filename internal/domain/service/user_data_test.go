@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+)
+
+type memoryNotificationRepository struct {
+	stubNotificationRepository
+}
+
+func (s *memoryNotificationRepository) Upsert(ctx context.Context, config *entity.NotificationConfig) error {
+	for i, existing := range s.configs {
+		if existing.ActivityID == config.ActivityID && existing.UserID == config.UserID {
+			s.configs[i] = config
+			return nil
+		}
+	}
+	s.configs = append(s.configs, config)
+	return nil
+}
+
+func (s *memoryNotificationRepository) Delete(ctx context.Context, activityID string, userID string) error {
+	kept := s.configs[:0]
+	for _, config := range s.configs {
+		if config.ActivityID != activityID || config.UserID != userID {
+			kept = append(kept, config)
+		}
+	}
+	s.configs = kept
+	return nil
+}
+
+type memoryBlockedRepository struct {
+	blocked map[string][]string
+}
+
+func (s *memoryBlockedRepository) Exists(ctx context.Context, activityID string, userID string) (bool, error) {
+	for _, id := range s.blocked[userID] {
+		if id == activityID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryBlockedRepository) Create(ctx context.Context, activityID string, userID string) error {
+	if s.blocked == nil {
+		s.blocked = make(map[string][]string)
+	}
+	s.blocked[userID] = append(s.blocked[userID], activityID)
+	return nil
+}
+
+func (s *memoryBlockedRepository) Delete(ctx context.Context, activityID string, userID string) error {
+	var kept []string
+	for _, id := range s.blocked[userID] {
+		if id != activityID {
+			kept = append(kept, id)
+		}
+	}
+	s.blocked[userID] = kept
+	return nil
+}
+
+func (s *memoryBlockedRepository) List(ctx context.Context, userID string) ([]string, error) {
+	return s.blocked[userID], nil
+}
+
+type memorySavedSearchRepository struct {
+	stubSavedSearchRepository
+}
+
+func (s *memorySavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*entity.SavedSearch, error) {
+	var result []*entity.SavedSearch
+	for _, search := range s.searches {
+		if search.UserID == userID {
+			result = append(result, search)
+		}
+	}
+	return result, nil
+}
+
+func (s *memorySavedSearchRepository) Delete(ctx context.Context, id int64, userID string) error {
+	kept := s.searches[:0]
+	for _, search := range s.searches {
+		if search.ID != id || search.UserID != userID {
+			kept = append(kept, search)
+		}
+	}
+	s.searches = kept
+	return nil
+}
+
+type memoryProductAnnotationRepository struct {
+	annotations []*entity.ProductAnnotation
+}
+
+func (s *memoryProductAnnotationRepository) Find(ctx context.Context, activityID string, userID string) (*entity.ProductAnnotation, error) {
+	for _, annotation := range s.annotations {
+		if annotation.ActivityID == activityID && annotation.UserID == userID {
+			return annotation, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryProductAnnotationRepository) ListByUser(ctx context.Context, userID string) ([]*entity.ProductAnnotation, error) {
+	var result []*entity.ProductAnnotation
+	for _, annotation := range s.annotations {
+		if annotation.UserID == userID {
+			result = append(result, annotation)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryProductAnnotationRepository) Upsert(ctx context.Context, annotation *entity.ProductAnnotation) error {
+	for i, existing := range s.annotations {
+		if existing.ActivityID == annotation.ActivityID && existing.UserID == annotation.UserID {
+			s.annotations[i] = annotation
+			return nil
+		}
+	}
+	s.annotations = append(s.annotations, annotation)
+	return nil
+}
+
+func (s *memoryProductAnnotationRepository) Delete(ctx context.Context, activityID string, userID string) error {
+	kept := s.annotations[:0]
+	for _, annotation := range s.annotations {
+		if annotation.ActivityID != activityID || annotation.UserID != userID {
+			kept = append(kept, annotation)
+		}
+	}
+	s.annotations = kept
+	return nil
+}
+
+// memoryUserDataTransactor runs imports on the in-memory repositories
+type memoryUserDataTransactor struct {
+	repos repository.UserDataRepositories
+}
+
+func (s *memoryUserDataTransactor) InTx(ctx context.Context, fn func(repos repository.UserDataRepositories) error) error {
+	return fn(s.repos)
+}
+
+func newTestUserDataService(settings *stubUserSettingsRepository, notifications *memoryNotificationRepository,
+	blocked *memoryBlockedRepository, searches *memorySavedSearchRepository,
+	annotations *memoryProductAnnotationRepository) *UserDataService {
+	searchService := NewSavedSearchService(searches, &stubMasterProductRepository{}, &stubUserNotifier{})
+	transactor := &memoryUserDataTransactor{repos: repository.UserDataRepositories{
+		Settings:      settings,
+		Notifications: notifications,
+		Blocked:       blocked,
+		SavedSearches: searches,
+		Annotations:   annotations,
+	}}
+	return NewUserDataService(settings, notifications, blocked, searches, annotations, transactor, searchService)
+}
+
+func TestUserDataService_ExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	source := newTestUserDataService(
+		&stubUserSettingsRepository{settings: &entity.UserSettings{
+			UserID:      "old",
+			BarkKey:     "BARK",
+			Preferences: entity.NotificationPreferences{QuietStart: "22:00", QuietEnd: "08:00"},
+		}},
+		&memoryNotificationRepository{stubNotificationRepository{configs: []*entity.NotificationConfig{
+			{ActivityID: "DT_1", UserID: "old", TargetPrice: 9.9},
+		}}},
+		&memoryBlockedRepository{blocked: map[string][]string{"old": {"DT_2"}}},
+		&memorySavedSearchRepository{stubSavedSearchRepository{searches: []*entity.SavedSearch{
+			{ID: 1, UserID: "old", Name: "咖啡", Keyword: "拿铁"},
+		}}},
+		&memoryProductAnnotationRepository{annotations: []*entity.ProductAnnotation{
+			{ActivityID: "DT_3", UserID: "old", Favorite: true, Tags: []string{"午餐"}, Note: "份量偏小"},
+		}},
+	)
+
+	doc, err := source.Export(ctx, "old")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("exported document is invalid: %v", err)
+	}
+
+	settings := &stubUserSettingsRepository{}
+	notifications := &memoryNotificationRepository{}
+	blocked := &memoryBlockedRepository{blocked: map[string][]string{"new": {"DT_2"}}}
+	searches := &memorySavedSearchRepository{}
+	annotations := &memoryProductAnnotationRepository{}
+	target := newTestUserDataService(settings, notifications, blocked, searches, annotations)
+
+	summary, err := target.Import(ctx, "new", doc, entity.ImportModeMerge)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if !summary.Settings || summary.Notifications != 1 || summary.BlockedProducts != 0 ||
+		summary.SavedSearches != 1 || summary.Annotations != 1 || summary.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if settings.settings == nil || settings.settings.BarkKey != "BARK" || settings.settings.Preferences.QuietStart != "22:00" {
+		t.Fatalf("settings not imported: %+v", settings.settings)
+	}
+	if len(notifications.configs) != 1 || notifications.configs[0].UserID != "new" || notifications.configs[0].TargetPrice != 9.9 {
+		t.Fatalf("notifications not imported: %+v", notifications.configs)
+	}
+	if len(searches.searches) != 1 || searches.searches[0].UserID != "new" {
+		t.Fatalf("saved searches not imported: %+v", searches.searches)
+	}
+	if len(annotations.annotations) != 1 || annotations.annotations[0].UserID != "new" ||
+		!annotations.annotations[0].Favorite || !annotations.annotations[0].HasTag("午餐") || annotations.annotations[0].Note != "份量偏小" {
+		t.Fatalf("annotations not imported: %+v", annotations.annotations)
+	}
+
+	// Importing the same document again only skips entries
+	summary, err = target.Import(ctx, "new", doc, entity.ImportModeMerge)
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	if summary.SavedSearches != 0 || summary.BlockedProducts != 0 || summary.Skipped != 2 {
+		t.Fatalf("unexpected summary on re-import: %+v", summary)
+	}
+	if len(notifications.configs) != 1 || len(searches.searches) != 1 || len(annotations.annotations) != 1 {
+		t.Fatal("re-import must not duplicate data")
+	}
+}
+
+func TestUserDataService_ImportReplaceClearsExistingData(t *testing.T) {
+	ctx := context.Background()
+
+	settings := &stubUserSettingsRepository{settings: &entity.UserSettings{UserID: "u1", BarkKey: "OLD"}}
+	notifications := &memoryNotificationRepository{stubNotificationRepository{configs: []*entity.NotificationConfig{
+		{ActivityID: "DT_OLD", UserID: "u1", TargetPrice: 5},
+	}}}
+	blocked := &memoryBlockedRepository{blocked: map[string][]string{"u1": {"DT_BLOCKED"}}}
+	searches := &memorySavedSearchRepository{stubSavedSearchRepository{searches: []*entity.SavedSearch{
+		{ID: 1, UserID: "u1", Keyword: "旧"},
+	}}}
+	annotations := &memoryProductAnnotationRepository{annotations: []*entity.ProductAnnotation{
+		{ActivityID: "DT_OLD", UserID: "u1", Favorite: true},
+	}}
+	svc := newTestUserDataService(settings, notifications, blocked, searches, annotations)
+
+	doc := &entity.UserDataExport{
+		Version:       entity.UserDataVersion,
+		Notifications: []entity.UserDataNotification{{ActivityID: "DT_NEW", TargetPrice: 8}},
+	}
+	if _, err := svc.Import(ctx, "u1", doc, entity.ImportModeReplace); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if settings.settings.BarkKey != "" {
+		t.Fatalf("BarkKey = %q, want it cleared", settings.settings.BarkKey)
+	}
+	if len(notifications.configs) != 1 || notifications.configs[0].ActivityID != "DT_NEW" {
+		t.Fatalf("notifications = %+v, want only DT_NEW", notifications.configs)
+	}
+	if len(blocked.blocked["u1"]) != 0 {
+		t.Fatalf("blocked = %v, want none", blocked.blocked["u1"])
+	}
+	if len(searches.searches) != 0 {
+		t.Fatalf("saved searches = %+v, want none", searches.searches)
+	}
+	if len(annotations.annotations) != 0 {
+		t.Fatalf("annotations = %+v, want none", annotations.annotations)
+	}
+}
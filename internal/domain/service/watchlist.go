@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Watchlist errors
+var (
+	ErrWatchlistNotFound  = apperrors.New(apperrors.NotFound, "清单不存在")
+	ErrWatchlistForbidden = apperrors.New(apperrors.Forbidden, "只有清单创建者可以执行此操作")
+	ErrInvalidInviteCode  = apperrors.New(apperrors.NotFound, "邀请链接无效或已失效")
+	ErrWatchlistOwnerLeft = apperrors.New(apperrors.Conflict, "创建者不能退出清单，请直接删除清单")
+)
+
+// WatchlistDetail is a watchlist with its items and members
+type WatchlistDetail struct {
+	*entity.Watchlist
+	Items   []*entity.WatchlistItem   `json:"items"`
+	Members []*entity.WatchlistMember `json:"members"`
+}
+
+// WatchlistService manages shared watchlists. The rules of the items of a list
+// are evaluated like personal watches and NotificationService sends their
+// alerts to every member.
+type WatchlistService struct {
+	watchlists repository.WatchlistRepository
+	publicURL  string
+}
+
+// NewWatchlistService creates a new watchlist service
+func NewWatchlistService(
+	watchlists repository.WatchlistRepository,
+	publicURL string,
+) *WatchlistService {
+	return &WatchlistService{
+		watchlists: watchlists,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Create creates a watchlist owned by the user
+func (s *WatchlistService) Create(ctx context.Context, userID, name string) (*entity.Watchlist, error) {
+	watchlist := &entity.Watchlist{Name: name}
+	watchlist.Normalize()
+	if err := watchlist.Validate(); err != nil {
+		return nil, err
+	}
+
+	inviteCode, err := randomHex(12)
+	if err != nil {
+		return nil, err
+	}
+	watchlist.InviteCode = inviteCode
+
+	if err := s.watchlists.Create(ctx, watchlist, userID); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("userId", userID).
+		Int64("watchlistId", watchlist.ID).
+		Msg("Watchlist created")
+	return s.withInviteURL(watchlist), nil
+}
+
+// List lists the watchlists the user is a member of
+func (s *WatchlistService) List(ctx context.Context, userID string) ([]*entity.Watchlist, error) {
+	watchlists, err := s.watchlists.ListByMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, watchlist := range watchlists {
+		s.withInviteURL(watchlist)
+	}
+	return watchlists, nil
+}
+
+// Get returns a watchlist with its items and members
+func (s *WatchlistService) Get(ctx context.Context, userID string, id int64) (*WatchlistDetail, error) {
+	watchlist, _, err := s.authorize(ctx, userID, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.watchlists.ListItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.watchlists.ListMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		m.Current = m.UserID == userID
+	}
+
+	if items == nil {
+		items = []*entity.WatchlistItem{}
+	}
+	return &WatchlistDetail{Watchlist: watchlist, Items: items, Members: members}, nil
+}
+
+// Rename renames a watchlist. Only the owner may rename it.
+func (s *WatchlistService) Rename(ctx context.Context, userID string, id int64, name string) (*entity.Watchlist, error) {
+	watchlist, _, err := s.authorize(ctx, userID, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist.Name = name
+	watchlist.Normalize()
+	if err := watchlist.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.watchlists.Update(ctx, watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// RotateInvite replaces the invite code so that earlier invite links stop
+// working. Only the owner may rotate it.
+func (s *WatchlistService) RotateInvite(ctx context.Context, userID string, id int64) (*entity.Watchlist, error) {
+	watchlist, _, err := s.authorize(ctx, userID, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteCode, err := randomHex(12)
+	if err != nil {
+		return nil, err
+	}
+	watchlist.InviteCode = inviteCode
+	if err := s.watchlists.Update(ctx, watchlist); err != nil {
+		return nil, err
+	}
+	return s.withInviteURL(watchlist), nil
+}
+
+// Delete deletes a watchlist with its items. Only the owner may delete it.
+func (s *WatchlistService) Delete(ctx context.Context, userID string, id int64) error {
+	if _, _, err := s.authorize(ctx, userID, id, true); err != nil {
+		return err
+	}
+	if err := s.watchlists.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("userId", userID).
+		Int64("watchlistId", id).
+		Msg("Watchlist deleted")
+	return nil
+}
+
+// Join adds the user to the watchlist of the invite code. Joining a list the
+// user is already a member of is not an error.
+func (s *WatchlistService) Join(ctx context.Context, userID, inviteCode string) (*entity.Watchlist, error) {
+	inviteCode = strings.TrimSpace(inviteCode)
+	if inviteCode == "" {
+		return nil, ErrInvalidInviteCode
+	}
+
+	watchlist, err := s.watchlists.FindByInviteCode(ctx, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if watchlist == nil {
+		return nil, ErrInvalidInviteCode
+	}
+
+	member := &entity.WatchlistMember{
+		WatchlistID: watchlist.ID,
+		UserID:      userID,
+		Role:        entity.WatchlistRoleMember,
+	}
+	added, err := s.watchlists.AddMember(ctx, member)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		watchlist.MemberCount++
+		log.Info().
+			Str("userId", userID).
+			Int64("watchlistId", watchlist.ID).
+			Msg("Watchlist joined")
+	}
+
+	existing, err := s.watchlists.FindMember(ctx, watchlist.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		watchlist.Role = existing.Role
+	}
+	return s.withInviteURL(watchlist), nil
+}
+
+// Leave removes the user from a watchlist. The owner cannot leave.
+func (s *WatchlistService) Leave(ctx context.Context, userID string, id int64) error {
+	_, member, err := s.authorize(ctx, userID, id, false)
+	if err != nil {
+		return err
+	}
+	if member.IsOwner() {
+		return ErrWatchlistOwnerLeft
+	}
+
+	_, err = s.watchlists.RemoveMember(ctx, id, member.ID)
+	return err
+}
+
+// RemoveMember removes another member from a watchlist. Only the owner may
+// remove members. Returns false if the member did not exist.
+func (s *WatchlistService) RemoveMember(ctx context.Context, userID string, id, memberID int64) (bool, error) {
+	_, owner, err := s.authorize(ctx, userID, id, true)
+	if err != nil {
+		return false, err
+	}
+	if owner.ID == memberID {
+		return false, ErrWatchlistOwnerLeft
+	}
+	return s.watchlists.RemoveMember(ctx, id, memberID)
+}
+
+// GetItem returns a product on the list, or nil if the product is not on it
+func (s *WatchlistService) GetItem(ctx context.Context, userID string, id int64, activityID string) (*entity.WatchlistItem, error) {
+	if _, _, err := s.authorize(ctx, userID, id, false); err != nil {
+		return nil, err
+	}
+	return s.watchlists.FindItem(ctx, id, activityID)
+}
+
+// SaveItem adds a product to the list or retargets it. Any member may change items.
+// The rule must have been validated.
+func (s *WatchlistService) SaveItem(ctx context.Context, userID string, id int64, item *entity.WatchlistItem) error {
+	if _, _, err := s.authorize(ctx, userID, id, false); err != nil {
+		return err
+	}
+
+	item.WatchlistID = id
+	return s.watchlists.SaveItem(ctx, item)
+}
+
+// DeleteItem removes a product from the list. Any member may remove items.
+func (s *WatchlistService) DeleteItem(ctx context.Context, userID string, id int64, activityID string) error {
+	if _, _, err := s.authorize(ctx, userID, id, false); err != nil {
+		return err
+	}
+	return s.watchlists.DeleteItem(ctx, id, activityID)
+}
+
+// authorize loads a watchlist and the membership of the user. Lists the user is
+// not a member of are reported as not found.
+func (s *WatchlistService) authorize(
+	ctx context.Context,
+	userID string,
+	id int64,
+	ownerOnly bool,
+) (*entity.Watchlist, *entity.WatchlistMember, error) {
+	member, err := s.watchlists.FindMember(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member == nil {
+		return nil, nil, ErrWatchlistNotFound
+	}
+	if ownerOnly && !member.IsOwner() {
+		return nil, nil, ErrWatchlistForbidden
+	}
+
+	watchlist, err := s.watchlists.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if watchlist == nil {
+		return nil, nil, ErrWatchlistNotFound
+	}
+	watchlist.Role = member.Role
+	return s.withInviteURL(watchlist), member, nil
+}
+
+// withInviteURL sets the invite link of a watchlist if a public URL is configured
+func (s *WatchlistService) withInviteURL(watchlist *entity.Watchlist) *entity.Watchlist {
+	if s.publicURL != "" {
+		watchlist.InviteURL = s.publicURL + "/?watchlistInvite=" + url.QueryEscape(watchlist.InviteCode)
+	}
+	return watchlist
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/infra/external"
+)
+
+type stubWatchlistRepository struct {
+	watchlists []*entity.Watchlist
+	members    []*entity.WatchlistMember
+	items      []*entity.WatchlistItem
+	notified   []string // activity IDs of items whose notify time was updated
+}
+
+func (s *stubWatchlistRepository) Create(ctx context.Context, watchlist *entity.Watchlist, ownerUserID string) error {
+	watchlist.ID = int64(len(s.watchlists) + 1)
+	watchlist.Role = entity.WatchlistRoleOwner
+	watchlist.MemberCount = 1
+	s.watchlists = append(s.watchlists, watchlist)
+	_, err := s.AddMember(ctx, &entity.WatchlistMember{
+		WatchlistID: watchlist.ID,
+		UserID:      ownerUserID,
+		Role:        entity.WatchlistRoleOwner,
+	})
+	return err
+}
+
+func (s *stubWatchlistRepository) FindByID(ctx context.Context, id int64) (*entity.Watchlist, error) {
+	for _, watchlist := range s.watchlists {
+		if watchlist.ID == id {
+			copied := *watchlist
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubWatchlistRepository) FindByInviteCode(ctx context.Context, inviteCode string) (*entity.Watchlist, error) {
+	for _, watchlist := range s.watchlists {
+		if watchlist.InviteCode == inviteCode {
+			copied := *watchlist
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubWatchlistRepository) ListByMember(ctx context.Context, userID string) ([]*entity.Watchlist, error) {
+	var result []*entity.Watchlist
+	for _, member := range s.members {
+		if member.UserID == userID {
+			watchlist, _ := s.FindByID(ctx, member.WatchlistID)
+			watchlist.Role = member.Role
+			result = append(result, watchlist)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubWatchlistRepository) Update(ctx context.Context, watchlist *entity.Watchlist) error {
+	for _, existing := range s.watchlists {
+		if existing.ID == watchlist.ID {
+			existing.Name = watchlist.Name
+			existing.InviteCode = watchlist.InviteCode
+		}
+	}
+	return nil
+}
+
+func (s *stubWatchlistRepository) Delete(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (s *stubWatchlistRepository) FindMember(ctx context.Context, watchlistID int64, userID string) (*entity.WatchlistMember, error) {
+	for _, member := range s.members {
+		if member.WatchlistID == watchlistID && member.UserID == userID {
+			return member, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubWatchlistRepository) ListMembers(ctx context.Context, watchlistID int64) ([]*entity.WatchlistMember, error) {
+	var result []*entity.WatchlistMember
+	for _, member := range s.members {
+		if member.WatchlistID == watchlistID {
+			result = append(result, member)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubWatchlistRepository) AddMember(ctx context.Context, member *entity.WatchlistMember) (bool, error) {
+	if existing, _ := s.FindMember(ctx, member.WatchlistID, member.UserID); existing != nil {
+		return false, nil
+	}
+	member.ID = int64(len(s.members) + 1)
+	s.members = append(s.members, member)
+	return true, nil
+}
+
+func (s *stubWatchlistRepository) RemoveMember(ctx context.Context, watchlistID, memberID int64) (bool, error) {
+	for i, member := range s.members {
+		if member.WatchlistID == watchlistID && member.ID == memberID {
+			s.members = append(s.members[:i], s.members[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubWatchlistRepository) ListItems(ctx context.Context, watchlistID int64) ([]*entity.WatchlistItem, error) {
+	var result []*entity.WatchlistItem
+	for _, item := range s.items {
+		if item.WatchlistID == watchlistID {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubWatchlistRepository) ListItemsByMember(ctx context.Context, userID string) ([]*entity.WatchlistItem, error) {
+	var result []*entity.WatchlistItem
+	for _, member := range s.members {
+		if member.UserID == userID {
+			items, _ := s.ListItems(ctx, member.WatchlistID)
+			result = append(result, items...)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubWatchlistRepository) ListItemsByActivityID(ctx context.Context, activityID string) ([]*entity.WatchlistItem, error) {
+	var result []*entity.WatchlistItem
+	for _, item := range s.items {
+		if item.ActivityID == activityID {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubWatchlistRepository) ListAllItems(ctx context.Context) ([]*entity.WatchlistItem, error) {
+	return s.items, nil
+}
+
+func (s *stubWatchlistRepository) FindItem(ctx context.Context, watchlistID int64, activityID string) (*entity.WatchlistItem, error) {
+	for _, item := range s.items {
+		if item.WatchlistID == watchlistID && item.ActivityID == activityID {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubWatchlistRepository) SaveItem(ctx context.Context, item *entity.WatchlistItem) error {
+	_ = s.DeleteItem(ctx, item.WatchlistID, item.ActivityID)
+	s.items = append(s.items, item)
+	return nil
+}
+
+func (s *stubWatchlistRepository) DeleteItem(ctx context.Context, watchlistID int64, activityID string) error {
+	for i, item := range s.items {
+		if item.WatchlistID == watchlistID && item.ActivityID == activityID {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *stubWatchlistRepository) UpdateItemNotifyTime(ctx context.Context, watchlistID int64, activityID string, price float64) error {
+	s.notified = append(s.notified, activityID)
+	return nil
+}
+
+func (s *stubWatchlistRepository) UpdateItemLastStatus(ctx context.Context, watchlistID int64, activityID string, status int) error {
+	return nil
+}
+
+// multiUserSettingsRepository serves settings for several users
+type multiUserSettingsRepository struct {
+	stubUserSettingsRepository
+	users map[string]*entity.UserSettings
+}
+
+func (s *multiUserSettingsRepository) Get(ctx context.Context, userID string) (*entity.UserSettings, error) {
+	return s.users[userID], nil
+}
+
+func TestWatchlistService_JoinAndPermissions(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubWatchlistRepository{}
+	svc := NewWatchlistService(repo, "https://kbfood.example.com/")
+
+	watchlist, err := svc.Create(ctx, "client-alice", " 周末火锅 ")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if watchlist.Name != "周末火锅" {
+		t.Fatalf("Name = %q, want trimmed name", watchlist.Name)
+	}
+	if !strings.HasPrefix(watchlist.InviteURL, "https://kbfood.example.com/?watchlistInvite=") {
+		t.Fatalf("InviteURL = %q", watchlist.InviteURL)
+	}
+
+	if _, err := svc.Get(ctx, "client-bob", watchlist.ID); !errors.Is(err, ErrWatchlistNotFound) {
+		t.Fatalf("Get() by non-member error = %v, want ErrWatchlistNotFound", err)
+	}
+	if _, err := svc.Join(ctx, "client-bob", "wrong"); !errors.Is(err, ErrInvalidInviteCode) {
+		t.Fatalf("Join() with wrong code error = %v, want ErrInvalidInviteCode", err)
+	}
+
+	joined, err := svc.Join(ctx, "client-bob", watchlist.InviteCode)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if joined.Role != entity.WatchlistRoleMember {
+		t.Fatalf("Role = %q, want member", joined.Role)
+	}
+	if _, err := svc.Join(ctx, "client-bob", watchlist.InviteCode); err != nil {
+		t.Fatalf("second Join() error = %v", err)
+	}
+	if len(repo.members) != 2 {
+		t.Fatalf("members = %d, want 2", len(repo.members))
+	}
+
+	// Members edit items, which are stored under the list
+	item := &entity.WatchlistItem{NotificationConfig: entity.NotificationConfig{ActivityID: "DT_1", TargetPrice: 50}}
+	if err := svc.SaveItem(ctx, "client-bob", watchlist.ID, item); err != nil {
+		t.Fatalf("SaveItem() error = %v", err)
+	}
+	if item.WatchlistID != watchlist.ID {
+		t.Fatalf("item watchlist = %d, want %d", item.WatchlistID, watchlist.ID)
+	}
+	detail, err := svc.Get(ctx, "client-alice", watchlist.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(detail.Items) != 1 || len(detail.Members) != 2 {
+		t.Fatalf("detail has %d items and %d members, want 1 and 2", len(detail.Items), len(detail.Members))
+	}
+
+	// Only the owner manages the list
+	if _, err := svc.Rename(ctx, "client-bob", watchlist.ID, "改名"); !errors.Is(err, ErrWatchlistForbidden) {
+		t.Fatalf("Rename() by member error = %v, want ErrWatchlistForbidden", err)
+	}
+	if err := svc.Leave(ctx, "client-alice", watchlist.ID); !errors.Is(err, ErrWatchlistOwnerLeft) {
+		t.Fatalf("Leave() by owner error = %v, want ErrWatchlistOwnerLeft", err)
+	}
+
+	oldCode := watchlist.InviteCode
+	rotated, err := svc.RotateInvite(ctx, "client-alice", watchlist.ID)
+	if err != nil {
+		t.Fatalf("RotateInvite() error = %v", err)
+	}
+	if _, err := svc.Join(ctx, "client-carol", oldCode); !errors.Is(err, ErrInvalidInviteCode) {
+		t.Fatalf("Join() with old code error = %v, want ErrInvalidInviteCode", err)
+	}
+	if _, err := svc.Join(ctx, "client-carol", rotated.InviteCode); err != nil {
+		t.Fatalf("Join() with new code error = %v", err)
+	}
+
+	if err := svc.Leave(ctx, "client-bob", watchlist.ID); err != nil {
+		t.Fatalf("Leave() error = %v", err)
+	}
+	if err := svc.SaveItem(ctx, "client-bob", watchlist.ID, item); !errors.Is(err, ErrWatchlistNotFound) {
+		t.Fatalf("SaveItem() after leaving error = %v, want ErrWatchlistNotFound", err)
+	}
+}
+
+func TestNotificationService_NotifiesEveryWatchlistMember(t *testing.T) {
+	ctx := context.Background()
+
+	watchlistRepo := &stubWatchlistRepository{}
+	if err := watchlistRepo.Create(ctx, &entity.Watchlist{Name: "周末火锅", InviteCode: "abc"}, "client-alice"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	watchlistRepo.AddMember(ctx, &entity.WatchlistMember{WatchlistID: 1, UserID: "client-bob", Role: entity.WatchlistRoleMember})
+	watchlistRepo.items = []*entity.WatchlistItem{
+		{
+			WatchlistID: 1,
+			NotificationConfig: entity.NotificationConfig{
+				ActivityID:  "DT_shared",
+				TargetPrice: 75.05,
+				CreateTime:  time.Now(),
+				UpdateTime:  time.Now(),
+			},
+		},
+	}
+
+	notiRepo := &stubNotificationRepository{}
+	masterRepo := &stubMasterProductRepository{
+		product: &entity.MasterProduct{
+			ID:            "DT_shared",
+			Region:        "广州",
+			Platform:      "DT",
+			StandardTitle: "火锅四人餐",
+			Price:         68.7,
+			Status:        entity.SalesStatusOnSale,
+		},
+	}
+	userSettingsRepo := &multiUserSettingsRepository{
+		users: map[string]*entity.UserSettings{
+			"client-alice": {UserID: "client-alice", BarkKey: "ALICE"},
+			"client-bob":   {UserID: "client-bob", BarkKey: "BOB"},
+		},
+	}
+
+	paths := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths[r.URL.Path]++
+		if msg := decodeBarkMessage(t, r); !strings.Contains(msg.Body, "周末火锅") {
+			t.Fatalf("expected watchlist name in alert, got %q", msg.Body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := NewNotificationService(notiRepo, &stubProductRepository{}, masterRepo, userSettingsRepo, nil, nil, watchlistRepo, external.NewBarkSender(server.URL), "")
+
+	if err := service.CheckAndNotify(ctx); err != nil {
+		t.Fatalf("CheckAndNotify() error = %v", err)
+	}
+	if paths["/ALICE"] != 1 || paths["/BOB"] != 1 {
+		t.Fatalf("expected one alert per member, got %v", paths)
+	}
+	if len(watchlistRepo.notified) != 1 || watchlistRepo.notified[0] != "DT_shared" {
+		t.Fatalf("expected notify time update for the watchlist item, got %v", watchlistRepo.notified)
+	}
+	if notiRepo.updatedUserID != "" {
+		t.Fatalf("expected no personal notify time update, got %q", notiRepo.updatedUserID)
+	}
+}
@@ -1,81 +1,574 @@
 package db
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 )
 
-// RunMigrations executes all migration files from the migrations directory
-func (p *Pool) RunMigrations() error {
-	migrationsDir := "/app/migrations"
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
 
-	log.Info().Str("dir", migrationsDir).Msg("Running database migrations")
+// Migrations returns the migrations embedded in the binary
+func Migrations() fs.FS {
+	sub, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		// Only fails for invalid paths, and "migrations" is a valid path
+		panic(err)
+	}
+	return sub
+}
 
-	// Check if migrations directory exists
-	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-		log.Warn().Msg("Migrations directory not found, skipping")
-		return nil
+// MigrationsFS returns the migrations of dir, or the embedded migrations if
+// dir is empty
+func MigrationsFS(dir string) fs.FS {
+	if dir == "" {
+		return Migrations()
 	}
+	return os.DirFS(dir)
+}
+
+// Migration errors
+var (
+	ErrMigrationModified = errors.New("applied migration was modified")
+	ErrMigrationMissing  = errors.New("applied migration has no file")
+	ErrNoDownMigration   = errors.New("migration has no down file")
+	ErrUnknownVersion    = errors.New("unknown migration version")
+)
+
+// migrationFilePattern matches NNN_name.sql and its rollback NNN_name.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+?)(\.down)?\.sql$`)
+
+// Migration is a schema change read from NNN_name.sql, reverted by the
+// optional NNN_name.down.sql
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA-256 of Up, recorded when the migration is applied
+	Checksum string
+}
+
+// Migration states reported by Status
+const (
+	MigrationApplied  = "applied"
+	MigrationPending  = "pending"
+	MigrationModified = "modified" // file changed since it was applied
+	MigrationMissing  = "missing"  // applied, but the file no longer exists
+)
 
-	// Read all migration files
-	entries, err := os.ReadDir(migrationsDir)
+// MigrationStatus is the state of one migration in the database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	State     string
+	AppliedAt string
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	version   int
+	name      string
+	checksum  string
+	appliedAt string
+}
+
+// Migrator applies and reverts migrations, recording the applied ones with
+// their checksums in the schema_migrations table. Each migration runs in its
+// own transaction.
+type Migrator struct {
+	pool       *Pool
+	migrations []Migration
+}
+
+// NewMigrator reads the migrations at the root of fsys
+func NewMigrator(pool *Pool, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
 	if err != nil {
-		return fmt.Errorf("read migrations dir: %w", err)
+		return nil, fmt.Errorf("read migrations dir: %w", err)
 	}
 
-	// Sort by filename (001_, 002_, etc.)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Name() < entries[j].Name()
-	})
-
-	executed := 0
+	byVersion := make(map[int]*Migration)
 	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".sql") {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
 			continue
 		}
-
-		shouldSkip, err := p.shouldSkipMigration(entry.Name())
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
 		if err != nil {
-			return fmt.Errorf("check migration %s: %w", entry.Name(), err)
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
 		}
-		if shouldSkip {
-			log.Info().Str("file", entry.Name()).Msg("Migration already satisfied by current schema, skipping")
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] != "" {
+			migration.Down = string(content)
 			continue
 		}
+		if migration.Up != "" {
+			return nil, fmt.Errorf("migration version %d has more than one file", version)
+		}
+		migration.Up = string(content)
+		sum := sha256.Sum256(content)
+		migration.Checksum = hex.EncodeToString(sum[:])
+	}
 
-		filePath := filepath.Join(migrationsDir, entry.Name())
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			return fmt.Errorf("read migration %s: %w", entry.Name(), err)
+	m := &Migrator{pool: pool}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %03d_%s has a down file but no up file", migration.Version, migration.Name)
 		}
+		m.migrations = append(m.migrations, *migration)
+	}
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return m, nil
+}
 
-		log.Info().Str("file", entry.Name()).Msg("Executing migration")
-		if _, err := p.Exec(string(content)); err != nil {
-			// Log but don't fail on duplicate column/table errors (migration already run)
-			if strings.Contains(err.Error(), "duplicate column") ||
-				strings.Contains(err.Error(), "already exists") {
-				log.Warn().Str("file", entry.Name()).Err(err).Msg("Migration already applied, skipping")
-				continue
+// Migrations returns the migrations of the migrator, by version
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status lists the known migrations and the applied ones without a file, by version
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: MigrationPending}
+		if record, ok := applied[migration.Version]; ok {
+			status.AppliedAt = record.appliedAt
+			status.State = MigrationApplied
+			if record.checksum != migration.Checksum {
+				status.State = MigrationModified
 			}
-			return fmt.Errorf("execute migration %s: %w", entry.Name(), err)
 		}
-		executed++
+		statuses = append(statuses, status)
+	}
+	for _, record := range applied {
+		if m.find(record.version) == nil {
+			statuses = append(statuses, MigrationStatus{
+				Version:   record.version,
+				Name:      record.name,
+				State:     MigrationMissing,
+				AppliedAt: record.appliedAt,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// Up applies all pending migrations and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.To(ctx, m.latest())
+}
+
+// Down reverts the most recently applied migration. It returns false if no
+// migration is applied.
+func (m *Migrator) Down(ctx context.Context) (bool, error) {
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return false, err
+	}
+	current := currentVersion(applied)
+	if current == 0 {
+		return false, nil
+	}
+	return true, m.revert(ctx, m.find(current))
+}
+
+// To applies or reverts migrations until version is the latest applied one;
+// version 0 reverts all migrations. It returns how many were applied or reverted.
+func (m *Migrator) To(ctx context.Context, version int) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	applied, err := m.verify(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := &m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+			continue
+		}
+		if err := m.revert(ctx, migration); err != nil {
+			return count, err
+		}
+		count++
+	}
+	for i := range m.migrations {
+		migration := &m.migrations[i]
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// verify creates schema_migrations, adopts databases created before it and
+// returns the applied migrations. It fails if an applied migration was edited
+// or removed, since the schema would no longer match the files.
+func (m *Migrator) verify(ctx context.Context) (map[int]appliedMigration, error) {
+	if _, err := m.pool.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TEXT NOT NULL DEFAULT (datetime('now'))
+		)
+	`); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) == 0 {
+		adopted, err := m.adoptLegacySchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if adopted {
+			if applied, err = m.applied(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, record := range applied {
+		migration := m.find(record.version)
+		if migration == nil {
+			return nil, fmt.Errorf("%w: %03d_%s", ErrMigrationMissing, record.version, record.name)
+		}
+		if record.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %03d_%s.sql", ErrMigrationModified, migration.Version, migration.Name)
+		}
+	}
+	return applied, nil
+}
+
+// applied returns the rows of schema_migrations by version, none if the
+// table does not exist yet
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	applied := make(map[int]appliedMigration)
+	exists, err := m.pool.tableExists("schema_migrations")
+	if err != nil || !exists {
+		return applied, err
+	}
+
+	rows, err := m.pool.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[record.version] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// apply runs the up file of a migration and records it in one transaction
+func (m *Migrator) apply(ctx context.Context, migration *Migration) error {
+	log.Info().Int("version", migration.Version).Str("name", migration.Name).Msg("Applying migration")
+	return m.inTx(ctx, migration, migration.Up, func(tx *Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)
+		`, migration.Version, migration.Name, migration.Checksum)
+		return err
+	})
+}
+
+// revert runs the down file of a migration and removes its record in one transaction
+func (m *Migrator) revert(ctx context.Context, migration *Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %03d_%s", ErrNoDownMigration, migration.Version, migration.Name)
+	}
+	log.Info().Int("version", migration.Version).Str("name", migration.Name).Msg("Reverting migration")
+	return m.inTx(ctx, migration, migration.Down, func(tx *Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+		return err
+	})
+}
+
+func (m *Migrator) inTx(ctx context.Context, migration *Migration, script string, record func(tx *Tx) error) error {
+	tx, err := m.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("execute migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if err := record(tx); err != nil {
+		return fmt.Errorf("record migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
 	}
+	return nil
+}
 
-	log.Info().Int("count", executed).Msg("Migrations completed")
+func (m *Migrator) latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func currentVersion(applied map[int]appliedMigration) int {
+	current := 0
+	for version := range applied {
+		current = max(current, version)
+	}
+	return current
+}
+
+// RunMigrations applies the pending migrations of migrations
+func (p *Pool) RunMigrations(migrations fs.FS) error {
+	migrator, err := NewMigrator(p, migrations)
+	if err != nil {
+		return err
+	}
+	count, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	log.Info().Int("count", count).Msg("Migrations completed")
 	return nil
 }
 
+// adoptLegacySchema records all migrations as applied on databases created
+// before schema_migrations existed, when every file was re-run on each start.
+// It re-runs the files once the old way, so files added since the database was
+// last started are still applied, and reports false for new databases. The
+// files and their records share one transaction, so a failed adoption leaves
+// nothing recorded and is retried in full on the next start.
+func (m *Migrator) adoptLegacySchema(ctx context.Context) (bool, error) {
+	legacy, err := m.pool.tableExists("product")
+	if err != nil || !legacy {
+		return false, err
+	}
+	log.Info().Msg("Adopting database created before schema_migrations")
+
+	// Checked up front: each check looks at what only its own file changes
+	skip := make([]bool, len(m.migrations))
+	for i, migration := range m.migrations {
+		filename := fmt.Sprintf("%03d_%s.sql", migration.Version, migration.Name)
+		if skip[i], err = m.pool.shouldSkipMigration(filename); err != nil {
+			return false, fmt.Errorf("check migration %s: %w", filename, err)
+		}
+	}
+
+	tx, err := m.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin legacy adoption tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for i := range m.migrations {
+		migration := &m.migrations[i]
+		filename := fmt.Sprintf("%03d_%s.sql", migration.Version, migration.Name)
+		// Statements run one at a time, so a statement run before does not
+		// keep the rest of its file from running. A failed statement is undone
+		// on its own, leaving the transaction open.
+		if !skip[i] {
+			for _, statement := range splitStatements(migration.Up) {
+				if _, err := tx.ExecContext(ctx, statement); err != nil && !isAlreadyAppliedError(err) {
+					return false, fmt.Errorf("execute migration %s: %w", filename, err)
+				}
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)
+		`, migration.Version, migration.Name, migration.Checksum); err != nil {
+			return false, fmt.Errorf("record migration %s: %w", filename, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit legacy adoption tx: %w", err)
+	}
+	return true, nil
+}
+
+// splitStatements splits an SQL script into its statements. Semicolons in
+// quotes, comments and the bodies of CREATE TRIGGER statements do not end a
+// statement. Empty statements are dropped.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		start      int
+		words      []string // upper-cased leading words of the statement
+		depth      int      // BEGIN and CASE blocks open in a trigger body
+		word       strings.Builder
+	)
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.ToUpper(word.String())
+		word.Reset()
+		if len(words) < 4 {
+			words = append(words, w)
+		}
+		if !isTrigger(words) {
+			return
+		}
+		switch w {
+		case "BEGIN", "CASE":
+			depth++
+		case "END":
+			depth--
+		}
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			endWord()
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			if end := strings.IndexByte(script[i+1:], closing); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(script)
+			}
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			endWord()
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			endWord()
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			word.WriteByte(c)
+		default:
+			endWord()
+			if c == ';' && depth <= 0 {
+				if statement := strings.TrimSpace(script[start : i+1]); statement != ";" {
+					statements = append(statements, statement)
+				}
+				start, words, depth = i+1, nil, 0
+			}
+		}
+	}
+	endWord()
+	if statement := strings.TrimSpace(script[start:]); statement != "" && !isCommentOnly(statement) {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// isTrigger reports whether the leading words start a CREATE TRIGGER statement
+func isTrigger(words []string) bool {
+	if len(words) < 2 || words[0] != "CREATE" {
+		return false
+	}
+	return words[1] == "TRIGGER" ||
+		len(words) > 2 && (words[1] == "TEMP" || words[1] == "TEMPORARY") && words[2] == "TRIGGER"
+}
+
+// isCommentOnly reports whether the trailing text of a script holds only comments
+func isCommentOnly(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlreadyAppliedError reports whether a legacy migration failed because it
+// had been run before
+func isAlreadyAppliedError(err error) bool {
+	return strings.Contains(err.Error(), "duplicate column") ||
+		strings.Contains(err.Error(), "already exists")
+}
+
+// shouldSkipMigration reports whether a legacy database already has the
+// changes of a migration that must not be re-run
 func (p *Pool) shouldSkipMigration(filename string) (bool, error) {
 	switch filename {
 	case "002_add_user_id.sql":
 		return p.multiUserSchemaReady()
+	case "015_product_search.sql":
+		// Re-running would import every product into the index again
+		return p.tableExists("product_search")
+	case "017_product_changes.sql":
+		// Re-running would record every master product as new again
+		return p.tableExists("product_change")
 	default:
 		return false, nil
 	}
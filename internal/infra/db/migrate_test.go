@@ -1,8 +1,13 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"io/fs"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	_ "modernc.org/sqlite"
 )
@@ -67,6 +72,341 @@ func TestShouldSkipMigration_UserIDMigrationPending(t *testing.T) {
 	}
 }
 
+func TestShouldSkipMigration_ProductSearchAlreadyCreated(t *testing.T) {
+	pool := setupMigrationPool(t)
+
+	skip, err := pool.shouldSkipMigration("015_product_search.sql")
+	if err != nil {
+		t.Fatalf("shouldSkipMigration() error = %v", err)
+	}
+	if skip {
+		t.Fatal("expected 015_product_search.sql to run on a fresh database")
+	}
+
+	mustExecMigrationSQL(t, pool.DB, `CREATE VIRTUAL TABLE product_search USING fts5(title, tokenize = 'trigram')`)
+
+	skip, err = pool.shouldSkipMigration("015_product_search.sql")
+	if err != nil {
+		t.Fatalf("shouldSkipMigration() error = %v", err)
+	}
+	if !skip {
+		t.Fatal("expected 015_product_search.sql to be skipped once the index exists")
+	}
+}
+
+func TestShouldSkipMigration_ProductChangesAlreadyCreated(t *testing.T) {
+	pool := setupMigrationPool(t)
+
+	skip, err := pool.shouldSkipMigration("017_product_changes.sql")
+	if err != nil {
+		t.Fatalf("shouldSkipMigration() error = %v", err)
+	}
+	if skip {
+		t.Fatal("expected 017_product_changes.sql to run on a fresh database")
+	}
+
+	mustExecMigrationSQL(t, pool.DB, `CREATE TABLE product_change (id INTEGER PRIMARY KEY AUTOINCREMENT)`)
+
+	skip, err = pool.shouldSkipMigration("017_product_changes.sql")
+	if err != nil {
+		t.Fatalf("shouldSkipMigration() error = %v", err)
+	}
+	if !skip {
+		t.Fatal("expected 017_product_changes.sql to be skipped once changes are recorded")
+	}
+}
+
+func TestRunMigrations_EmbeddedMigrationsCreateSchema(t *testing.T) {
+	pool := setupMigrationPool(t)
+
+	if err := pool.RunMigrations(Migrations()); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	for _, table := range []string{"product", "user_settings", "product_search", "product_change"} {
+		exists, err := pool.tableExists(table)
+		if err != nil {
+			t.Fatalf("tableExists(%q) error = %v", table, err)
+		}
+		if !exists {
+			t.Errorf("table %q was not created", table)
+		}
+	}
+}
+
+func TestMigrator_UpDownTo(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+	migrator, err := NewMigrator(pool, testMigrations())
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	if count, err := migrator.Up(ctx); err != nil || count != 3 {
+		t.Fatalf("Up() = %d, %v, want 3 applied", count, err)
+	}
+	if count, err := migrator.Up(ctx); err != nil || count != 0 {
+		t.Fatalf("second Up() = %d, %v, want nothing applied", count, err)
+	}
+	assertMigrationStates(t, migrator, MigrationApplied, MigrationApplied, MigrationApplied)
+
+	if reverted, err := migrator.Down(ctx); err != nil || !reverted {
+		t.Fatalf("Down() = %v, %v, want one migration reverted", reverted, err)
+	}
+	if hasColumn, _ := pool.tableHasColumn("item", "price"); hasColumn {
+		t.Error("Down() kept the column added by migration 3")
+	}
+	assertMigrationStates(t, migrator, MigrationApplied, MigrationApplied, MigrationPending)
+
+	if count, err := migrator.To(ctx, 0); err != nil || count != 2 {
+		t.Fatalf("To(0) = %d, %v, want 2 reverted", count, err)
+	}
+	if exists, _ := pool.tableExists("item"); exists {
+		t.Error("To(0) kept the item table")
+	}
+	if count, err := migrator.To(ctx, 2); err != nil || count != 2 {
+		t.Fatalf("To(2) = %d, %v, want 2 applied", count, err)
+	}
+	assertMigrationStates(t, migrator, MigrationApplied, MigrationApplied, MigrationPending)
+
+	if _, err := migrator.To(ctx, 9); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("To(9) error = %v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+	migrations := testMigrations()
+	migrations["003_item_price.sql"] = &fstest.MapFile{Data: []byte(`
+		ALTER TABLE item ADD COLUMN price REAL;
+		INSERT INTO missing_table VALUES (1);
+	`)}
+	migrator, err := NewMigrator(pool, migrations)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	if count, err := migrator.Up(ctx); err == nil || count != 2 {
+		t.Fatalf("Up() = %d, %v, want an error after 2 applied", count, err)
+	}
+	if hasColumn, _ := pool.tableHasColumn("item", "price"); hasColumn {
+		t.Error("the failed migration was partially applied")
+	}
+	assertMigrationStates(t, migrator, MigrationApplied, MigrationApplied, MigrationPending)
+}
+
+func TestMigrator_DetectsModifiedAndMissingMigrations(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+	migrator, err := NewMigrator(pool, testMigrations())
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	modified := testMigrations()
+	modified["002_item_index.sql"] = &fstest.MapFile{Data: []byte(`CREATE INDEX idx_item_name ON item(name, id);`)}
+	migrator, err = NewMigrator(pool, modified)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := migrator.Up(ctx); !errors.Is(err, ErrMigrationModified) {
+		t.Errorf("Up() error = %v, want ErrMigrationModified", err)
+	}
+	if _, err := migrator.Down(ctx); !errors.Is(err, ErrMigrationModified) {
+		t.Errorf("Down() error = %v, want ErrMigrationModified", err)
+	}
+	assertMigrationStates(t, migrator, MigrationApplied, MigrationModified, MigrationApplied)
+
+	missing := testMigrations()
+	delete(missing, "003_item_price.sql")
+	delete(missing, "003_item_price.down.sql")
+	migrator, err = NewMigrator(pool, missing)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := migrator.Up(ctx); !errors.Is(err, ErrMigrationMissing) {
+		t.Errorf("Up() error = %v, want ErrMigrationMissing", err)
+	}
+	assertMigrationStates(t, migrator, MigrationApplied, MigrationApplied, MigrationMissing)
+}
+
+func TestMigrator_AdoptsLegacyDatabase(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+
+	// Databases created before schema_migrations ran every file on each start
+	for _, name := range []string{"001_schema.sql", "002_add_user_id.sql", "003_add_platform.sql"} {
+		content, err := fs.ReadFile(Migrations(), name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		mustExecMigrationSQL(t, pool.DB, string(content))
+	}
+
+	migrator, err := NewMigrator(pool, Migrations())
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if count, err := migrator.Up(ctx); err != nil || count != 0 {
+		t.Fatalf("Up() = %d, %v, want the legacy database adopted", count, err)
+	}
+	if exists, _ := pool.tableExists("product_change"); !exists {
+		t.Error("migrations added after the legacy database were not applied")
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, status := range statuses {
+		if status.State != MigrationApplied {
+			t.Errorf("migration %03d_%s is %s, want applied", status.Version, status.Name, status.State)
+		}
+	}
+}
+
+func TestMigrator_FailedLegacyAdoptionRecordsNothing(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+	mustExecMigrationSQL(t, pool.DB, `CREATE TABLE product (id INTEGER PRIMARY KEY)`)
+
+	migrations := testMigrations()
+	migrations["003_item_price.sql"] = &fstest.MapFile{Data: []byte(`INSERT INTO missing_table VALUES (1);`)}
+	migrator, err := NewMigrator(pool, migrations)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	if _, err := migrator.Up(ctx); err == nil {
+		t.Fatal("Up() succeeded, want the adoption to fail")
+	}
+	if exists, _ := pool.tableExists("item"); exists {
+		t.Error("files run before the failure were kept")
+	}
+	assertMigrationStates(t, migrator, MigrationPending, MigrationPending, MigrationPending)
+}
+
+func TestMigrator_AdoptsPartlyAppliedLegacyFile(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+	// The legacy database already has the column of 003 but not its index
+	mustExecMigrationSQL(t, pool.DB, `
+		CREATE TABLE product (id INTEGER PRIMARY KEY);
+		CREATE TABLE item (id INTEGER PRIMARY KEY, name TEXT NOT NULL, price REAL);
+	`)
+
+	migrations := testMigrations()
+	migrations["003_item_price.sql"] = &fstest.MapFile{Data: []byte(`
+		ALTER TABLE item ADD COLUMN price REAL;
+		CREATE INDEX idx_item_price ON item(price);
+	`)}
+	migrator, err := NewMigrator(pool, migrations)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	var indexes int
+	if err := pool.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_item_price'`).Scan(&indexes); err != nil {
+		t.Fatalf("count indexes: %v", err)
+	}
+	if indexes != 1 {
+		t.Error("statements after the already applied one were skipped")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements(`
+		-- a comment; not a statement
+		CREATE TABLE item (name TEXT DEFAULT 'a;b');
+		CREATE TRIGGER item_insert AFTER INSERT ON item
+		BEGIN
+			UPDATE item SET name = CASE WHEN new.name = '' THEN 'x' ELSE new.name END;
+			/* end; */ DELETE FROM item WHERE name = "end;";
+		END;
+		;
+		INSERT INTO item (name) VALUES ('c')
+		-- trailing comment
+	`)
+
+	if len(statements) != 3 {
+		t.Fatalf("splitStatements() = %d statements, want 3: %q", len(statements), statements)
+	}
+	if !strings.HasPrefix(statements[1], "CREATE TRIGGER") || !strings.HasSuffix(statements[1], "END;") {
+		t.Errorf("trigger statement = %q", statements[1])
+	}
+	if !strings.HasPrefix(statements[2], "INSERT INTO item") {
+		t.Errorf("last statement = %q", statements[2])
+	}
+}
+
+func TestMigrator_EmbeddedMigrationsRevertCleanly(t *testing.T) {
+	ctx := context.Background()
+	pool := setupMigrationPool(t)
+	migrator, err := NewMigrator(pool, Migrations())
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	for _, migration := range migrator.Migrations() {
+		if migration.Down == "" {
+			t.Errorf("migration %03d_%s has no down file", migration.Version, migration.Name)
+		}
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if _, err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("To(0) error = %v", err)
+	}
+
+	var tables int
+	if err := pool.QueryRow(`
+		SELECT COUNT(*) FROM sqlite_master
+		WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')
+	`).Scan(&tables); err != nil {
+		t.Fatalf("count tables: %v", err)
+	}
+	if tables != 0 {
+		t.Errorf("To(0) left %d tables", tables)
+	}
+	if count, err := migrator.Up(ctx); err != nil || count != len(migrator.Migrations()) {
+		t.Fatalf("Up() after To(0) = %d, %v, want all migrations applied", count, err)
+	}
+}
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"001_item.sql":            {Data: []byte(`CREATE TABLE item (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`)},
+		"001_item.down.sql":       {Data: []byte(`DROP TABLE item;`)},
+		"002_item_index.sql":      {Data: []byte(`CREATE INDEX idx_item_name ON item(name);`)},
+		"002_item_index.down.sql": {Data: []byte(`DROP INDEX idx_item_name;`)},
+		"003_item_price.sql":      {Data: []byte(`ALTER TABLE item ADD COLUMN price REAL;`)},
+		"003_item_price.down.sql": {Data: []byte(`ALTER TABLE item DROP COLUMN price;`)},
+		"README.md":               {Data: []byte(`not a migration`)},
+	}
+}
+
+func assertMigrationStates(t *testing.T, migrator *Migrator, want ...string) {
+	t.Helper()
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Status() returned %d migrations, want %d", len(statuses), len(want))
+	}
+	for i, status := range statuses {
+		if status.State != want[i] {
+			t.Errorf("migration %03d_%s is %s, want %s", status.Version, status.Name, status.State, want[i])
+		}
+	}
+}
+
 func setupMigrationPool(t *testing.T) *Pool {
 	t.Helper()
 
@@ -0,0 +1,8 @@
+-- 删除初始表结构
+DROP TABLE IF EXISTS sync_status;
+DROP TABLE IF EXISTS product_price_trend;
+DROP TABLE IF EXISTS notification_config;
+DROP TABLE IF EXISTS blocked_product;
+DROP TABLE IF EXISTS candidate_item;
+DROP TABLE IF EXISTS master_product;
+DROP TABLE IF EXISTS product;
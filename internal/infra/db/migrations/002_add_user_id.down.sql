@@ -0,0 +1,23 @@
+-- 恢复单用户表结构：同一商品的多个用户记录只保留一条，此回滚会丢失数据
+DROP TABLE IF EXISTS user_settings;
+
+CREATE TABLE blocked_product_old (
+    activity_id TEXT PRIMARY KEY,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE notification_config_old (
+    activity_id TEXT PRIMARY KEY,
+    target_price REAL NOT NULL,
+    last_notify_time TEXT,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    update_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+INSERT OR IGNORE INTO blocked_product_old SELECT activity_id, create_time FROM blocked_product;
+INSERT OR IGNORE INTO notification_config_old SELECT activity_id, target_price, last_notify_time, create_time, update_time FROM notification_config;
+
+DROP TABLE blocked_product;
+DROP TABLE notification_config;
+ALTER TABLE blocked_product_old RENAME TO blocked_product;
+ALTER TABLE notification_config_old RENAME TO notification_config;
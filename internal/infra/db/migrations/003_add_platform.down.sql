@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_master_product_platform;
+ALTER TABLE master_product DROP COLUMN platform;
@@ -0,0 +1,5 @@
+ALTER TABLE notification_config DROP COLUMN last_status;
+ALTER TABLE notification_config DROP COLUMN reference_price;
+ALTER TABLE notification_config DROP COLUMN drop_amount;
+ALTER TABLE notification_config DROP COLUMN drop_percent;
+ALTER TABLE notification_config DROP COLUMN rule_type;
@@ -0,0 +1,8 @@
+-- 通知规则类型：目标价、较昨日跌幅、历史新低、重新开售、较上次提醒降价
+ALTER TABLE notification_config ADD COLUMN rule_type TEXT NOT NULL DEFAULT 'target_price';
+ALTER TABLE notification_config ADD COLUMN drop_percent REAL;
+ALTER TABLE notification_config ADD COLUMN drop_amount REAL;
+-- 上次提醒时（或创建时）的价格，用于“较上次提醒降价”规则
+ALTER TABLE notification_config ADD COLUMN reference_price REAL;
+-- 最近一次检查到的销售状态，用于“重新开售”规则
+ALTER TABLE notification_config ADD COLUMN last_status INTEGER;
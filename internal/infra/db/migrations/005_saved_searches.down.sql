@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS saved_search_match;
+DROP TABLE IF EXISTS saved_search;
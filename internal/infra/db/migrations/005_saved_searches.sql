@@ -0,0 +1,22 @@
+-- 订阅搜索：新上架商品匹配时推送提醒
+CREATE TABLE IF NOT EXISTS saved_search (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    keyword TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    platform TEXT NOT NULL DEFAULT '',
+    max_price REAL,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    update_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_user ON saved_search(user_id);
+
+-- 已匹配记录，保证每个商品对每个订阅只提醒一次
+CREATE TABLE IF NOT EXISTS saved_search_match (
+    search_id INTEGER NOT NULL,
+    activity_id TEXT NOT NULL,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (search_id, activity_id)
+);
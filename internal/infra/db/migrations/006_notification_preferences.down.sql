@@ -0,0 +1,11 @@
+ALTER TABLE notification_config DROP COLUMN snooze_until;
+
+ALTER TABLE user_settings DROP COLUMN bark_sound;
+ALTER TABLE user_settings DROP COLUMN bark_level;
+ALTER TABLE user_settings DROP COLUMN max_per_day;
+ALTER TABLE user_settings DROP COLUMN max_per_hour;
+ALTER TABLE user_settings DROP COLUMN quiet_end;
+ALTER TABLE user_settings DROP COLUMN quiet_start;
+ALTER TABLE user_settings DROP COLUMN timezone;
+
+DROP TABLE IF EXISTS notification_delivery;
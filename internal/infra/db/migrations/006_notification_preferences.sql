@@ -0,0 +1,24 @@
+-- 推送记录：已发送或因免打扰/频率限制暂存的提醒
+CREATE TABLE IF NOT EXISTS notification_delivery (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    activity_id TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'held',  -- held / sent
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    sent_time TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_delivery_user_status ON notification_delivery(user_id, status, sent_time);
+
+-- 用户推送偏好：时区、免打扰时段、频率上限、Bark 级别与铃声
+ALTER TABLE user_settings ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN quiet_start TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN quiet_end TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN max_per_hour INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN max_per_day INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN bark_level TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN bark_sound TEXT NOT NULL DEFAULT '';
+
+-- 单条提醒暂停至指定时间
+ALTER TABLE notification_config ADD COLUMN snooze_until TEXT;
@@ -0,0 +1,3 @@
+ALTER TABLE user_settings DROP COLUMN last_digest_time;
+ALTER TABLE user_settings DROP COLUMN digest_time;
+ALTER TABLE user_settings DROP COLUMN digest_enabled;
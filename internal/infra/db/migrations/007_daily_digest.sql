@@ -0,0 +1,4 @@
+-- 每日摘要：开启后不再即时推送，改为每天定时推送一条汇总
+ALTER TABLE user_settings ADD COLUMN digest_enabled INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN digest_time TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN last_digest_time TEXT;
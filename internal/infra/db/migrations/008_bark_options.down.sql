@@ -0,0 +1,5 @@
+ALTER TABLE user_settings DROP COLUMN bark_encrypt_iv;
+ALTER TABLE user_settings DROP COLUMN bark_encrypt_key;
+ALTER TABLE user_settings DROP COLUMN bark_archive;
+ALTER TABLE user_settings DROP COLUMN bark_icon;
+ALTER TABLE user_settings DROP COLUMN bark_group;
@@ -0,0 +1,6 @@
+-- Bark 推送选项：分组、图标、存档及 AES 加密推送
+ALTER TABLE user_settings ADD COLUMN bark_group TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN bark_icon TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN bark_archive INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN bark_encrypt_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN bark_encrypt_iv TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS client_claim;
+DROP TABLE IF EXISTS auth_token;
+DROP TABLE IF EXISTS app_user;
@@ -0,0 +1,29 @@
+-- 用户账号
+CREATE TABLE IF NOT EXISTS app_user (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    update_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- 登录会话与个人 API Token，仅保存 Token 的 SHA-256
+CREATE TABLE IF NOT EXISTS auth_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash TEXT NOT NULL UNIQUE,
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,  -- session / api
+    name TEXT NOT NULL DEFAULT '',
+    expire_time TEXT,
+    last_used_time TEXT,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_token_user ON auth_token(user_id);
+
+-- 匿名客户端标识认领记录，每个标识只能被认领一次
+CREATE TABLE IF NOT EXISTS client_claim (
+    client_id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
@@ -0,0 +1,2 @@
+ALTER TABLE app_user DROP COLUMN role;
+DROP TABLE IF EXISTS audit_log;
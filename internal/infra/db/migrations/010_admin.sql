@@ -0,0 +1,15 @@
+-- 管理操作审计日志
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,            -- 如 POST /api/admin/sync
+    params TEXT NOT NULL DEFAULT '', -- 路径参数、查询参数与请求体 (JSON)
+    status INTEGER NOT NULL DEFAULT 0,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log(user_id);
+
+-- 账号角色：admin / user
+ALTER TABLE app_user ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
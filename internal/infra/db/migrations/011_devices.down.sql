@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS device;
+DROP TABLE IF EXISTS pairing_code;
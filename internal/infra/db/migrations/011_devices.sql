@@ -0,0 +1,21 @@
+-- 设备配对码：6 位数字，短期有效，使用一次即删除
+CREATE TABLE IF NOT EXISTS pairing_code (
+    code TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    expire_time TEXT NOT NULL,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pairing_code_user ON pairing_code(user_id);
+
+-- 已配对设备：设备的匿名标识绑定到共享的用户标识
+CREATE TABLE IF NOT EXISTS device (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id TEXT NOT NULL UNIQUE,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    last_seen_time TEXT,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_user ON device(user_id);
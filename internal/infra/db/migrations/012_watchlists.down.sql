@@ -0,0 +1,4 @@
+-- 清单内的提醒规则保存在 notification_config 中，一并删除
+DELETE FROM notification_config WHERE user_id LIKE 'wl\_%' ESCAPE '\';
+DROP TABLE IF EXISTS watchlist_member;
+DROP TABLE IF EXISTS watchlist;
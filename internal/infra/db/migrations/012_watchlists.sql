@@ -0,0 +1,20 @@
+-- 共享关注清单：清单内的提醒规则保存在 notification_config 中，user_id 为 'wl_<清单 ID>'
+CREATE TABLE IF NOT EXISTS watchlist (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    invite_code TEXT NOT NULL UNIQUE,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    update_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- 清单成员：owner 为创建者，member 通过邀请链接加入
+CREATE TABLE IF NOT EXISTS watchlist_member (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    watchlist_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'member',
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (watchlist_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_member_user ON watchlist_member(user_id);
@@ -0,0 +1,13 @@
+-- 商品标注：用户的收藏、自定义标签（JSON 数组）与个人备注
+CREATE TABLE IF NOT EXISTS product_annotation (
+    activity_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    favorite INTEGER NOT NULL DEFAULT 0,
+    tags TEXT NOT NULL DEFAULT '[]',
+    note TEXT NOT NULL DEFAULT '',
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    update_time TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (activity_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_annotation_user ON product_annotation(user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_trend_activity_price;
+DROP INDEX IF EXISTS idx_master_status;
+DROP INDEX IF EXISTS idx_master_price;
+DROP INDEX IF EXISTS idx_master_update_time;
+DROP INDEX IF EXISTS idx_master_region_platform_update;
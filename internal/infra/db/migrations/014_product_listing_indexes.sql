@@ -0,0 +1,8 @@
+-- 商品列表查询索引：筛选、排序与游标分页均在 SQL 中完成
+CREATE INDEX IF NOT EXISTS idx_master_region_platform_update ON master_product(region, platform, update_time);
+CREATE INDEX IF NOT EXISTS idx_master_update_time ON master_product(update_time, id);
+CREATE INDEX IF NOT EXISTS idx_master_price ON master_product(price, id);
+CREATE INDEX IF NOT EXISTS idx_master_status ON master_product(status);
+
+-- 原价取历史最高价，按商品聚合价格趋势
+CREATE INDEX IF NOT EXISTS idx_trend_activity_price ON product_price_trend(activity_id, price);
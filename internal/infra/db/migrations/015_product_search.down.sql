@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS product_search_delete;
+DROP TRIGGER IF EXISTS product_search_update;
+DROP TRIGGER IF EXISTS product_search_insert;
+DROP TRIGGER IF EXISTS master_product_search_delete;
+DROP TRIGGER IF EXISTS master_product_search_update;
+DROP TRIGGER IF EXISTS master_product_search_insert;
+DROP TABLE IF EXISTS product_search;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_product_shop_name;
+DROP TABLE IF EXISTS master_title_vote;
@@ -0,0 +1,11 @@
+-- 标准商品的标题投票：候选晋升时保留各原始标题的出现次数（候选记录晋升后会被删除）
+CREATE TABLE IF NOT EXISTS master_title_vote (
+    master_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    votes INTEGER NOT NULL DEFAULT 0,
+    update_time TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (master_id, title)
+);
+
+-- 同店铺相关商品查询
+CREATE INDEX IF NOT EXISTS idx_product_shop_name ON product(shop_name);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS feed_token;
+DROP TABLE IF EXISTS product_change;
@@ -0,0 +1,28 @@
+-- 商品变化记录：价格变化与候选晋升，供 Atom 订阅源使用，自增 ID 作为条目的稳定标识
+CREATE TABLE IF NOT EXISTS product_change (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,            -- price: 价格变化, new: 新商品晋升
+    activity_id TEXT NOT NULL,
+    region TEXT NOT NULL DEFAULT '',
+    platform TEXT NOT NULL DEFAULT '',
+    title TEXT NOT NULL DEFAULT '',
+    price REAL NOT NULL,
+    old_price REAL,                -- 变化前的价格，新商品为空
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_change_kind ON product_change(kind, id);
+CREATE INDEX IF NOT EXISTS idx_product_change_activity ON product_change(activity_id, id);
+
+-- 已有标准商品按创建时间记为新商品（迁移只执行一次，不会重复导入）
+INSERT INTO product_change (kind, activity_id, region, platform, title, price, create_time)
+SELECT 'new', id, region, COALESCE(platform, ''), standard_title, COALESCE(price, 0), create_time
+FROM master_product
+ORDER BY create_time, id;
+
+-- 私人订阅源 Token：每个用户一个，重新生成后旧地址失效
+CREATE TABLE IF NOT EXISTS feed_token (
+    user_id TEXT PRIMARY KEY,
+    token TEXT NOT NULL UNIQUE,
+    create_time TEXT NOT NULL DEFAULT (datetime('now'))
+);
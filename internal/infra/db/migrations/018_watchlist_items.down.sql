@@ -0,0 +1,9 @@
+INSERT OR IGNORE INTO notification_config (
+    activity_id, user_id, rule_type, target_price, drop_percent, drop_amount,
+    reference_price, last_status, last_notify_time, create_time, update_time
+)
+SELECT activity_id, 'wl_' || watchlist_id, rule_type, target_price, drop_percent, drop_amount,
+       reference_price, last_status, last_notify_time, create_time, update_time
+FROM watchlist_item;
+
+DROP TABLE IF EXISTS watchlist_item;
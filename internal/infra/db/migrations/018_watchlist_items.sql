@@ -0,0 +1,30 @@
+-- 共享清单商品：每个清单一行一个商品，提醒规则与个人关注相同，提醒发送给所有成员
+CREATE TABLE IF NOT EXISTS watchlist_item (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    watchlist_id INTEGER NOT NULL,
+    activity_id TEXT NOT NULL,
+    rule_type TEXT NOT NULL DEFAULT 'target_price',
+    target_price REAL NOT NULL DEFAULT 0,
+    drop_percent REAL,
+    drop_amount REAL,
+    reference_price REAL,          -- 上次提醒（或添加）时的价格
+    last_status INTEGER,           -- 上次观察到的销售状态
+    last_notify_time TEXT,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    update_time TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (watchlist_id, activity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_item_activity ON watchlist_item(activity_id);
+
+-- 原先清单商品以 user_id = 'wl_<清单 ID>' 保存在 notification_config 中，迁移过来
+INSERT OR IGNORE INTO watchlist_item (
+    watchlist_id, activity_id, rule_type, target_price, drop_percent, drop_amount,
+    reference_price, last_status, last_notify_time, create_time, update_time
+)
+SELECT CAST(substr(user_id, 4) AS INTEGER), activity_id, rule_type, target_price, drop_percent, drop_amount,
+       reference_price, last_status, last_notify_time, create_time, update_time
+FROM notification_config
+WHERE user_id LIKE 'wl\_%' ESCAPE '\';
+
+DELETE FROM notification_config WHERE user_id LIKE 'wl\_%' ESCAPE '\';
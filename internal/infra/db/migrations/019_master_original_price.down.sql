@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_master_price_ratio;
+DROP INDEX IF EXISTS idx_master_price_sort;
+CREATE INDEX IF NOT EXISTS idx_master_price ON master_product(price, id);
+DROP TRIGGER IF EXISTS price_trend_original_price;
+DROP TRIGGER IF EXISTS master_product_original_price_update;
+DROP TRIGGER IF EXISTS master_product_original_price_insert;
+ALTER TABLE master_product DROP COLUMN original_price;
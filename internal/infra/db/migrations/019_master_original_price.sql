@@ -0,0 +1,37 @@
+-- 原价（历史最高价）保存在标准商品上，由触发器随现价与价格趋势更新，
+-- 商品列表按价格、折扣与降幅排序时无需逐行聚合价格趋势
+ALTER TABLE master_product ADD COLUMN original_price REAL NOT NULL DEFAULT 0;
+
+UPDATE master_product
+SET original_price = MAX(COALESCE(price, 0), COALESCE(
+    (SELECT MAX(t.price) FROM product_price_trend t WHERE t.activity_id = master_product.id), 0
+));
+
+CREATE TRIGGER IF NOT EXISTS master_product_original_price_insert AFTER INSERT ON master_product
+BEGIN
+    UPDATE master_product
+    SET original_price = MAX(COALESCE(NEW.price, 0), COALESCE(
+        (SELECT MAX(t.price) FROM product_price_trend t WHERE t.activity_id = NEW.id), 0
+    ))
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_product_original_price_update AFTER UPDATE OF price ON master_product
+WHEN COALESCE(NEW.price, 0) > NEW.original_price
+BEGIN
+    UPDATE master_product SET original_price = NEW.price WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS price_trend_original_price AFTER INSERT ON product_price_trend
+BEGIN
+    UPDATE master_product SET original_price = NEW.price
+    WHERE id = NEW.activity_id AND original_price < NEW.price;
+END;
+
+-- 排序索引：表达式须与商品列表查询中的写法一致
+DROP INDEX IF EXISTS idx_master_price;
+CREATE INDEX IF NOT EXISTS idx_master_price_sort ON master_product(COALESCE(price, 0), id);
+-- 现价与原价之比，折扣与降幅排序共用，原价未知时为 1
+CREATE INDEX IF NOT EXISTS idx_master_price_ratio ON master_product(
+    (CASE WHEN original_price > 0 THEN COALESCE(price, 0) / original_price ELSE 1 END), id
+);
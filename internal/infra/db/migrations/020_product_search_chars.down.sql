@@ -0,0 +1,66 @@
+DROP TRIGGER IF EXISTS product_search_delete;
+DROP TRIGGER IF EXISTS product_search_update;
+DROP TRIGGER IF EXISTS product_search_insert;
+DROP TRIGGER IF EXISTS master_shop_search_delete;
+DROP TRIGGER IF EXISTS master_shop_search_insert;
+DROP TRIGGER IF EXISTS master_product_search_delete;
+DROP TRIGGER IF EXISTS master_product_search_update;
+DROP TRIGGER IF EXISTS master_product_search_insert;
+DROP VIEW IF EXISTS product_search_document;
+DROP TABLE IF EXISTS search_char_position;
+DROP TABLE IF EXISTS product_search;
+DROP INDEX IF EXISTS idx_master_shop_name;
+DROP TABLE IF EXISTS master_shop;
+
+-- 恢复 trigram 索引
+CREATE VIRTUAL TABLE IF NOT EXISTS product_search USING fts5(
+    title,
+    shop_name,
+    activity_id UNINDEXED,
+    source UNINDEXED,          -- master: 标准商品库, product: 平台商品
+    tokenize = 'trigram'
+);
+
+-- 重新导入已有商品
+INSERT INTO product_search (title, shop_name, activity_id, source)
+SELECT standard_title, '', id, 'master' FROM master_product;
+
+INSERT INTO product_search (title, shop_name, activity_id, source)
+SELECT COALESCE(title, ''), COALESCE(shop_name, ''), activity_id, 'product' FROM product;
+
+-- 触发器保持索引与商品表同步
+CREATE TRIGGER IF NOT EXISTS master_product_search_insert AFTER INSERT ON master_product
+BEGIN
+    INSERT INTO product_search (title, shop_name, activity_id, source)
+    VALUES (new.standard_title, '', new.id, 'master');
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_product_search_update AFTER UPDATE OF id, standard_title ON master_product
+BEGIN
+    DELETE FROM product_search WHERE source = 'master' AND activity_id = old.id;
+    INSERT INTO product_search (title, shop_name, activity_id, source)
+    VALUES (new.standard_title, '', new.id, 'master');
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_product_search_delete AFTER DELETE ON master_product
+BEGIN
+    DELETE FROM product_search WHERE source = 'master' AND activity_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS product_search_insert AFTER INSERT ON product
+BEGIN
+    INSERT INTO product_search (title, shop_name, activity_id, source)
+    VALUES (COALESCE(new.title, ''), COALESCE(new.shop_name, ''), new.activity_id, 'product');
+END;
+
+CREATE TRIGGER IF NOT EXISTS product_search_update AFTER UPDATE OF activity_id, title, shop_name ON product
+BEGIN
+    DELETE FROM product_search WHERE source = 'product' AND activity_id = old.activity_id;
+    INSERT INTO product_search (title, shop_name, activity_id, source)
+    VALUES (COALESCE(new.title, ''), COALESCE(new.shop_name, ''), new.activity_id, 'product');
+END;
+
+CREATE TRIGGER IF NOT EXISTS product_search_delete AFTER DELETE ON product
+BEGIN
+    DELETE FROM product_search WHERE source = 'product' AND activity_id = old.activity_id;
+END;
@@ -0,0 +1,125 @@
+-- 标准商品的在售店铺：同步时平台商品匹配到标准商品后记录其店铺名，
+-- 标准商品按店铺名搜索与同店铺相关商品都依赖此表
+CREATE TABLE IF NOT EXISTS master_shop (
+    master_id TEXT NOT NULL,
+    shop_name TEXT NOT NULL,
+    create_time TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (master_id, shop_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_master_shop_name ON master_shop(shop_name);
+
+-- 商品全文搜索改为逐字索引：trigram 无法匹配一两个字的词，
+-- 标题与店铺名按字拆开、以空格分隔后交给 unicode61 分词，每个字即一个词元，
+-- 任意长度的词都可按字查询，整词匹配即相邻字的短语查询
+DROP TRIGGER IF EXISTS product_search_delete;
+DROP TRIGGER IF EXISTS product_search_update;
+DROP TRIGGER IF EXISTS product_search_insert;
+DROP TRIGGER IF EXISTS master_product_search_delete;
+DROP TRIGGER IF EXISTS master_product_search_update;
+DROP TRIGGER IF EXISTS master_product_search_insert;
+DROP TABLE IF EXISTS product_search;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS product_search USING fts5(
+    title_chars,
+    shop_chars,
+    title UNINDEXED,
+    shop_name UNINDEXED,
+    activity_id UNINDEXED,
+    source UNINDEXED,          -- master: 标准商品库, product: 平台商品
+    tokenize = 'unicode61'
+);
+
+-- 拆字用的位置表，超出部分的字不进索引，由查询的 LIKE 兜底
+CREATE TABLE IF NOT EXISTS search_char_position (n INTEGER PRIMARY KEY);
+
+WITH RECURSIVE position(n) AS (
+    SELECT 1 UNION ALL SELECT n + 1 FROM position WHERE n < 512
+)
+INSERT OR IGNORE INTO search_char_position (n) SELECT n FROM position;
+
+-- 待索引的文档：标准商品的店铺名为其各在售店铺，以空格分隔
+CREATE VIEW IF NOT EXISTS product_search_document AS
+SELECT
+    d.activity_id,
+    d.source,
+    d.title,
+    d.shop_name,
+    COALESCE((SELECT group_concat(substr(d.title, p.n, 1), ' ' ORDER BY p.n)
+              FROM search_char_position p WHERE p.n <= length(d.title)), '') AS title_chars,
+    COALESCE((SELECT group_concat(substr(d.shop_name, p.n, 1), ' ' ORDER BY p.n)
+              FROM search_char_position p WHERE p.n <= length(d.shop_name)), '') AS shop_chars
+FROM (
+    SELECT
+        m.id AS activity_id,
+        'master' AS source,
+        m.standard_title AS title,
+        COALESCE((SELECT group_concat(s.shop_name, ' ' ORDER BY s.shop_name)
+                  FROM master_shop s WHERE s.master_id = m.id), '') AS shop_name
+    FROM master_product m
+    UNION ALL
+    SELECT activity_id, 'product', COALESCE(title, ''), COALESCE(shop_name, '')
+    FROM product
+) d;
+
+INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+SELECT title_chars, shop_chars, title, shop_name, activity_id, source FROM product_search_document;
+
+-- 触发器保持索引与商品表、店铺表同步
+CREATE TRIGGER IF NOT EXISTS master_product_search_insert AFTER INSERT ON master_product
+BEGIN
+    INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+    SELECT title_chars, shop_chars, title, shop_name, activity_id, source
+    FROM product_search_document WHERE source = 'master' AND activity_id = new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_product_search_update AFTER UPDATE OF id, standard_title ON master_product
+BEGIN
+    UPDATE master_shop SET master_id = new.id WHERE master_id = old.id AND new.id <> old.id;
+    DELETE FROM product_search WHERE source = 'master' AND activity_id IN (old.id, new.id);
+    INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+    SELECT title_chars, shop_chars, title, shop_name, activity_id, source
+    FROM product_search_document WHERE source = 'master' AND activity_id = new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_product_search_delete AFTER DELETE ON master_product
+BEGIN
+    DELETE FROM master_shop WHERE master_id = old.id;
+    DELETE FROM product_search WHERE source = 'master' AND activity_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_shop_search_insert AFTER INSERT ON master_shop
+BEGIN
+    DELETE FROM product_search WHERE source = 'master' AND activity_id = new.master_id;
+    INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+    SELECT title_chars, shop_chars, title, shop_name, activity_id, source
+    FROM product_search_document WHERE source = 'master' AND activity_id = new.master_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS master_shop_search_delete AFTER DELETE ON master_shop
+BEGIN
+    DELETE FROM product_search WHERE source = 'master' AND activity_id = old.master_id;
+    INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+    SELECT title_chars, shop_chars, title, shop_name, activity_id, source
+    FROM product_search_document WHERE source = 'master' AND activity_id = old.master_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS product_search_insert AFTER INSERT ON product
+BEGIN
+    INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+    SELECT title_chars, shop_chars, title, shop_name, activity_id, source
+    FROM product_search_document WHERE source = 'product' AND activity_id = new.activity_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS product_search_update AFTER UPDATE OF activity_id, title, shop_name ON product
+BEGIN
+    DELETE FROM product_search WHERE source = 'product' AND activity_id IN (old.activity_id, new.activity_id);
+    INSERT INTO product_search (title_chars, shop_chars, title, shop_name, activity_id, source)
+    SELECT title_chars, shop_chars, title, shop_name, activity_id, source
+    FROM product_search_document WHERE source = 'product' AND activity_id = new.activity_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS product_search_delete AFTER DELETE ON product
+BEGIN
+    DELETE FROM product_search WHERE source = 'product' AND activity_id = old.activity_id;
+END;
@@ -3,10 +3,13 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	dbSqlc "kbfood/internal/infra/db/sqlc"
+	"kbfood/internal/pkg/tracing"
 
 	_ "modernc.org/sqlite"
 )
@@ -24,10 +27,27 @@ type Config struct {
 	MaxIdleConns    int           `envconfig:"MAX_IDLE_CONNS" default:"5"`
 	ConnMaxLifetime time.Duration `envconfig:"MAX_LIFETIME" default:"1h"`
 	ConnMaxIdleTime time.Duration `envconfig:"MAX_IDLE_TIME" default:"30m"`
+	// Directory of .sql migrations used instead of the embedded ones, for development
+	MigrationsDir string `envconfig:"MIGRATIONS_DIR"`
 }
 
-// NewPool creates a new database connection pool
+// NewPool creates a new database connection pool and applies the pending migrations
 func NewPool(ctx context.Context, cfg *Config) (*Pool, error) {
+	pool, err := Open(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.RunMigrations(MigrationsFS(cfg.MigrationsDir)); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("run migrations: %w", err)
+	}
+
+	return pool, nil
+}
+
+// Open creates a new database connection pool without running migrations
+func Open(ctx context.Context, cfg *Config) (*Pool, error) {
 	db, err := sql.Open("sqlite", cfg.Path)
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
@@ -52,14 +72,7 @@ func NewPool(ctx context.Context, cfg *Config) (*Pool, error) {
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	pool := &Pool{DB: db}
-
-	// Run migrations
-	if err := pool.RunMigrations(); err != nil {
-		return nil, fmt.Errorf("run migrations: %w", err)
-	}
-
-	return pool, nil
+	return &Pool{DB: db}, nil
 }
 
 // Ping checks if the database connection is alive
@@ -74,5 +87,110 @@ func (p *Pool) Close() {
 
 // Queries returns a new Queries instance bound to this pool
 func (p *Pool) Queries() *dbSqlc.Queries {
-	return dbSqlc.New(p.DB)
+	return dbSqlc.New(p)
+}
+
+// ExecContext executes a statement, recording a span in the trace of ctx
+func (p *Pool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execTraced(ctx, p.DB, query, args...)
+}
+
+// QueryContext executes a query, recording a span in the trace of ctx. The
+// span covers running the query, not reading the rows.
+func (p *Pool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return queryTraced(ctx, p.DB, query, args...)
+}
+
+// QueryRowContext executes a query returning at most one row, recording a
+// span in the trace of ctx
+func (p *Pool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return queryRowTraced(ctx, p.DB, query, args...)
+}
+
+// BeginTx starts a transaction whose statements are traced like those of the pool
+func (p *Pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := p.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// Tx wraps sql.Tx, recording a span for each statement
+type Tx struct {
+	*sql.Tx
+}
+
+// Queries returns a new Queries instance bound to this transaction
+func (t *Tx) Queries() *dbSqlc.Queries {
+	return dbSqlc.New(t)
+}
+
+// ExecContext executes a statement in the transaction, recording a span in the trace of ctx
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return execTraced(ctx, t.Tx, query, args...)
+}
+
+// QueryContext executes a query in the transaction, recording a span in the
+// trace of ctx. The span covers running the query, not reading the rows.
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return queryTraced(ctx, t.Tx, query, args...)
+}
+
+// QueryRowContext executes a query in the transaction returning at most one
+// row, recording a span in the trace of ctx
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return queryRowTraced(ctx, t.Tx, query, args...)
+}
+
+// statementRunner is implemented by both sql.DB and sql.Tx
+type statementRunner interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func execTraced(ctx context.Context, runner statementRunner, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	result, err := runner.ExecContext(ctx, query, args...)
+	span.RecordError(err)
+	return result, err
+}
+
+func queryTraced(ctx context.Context, runner statementRunner, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := runner.QueryContext(ctx, query, args...)
+	span.RecordError(err)
+	return rows, err
+}
+
+func queryRowTraced(ctx context.Context, runner statementRunner, query string, args ...any) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	row := runner.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+	}
+	return row
+}
+
+// maxTracedStatement limits the statement length recorded in spans
+const maxTracedStatement = 1000
+
+// startQuerySpan starts a span named after the SQL operation, e.g. "db SELECT"
+func startQuerySpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	statement := strings.Join(strings.Fields(query), " ")
+	operation, _, _ := strings.Cut(statement, " ")
+	if len(statement) > maxTracedStatement {
+		statement = statement[:maxTracedStatement]
+	}
+	return tracing.Start(ctx, "db "+strings.ToUpper(operation), tracing.KindClient,
+		tracing.String("db.system", "sqlite"),
+		tracing.String("db.statement", statement),
+	)
 }
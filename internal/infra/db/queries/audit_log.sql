@@ -0,0 +1,8 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_log (user_id, username, action, params, status)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListAuditLogs :many
+SELECT * FROM audit_log
+ORDER BY id DESC
+LIMIT ?;
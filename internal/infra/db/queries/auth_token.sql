@@ -0,0 +1,27 @@
+-- name: GetAuthTokenByHash :one
+SELECT * FROM auth_token WHERE token_hash = ?;
+
+-- name: ListAuthTokensByUser :many
+SELECT * FROM auth_token
+WHERE user_id = ?
+ORDER BY id DESC;
+
+-- name: CreateAuthToken :one
+INSERT INTO auth_token (token_hash, user_id, kind, name, expire_time)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateAuthTokenLastUsed :exec
+UPDATE auth_token
+SET last_used_time = ?
+WHERE id = ?;
+
+-- name: DeleteAuthToken :execrows
+DELETE FROM auth_token WHERE id = ? AND user_id = ?;
+
+-- name: DeleteAuthTokenByHash :exec
+DELETE FROM auth_token WHERE token_hash = ?;
+
+-- name: DeleteExpiredAuthTokens :exec
+DELETE FROM auth_token
+WHERE expire_time IS NOT NULL AND expire_time <= ?;
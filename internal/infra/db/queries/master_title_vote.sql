@@ -0,0 +1,11 @@
+-- name: AddMasterTitleVotes :exec
+INSERT INTO master_title_vote (master_id, title, votes)
+VALUES (?, ?, ?)
+ON CONFLICT (master_id, title) DO UPDATE
+SET votes = master_title_vote.votes + excluded.votes,
+    update_time = datetime('now');
+
+-- name: ListMasterTitleVotes :many
+SELECT * FROM master_title_vote
+WHERE master_id = ?
+ORDER BY votes DESC, title ASC;
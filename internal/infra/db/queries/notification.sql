@@ -5,22 +5,41 @@ WHERE activity_id = ? AND user_id = ?;
 -- name: ListNotificationsByUser :many
 SELECT * FROM notification_config WHERE user_id = ?;
 
+-- name: ListNotificationsByActivity :many
+SELECT * FROM notification_config WHERE activity_id = ?;
+
 -- name: ListAllNotifications :many
 SELECT * FROM notification_config;
 
 -- name: UpsertNotification :exec
-INSERT INTO notification_config (activity_id, user_id, target_price, last_notify_time)
-VALUES (?, ?, ?, ?)
+INSERT INTO notification_config (
+  activity_id, user_id, rule_type, target_price, drop_percent, drop_amount,
+  reference_price, last_status, last_notify_time, snooze_until
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
 ON CONFLICT (activity_id, user_id) DO UPDATE
-SET target_price = excluded.target_price,
+SET rule_type = excluded.rule_type,
+    target_price = excluded.target_price,
+    drop_percent = excluded.drop_percent,
+    drop_amount = excluded.drop_amount,
+    reference_price = excluded.reference_price,
+    last_status = excluded.last_status,
     last_notify_time = excluded.last_notify_time,
+    snooze_until = excluded.snooze_until,
     update_time = datetime('now');
 
 -- name: UpdateNotificationNotifyTime :exec
 UPDATE notification_config
 SET last_notify_time = datetime('now'),
+    reference_price = ?,
     update_time = datetime('now')
 WHERE activity_id = ? AND user_id = ?;
 
+-- name: UpdateNotificationLastStatus :exec
+UPDATE notification_config
+SET last_status = ?
+WHERE activity_id = ? AND user_id = ?;
+
 -- name: DeleteNotification :exec
 DELETE FROM notification_config WHERE activity_id = ? AND user_id = ?;
@@ -0,0 +1,20 @@
+-- name: CreateNotificationDelivery :exec
+INSERT INTO notification_delivery (user_id, activity_id, message, status, sent_time)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: CountSentNotificationsSince :one
+SELECT COUNT(*) FROM notification_delivery
+WHERE user_id = ? AND status = 'sent' AND sent_time >= ?;
+
+-- name: ListHeldNotifications :many
+SELECT * FROM notification_delivery
+WHERE status = 'held'
+ORDER BY create_time, id;
+
+-- name: MarkNotificationDeliverySent :exec
+UPDATE notification_delivery
+SET status = 'sent', sent_time = ?
+WHERE id = ?;
+
+-- name: DeleteSentNotificationsBefore :exec
+DELETE FROM notification_delivery WHERE status = 'sent' AND sent_time < ?;
@@ -52,3 +52,9 @@ DELETE FROM product WHERE platform = ?;
 
 -- name: CountByPlatform :one
 SELECT COUNT(*) FROM product WHERE platform = ?;
+
+-- name: ListProductsByShop :many
+SELECT * FROM product
+WHERE shop_name = ? AND activity_id != ?
+ORDER BY activity_create_time DESC
+LIMIT ?;
@@ -0,0 +1,20 @@
+-- name: GetProductAnnotation :one
+SELECT * FROM product_annotation
+WHERE activity_id = ? AND user_id = ?;
+
+-- name: ListProductAnnotationsByUser :many
+SELECT * FROM product_annotation
+WHERE user_id = ?
+ORDER BY update_time DESC;
+
+-- name: UpsertProductAnnotation :exec
+INSERT INTO product_annotation (activity_id, user_id, favorite, tags, note)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (activity_id, user_id) DO UPDATE
+SET favorite = excluded.favorite,
+    tags = excluded.tags,
+    note = excluded.note,
+    update_time = datetime('now');
+
+-- name: DeleteProductAnnotation :exec
+DELETE FROM product_annotation WHERE activity_id = ? AND user_id = ?;
@@ -0,0 +1,31 @@
+-- name: GetSavedSearch :one
+SELECT * FROM saved_search
+WHERE id = ? AND user_id = ?;
+
+-- name: ListSavedSearchesByUser :many
+SELECT * FROM saved_search
+WHERE user_id = ?
+ORDER BY create_time DESC, id DESC;
+
+-- name: ListAllSavedSearches :many
+SELECT * FROM saved_search;
+
+-- name: CreateSavedSearch :one
+INSERT INTO saved_search (user_id, name, keyword, region, platform, max_price)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateSavedSearch :exec
+UPDATE saved_search
+SET name = ?, keyword = ?, region = ?, platform = ?, max_price = ?, update_time = datetime('now')
+WHERE id = ? AND user_id = ?;
+
+-- name: DeleteSavedSearch :exec
+DELETE FROM saved_search WHERE id = ? AND user_id = ?;
+
+-- name: DeleteSavedSearchMatches :exec
+DELETE FROM saved_search_match WHERE search_id = ?;
+
+-- name: CreateSavedSearchMatch :execrows
+INSERT OR IGNORE INTO saved_search_match (search_id, activity_id)
+VALUES (?, ?);
@@ -0,0 +1,13 @@
+-- name: GetUser :one
+SELECT * FROM app_user WHERE id = ?;
+
+-- name: GetUserByUsername :one
+SELECT * FROM app_user WHERE username = ?;
+
+-- name: CreateUser :exec
+INSERT INTO app_user (id, username, password_hash, role)
+VALUES (?, ?, ?, ?);
+
+-- name: UpdateUserRole :execrows
+UPDATE app_user SET role = ?, update_time = datetime('now')
+WHERE username = ?;
@@ -7,3 +7,37 @@ VALUES (?, ?)
 ON CONFLICT (user_id) DO UPDATE
 SET bark_key = excluded.bark_key,
     update_time = datetime('now');
+
+-- name: UpsertUserPreferences :exec
+INSERT INTO user_settings (
+  user_id, bark_key, timezone, quiet_start, quiet_end,
+  max_per_hour, max_per_day, bark_level, bark_sound,
+  digest_enabled, digest_time, bark_group, bark_icon,
+  bark_archive, bark_encrypt_key, bark_encrypt_iv
+) VALUES (
+  ?, '', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET timezone = excluded.timezone,
+    quiet_start = excluded.quiet_start,
+    quiet_end = excluded.quiet_end,
+    max_per_hour = excluded.max_per_hour,
+    max_per_day = excluded.max_per_day,
+    bark_level = excluded.bark_level,
+    bark_sound = excluded.bark_sound,
+    digest_enabled = excluded.digest_enabled,
+    digest_time = excluded.digest_time,
+    bark_group = excluded.bark_group,
+    bark_icon = excluded.bark_icon,
+    bark_archive = excluded.bark_archive,
+    bark_encrypt_key = excluded.bark_encrypt_key,
+    bark_encrypt_iv = excluded.bark_encrypt_iv,
+    update_time = datetime('now');
+
+-- name: ListDigestSubscribers :many
+SELECT * FROM user_settings WHERE digest_enabled = 1;
+
+-- name: UpdateUserLastDigestTime :exec
+UPDATE user_settings
+SET last_digest_time = ?
+WHERE user_id = ?;
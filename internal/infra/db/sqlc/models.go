@@ -8,6 +8,36 @@ import (
 	"database/sql"
 )
 
+type AppUser struct {
+	ID           string `json:"id"`
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+	CreateTime   string `json:"create_time"`
+	UpdateTime   string `json:"update_time"`
+	Role         string `json:"role"`
+}
+
+type AuditLog struct {
+	ID         int64  `json:"id"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	Action     string `json:"action"`
+	Params     string `json:"params"`
+	Status     int64  `json:"status"`
+	CreateTime string `json:"create_time"`
+}
+
+type AuthToken struct {
+	ID           int64          `json:"id"`
+	TokenHash    string         `json:"token_hash"`
+	UserID       string         `json:"user_id"`
+	Kind         string         `json:"kind"`
+	Name         string         `json:"name"`
+	ExpireTime   sql.NullString `json:"expire_time"`
+	LastUsedTime sql.NullString `json:"last_used_time"`
+	CreateTime   string         `json:"create_time"`
+}
+
 type BlockedProduct struct {
 	ActivityID string `json:"activity_id"`
 	UserID     string `json:"user_id"`
@@ -28,6 +58,12 @@ type CandidateItem struct {
 	UpdateTime       string          `json:"update_time"`
 }
 
+type ClientClaim struct {
+	ClientID   string `json:"client_id"`
+	UserID     string `json:"user_id"`
+	CreateTime string `json:"create_time"`
+}
+
 type MasterProduct struct {
 	ID            string          `json:"id"`
 	Region        string          `json:"region"`
@@ -40,13 +76,36 @@ type MasterProduct struct {
 	Platform      sql.NullString  `json:"platform"`
 }
 
+type MasterTitleVote struct {
+	MasterID   string `json:"master_id"`
+	Title      string `json:"title"`
+	Votes      int64  `json:"votes"`
+	UpdateTime string `json:"update_time"`
+}
+
 type NotificationConfig struct {
-	ActivityID     string         `json:"activity_id"`
-	UserID         string         `json:"user_id"`
-	TargetPrice    float64        `json:"target_price"`
-	LastNotifyTime sql.NullString `json:"last_notify_time"`
-	CreateTime     string         `json:"create_time"`
-	UpdateTime     string         `json:"update_time"`
+	ActivityID     string          `json:"activity_id"`
+	UserID         string          `json:"user_id"`
+	TargetPrice    float64         `json:"target_price"`
+	LastNotifyTime sql.NullString  `json:"last_notify_time"`
+	CreateTime     string          `json:"create_time"`
+	UpdateTime     string          `json:"update_time"`
+	RuleType       string          `json:"rule_type"`
+	DropPercent    sql.NullFloat64 `json:"drop_percent"`
+	DropAmount     sql.NullFloat64 `json:"drop_amount"`
+	ReferencePrice sql.NullFloat64 `json:"reference_price"`
+	LastStatus     sql.NullInt64   `json:"last_status"`
+	SnoozeUntil    sql.NullString  `json:"snooze_until"`
+}
+
+type NotificationDelivery struct {
+	ID         int64          `json:"id"`
+	UserID     string         `json:"user_id"`
+	ActivityID string         `json:"activity_id"`
+	Message    string         `json:"message"`
+	Status     string         `json:"status"`
+	CreateTime string         `json:"create_time"`
+	SentTime   sql.NullString `json:"sent_time"`
 }
 
 type Product struct {
@@ -64,6 +123,16 @@ type Product struct {
 	UpdateTime         string          `json:"update_time"`
 }
 
+type ProductAnnotation struct {
+	ActivityID string `json:"activity_id"`
+	UserID     string `json:"user_id"`
+	Favorite   int64  `json:"favorite"`
+	Tags       string `json:"tags"`
+	Note       string `json:"note"`
+	CreateTime string `json:"create_time"`
+	UpdateTime string `json:"update_time"`
+}
+
 type ProductPriceTrend struct {
 	ID         int64   `json:"id"`
 	ActivityID string  `json:"activity_id"`
@@ -72,6 +141,24 @@ type ProductPriceTrend struct {
 	CreateTime string  `json:"create_time"`
 }
 
+type SavedSearch struct {
+	ID         int64           `json:"id"`
+	UserID     string          `json:"user_id"`
+	Name       string          `json:"name"`
+	Keyword    string          `json:"keyword"`
+	Region     string          `json:"region"`
+	Platform   string          `json:"platform"`
+	MaxPrice   sql.NullFloat64 `json:"max_price"`
+	CreateTime string          `json:"create_time"`
+	UpdateTime string          `json:"update_time"`
+}
+
+type SavedSearchMatch struct {
+	SearchID   int64  `json:"search_id"`
+	ActivityID string `json:"activity_id"`
+	CreateTime string `json:"create_time"`
+}
+
 type SyncStatus struct {
 	ID           int64          `json:"id"`
 	JobName      string         `json:"job_name"`
@@ -84,8 +171,23 @@ type SyncStatus struct {
 }
 
 type UserSetting struct {
-	UserID     string `json:"user_id"`
-	BarkKey    string `json:"bark_key"`
-	CreateTime string `json:"create_time"`
-	UpdateTime string `json:"update_time"`
+	UserID         string         `json:"user_id"`
+	BarkKey        string         `json:"bark_key"`
+	CreateTime     string         `json:"create_time"`
+	UpdateTime     string         `json:"update_time"`
+	Timezone       string         `json:"timezone"`
+	QuietStart     string         `json:"quiet_start"`
+	QuietEnd       string         `json:"quiet_end"`
+	MaxPerHour     int64          `json:"max_per_hour"`
+	MaxPerDay      int64          `json:"max_per_day"`
+	BarkLevel      string         `json:"bark_level"`
+	BarkSound      string         `json:"bark_sound"`
+	DigestEnabled  int64          `json:"digest_enabled"`
+	DigestTime     string         `json:"digest_time"`
+	LastDigestTime sql.NullString `json:"last_digest_time"`
+	BarkGroup      string         `json:"bark_group"`
+	BarkIcon       string         `json:"bark_icon"`
+	BarkArchive    int64          `json:"bark_archive"`
+	BarkEncryptKey string         `json:"bark_encrypt_key"`
+	BarkEncryptIv  string         `json:"bark_encrypt_iv"`
 }
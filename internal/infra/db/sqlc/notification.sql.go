@@ -25,7 +25,7 @@ func (q *Queries) DeleteNotification(ctx context.Context, arg DeleteNotification
 }
 
 const getNotification = `-- name: GetNotification :one
-SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time FROM notification_config
+SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time, rule_type, drop_percent, drop_amount, reference_price, last_status, snooze_until FROM notification_config
 WHERE activity_id = ? AND user_id = ?
 `
 
@@ -44,12 +44,18 @@ func (q *Queries) GetNotification(ctx context.Context, arg GetNotificationParams
 		&i.LastNotifyTime,
 		&i.CreateTime,
 		&i.UpdateTime,
+		&i.RuleType,
+		&i.DropPercent,
+		&i.DropAmount,
+		&i.ReferencePrice,
+		&i.LastStatus,
+		&i.SnoozeUntil,
 	)
 	return i, err
 }
 
 const listAllNotifications = `-- name: ListAllNotifications :many
-SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time FROM notification_config
+SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time, rule_type, drop_percent, drop_amount, reference_price, last_status, snooze_until FROM notification_config
 `
 
 func (q *Queries) ListAllNotifications(ctx context.Context) ([]NotificationConfig, error) {
@@ -68,6 +74,52 @@ func (q *Queries) ListAllNotifications(ctx context.Context) ([]NotificationConfi
 			&i.LastNotifyTime,
 			&i.CreateTime,
 			&i.UpdateTime,
+			&i.RuleType,
+			&i.DropPercent,
+			&i.DropAmount,
+			&i.ReferencePrice,
+			&i.LastStatus,
+			&i.SnoozeUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationsByActivity = `-- name: ListNotificationsByActivity :many
+SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time, rule_type, drop_percent, drop_amount, reference_price, last_status, snooze_until FROM notification_config WHERE activity_id = ?
+`
+
+func (q *Queries) ListNotificationsByActivity(ctx context.Context, activityID string) ([]NotificationConfig, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationsByActivity, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []NotificationConfig{}
+	for rows.Next() {
+		var i NotificationConfig
+		if err := rows.Scan(
+			&i.ActivityID,
+			&i.UserID,
+			&i.TargetPrice,
+			&i.LastNotifyTime,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.RuleType,
+			&i.DropPercent,
+			&i.DropAmount,
+			&i.ReferencePrice,
+			&i.LastStatus,
+			&i.SnoozeUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -83,7 +135,7 @@ func (q *Queries) ListAllNotifications(ctx context.Context) ([]NotificationConfi
 }
 
 const listNotificationsByUser = `-- name: ListNotificationsByUser :many
-SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time FROM notification_config WHERE user_id = ?
+SELECT activity_id, user_id, target_price, last_notify_time, create_time, update_time, rule_type, drop_percent, drop_amount, reference_price, last_status, snooze_until FROM notification_config WHERE user_id = ?
 `
 
 func (q *Queries) ListNotificationsByUser(ctx context.Context, userID string) ([]NotificationConfig, error) {
@@ -102,6 +154,12 @@ func (q *Queries) ListNotificationsByUser(ctx context.Context, userID string) ([
 			&i.LastNotifyTime,
 			&i.CreateTime,
 			&i.UpdateTime,
+			&i.RuleType,
+			&i.DropPercent,
+			&i.DropAmount,
+			&i.ReferencePrice,
+			&i.LastStatus,
+			&i.SnoozeUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -116,45 +174,86 @@ func (q *Queries) ListNotificationsByUser(ctx context.Context, userID string) ([
 	return items, nil
 }
 
+const updateNotificationLastStatus = `-- name: UpdateNotificationLastStatus :exec
+UPDATE notification_config
+SET last_status = ?
+WHERE activity_id = ? AND user_id = ?
+`
+
+type UpdateNotificationLastStatusParams struct {
+	LastStatus sql.NullInt64 `json:"last_status"`
+	ActivityID string        `json:"activity_id"`
+	UserID     string        `json:"user_id"`
+}
+
+func (q *Queries) UpdateNotificationLastStatus(ctx context.Context, arg UpdateNotificationLastStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateNotificationLastStatus, arg.LastStatus, arg.ActivityID, arg.UserID)
+	return err
+}
+
 const updateNotificationNotifyTime = `-- name: UpdateNotificationNotifyTime :exec
 UPDATE notification_config
 SET last_notify_time = datetime('now'),
+    reference_price = ?,
     update_time = datetime('now')
 WHERE activity_id = ? AND user_id = ?
 `
 
 type UpdateNotificationNotifyTimeParams struct {
-	ActivityID string `json:"activity_id"`
-	UserID     string `json:"user_id"`
+	ReferencePrice sql.NullFloat64 `json:"reference_price"`
+	ActivityID     string          `json:"activity_id"`
+	UserID         string          `json:"user_id"`
 }
 
 func (q *Queries) UpdateNotificationNotifyTime(ctx context.Context, arg UpdateNotificationNotifyTimeParams) error {
-	_, err := q.db.ExecContext(ctx, updateNotificationNotifyTime, arg.ActivityID, arg.UserID)
+	_, err := q.db.ExecContext(ctx, updateNotificationNotifyTime, arg.ReferencePrice, arg.ActivityID, arg.UserID)
 	return err
 }
 
 const upsertNotification = `-- name: UpsertNotification :exec
-INSERT INTO notification_config (activity_id, user_id, target_price, last_notify_time)
-VALUES (?, ?, ?, ?)
+INSERT INTO notification_config (
+  activity_id, user_id, rule_type, target_price, drop_percent, drop_amount,
+  reference_price, last_status, last_notify_time, snooze_until
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
 ON CONFLICT (activity_id, user_id) DO UPDATE
-SET target_price = excluded.target_price,
+SET rule_type = excluded.rule_type,
+    target_price = excluded.target_price,
+    drop_percent = excluded.drop_percent,
+    drop_amount = excluded.drop_amount,
+    reference_price = excluded.reference_price,
+    last_status = excluded.last_status,
     last_notify_time = excluded.last_notify_time,
+    snooze_until = excluded.snooze_until,
     update_time = datetime('now')
 `
 
 type UpsertNotificationParams struct {
-	ActivityID     string         `json:"activity_id"`
-	UserID         string         `json:"user_id"`
-	TargetPrice    float64        `json:"target_price"`
-	LastNotifyTime sql.NullString `json:"last_notify_time"`
+	ActivityID     string          `json:"activity_id"`
+	UserID         string          `json:"user_id"`
+	RuleType       string          `json:"rule_type"`
+	TargetPrice    float64         `json:"target_price"`
+	DropPercent    sql.NullFloat64 `json:"drop_percent"`
+	DropAmount     sql.NullFloat64 `json:"drop_amount"`
+	ReferencePrice sql.NullFloat64 `json:"reference_price"`
+	LastStatus     sql.NullInt64   `json:"last_status"`
+	LastNotifyTime sql.NullString  `json:"last_notify_time"`
+	SnoozeUntil    sql.NullString  `json:"snooze_until"`
 }
 
 func (q *Queries) UpsertNotification(ctx context.Context, arg UpsertNotificationParams) error {
 	_, err := q.db.ExecContext(ctx, upsertNotification,
 		arg.ActivityID,
 		arg.UserID,
+		arg.RuleType,
 		arg.TargetPrice,
+		arg.DropPercent,
+		arg.DropAmount,
+		arg.ReferencePrice,
+		arg.LastStatus,
 		arg.LastNotifyTime,
+		arg.SnoozeUntil,
 	)
 	return err
 }
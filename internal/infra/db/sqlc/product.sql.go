@@ -178,6 +178,55 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 	return items, nil
 }
 
+const listProductsByShop = `-- name: ListProductsByShop :many
+SELECT id, activity_id, platform, region, title, shop_name, original_price, current_price, sales_status, activity_create_time, create_time, update_time FROM product
+WHERE shop_name = ? AND activity_id != ?
+ORDER BY activity_create_time DESC
+LIMIT ?
+`
+
+type ListProductsByShopParams struct {
+	ShopName   sql.NullString `json:"shop_name"`
+	ActivityID string         `json:"activity_id"`
+	Limit      int64          `json:"limit"`
+}
+
+func (q *Queries) ListProductsByShop(ctx context.Context, arg ListProductsByShopParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByShop, arg.ShopName, arg.ActivityID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActivityID,
+			&i.Platform,
+			&i.Region,
+			&i.Title,
+			&i.ShopName,
+			&i.OriginalPrice,
+			&i.CurrentPrice,
+			&i.SalesStatus,
+			&i.ActivityCreateTime,
+			&i.CreateTime,
+			&i.UpdateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProductsWithBlockedStatus = `-- name: ListProductsWithBlockedStatus :many
 SELECT p.id, p.activity_id, p.platform, p.region, p.title, p.shop_name, p.original_price, p.current_price, p.sales_status, p.activity_create_time, p.create_time, p.update_time FROM product p
 INNER JOIN blocked_product b ON p.activity_id = b.activity_id
@@ -10,12 +10,22 @@ import (
 )
 
 type Querier interface {
+	AddMasterTitleVotes(ctx context.Context, arg AddMasterTitleVotesParams) error
 	CountByPlatform(ctx context.Context, platform sql.NullString) (int64, error)
+	CountSentNotificationsSince(ctx context.Context, arg CountSentNotificationsSinceParams) (int64, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
+	CreateAuthToken(ctx context.Context, arg CreateAuthTokenParams) (AuthToken, error)
 	CreateBlockedProduct(ctx context.Context, arg CreateBlockedProductParams) error
 	CreateCandidate(ctx context.Context, arg CreateCandidateParams) error
 	CreateMasterProduct(ctx context.Context, arg CreateMasterProductParams) error
+	CreateNotificationDelivery(ctx context.Context, arg CreateNotificationDeliveryParams) error
 	CreateProduct(ctx context.Context, arg CreateProductParams) error
+	CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error)
+	CreateSavedSearchMatch(ctx context.Context, arg CreateSavedSearchMatchParams) (int64, error)
 	CreateTrend(ctx context.Context, arg CreateTrendParams) error
+	CreateUser(ctx context.Context, arg CreateUserParams) error
+	DeleteAuthToken(ctx context.Context, arg DeleteAuthTokenParams) (int64, error)
+	DeleteAuthTokenByHash(ctx context.Context, tokenHash string) error
 	DeleteBlockedProduct(ctx context.Context, arg DeleteBlockedProductParams) error
 	// Delete multiple products by activity IDs
 	// Note: IN clause with multiple values handled in Go code
@@ -25,40 +35,68 @@ type Querier interface {
 	// Delete multiple candidates by IDs
 	// Note: IN clause with multiple values handled in Go code
 	DeleteCandidatesByIDs(ctx context.Context, id int64) error
+	DeleteExpiredAuthTokens(ctx context.Context, expireTime sql.NullString) error
 	DeleteMasterProduct(ctx context.Context, id string) error
 	DeleteNotification(ctx context.Context, arg DeleteNotificationParams) error
 	DeleteProduct(ctx context.Context, id int64) error
+	DeleteProductAnnotation(ctx context.Context, arg DeleteProductAnnotationParams) error
+	DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) error
+	DeleteSavedSearchMatches(ctx context.Context, searchID int64) error
+	DeleteSentNotificationsBefore(ctx context.Context, sentTime sql.NullString) error
 	// Delete multiple trends by activity IDs
 	// Note: IN clause with multiple values handled in Go code
 	DeleteTrendsByActivityIDs(ctx context.Context, activityID string) error
 	ExistsBlockedProduct(ctx context.Context, arg ExistsBlockedProductParams) (bool, error)
+	GetAuthTokenByHash(ctx context.Context, tokenHash string) (AuthToken, error)
 	GetBlockedProduct(ctx context.Context, arg GetBlockedProductParams) (BlockedProduct, error)
 	GetCandidateByID(ctx context.Context, id int64) (CandidateItem, error)
 	GetMasterProductByID(ctx context.Context, id string) (MasterProduct, error)
 	GetNotification(ctx context.Context, arg GetNotificationParams) (NotificationConfig, error)
+	GetProductAnnotation(ctx context.Context, arg GetProductAnnotationParams) (ProductAnnotation, error)
 	GetProductByActivityID(ctx context.Context, activityID string) (Product, error)
+	GetSavedSearch(ctx context.Context, arg GetSavedSearchParams) (SavedSearch, error)
 	GetTrendByActivityIDAndDate(ctx context.Context, arg GetTrendByActivityIDAndDateParams) (ProductPriceTrend, error)
+	GetUser(ctx context.Context, id string) (AppUser, error)
+	GetUserByUsername(ctx context.Context, username string) (AppUser, error)
 	GetUserSettings(ctx context.Context, userID string) (UserSetting, error)
 	ListAllBlockedProducts(ctx context.Context) ([]BlockedProduct, error)
 	ListAllCandidates(ctx context.Context) ([]CandidateItem, error)
 	ListAllMasterProducts(ctx context.Context) ([]MasterProduct, error)
 	ListAllNotifications(ctx context.Context) ([]NotificationConfig, error)
+	ListAllSavedSearches(ctx context.Context) ([]SavedSearch, error)
+	ListAuditLogs(ctx context.Context, limit int64) ([]AuditLog, error)
+	ListAuthTokensByUser(ctx context.Context, userID string) ([]AuthToken, error)
 	ListBlockedProductsByUser(ctx context.Context, userID string) ([]string, error)
 	ListCandidatesByRegion(ctx context.Context, region string) ([]CandidateItem, error)
+	ListDigestSubscribers(ctx context.Context) ([]UserSetting, error)
+	ListHeldNotifications(ctx context.Context) ([]NotificationDelivery, error)
 	ListMasterProductsByPlatform(ctx context.Context, platform sql.NullString) ([]MasterProduct, error)
 	ListMasterProductsByRegion(ctx context.Context, region string) ([]MasterProduct, error)
 	ListMasterProductsByRegionAndPlatform(ctx context.Context, arg ListMasterProductsByRegionAndPlatformParams) ([]MasterProduct, error)
+	ListMasterTitleVotes(ctx context.Context, masterID string) ([]MasterTitleVote, error)
+	ListNotificationsByActivity(ctx context.Context, activityID string) ([]NotificationConfig, error)
 	ListNotificationsByUser(ctx context.Context, userID string) ([]NotificationConfig, error)
+	ListProductAnnotationsByUser(ctx context.Context, userID string) ([]ProductAnnotation, error)
 	ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error)
+	ListProductsByShop(ctx context.Context, arg ListProductsByShopParams) ([]Product, error)
 	ListProductsWithBlockedStatus(ctx context.Context) ([]Product, error)
+	ListSavedSearchesByUser(ctx context.Context, userID string) ([]SavedSearch, error)
 	ListTrendsByActivityID(ctx context.Context, activityID string) ([]ProductPriceTrend, error)
+	MarkNotificationDeliverySent(ctx context.Context, arg MarkNotificationDeliverySentParams) error
+	UpdateAuthTokenLastUsed(ctx context.Context, arg UpdateAuthTokenLastUsedParams) error
 	UpdateCandidate(ctx context.Context, arg UpdateCandidateParams) error
 	UpdateMasterProduct(ctx context.Context, arg UpdateMasterProductParams) error
 	UpdateMasterProductPlatform(ctx context.Context, arg UpdateMasterProductPlatformParams) error
+	UpdateNotificationLastStatus(ctx context.Context, arg UpdateNotificationLastStatusParams) error
 	UpdateNotificationNotifyTime(ctx context.Context, arg UpdateNotificationNotifyTimeParams) error
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) error
 	UpdateProductByActivityID(ctx context.Context, arg UpdateProductByActivityIDParams) error
+	UpdateSavedSearch(ctx context.Context, arg UpdateSavedSearchParams) error
+	UpdateUserLastDigestTime(ctx context.Context, arg UpdateUserLastDigestTimeParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (int64, error)
 	UpsertNotification(ctx context.Context, arg UpsertNotificationParams) error
+	UpsertProductAnnotation(ctx context.Context, arg UpsertProductAnnotationParams) error
+	UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) error
 	UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) error
 }
 
@@ -7,10 +7,11 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const getUserSettings = `-- name: GetUserSettings :one
-SELECT user_id, bark_key, create_time, update_time FROM user_settings WHERE user_id = ?
+SELECT user_id, bark_key, create_time, update_time, timezone, quiet_start, quiet_end, max_per_hour, max_per_day, bark_level, bark_sound, digest_enabled, digest_time, last_digest_time, bark_group, bark_icon, bark_archive, bark_encrypt_key, bark_encrypt_iv FROM user_settings WHERE user_id = ?
 `
 
 func (q *Queries) GetUserSettings(ctx context.Context, userID string) (UserSetting, error) {
@@ -21,10 +22,154 @@ func (q *Queries) GetUserSettings(ctx context.Context, userID string) (UserSetti
 		&i.BarkKey,
 		&i.CreateTime,
 		&i.UpdateTime,
+		&i.Timezone,
+		&i.QuietStart,
+		&i.QuietEnd,
+		&i.MaxPerHour,
+		&i.MaxPerDay,
+		&i.BarkLevel,
+		&i.BarkSound,
+		&i.DigestEnabled,
+		&i.DigestTime,
+		&i.LastDigestTime,
+		&i.BarkGroup,
+		&i.BarkIcon,
+		&i.BarkArchive,
+		&i.BarkEncryptKey,
+		&i.BarkEncryptIv,
 	)
 	return i, err
 }
 
+const listDigestSubscribers = `-- name: ListDigestSubscribers :many
+SELECT user_id, bark_key, create_time, update_time, timezone, quiet_start, quiet_end, max_per_hour, max_per_day, bark_level, bark_sound, digest_enabled, digest_time, last_digest_time, bark_group, bark_icon, bark_archive, bark_encrypt_key, bark_encrypt_iv FROM user_settings WHERE digest_enabled = 1
+`
+
+func (q *Queries) ListDigestSubscribers(ctx context.Context) ([]UserSetting, error) {
+	rows, err := q.db.QueryContext(ctx, listDigestSubscribers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserSetting{}
+	for rows.Next() {
+		var i UserSetting
+		if err := rows.Scan(
+			&i.UserID,
+			&i.BarkKey,
+			&i.CreateTime,
+			&i.UpdateTime,
+			&i.Timezone,
+			&i.QuietStart,
+			&i.QuietEnd,
+			&i.MaxPerHour,
+			&i.MaxPerDay,
+			&i.BarkLevel,
+			&i.BarkSound,
+			&i.DigestEnabled,
+			&i.DigestTime,
+			&i.LastDigestTime,
+			&i.BarkGroup,
+			&i.BarkIcon,
+			&i.BarkArchive,
+			&i.BarkEncryptKey,
+			&i.BarkEncryptIv,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserLastDigestTime = `-- name: UpdateUserLastDigestTime :exec
+UPDATE user_settings
+SET last_digest_time = ?
+WHERE user_id = ?
+`
+
+type UpdateUserLastDigestTimeParams struct {
+	LastDigestTime sql.NullString `json:"last_digest_time"`
+	UserID         string         `json:"user_id"`
+}
+
+func (q *Queries) UpdateUserLastDigestTime(ctx context.Context, arg UpdateUserLastDigestTimeParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserLastDigestTime, arg.LastDigestTime, arg.UserID)
+	return err
+}
+
+const upsertUserPreferences = `-- name: UpsertUserPreferences :exec
+INSERT INTO user_settings (
+  user_id, bark_key, timezone, quiet_start, quiet_end,
+  max_per_hour, max_per_day, bark_level, bark_sound,
+  digest_enabled, digest_time, bark_group, bark_icon,
+  bark_archive, bark_encrypt_key, bark_encrypt_iv
+) VALUES (
+  ?, '', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET timezone = excluded.timezone,
+    quiet_start = excluded.quiet_start,
+    quiet_end = excluded.quiet_end,
+    max_per_hour = excluded.max_per_hour,
+    max_per_day = excluded.max_per_day,
+    bark_level = excluded.bark_level,
+    bark_sound = excluded.bark_sound,
+    digest_enabled = excluded.digest_enabled,
+    digest_time = excluded.digest_time,
+    bark_group = excluded.bark_group,
+    bark_icon = excluded.bark_icon,
+    bark_archive = excluded.bark_archive,
+    bark_encrypt_key = excluded.bark_encrypt_key,
+    bark_encrypt_iv = excluded.bark_encrypt_iv,
+    update_time = datetime('now')
+`
+
+type UpsertUserPreferencesParams struct {
+	UserID         string `json:"user_id"`
+	Timezone       string `json:"timezone"`
+	QuietStart     string `json:"quiet_start"`
+	QuietEnd       string `json:"quiet_end"`
+	MaxPerHour     int64  `json:"max_per_hour"`
+	MaxPerDay      int64  `json:"max_per_day"`
+	BarkLevel      string `json:"bark_level"`
+	BarkSound      string `json:"bark_sound"`
+	DigestEnabled  int64  `json:"digest_enabled"`
+	DigestTime     string `json:"digest_time"`
+	BarkGroup      string `json:"bark_group"`
+	BarkIcon       string `json:"bark_icon"`
+	BarkArchive    int64  `json:"bark_archive"`
+	BarkEncryptKey string `json:"bark_encrypt_key"`
+	BarkEncryptIv  string `json:"bark_encrypt_iv"`
+}
+
+func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserPreferences,
+		arg.UserID,
+		arg.Timezone,
+		arg.QuietStart,
+		arg.QuietEnd,
+		arg.MaxPerHour,
+		arg.MaxPerDay,
+		arg.BarkLevel,
+		arg.BarkSound,
+		arg.DigestEnabled,
+		arg.DigestTime,
+		arg.BarkGroup,
+		arg.BarkIcon,
+		arg.BarkArchive,
+		arg.BarkEncryptKey,
+		arg.BarkEncryptIv,
+	)
+	return err
+}
+
 const upsertUserSettings = `-- name: UpsertUserSettings :exec
 INSERT INTO user_settings (user_id, bark_key)
 VALUES (?, ?)
@@ -1,55 +1,107 @@
 package external
 
 import (
+	"bytes"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"kbfood/internal/domain/entity"
 )
 
+// DefaultBarkURL is the public Bark server
+const DefaultBarkURL = "https://api.day.app"
+
+// BarkMessage is the payload of a Bark push
+type BarkMessage = entity.BarkMessage
+
 // BarkClient handles Bark push notifications
 type BarkClient struct {
-	client *resty.Client
-	baseURL string
+	client    *resty.Client
+	baseURL   string
 	deviceKey string
+
+	// AES-CBC key and IV configured on the device, empty sends plaintext
+	encryptKey string
+	encryptIV  string
 }
 
 // NewBarkClient creates a new Bark client
 func NewBarkClient(deviceKey string) *BarkClient {
-	return &BarkClient{
-		client: resty.New().
-			SetTimeout(10 * time.Second),
-		baseURL:   "https://api.day.app",
-		deviceKey: deviceKey,
-	}
+	return NewBarkClientWithURL(DefaultBarkURL, deviceKey)
 }
 
 // NewBarkClientWithURL creates a new Bark client with custom URL
 func NewBarkClientWithURL(baseURL, deviceKey string) *BarkClient {
+	if baseURL == "" {
+		baseURL = DefaultBarkURL
+	}
 	return &BarkClient{
 		client: resty.New().
 			SetTimeout(10 * time.Second),
-		baseURL:   baseURL,
+		baseURL:   strings.TrimRight(baseURL, "/"),
 		deviceKey: deviceKey,
 	}
 }
 
-// Send sends a push notification
-func (b *BarkClient) Send(ctx context.Context, title, body string, level string) error {
-	url := fmt.Sprintf("%s/%s/%s/%s", b.baseURL, b.deviceKey,
-		url.QueryEscape(title), url.QueryEscape(body))
+// BarkSender sends pushes to the devices of one Bark server
+type BarkSender struct {
+	client  *resty.Client
+	baseURL string
+}
 
-	if level != "" {
-		url += fmt.Sprintf("?level=%s", level)
+// NewBarkSender creates a sender for the Bark server at baseURL, the public
+// server if empty
+func NewBarkSender(baseURL string) *BarkSender {
+	client := NewBarkClientWithURL(baseURL, "")
+	return &BarkSender{client: client.client, baseURL: client.baseURL}
+}
+
+// Push sends a push to a device, encrypted if the device has an encryption key
+func (s *BarkSender) Push(ctx context.Context, device entity.BarkDevice, msg *entity.BarkMessage) error {
+	client := &BarkClient{client: s.client, baseURL: s.baseURL, deviceKey: device.Key}
+	if device.EncryptKey != "" {
+		client = client.WithEncryption(device.EncryptKey, device.EncryptIV)
 	}
+	return client.Push(ctx, msg)
+}
+
+// WithEncryption returns a copy of the client that sends AES-CBC encrypted pushes.
+// key must be 16, 24 or 32 bytes (AES-128/192/256) and iv 16 bytes.
+func (b *BarkClient) WithEncryption(key, iv string) *BarkClient {
+	clone := *b
+	clone.encryptKey = key
+	clone.encryptIV = iv
+	return &clone
+}
 
-	resp, err := b.client.R().
-		SetContext(ctx).
-		Get(url)
+// Push sends a push notification. The message is sent in the request body so
+// that its content never appears in request URLs.
+func (b *BarkClient) Push(ctx context.Context, msg *BarkMessage) error {
+	req := b.client.R().SetContext(ctx)
 
+	if b.encryptKey != "" {
+		ciphertext, err := b.encrypt(msg)
+		if err != nil {
+			return fmt.Errorf("encrypt bark notification: %w", err)
+		}
+		req.SetFormData(map[string]string{
+			"ciphertext": ciphertext,
+			"iv":         b.encryptIV,
+		})
+	} else {
+		req.SetHeader("Content-Type", "application/json; charset=utf-8").
+			SetBody(msg)
+	}
+
+	resp, err := req.Post(b.baseURL + "/" + b.deviceKey)
 	if err != nil {
 		return fmt.Errorf("send bark notification: %w", err)
 	}
@@ -61,6 +113,15 @@ func (b *BarkClient) Send(ctx context.Context, title, body string, level string)
 	return nil
 }
 
+// Send sends a push notification
+func (b *BarkClient) Send(ctx context.Context, title, body string, level string) error {
+	return b.Push(ctx, &BarkMessage{
+		Title: title,
+		Body:  body,
+		Level: level,
+	})
+}
+
 // SendPriceAlert sends a price alert notification
 func (b *BarkClient) SendPriceAlert(ctx context.Context, product *entity.Product, targetPrice float64) error {
 	title := "价格提醒"
@@ -79,3 +140,28 @@ func (b *BarkClient) SendPriceAlert(ctx context.Context, product *entity.Product
 func (b *BarkClient) SendSimple(ctx context.Context, message string) error {
 	return b.Send(ctx, "kbFood", message, "")
 }
+
+// encrypt encrypts the JSON payload with AES-CBC and PKCS7 padding, base64 encoded
+func (b *BarkClient) encrypt(msg *BarkMessage) (string, error) {
+	if len(b.encryptIV) != aes.BlockSize {
+		return "", errors.New("iv must be 16 bytes")
+	}
+
+	plaintext, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher([]byte(b.encryptKey))
+	if err != nil {
+		return "", err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	plaintext = append(plaintext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, []byte(b.encryptIV)).CryptBlocks(ciphertext, plaintext)
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
@@ -0,0 +1,62 @@
+package external
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+)
+
+func TestBarkSender_PushEncrypted(t *testing.T) {
+	const key = "1234567890abcdef"
+	const iv = "fedcba0987654321"
+
+	var got BarkMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/DEVICE123" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.FormValue("iv") != iv {
+			t.Errorf("unexpected iv %q", r.FormValue("iv"))
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(r.FormValue("ciphertext"))
+		if err != nil {
+			t.Fatalf("decode ciphertext: %v", err)
+		}
+		block, _ := aes.NewCipher([]byte(key))
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, []byte(iv)).CryptBlocks(plaintext, ciphertext)
+		plaintext = plaintext[:len(plaintext)-int(plaintext[len(plaintext)-1])]
+
+		if err := json.Unmarshal(plaintext, &got); err != nil {
+			t.Fatalf("unmarshal payload %q: %v", plaintext, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewBarkSender(server.URL + "/")
+	device := entity.BarkDevice{Key: "DEVICE123", EncryptKey: key, EncryptIV: iv}
+	msg := &BarkMessage{Body: "降价啦", Group: "广州", URL: "https://food.example.com/?activityId=DT_1"}
+	if err := sender.Push(context.Background(), device, msg); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	if got != *msg {
+		t.Fatalf("decrypted payload = %+v, want %+v", got, *msg)
+	}
+}
+
+func TestBarkClient_PushRejectsInvalidKey(t *testing.T) {
+	client := NewBarkClientWithURL("http://127.0.0.1:0", "DEVICE123").WithEncryption("short", "fedcba0987654321")
+	if err := client.Push(context.Background(), &BarkMessage{Body: "x"}); err == nil {
+		t.Fatal("expected error for invalid AES key")
+	}
+}
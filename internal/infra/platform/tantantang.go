@@ -13,6 +13,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"kbfood/internal/config"
 	"kbfood/internal/domain/entity"
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/metrics"
+	"kbfood/internal/pkg/tracing"
 )
 
 // TanTanTangClient implements the TanTanTang platform client
@@ -57,11 +60,29 @@ func (c *TanTanTangClient) ShouldFetch(now time.Time) bool {
 
 // FetchProducts fetches products from TanTanTang
 func (c *TanTanTangClient) FetchProducts(ctx context.Context, region string) ([]*entity.PlatformProductDTO, error) {
+	ctx, span := tracing.Start(ctx, "platform.fetch", tracing.KindClient,
+		tracing.String("platform", c.Name()),
+		tracing.String("region", region),
+	)
+	defer span.End()
+
+	products, err := c.fetchProducts(ctx, region)
+	span.SetAttributes(tracing.Int("products", len(products)))
+	span.RecordError(err)
+	return products, err
+}
+
+func (c *TanTanTangClient) fetchProducts(ctx context.Context, region string) ([]*entity.PlatformProductDTO, error) {
+	logger := applog.LoggerFromContext(ctx)
+
 	if !c.ShouldFetch(time.Now()) {
-		log.Info().Msg("Skipping fetch due to maintenance window")
+		logger.Info().Msg("Skipping fetch due to maintenance window")
 		return nil, nil
 	}
 
+	start := time.Now()
+	defer metrics.PlatformFetchDuration.With(c.Name()).ObserveSince(start)
+
 	var allProducts []*entity.PlatformProductDTO
 	page := 1
 	maxPages := 100
@@ -79,7 +100,7 @@ func (c *TanTanTangClient) FetchProducts(ctx context.Context, region string) ([]
 				break
 			}
 
-			log.Warn().
+			logger.Warn().
 				Err(err).
 				Int("page", page).
 				Int("retry", retry+1).
@@ -93,7 +114,7 @@ func (c *TanTanTangClient) FetchProducts(ctx context.Context, region string) ([]
 
 		if err != nil {
 			// If all retries failed, log and continue to next page or return partial results
-			log.Error().
+			logger.Error().
 				Err(err).
 				Int("page", page).
 				Int("productsSoFar", len(allProducts)).
@@ -106,8 +127,10 @@ func (c *TanTanTangClient) FetchProducts(ctx context.Context, region string) ([]
 		}
 
 		allProducts = append(allProducts, products...)
+		metrics.PlatformFetchPages.With(c.Name()).Inc()
+		metrics.PlatformProductsFetched.With(c.Name()).Add(float64(len(products)))
 
-		log.Info().
+		logger.Info().
 			Int("page", page).
 			Int("pageProducts", len(products)).
 			Int("totalProducts", len(allProducts)).
@@ -122,7 +145,7 @@ func (c *TanTanTangClient) FetchProducts(ctx context.Context, region string) ([]
 		time.Sleep(1 * time.Second) // Rate limiting
 	}
 
-	log.Info().
+	logger.Info().
 		Int("totalProducts", len(allProducts)).
 		Int("totalPages", page).
 		Msg("Fetch completed")
@@ -132,6 +155,15 @@ func (c *TanTanTangClient) FetchProducts(ctx context.Context, region string) ([]
 
 // fetchPage fetches a single page of products
 func (c *TanTanTangClient) fetchPage(ctx context.Context, region string, page int) ([]*entity.PlatformProductDTO, bool, error) {
+	ctx, span := tracing.Start(ctx, "platform.fetch_page", tracing.KindClient,
+		tracing.String("platform", c.Name()),
+		tracing.String("region", region),
+		tracing.Int("page", page),
+	)
+	defer span.End()
+
+	logger := applog.LoggerFromContext(ctx)
+
 	regionCfg := GetRegionConfig(region)
 	cityName := regionCfg.CityName
 
@@ -151,7 +183,7 @@ func (c *TanTanTangClient) fetchPage(ctx context.Context, region string, page in
 		tokenPreview = tokenPreview[:10] + "..."
 	}
 
-	log.Debug().
+	logger.Debug().
 		Str("region", region).
 		Str("city", cityName).
 		Int("page", page).
@@ -176,23 +208,25 @@ func (c *TanTanTangClient) fetchPage(ctx context.Context, region string, page in
 		Post(c.cfg.BaseURL)
 
 	if err != nil {
-		log.Error().Err(err).Msg("HTTP request failed")
+		logger.Error().Err(err).Msg("HTTP request failed")
+		metrics.PlatformFetchErrors.With(c.Name(), "HTTP_ERROR").Inc()
+		span.RecordError(err)
 		return nil, false, fmt.Errorf("http request: %w", err)
 	}
 
 	// Log raw response body for debugging
 	rawBody := string(apiResp.Body())
 	if len(resp.Data.Data) == 0 {
-		log.Debug().
+		logger.Debug().
 			Str("rawResponse", rawBody).
 			Msg("Empty API response - raw body")
 	} else {
-		log.Debug().
+		logger.Debug().
 			Int("productCount", len(resp.Data.Data)).
 			Msg("Got products")
 	}
 
-	log.Debug().
+	logger.Debug().
 		Int("statusCode", apiResp.StatusCode()).
 		Int("code", resp.Code).
 		Str("msg", resp.Msg).
@@ -219,7 +253,7 @@ func (c *TanTanTangClient) fetchPage(ctx context.Context, region string, page in
 			errorType = "UNKNOWN_ERROR"
 		}
 
-		log.Warn().
+		logger.Warn().
 			Int("code", resp.Code).
 			Str("msg", resp.Msg).
 			Str("errorType", errorType).
@@ -227,13 +261,17 @@ func (c *TanTanTangClient) fetchPage(ctx context.Context, region string, page in
 			Str("token", tokenPreview).
 			Str("rqToken", rqToken).
 			Msg("API returned error")
+		metrics.PlatformFetchErrors.With(c.Name(), errorType).Inc()
 
 		// Return more specific error message
-		return nil, false, fmt.Errorf("api error [%s]: code=%d, msg=%s", errorType, resp.Code, resp.Msg)
+		err := fmt.Errorf("api error [%s]: code=%d, msg=%s", errorType, resp.Code, resp.Msg)
+		span.SetAttributes(tracing.String("errorType", errorType))
+		span.RecordError(err)
+		return nil, false, err
 	}
 
 	products := c.parseProducts(resp.Data.Data)
-	log.Debug().
+	logger.Debug().
 		Int("productsParsed", len(products)).
 		Msg("Parsed products")
 
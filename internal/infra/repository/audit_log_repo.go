@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type auditLogRepository struct {
+	db *db.Queries
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *db.Queries) repository.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, log *entity.AuditLog) error {
+	err := r.db.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		UserID:   log.UserID,
+		Username: log.Username,
+		Action:   log.Action,
+		Params:   log.Params,
+		Status:   int64(log.Status),
+	})
+	if err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
+
+func (r *auditLogRepository) ListRecent(ctx context.Context, limit int) ([]*entity.AuditLog, error) {
+	logs, err := r.db.ListAuditLogs(ctx, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("list audit logs: %w", err)
+	}
+
+	result := make([]*entity.AuditLog, len(logs))
+	for i, l := range logs {
+		result[i] = convertDBAuditLogToEntity(&l)
+	}
+	return result, nil
+}
+
+// convertDBAuditLogToEntity converts db.AuditLog to entity.AuditLog
+func convertDBAuditLogToEntity(l *db.AuditLog) *entity.AuditLog {
+	return &entity.AuditLog{
+		ID:         l.ID,
+		UserID:     l.UserID,
+		Username:   l.Username,
+		Action:     l.Action,
+		Params:     l.Params,
+		Status:     int(l.Status),
+		CreateTime: parseSQLiteTime(l.CreateTime),
+	}
+}
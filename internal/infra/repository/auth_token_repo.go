@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type authTokenRepository struct {
+	db *db.Queries
+}
+
+// NewAuthTokenRepository creates a new auth token repository
+func NewAuthTokenRepository(db *db.Queries) repository.AuthTokenRepository {
+	return &authTokenRepository{db: db}
+}
+
+func (r *authTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.AuthToken, error) {
+	token, err := r.db.GetAuthTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get auth token: %w", err)
+	}
+	return convertDBAuthTokenToEntity(&token), nil
+}
+
+func (r *authTokenRepository) ListByUser(ctx context.Context, userID string) ([]*entity.AuthToken, error) {
+	tokens, err := r.db.ListAuthTokensByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list auth tokens: %w", err)
+	}
+
+	result := make([]*entity.AuthToken, len(tokens))
+	for i, t := range tokens {
+		result[i] = convertDBAuthTokenToEntity(&t)
+	}
+	return result, nil
+}
+
+func (r *authTokenRepository) Create(ctx context.Context, token *entity.AuthToken) error {
+	var expireTime sql.NullString
+	if token.ExpireTime != nil {
+		expireTime = sql.NullString{String: sqliteDateTime(*token.ExpireTime), Valid: true}
+	}
+
+	created, err := r.db.CreateAuthToken(ctx, db.CreateAuthTokenParams{
+		TokenHash:  token.TokenHash,
+		UserID:     token.UserID,
+		Kind:       token.Kind,
+		Name:       token.Name,
+		ExpireTime: expireTime,
+	})
+	if err != nil {
+		return fmt.Errorf("create auth token: %w", err)
+	}
+
+	token.ID = created.ID
+	token.CreateTime = parseSQLiteTime(created.CreateTime)
+	return nil
+}
+
+func (r *authTokenRepository) Touch(ctx context.Context, id int64, usedAt time.Time) error {
+	err := r.db.UpdateAuthTokenLastUsed(ctx, db.UpdateAuthTokenLastUsedParams{
+		LastUsedTime: sql.NullString{String: sqliteDateTime(usedAt), Valid: true},
+		ID:           id,
+	})
+	if err != nil {
+		return fmt.Errorf("touch auth token: %w", err)
+	}
+	return nil
+}
+
+func (r *authTokenRepository) Delete(ctx context.Context, id int64, userID string) (bool, error) {
+	rows, err := r.db.DeleteAuthToken(ctx, db.DeleteAuthTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("delete auth token: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *authTokenRepository) DeleteByHash(ctx context.Context, tokenHash string) error {
+	if err := r.db.DeleteAuthTokenByHash(ctx, tokenHash); err != nil {
+		return fmt.Errorf("delete auth token by hash: %w", err)
+	}
+	return nil
+}
+
+func (r *authTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	err := r.db.DeleteExpiredAuthTokens(ctx, sql.NullString{String: sqliteDateTime(before), Valid: true})
+	if err != nil {
+		return fmt.Errorf("delete expired auth tokens: %w", err)
+	}
+	return nil
+}
+
+// convertDBAuthTokenToEntity converts db.AuthToken to entity.AuthToken
+func convertDBAuthTokenToEntity(t *db.AuthToken) *entity.AuthToken {
+	token := &entity.AuthToken{
+		ID:         t.ID,
+		TokenHash:  t.TokenHash,
+		UserID:     t.UserID,
+		Kind:       t.Kind,
+		Name:       t.Name,
+		CreateTime: parseSQLiteTime(t.CreateTime),
+	}
+	if t.ExpireTime.Valid {
+		if expireTime := parseSQLiteTime(t.ExpireTime.String); !expireTime.IsZero() {
+			token.ExpireTime = &expireTime
+		}
+	}
+	if t.LastUsedTime.Valid {
+		if lastUsed := parseSQLiteTime(t.LastUsedTime.String); !lastUsed.IsZero() {
+			token.LastUsedTime = &lastUsed
+		}
+	}
+	return token
+}
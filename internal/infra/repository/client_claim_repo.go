@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+type clientClaimRepository struct {
+	db *db.Pool
+}
+
+// NewClientClaimRepository creates a new client claim repository
+func NewClientClaimRepository(db *db.Pool) repository.ClientClaimRepository {
+	return &clientClaimRepository{db: db}
+}
+
+func (r *clientClaimRepository) IsClaimed(ctx context.Context, clientID string) (bool, error) {
+	var count int
+	row := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM client_claim WHERE client_id = ?`, clientID)
+	if err := row.Scan(&count); err != nil {
+		return false, fmt.Errorf("check client claim: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *clientClaimRepository) Claim(ctx context.Context, clientID, userID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin claim tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO client_claim (client_id, user_id)
+		VALUES (?, ?)
+	`, clientID, userID)
+	if err != nil {
+		return false, fmt.Errorf("record client claim: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return false, err
+	}
+
+	// Rows the account already has take precedence over the client's copies
+	if err := moveUserRows(ctx, tx, userOwnedTables, userID, clientID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit claim tx: %w", err)
+	}
+	return true, nil
+}
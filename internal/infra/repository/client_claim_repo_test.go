@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClientClaimRepository_ClaimMovesUserOwnedRows(t *testing.T) {
+	ctx := context.Background()
+	pool := setupUserDataDB(t)
+	repo := NewClientClaimRepository(pool)
+
+	mustExecLegacy(t, pool.DB, `
+		INSERT INTO feed_token (user_id, token) VALUES ('client-a', 'token-a');
+		INSERT INTO blocked_product (activity_id, user_id) VALUES ('DT_1', 'client-a');
+	`)
+
+	claimed, err := repo.Claim(ctx, "client-a", "user-1")
+	if err != nil || !claimed {
+		t.Fatalf("Claim() = %v, %v", claimed, err)
+	}
+
+	for _, table := range userOwnedTables {
+		var left int
+		if err := pool.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table+` WHERE user_id = 'client-a'`).Scan(&left); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if left != 0 {
+			t.Errorf("%s kept %d rows of the claimed client", table, left)
+		}
+	}
+	var token string
+	if err := pool.QueryRowContext(ctx, `SELECT token FROM feed_token WHERE user_id = 'user-1'`).Scan(&token); err != nil || token != "token-a" {
+		t.Fatalf("feed token of the account = %q, %v", token, err)
+	}
+}
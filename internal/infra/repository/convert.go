@@ -36,6 +36,13 @@ func sqlNullTime(t interface{}) sql.NullTime {
 	return sql.NullTime{}
 }
 
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func sqlNullBool(b bool) sql.NullBool {
 	return sql.NullBool{Bool: b, Valid: true}
 }
@@ -120,6 +127,12 @@ func parseSQLiteDate(s string) time.Time {
 	return t
 }
 
+// sqliteDateTime formats a time the way SQLite's datetime('now') does (UTC),
+// so that stored values compare correctly as strings
+func sqliteDateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
 func sqlNullInt64FromInt(i int) sql.NullInt64 {
 	return sql.NullInt64{Int64: int64(i), Valid: true}
 }
@@ -144,3 +157,33 @@ func sqlNullStringFromTime(t time.Time) sql.NullString {
 	}
 	return sql.NullString{String: timeToSQLite(t), Valid: true}
 }
+
+func sqlNullFloat64FromPtr(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
+
+func sqlNullInt64FromPtr(i *int) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*i), Valid: true}
+}
+
+func float64PtrFromNull(nf sql.NullFloat64) *float64 {
+	if !nf.Valid {
+		return nil
+	}
+	f := nf.Float64
+	return &f
+}
+
+func intPtrFromNull(ni sql.NullInt64) *int {
+	if !ni.Valid {
+		return nil
+	}
+	i := int(ni.Int64)
+	return &i
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+type deviceRepository struct {
+	db *db.Pool
+}
+
+// NewDeviceRepository creates a new device repository
+func NewDeviceRepository(db *db.Pool) repository.DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) CreatePairingCode(ctx context.Context, code *entity.PairingCode) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin pairing code tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM pairing_code WHERE user_id = ?`, code.UserID); err != nil {
+		return false, fmt.Errorf("delete earlier pairing codes: %w", err)
+	}
+
+	var createTime string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO pairing_code (code, user_id, expire_time)
+		VALUES (?, ?, ?)
+		ON CONFLICT(code) DO NOTHING
+		RETURNING create_time
+	`, code.Code, code.UserID, sqliteDateTime(code.ExpireTime)).Scan(&createTime)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("create pairing code: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit pairing code tx: %w", err)
+	}
+	code.CreateTime = parseSQLiteTime(createTime)
+	return true, nil
+}
+
+func (r *deviceRepository) TakePairingCode(ctx context.Context, code string) (*entity.PairingCode, error) {
+	var pairing entity.PairingCode
+	var expireTime, createTime string
+	err := r.db.QueryRowContext(ctx, `
+		DELETE FROM pairing_code
+		WHERE code = ?
+		RETURNING code, user_id, expire_time, create_time
+	`, code).Scan(&pairing.Code, &pairing.UserID, &expireTime, &createTime)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("take pairing code: %w", err)
+	}
+
+	pairing.ExpireTime = parseSQLiteTime(expireTime)
+	pairing.CreateTime = parseSQLiteTime(createTime)
+	return &pairing, nil
+}
+
+func (r *deviceRepository) DeleteExpiredPairingCodes(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM pairing_code WHERE expire_time < ?`, sqliteDateTime(before))
+	if err != nil {
+		return fmt.Errorf("delete expired pairing codes: %w", err)
+	}
+	return nil
+}
+
+func (r *deviceRepository) Pair(ctx context.Context, device *entity.Device) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin pair tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var pairedUserID string
+	err = tx.QueryRowContext(ctx, `SELECT user_id FROM device WHERE client_id = ?`, device.ClientID).Scan(&pairedUserID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("check paired device: %w", err)
+	}
+
+	// A client that is already paired uses the data of its current user, which
+	// stays there. Otherwise the client's own data joins the user.
+	if err == sql.ErrNoRows {
+		if _, err := MergeUserData(ctx, tx, device.UserID, device.ClientID); err != nil {
+			return err
+		}
+		if err := moveUserRows(ctx, tx, unmergedUserTables, device.UserID, device.ClientID); err != nil {
+			return err
+		}
+		// Devices paired to the client follow it
+		if _, err := tx.ExecContext(ctx, `UPDATE device SET user_id = ? WHERE user_id = ?`, device.UserID, device.ClientID); err != nil {
+			return fmt.Errorf("move paired devices: %w", err)
+		}
+	}
+
+	var createTime string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO device (client_id, user_id, name)
+		VALUES (?, ?, ?)
+		ON CONFLICT(client_id) DO UPDATE SET
+			user_id = excluded.user_id,
+			name = excluded.name,
+			last_seen_time = NULL,
+			create_time = datetime('now')
+		RETURNING id, create_time
+	`, device.ClientID, device.UserID, device.Name).Scan(&device.ID, &createTime)
+	if err != nil {
+		return fmt.Errorf("pair device: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit pair tx: %w", err)
+	}
+	device.CreateTime = parseSQLiteTime(createTime)
+	return nil
+}
+
+func (r *deviceRepository) FindByClientID(ctx context.Context, clientID string) (*entity.Device, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, client_id, user_id, name, last_seen_time, create_time
+		FROM device
+		WHERE client_id = ?
+	`, clientID)
+
+	device, err := scanDevice(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get device: %w", err)
+	}
+	return device, nil
+}
+
+func (r *deviceRepository) ListByUser(ctx context.Context, userID string) ([]*entity.Device, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, client_id, user_id, name, last_seen_time, create_time
+		FROM device
+		WHERE user_id = ?
+		ORDER BY id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*entity.Device
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate devices: %w", err)
+	}
+	return devices, nil
+}
+
+func (r *deviceRepository) Touch(ctx context.Context, id int64, seenAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE device SET last_seen_time = ? WHERE id = ?`, sqliteDateTime(seenAt), id)
+	if err != nil {
+		return fmt.Errorf("touch device: %w", err)
+	}
+	return nil
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, id int64, userID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM device WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete device: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete device: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// scanDevice scans a device row selected with the columns used above
+func scanDevice(row interface{ Scan(dest ...any) error }) (*entity.Device, error) {
+	var device entity.Device
+	var lastSeen sql.NullString
+	var createTime string
+	if err := row.Scan(&device.ID, &device.ClientID, &device.UserID, &device.Name, &lastSeen, &createTime); err != nil {
+		return nil, err
+	}
+
+	device.CreateTime = parseSQLiteTime(createTime)
+	if lastSeen.Valid {
+		if t := parseSQLiteTime(lastSeen.String); !t.IsZero() {
+			device.LastSeenTime = &t
+		}
+	}
+	return &device, nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"kbfood/internal/domain/entity"
+	db "kbfood/internal/infra/db"
+)
+
+// Legacy user resolution statuses
+const (
+	LegacyUserMigrated = "migrated" // data merged into the only user with the Bark key
+	LegacyUserConflict = "conflict" // several users share the Bark key, data left in place
+	LegacyUserOrphaned = "orphaned" // no current user has the Bark key, data left in place
+)
+
+// LegacyUserResolution reports what happened to the data of one legacy user ID.
+// User IDs are masked because Bark keys and anonymous client IDs act as credentials.
+type LegacyUserResolution struct {
+	LegacyUserID       string   `json:"legacyUserId"`
+	Status             string   `json:"status"`
+	TargetUserID       string   `json:"targetUserId,omitempty"`
+	CandidateUserIDs   []string `json:"candidateUserIds,omitempty"`
+	Settings           bool     `json:"settings"`
+	Notifications      int      `json:"notifications"`
+	BlockedProducts    int      `json:"blockedProducts"`
+	ConflictingRecords int      `json:"conflictingRecords"` // rows under both IDs, resolved by recency
+}
+
+// LegacyMigrationReport summarizes a bulk legacy user migration
+type LegacyMigrationReport struct {
+	DryRun    bool                   `json:"dryRun"`
+	Migrated  int                    `json:"migrated"`
+	Conflicts int                    `json:"conflicts"`
+	Orphaned  int                    `json:"orphaned"`
+	Users     []LegacyUserResolution `json:"users"`
+}
+
+// MigrateLegacyUsers resolves every legacy Bark-key user ID in one pass. Legacy
+// clients used the normalized Bark key as their user ID, so the legacy data of a
+// key belongs to the current user whose settings hold that key. Keys held by
+// several users, or by none, are reported and left untouched. With dryRun the
+// report is produced but all changes are rolled back.
+func MigrateLegacyUsers(ctx context.Context, database *db.Pool, dryRun bool) (*LegacyMigrationReport, error) {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin legacy migration tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	owners, legacyIDs, err := findLegacyUsers(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LegacyMigrationReport{DryRun: dryRun, Users: []LegacyUserResolution{}}
+	for _, legacyUserID := range legacyIDs {
+		resolution := LegacyUserResolution{LegacyUserID: maskUserID(legacyUserID)}
+		if err := countLegacyUserData(ctx, tx, legacyUserID, &resolution); err != nil {
+			return nil, err
+		}
+		if !resolution.Settings && resolution.Notifications == 0 && resolution.BlockedProducts == 0 {
+			continue
+		}
+
+		candidates := owners[legacyUserID]
+		switch len(candidates) {
+		case 0:
+			resolution.Status = LegacyUserOrphaned
+			report.Orphaned++
+		case 1:
+			targetUserID := candidates[0]
+			conflicting, err := countConflictingRecords(ctx, tx, targetUserID, legacyUserID)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := MergeUserData(ctx, tx, targetUserID, legacyUserID); err != nil {
+				return nil, fmt.Errorf("merge legacy user data: %w", err)
+			}
+			resolution.Status = LegacyUserMigrated
+			resolution.TargetUserID = maskUserID(targetUserID)
+			resolution.ConflictingRecords = conflicting
+			report.Migrated++
+		default:
+			resolution.Status = LegacyUserConflict
+			for _, candidate := range candidates {
+				resolution.CandidateUserIDs = append(resolution.CandidateUserIDs, maskUserID(candidate))
+			}
+			report.Conflicts++
+		}
+		report.Users = append(report.Users, resolution)
+	}
+
+	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit legacy migration tx: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// findLegacyUsers maps each normalized Bark key to the current users holding it
+// and lists the legacy user IDs, which are user IDs equal to a Bark key
+func findLegacyUsers(ctx context.Context, tx *db.Tx) (map[string][]string, []string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT user_id, bark_key FROM user_settings ORDER BY user_id`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query user settings: %w", err)
+	}
+	defer rows.Close()
+
+	owners := make(map[string][]string)
+	legacy := make(map[string]bool)
+	for rows.Next() {
+		var userID, barkKey string
+		if err := rows.Scan(&userID, &barkKey); err != nil {
+			return nil, nil, fmt.Errorf("scan user settings: %w", err)
+		}
+		key := entity.NormalizeBarkKey(barkKey)
+		switch {
+		case key == "":
+		case key == userID:
+			// Settings saved by a legacy client under its own key
+			legacy[userID] = true
+		default:
+			owners[key] = append(owners[key], userID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate user settings: %w", err)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, nil, fmt.Errorf("close user settings rows: %w", err)
+	}
+
+	// Data stored under a key some user holds is legacy even without legacy settings
+	dataRows, err := tx.QueryContext(ctx, `
+		SELECT user_id FROM notification_config
+		UNION
+		SELECT user_id FROM blocked_product
+	`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query user data owners: %w", err)
+	}
+	defer dataRows.Close()
+
+	for dataRows.Next() {
+		var userID string
+		if err := dataRows.Scan(&userID); err != nil {
+			return nil, nil, fmt.Errorf("scan user data owner: %w", err)
+		}
+		if _, ok := owners[userID]; ok {
+			legacy[userID] = true
+		}
+	}
+	if err := dataRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate user data owners: %w", err)
+	}
+
+	legacyIDs := make([]string, 0, len(legacy))
+	for userID := range legacy {
+		legacyIDs = append(legacyIDs, userID)
+	}
+	sort.Strings(legacyIDs)
+	return owners, legacyIDs, nil
+}
+
+// countLegacyUserData fills in how much data a legacy user ID has
+func countLegacyUserData(ctx context.Context, tx *db.Tx, legacyUserID string, resolution *LegacyUserResolution) error {
+	var settings int
+	row := tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM user_settings WHERE user_id = ?),
+			(SELECT COUNT(*) FROM notification_config WHERE user_id = ?),
+			(SELECT COUNT(*) FROM blocked_product WHERE user_id = ?)
+	`, legacyUserID, legacyUserID, legacyUserID)
+	if err := row.Scan(&settings, &resolution.Notifications, &resolution.BlockedProducts); err != nil {
+		return fmt.Errorf("count legacy user data: %w", err)
+	}
+	resolution.Settings = settings > 0
+	return nil
+}
+
+// countConflictingRecords counts the settings, notification rules and blocked
+// products both user IDs have for the same product
+func countConflictingRecords(ctx context.Context, tx *db.Tx, currentUserID, legacyUserID string) (int, error) {
+	var count int
+	row := tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM user_settings c JOIN user_settings l
+				ON c.user_id = ? AND l.user_id = ?) +
+			(SELECT COUNT(*) FROM notification_config c JOIN notification_config l
+				ON c.activity_id = l.activity_id AND c.user_id = ? AND l.user_id = ?) +
+			(SELECT COUNT(*) FROM blocked_product c JOIN blocked_product l
+				ON c.activity_id = l.activity_id AND c.user_id = ? AND l.user_id = ?)
+	`, currentUserID, legacyUserID, currentUserID, legacyUserID, currentUserID, legacyUserID)
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("count conflicting records: %w", err)
+	}
+	return count, nil
+}
+
+// maskUserID keeps enough of a user ID to recognize it in a report
+func maskUserID(userID string) string {
+	if len(userID) <= 8 {
+		return "***"
+	}
+	return userID[:4] + "***" + userID[len(userID)-2:]
+}
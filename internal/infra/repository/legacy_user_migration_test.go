@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	dbinfra "kbfood/internal/infra/db"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMigrateLegacyUsers(t *testing.T) {
+	pool := setupLegacyMigrationDB(t)
+	ctx := context.Background()
+
+	mustExecLegacy(t, pool.DB, `
+		INSERT INTO user_settings (user_id, bark_key, create_time, update_time)
+		VALUES
+			('client-alice', 'https://api.day.app/ALICEKEY123/', '2026-03-20 00:00:00', '2026-03-20 00:00:00'),
+			('ALICEKEY123', 'ALICEKEY123', '2026-03-01 00:00:00', '2026-03-01 00:00:00'),
+			('client-bob-phone', 'SHAREDKEY99', '2026-03-20 00:00:00', '2026-03-20 00:00:00'),
+			('client-bob-laptop', 'SHAREDKEY99', '2026-03-20 00:00:00', '2026-03-20 00:00:00'),
+			('ORPHANKEY77', 'ORPHANKEY77', '2026-03-01 00:00:00', '2026-03-01 00:00:00')
+	`)
+	mustExecLegacy(t, pool.DB, `
+		INSERT INTO notification_config (activity_id, user_id, target_price, create_time, update_time)
+		VALUES
+			('DT_SHARED', 'client-alice', 99.0, '2026-03-20 00:00:00', '2026-03-20 00:00:00'),
+			('DT_SHARED', 'ALICEKEY123', 68.7, '2026-03-01 00:00:00', '2026-03-01 00:00:00'),
+			('DT_LEGACY', 'ALICEKEY123', 12.3, '2026-03-01 00:00:00', '2026-03-01 00:00:00'),
+			('DT_BOB', 'SHAREDKEY99', 10.0, '2026-03-01 00:00:00', '2026-03-01 00:00:00'),
+			('DT_ORPHAN', 'ORPHANKEY77', 10.0, '2026-03-01 00:00:00', '2026-03-01 00:00:00')
+	`)
+	mustExecLegacy(t, pool.DB, `
+		INSERT INTO blocked_product (activity_id, user_id, create_time)
+		VALUES ('DT_BLOCKED', 'ALICEKEY123', '2026-03-01 00:00:00')
+	`)
+
+	// A dry run reports without changing data
+	report, err := MigrateLegacyUsers(ctx, pool, true)
+	if err != nil {
+		t.Fatalf("MigrateLegacyUsers(dry run) error = %v", err)
+	}
+	if !report.DryRun || report.Migrated != 1 || report.Conflicts != 1 || report.Orphaned != 1 {
+		t.Fatalf("unexpected dry run report: %+v", report)
+	}
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM notification_config WHERE user_id = 'ALICEKEY123'", 2)
+
+	report, err = MigrateLegacyUsers(ctx, pool, false)
+	if err != nil {
+		t.Fatalf("MigrateLegacyUsers() error = %v", err)
+	}
+	if report.Migrated != 1 || report.Conflicts != 1 || report.Orphaned != 1 || len(report.Users) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	statuses := make(map[string]LegacyUserResolution)
+	for _, u := range report.Users {
+		statuses[u.Status] = u
+	}
+	migrated := statuses[LegacyUserMigrated]
+	if migrated.LegacyUserID != "ALIC***23" || migrated.TargetUserID != "clie***ce" ||
+		migrated.Notifications != 2 || migrated.BlockedProducts != 1 || migrated.ConflictingRecords != 2 {
+		t.Fatalf("unexpected migrated entry: %+v", migrated)
+	}
+	if conflict := statuses[LegacyUserConflict]; len(conflict.CandidateUserIDs) != 2 || conflict.Notifications != 1 {
+		t.Fatalf("unexpected conflict entry: %+v", conflict)
+	}
+	if orphan := statuses[LegacyUserOrphaned]; !orphan.Settings || orphan.Notifications != 1 {
+		t.Fatalf("unexpected orphaned entry: %+v", orphan)
+	}
+
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM user_settings WHERE user_id = 'ALICEKEY123'", 0)
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM notification_config WHERE user_id = 'ALICEKEY123'", 0)
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM notification_config WHERE user_id = 'client-alice'", 2)
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM notification_config WHERE user_id = 'client-alice' AND activity_id = 'DT_SHARED' AND target_price = 99.0", 1)
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM blocked_product WHERE user_id = 'client-alice'", 1)
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM notification_config WHERE user_id = 'SHAREDKEY99'", 1)
+	assertLegacyCount(t, pool.DB, "SELECT COUNT(*) FROM notification_config WHERE user_id = 'ORPHANKEY77'", 1)
+
+	// Running again only reports what could not be resolved
+	report, err = MigrateLegacyUsers(ctx, pool, false)
+	if err != nil {
+		t.Fatalf("second MigrateLegacyUsers() error = %v", err)
+	}
+	if report.Migrated != 0 || report.Conflicts != 1 || report.Orphaned != 1 {
+		t.Fatalf("unexpected report on second run: %+v", report)
+	}
+}
+
+func setupLegacyMigrationDB(t *testing.T) *dbinfra.Pool {
+	t.Helper()
+
+	sqlDB, err := sql.Open("sqlite", "file:"+t.TempDir()+"/legacy.db?mode=rwc")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	pool := &dbinfra.Pool{DB: sqlDB}
+	mustExecLegacy(t, pool.DB, `
+		CREATE TABLE user_settings (
+			user_id TEXT PRIMARY KEY,
+			bark_key TEXT NOT NULL,
+			create_time TEXT NOT NULL DEFAULT (datetime('now')),
+			update_time TEXT NOT NULL DEFAULT (datetime('now'))
+		);
+
+		CREATE TABLE notification_config (
+			activity_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			target_price REAL NOT NULL,
+			last_notify_time TEXT,
+			create_time TEXT NOT NULL DEFAULT (datetime('now')),
+			update_time TEXT NOT NULL DEFAULT (datetime('now')),
+			rule_type TEXT NOT NULL DEFAULT 'target_price',
+			drop_percent REAL,
+			drop_amount REAL,
+			reference_price REAL,
+			last_status INTEGER,
+			snooze_until TEXT,
+			PRIMARY KEY (activity_id, user_id)
+		);
+
+		CREATE TABLE blocked_product (
+			activity_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			create_time TEXT NOT NULL DEFAULT (datetime('now')),
+			PRIMARY KEY (activity_id, user_id)
+		);
+	`)
+
+	return pool
+}
+
+func mustExecLegacy(t *testing.T, db *sql.DB, query string) {
+	t.Helper()
+	if _, err := db.Exec(query); err != nil {
+		t.Fatalf("exec query failed: %v\nquery:\n%s", err, query)
+	}
+}
+
+func assertLegacyCount(t *testing.T, db *sql.DB, query string, want int) {
+	t.Helper()
+	var got int
+	if err := db.QueryRow(query).Scan(&got); err != nil {
+		t.Fatalf("query count failed: %v\nquery:\n%s", err, query)
+	}
+	if got != want {
+		t.Fatalf("count = %d, want %d\nquery:\n%s", got, want, query)
+	}
+}
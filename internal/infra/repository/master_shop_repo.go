@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+type masterShopRepository struct {
+	db *db.Pool
+}
+
+// NewMasterShopRepository creates a new master shop repository
+func NewMasterShopRepository(db *db.Pool) repository.MasterShopRepository {
+	return &masterShopRepository{db: db}
+}
+
+func (r *masterShopRepository) AddShop(ctx context.Context, masterID, shopName string) error {
+	// Known shops are ignored, which also spares the search index a rewrite
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO master_shop (master_id, shop_name) VALUES (?, ?)
+	`, masterID, shopName); err != nil {
+		return fmt.Errorf("add master shop: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type notificationDeliveryRepository struct {
+	db *db.Queries
+}
+
+// NewNotificationDeliveryRepository creates a new notification delivery repository
+func NewNotificationDeliveryRepository(db *db.Queries) repository.NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{db: db}
+}
+
+func (r *notificationDeliveryRepository) Create(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	var sentTime sql.NullString
+	if delivery.SentTime != nil {
+		sentTime = sql.NullString{String: sqliteDateTime(*delivery.SentTime), Valid: true}
+	}
+
+	err := r.db.CreateNotificationDelivery(ctx, db.CreateNotificationDeliveryParams{
+		UserID:     delivery.UserID,
+		ActivityID: delivery.ActivityID,
+		Message:    delivery.Message,
+		Status:     delivery.Status,
+		SentTime:   sentTime,
+	})
+	if err != nil {
+		return fmt.Errorf("create notification delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationDeliveryRepository) CountSentSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	count, err := r.db.CountSentNotificationsSince(ctx, db.CountSentNotificationsSinceParams{
+		UserID:   userID,
+		SentTime: sql.NullString{String: sqliteDateTime(since), Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count sent notifications: %w", err)
+	}
+	return int(count), nil
+}
+
+func (r *notificationDeliveryRepository) ListHeld(ctx context.Context) ([]*entity.NotificationDelivery, error) {
+	deliveries, err := r.db.ListHeldNotifications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list held notifications: %w", err)
+	}
+
+	result := make([]*entity.NotificationDelivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = convertDBNotificationDeliveryToEntity(&d)
+	}
+	return result, nil
+}
+
+func (r *notificationDeliveryRepository) MarkSent(ctx context.Context, id int64) error {
+	err := r.db.MarkNotificationDeliverySent(ctx, db.MarkNotificationDeliverySentParams{
+		SentTime: sql.NullString{String: sqliteDateTime(time.Now()), Valid: true},
+		ID:       id,
+	})
+	if err != nil {
+		return fmt.Errorf("mark notification sent: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationDeliveryRepository) DeleteSentBefore(ctx context.Context, before time.Time) error {
+	err := r.db.DeleteSentNotificationsBefore(ctx, sql.NullString{String: sqliteDateTime(before), Valid: true})
+	if err != nil {
+		return fmt.Errorf("delete sent notifications: %w", err)
+	}
+	return nil
+}
+
+// convertDBNotificationDeliveryToEntity converts db.NotificationDelivery to entity.NotificationDelivery
+func convertDBNotificationDeliveryToEntity(d *db.NotificationDelivery) *entity.NotificationDelivery {
+	var sentTime *time.Time
+	if d.SentTime.Valid && d.SentTime.String != "" {
+		if t := parseSQLiteTime(d.SentTime.String); !t.IsZero() {
+			sentTime = &t
+		}
+	}
+
+	return &entity.NotificationDelivery{
+		ID:         d.ID,
+		UserID:     d.UserID,
+		ActivityID: d.ActivityID,
+		Message:    d.Message,
+		Status:     d.Status,
+		CreateTime: parseSQLiteTime(d.CreateTime),
+		SentTime:   sentTime,
+	}
+}
@@ -47,6 +47,19 @@ func (r *notificationRepository) ListByUser(ctx context.Context, userID string)
 	return result, nil
 }
 
+func (r *notificationRepository) ListByActivityID(ctx context.Context, activityID string) ([]*entity.NotificationConfig, error) {
+	configs, err := r.db.ListNotificationsByActivity(ctx, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications by activity: %w", err)
+	}
+
+	result := make([]*entity.NotificationConfig, len(configs))
+	for i, c := range configs {
+		result[i] = convertDBNotificationToEntity(&c)
+	}
+	return result, nil
+}
+
 func (r *notificationRepository) ListAll(ctx context.Context) ([]*entity.NotificationConfig, error) {
 	configs, err := r.db.ListAllNotifications(ctx)
 	if err != nil {
@@ -64,8 +77,14 @@ func (r *notificationRepository) Upsert(ctx context.Context, config *entity.Noti
 	params := db.UpsertNotificationParams{
 		ActivityID:     config.ActivityID,
 		UserID:         config.UserID,
+		RuleType:       config.EffectiveRuleType(),
 		TargetPrice:    config.TargetPrice,
+		DropPercent:    sqlNullFloat64FromFloat(config.DropPercent),
+		DropAmount:     sqlNullFloat64FromFloat(config.DropAmount),
+		ReferencePrice: sqlNullFloat64FromPtr(config.ReferencePrice),
+		LastStatus:     sqlNullInt64FromPtr(config.LastStatus),
 		LastNotifyTime: sqlNullStringFromTimePtr(config.LastNotifyTime),
+		SnoozeUntil:    sqlNullStringFromTimePtr(config.SnoozeUntil),
 	}
 
 	err := r.db.UpsertNotification(ctx, params)
@@ -86,13 +105,26 @@ func (r *notificationRepository) Delete(ctx context.Context, activityID string,
 	return nil
 }
 
-func (r *notificationRepository) UpdateNotifyTime(ctx context.Context, activityID string, userID string) error {
+func (r *notificationRepository) UpdateNotifyTime(ctx context.Context, activityID string, userID string, price float64) error {
 	err := r.db.UpdateNotificationNotifyTime(ctx, db.UpdateNotificationNotifyTimeParams{
+		ReferencePrice: sqlNullFloat64(price),
+		ActivityID:     activityID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return fmt.Errorf("update notify time: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) UpdateLastStatus(ctx context.Context, activityID string, userID string, status int) error {
+	err := r.db.UpdateNotificationLastStatus(ctx, db.UpdateNotificationLastStatusParams{
+		LastStatus: sqlNullInt64FromInt(status),
 		ActivityID: activityID,
 		UserID:     userID,
 	})
 	if err != nil {
-		return fmt.Errorf("update notify time: %w", err)
+		return fmt.Errorf("update last status: %w", err)
 	}
 	return nil
 }
@@ -106,11 +138,24 @@ func convertDBNotificationToEntity(c *db.NotificationConfig) *entity.Notificatio
 		}
 	}
 
+	var snoozeUntil *time.Time
+	if c.SnoozeUntil.Valid && c.SnoozeUntil.String != "" {
+		if t := parseSQLiteTime(c.SnoozeUntil.String); !t.IsZero() {
+			snoozeUntil = &t
+		}
+	}
+
 	return &entity.NotificationConfig{
 		ActivityID:     c.ActivityID,
 		UserID:         c.UserID,
+		RuleType:       c.RuleType,
 		TargetPrice:    c.TargetPrice,
+		DropPercent:    float64FromNull(c.DropPercent),
+		DropAmount:     float64FromNull(c.DropAmount),
+		ReferencePrice: float64PtrFromNull(c.ReferencePrice),
+		LastStatus:     intPtrFromNull(c.LastStatus),
 		LastNotifyTime: lastNotifyTime,
+		SnoozeUntil:    snoozeUntil,
 		CreateTime:     parseSQLiteTime(c.CreateTime),
 		UpdateTime:     parseSQLiteTime(c.UpdateTime),
 	}
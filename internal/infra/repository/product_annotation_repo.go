@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type productAnnotationRepository struct {
+	db *db.Queries
+}
+
+// NewProductAnnotationRepository creates a new product annotation repository
+func NewProductAnnotationRepository(db *db.Queries) repository.ProductAnnotationRepository {
+	return &productAnnotationRepository{db: db}
+}
+
+func (r *productAnnotationRepository) Find(ctx context.Context, activityID string, userID string) (*entity.ProductAnnotation, error) {
+	annotation, err := r.db.GetProductAnnotation(ctx, db.GetProductAnnotationParams{
+		ActivityID: activityID,
+		UserID:     userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get product annotation: %w", err)
+	}
+	return convertDBProductAnnotationToEntity(&annotation), nil
+}
+
+func (r *productAnnotationRepository) ListByUser(ctx context.Context, userID string) ([]*entity.ProductAnnotation, error) {
+	annotations, err := r.db.ListProductAnnotationsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list product annotations by user: %w", err)
+	}
+
+	result := make([]*entity.ProductAnnotation, len(annotations))
+	for i, a := range annotations {
+		result[i] = convertDBProductAnnotationToEntity(&a)
+	}
+	return result, nil
+}
+
+func (r *productAnnotationRepository) Upsert(ctx context.Context, annotation *entity.ProductAnnotation) error {
+	tags := annotation.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	encodedTags, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("encode product tags: %w", err)
+	}
+
+	var favorite int64
+	if annotation.Favorite {
+		favorite = 1
+	}
+
+	err = r.db.UpsertProductAnnotation(ctx, db.UpsertProductAnnotationParams{
+		ActivityID: annotation.ActivityID,
+		UserID:     annotation.UserID,
+		Favorite:   favorite,
+		Tags:       string(encodedTags),
+		Note:       annotation.Note,
+	})
+	if err != nil {
+		return fmt.Errorf("upsert product annotation: %w", err)
+	}
+	return nil
+}
+
+func (r *productAnnotationRepository) Delete(ctx context.Context, activityID string, userID string) error {
+	err := r.db.DeleteProductAnnotation(ctx, db.DeleteProductAnnotationParams{
+		ActivityID: activityID,
+		UserID:     userID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete product annotation: %w", err)
+	}
+	return nil
+}
+
+func convertDBProductAnnotationToEntity(a *db.ProductAnnotation) *entity.ProductAnnotation {
+	var tags []string
+	if err := json.Unmarshal([]byte(a.Tags), &tags); err != nil || tags == nil {
+		tags = []string{}
+	}
+	return &entity.ProductAnnotation{
+		ActivityID: a.ActivityID,
+		UserID:     a.UserID,
+		Favorite:   a.Favorite != 0,
+		Tags:       tags,
+		Note:       a.Note,
+		CreateTime: parseSQLiteTime(a.CreateTime),
+		UpdateTime: parseSQLiteTime(a.UpdateTime),
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+type productChangeRepository struct {
+	db *db.Pool
+}
+
+// NewProductChangeRepository creates a new product change repository
+func NewProductChangeRepository(db *db.Pool) repository.ProductChangeRepository {
+	return &productChangeRepository{db: db}
+}
+
+func (r *productChangeRepository) Create(ctx context.Context, change *entity.ProductChange) error {
+	var createTime string
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO product_change (kind, activity_id, region, platform, title, price, old_price)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, create_time
+	`, change.Kind, change.ActivityID, change.Region, change.Platform, change.Title,
+		change.Price, sqlNullFloat64FromPtr(change.OldPrice)).Scan(&change.ID, &createTime)
+	if err != nil {
+		return fmt.Errorf("create product change: %w", err)
+	}
+	change.CreateTime = parseSQLiteTime(createTime)
+	return nil
+}
+
+func (r *productChangeRepository) List(ctx context.Context, query repository.ProductChangeQuery) ([]*entity.ProductChange, error) {
+	if query.ActivityIDs != nil && len(query.ActivityIDs) == 0 {
+		return []*entity.ProductChange{}, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	if query.Kind != "" {
+		conditions = append(conditions, "kind = ?")
+		args = append(args, query.Kind)
+	}
+	if query.DropsOnly {
+		conditions = append(conditions, "kind = ? AND old_price IS NOT NULL AND price < old_price")
+		args = append(args, entity.ProductChangePrice)
+	}
+	if query.Region != "" {
+		conditions = append(conditions, "region = ?")
+		args = append(args, query.Region)
+	}
+	if query.Platform != "" {
+		conditions = append(conditions, "platform = ?")
+		args = append(args, query.Platform)
+	}
+	for _, term := range entity.ParseSearchTerms(query.Keyword) {
+		conditions = append(conditions, "instr(lower(title), ?) > 0")
+		args = append(args, term)
+	}
+	if len(query.ActivityIDs) > 0 {
+		conditions = append(conditions, "activity_id IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(query.ActivityIDs)), ", ")+")")
+		for _, id := range query.ActivityIDs {
+			args = append(args, id)
+		}
+	}
+
+	where := "1 = 1"
+	if len(conditions) > 0 {
+		where = strings.Join(conditions, " AND ")
+	}
+	limit := query.Limit
+	if limit <= 0 || limit > repository.MaxFeedEntries {
+		limit = repository.MaxFeedEntries
+	}
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, kind, activity_id, region, platform, title, price, old_price, create_time
+		FROM product_change
+		WHERE `+where+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list product changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*entity.ProductChange{}
+	for rows.Next() {
+		var change entity.ProductChange
+		var oldPrice sql.NullFloat64
+		var createTime string
+		if err := rows.Scan(&change.ID, &change.Kind, &change.ActivityID, &change.Region, &change.Platform,
+			&change.Title, &change.Price, &oldPrice, &createTime); err != nil {
+			return nil, fmt.Errorf("scan product change: %w", err)
+		}
+		change.OldPrice = float64PtrFromNull(oldPrice)
+		change.CreateTime = parseSQLiteTime(createTime)
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate product changes: %w", err)
+	}
+	return changes, nil
+}
+
+type feedTokenRepository struct {
+	db *db.Pool
+}
+
+// NewFeedTokenRepository creates a new feed token repository
+func NewFeedTokenRepository(db *db.Pool) repository.FeedTokenRepository {
+	return &feedTokenRepository{db: db}
+}
+
+func (r *feedTokenRepository) FindByUser(ctx context.Context, userID string) (*entity.FeedToken, error) {
+	return r.find(ctx, `SELECT user_id, token, create_time FROM feed_token WHERE user_id = ?`, userID)
+}
+
+func (r *feedTokenRepository) FindByToken(ctx context.Context, token string) (*entity.FeedToken, error) {
+	return r.find(ctx, `SELECT user_id, token, create_time FROM feed_token WHERE token = ?`, token)
+}
+
+func (r *feedTokenRepository) find(ctx context.Context, query string, arg string) (*entity.FeedToken, error) {
+	var token entity.FeedToken
+	var createTime string
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&token.UserID, &token.Token, &createTime)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find feed token: %w", err)
+	}
+	token.CreateTime = parseSQLiteTime(createTime)
+	return &token, nil
+}
+
+func (r *feedTokenRepository) Save(ctx context.Context, token *entity.FeedToken) error {
+	var createTime string
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO feed_token (user_id, token)
+		VALUES (?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			token = excluded.token,
+			create_time = datetime('now')
+		RETURNING create_time
+	`, token.UserID, token.Token).Scan(&createTime)
+	if err != nil {
+		return fmt.Errorf("save feed token: %w", err)
+	}
+	token.CreateTime = parseSQLiteTime(createTime)
+	return nil
+}
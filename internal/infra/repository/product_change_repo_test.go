@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+)
+
+func TestProductChangeRepository_List(t *testing.T) {
+	pool := setupProductListingDB(t)
+	migration, err := os.ReadFile("../db/migrations/017_product_changes.sql")
+	if err != nil {
+		t.Fatalf("read product change migration: %v", err)
+	}
+	mustExecLegacy(t, pool.DB, string(migration))
+
+	repo := NewProductChangeRepository(pool)
+	ctx := context.Background()
+
+	// Existing masters are recorded as new products in creation order
+	backfilled, err := repo.List(ctx, repository.ProductChangeQuery{Kind: entity.ProductChangeNew})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(backfilled) != 5 || backfilled[0].OldPrice != nil || backfilled[0].Platform != "DT" {
+		t.Fatalf("backfilled = %d changes, first %+v", len(backfilled), backfilled[0])
+	}
+
+	price := func(p float64) *float64 { return &p }
+	for _, change := range []*entity.ProductChange{
+		{Kind: entity.ProductChangePrice, ActivityID: "DT_fish", Region: "广州", Platform: "DT", Title: "烤全鱼双人餐", Price: 45, OldPrice: price(50)},
+		{Kind: entity.ProductChangePrice, ActivityID: "DT_fish", Region: "广州", Platform: "DT", Title: "烤全鱼双人餐", Price: 48, OldPrice: price(45)},
+		{Kind: entity.ProductChangePrice, ActivityID: "DT_noodle", Region: "深圳", Platform: "DT", Title: "牛肉面", Price: 80, OldPrice: price(90)},
+		{Kind: entity.ProductChangePrice, ActivityID: "DT_tea", Region: "深圳", Platform: "XC", Title: "Milk Tea", Price: 12, OldPrice: price(15)},
+	} {
+		if err := repo.Create(ctx, change); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if change.ID == 0 || change.CreateTime.IsZero() {
+			t.Fatalf("Create() did not set ID and time: %+v", change)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query repository.ProductChangeQuery
+		want  []string
+	}{
+		{"drops newest first", repository.ProductChangeQuery{DropsOnly: true}, []string{"DT_tea", "DT_noodle", "DT_fish"}},
+		{"region", repository.ProductChangeQuery{DropsOnly: true, Region: "深圳"}, []string{"DT_tea", "DT_noodle"}},
+		{"platform", repository.ProductChangeQuery{DropsOnly: true, Platform: "XC"}, []string{"DT_tea"}},
+		{"keyword", repository.ProductChangeQuery{DropsOnly: true, Keyword: "tea"}, []string{"DT_tea"}},
+		{"keyword terms", repository.ProductChangeQuery{Kind: entity.ProductChangeNew, Keyword: "牛肉 火锅"}, []string{"DT_hotpot"}},
+		{"watched", repository.ProductChangeQuery{DropsOnly: true, ActivityIDs: []string{"DT_fish", "DT_cake"}}, []string{"DT_fish"}},
+		{"nothing watched", repository.ProductChangeQuery{DropsOnly: true, ActivityIDs: []string{}}, []string{}},
+		{"limit", repository.ProductChangeQuery{Limit: 2}, []string{"DT_tea", "DT_noodle"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := repo.List(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got := make([]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, change.ActivityID)
+			}
+			if !equalStrings(got, tt.want) {
+				t.Fatalf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedTokenRepository_Save(t *testing.T) {
+	pool := setupProductListingDB(t)
+	migration, err := os.ReadFile("../db/migrations/017_product_changes.sql")
+	if err != nil {
+		t.Fatalf("read product change migration: %v", err)
+	}
+	mustExecLegacy(t, pool.DB, string(migration))
+
+	repo := NewFeedTokenRepository(pool)
+	ctx := context.Background()
+
+	if err := repo.Save(ctx, &entity.FeedToken{UserID: "client-1", Token: "first"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := repo.Save(ctx, &entity.FeedToken{UserID: "client-1", Token: "second"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if token, err := repo.FindByToken(ctx, "first"); err != nil || token != nil {
+		t.Fatalf("FindByToken(replaced) = %+v, %v, want nil", token, err)
+	}
+	token, err := repo.FindByUser(ctx, "client-1")
+	if err != nil || token == nil || token.Token != "second" {
+		t.Fatalf("FindByUser() = %+v, %v", token, err)
+	}
+	if token, err := repo.FindByToken(ctx, "second"); err != nil || token == nil || token.UserID != "client-1" {
+		t.Fatalf("FindByToken() = %+v, %v", token, err)
+	}
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+// listingPriceSQL and listingPriceRatioSQL match the expression indexes of
+// master_product, so that the price, discount and drop rate sorts use them
+const (
+	listingPriceSQL      = "COALESCE(m.price, 0)"
+	listingPriceRatioSQL = "(CASE WHEN m.original_price > 0 THEN COALESCE(m.price, 0) / m.original_price ELSE 1 END)"
+)
+
+// listingSort is the sort expression of a listing sort key. Reversed sorts
+// run in the opposite direction of the expression.
+type listingSort struct {
+	expr     string
+	reversed bool
+}
+
+// listingSorts maps sort keys to expressions on master_product m. The discount
+// ((1 - price/original) * 10) and the drop rate ((1 - price/original) * 100)
+// both grow as the price ratio falls, so both sort on the ratio reversed.
+var listingSorts = map[string]listingSort{
+	repository.ListingSortUpdateTime: {expr: "m.update_time"},
+	repository.ListingSortPrice:      {expr: listingPriceSQL},
+	repository.ListingSortDiscount:   {expr: listingPriceRatioSQL, reversed: true},
+	repository.ListingSortDropRate:   {expr: listingPriceRatioSQL, reversed: true},
+}
+
+type productListingRepository struct {
+	db *db.Pool
+}
+
+// NewProductListingRepository creates a new product listing repository
+func NewProductListingRepository(db *db.Pool) repository.ProductListingRepository {
+	return &productListingRepository{db: db}
+}
+
+func (r *productListingRepository) List(ctx context.Context, query repository.ProductListingQuery) (*repository.ProductListingPage, error) {
+	// Keyword matches come from the full-text index, shared by count and list
+	terms := entity.ParseSearchTerms(query.Keyword)
+	withSQL, relevanceSQL := "", "0"
+	var args []interface{}
+	if len(terms) > 0 {
+		matchesSQL, matchArgs := searchMatchesSQL(terms)
+		withSQL = "WITH matches AS (" + matchesSQL + ") "
+		relevanceSQL = `COALESCE((SELECT MAX(s.relevance) FROM matches s
+			WHERE s.activity_id = m.id AND s.source = '` + repository.SearchSourceMaster + `'), 0)`
+		args = append(args, matchArgs...)
+	}
+	where, filterArgs := buildListingFilter(query, len(terms) > 0)
+	args = append(args, filterArgs...)
+
+	page := &repository.ProductListingPage{Products: []*repository.ProductListing{}}
+	countSQL := withSQL + `SELECT COUNT(*) FROM master_product m WHERE ` + where
+	if err := r.db.QueryRowContext(ctx, countSQL, args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("count product listing: %w", err)
+	}
+
+	sort, ok := listingSorts[query.Sort]
+	if query.Sort == repository.ListingSortRelevance {
+		sort, ok = listingSort{expr: relevanceSQL}, true
+	}
+	if !ok {
+		sort = listingSorts[repository.ListingSortUpdateTime]
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending != sort.reversed {
+		direction, comparison = "DESC", "<"
+	}
+
+	// The original price is kept on master_product, so the rows are sorted
+	// and paged on indexed expressions without aggregating the price history
+	listSQL := withSQL + `SELECT
+			m.id, m.region, COALESCE(m.platform, ''), m.standard_title,
+			` + listingPriceSQL + `, COALESCE(m.status, 0), COALESCE(m.trust_score, 0),
+			m.create_time, m.update_time, m.original_price,
+			` + relevanceSQL + `, ` + sort.expr + `
+		FROM master_product m
+		WHERE ` + where
+
+	listArgs := append([]interface{}{}, args...)
+	if query.Cursor != nil {
+		cursorValue, err := listingCursorValue(query.Sort, query.Cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		listSQL += fmt.Sprintf(`
+			AND (%[1]s %[2]s ? OR (%[1]s = ? AND m.id %[2]s ?))`, sort.expr, comparison)
+		listArgs = append(listArgs, cursorValue, cursorValue, query.Cursor.ID)
+	}
+	listSQL += fmt.Sprintf(`
+		ORDER BY %s %s, m.id %s`, sort.expr, direction, direction)
+	if query.Limit > 0 {
+		// One extra row tells whether another page follows
+		listSQL += `
+		LIMIT ?`
+		listArgs = append(listArgs, query.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, listSQL, listArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("query product listing: %w", err)
+	}
+	defer rows.Close()
+
+	var lastSortValue string
+	for rows.Next() {
+		if query.Limit > 0 && len(page.Products) == query.Limit {
+			page.NextCursor = &repository.ListingCursor{
+				Value: lastSortValue,
+				ID:    page.Products[len(page.Products)-1].ID,
+			}
+			break
+		}
+
+		var (
+			listing    = &repository.ProductListing{MasterProduct: &entity.MasterProduct{}}
+			createTime string
+			updateTime string
+			sortValue  interface{}
+		)
+		if err := rows.Scan(
+			&listing.ID,
+			&listing.Region,
+			&listing.Platform,
+			&listing.StandardTitle,
+			&listing.Price,
+			&listing.Status,
+			&listing.TrustScore,
+			&createTime,
+			&updateTime,
+			&listing.OriginalPrice,
+			&listing.Relevance,
+			&sortValue,
+		); err != nil {
+			return nil, fmt.Errorf("scan product listing: %w", err)
+		}
+		listing.CreateTime = parseSQLiteTime(createTime)
+		listing.UpdateTime = parseSQLiteTime(updateTime)
+		lastSortValue = formatListingSortValue(sortValue)
+		page.Products = append(page.Products, listing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate product listing: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *productListingRepository) OriginalPrice(ctx context.Context, masterID string) (float64, error) {
+	var originalPrice float64
+	err := r.db.QueryRowContext(ctx, `SELECT original_price FROM master_product WHERE id = ?`, masterID).Scan(&originalPrice)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("get original price: %w", err)
+	}
+	return originalPrice, nil
+}
+
+// buildListingFilter builds the WHERE clause on master_product m for a listing query.
+// With search the query must define the matches table of searchMatchesSQL.
+func buildListingFilter(query repository.ProductListingQuery, search bool) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+
+	if query.Region != "" {
+		conditions = append(conditions, "m.region = ?")
+		args = append(args, query.Region)
+	}
+	if query.Platform != "" {
+		conditions = append(conditions, "m.platform = ?")
+		args = append(args, query.Platform)
+	}
+	if query.SharedShopOf != "" {
+		conditions = append(conditions, `m.id IN (
+			SELECT o.master_id FROM master_shop s
+			JOIN master_shop o ON o.shop_name = s.shop_name AND o.master_id <> s.master_id
+			WHERE s.master_id = ?)`)
+		args = append(args, query.SharedShopOf)
+	}
+	if search {
+		conditions = append(conditions, "m.id IN (SELECT activity_id FROM matches WHERE source = ?)")
+		args = append(args, repository.SearchSourceMaster)
+	}
+	if query.SalesStatus != nil {
+		conditions = append(conditions, "COALESCE(m.status, 0) = ?")
+		args = append(args, *query.SalesStatus)
+	}
+	if query.MinPrice != nil {
+		conditions = append(conditions, "COALESCE(m.price, 0) >= ?")
+		args = append(args, *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		conditions = append(conditions, "COALESCE(m.price, 0) <= ?")
+		args = append(args, *query.MaxPrice)
+	}
+	if query.UserID != "" {
+		conditions = append(conditions, `NOT EXISTS (
+			SELECT 1 FROM blocked_product b WHERE b.activity_id = m.id AND b.user_id = ?
+		)`)
+		args = append(args, query.UserID)
+	}
+	if query.MonitorStatus != nil {
+		monitored := `EXISTS (
+			SELECT 1 FROM notification_config n WHERE n.activity_id = m.id AND n.user_id = ?
+		)`
+		if *query.MonitorStatus == 0 {
+			monitored = "NOT " + monitored
+		}
+		conditions = append(conditions, monitored)
+		args = append(args, query.UserID)
+	}
+	if query.FavoriteOnly {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM product_annotation a
+			WHERE a.activity_id = m.id AND a.user_id = ? AND a.favorite = 1
+		)`)
+		args = append(args, query.UserID)
+	}
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM product_annotation a, json_each(a.tags) t
+			WHERE a.activity_id = m.id AND a.user_id = ? AND t.value = ?
+		)`)
+		args = append(args, query.UserID, tag)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// escapeLike escapes LIKE wildcards so the keyword matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// formatListingSortValue formats a scanned sort value so that it round-trips exactly
+func formatListingSortValue(v interface{}) string {
+	switch value := v.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case []byte:
+		return string(value)
+	case string:
+		return value
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// listingCursorValue converts a cursor value back to the type of its sort column
+func listingCursorValue(sort, value string) (interface{}, error) {
+	if sort == "" || sort == repository.ListingSortUpdateTime {
+		return value, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse listing cursor: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"kbfood/internal/domain/repository"
+	dbinfra "kbfood/internal/infra/db"
+	"kbfood/internal/interface/http/dto"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestProductListingRepository_FiltersAndSorts(t *testing.T) {
+	repo := NewProductListingRepository(setupProductListingDB(t))
+	ctx := context.Background()
+
+	onSale := 1
+	monitored := 1
+	minPrice, maxPrice := 20.0, 80.0
+
+	tests := []struct {
+		name  string
+		query repository.ProductListingQuery
+		want  []string
+	}{
+		{
+			name:  "blocked products are hidden",
+			query: repository.ProductListingQuery{UserID: "client-1", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_cake", "DT_fish", "DT_hotpot", "DT_noodle"},
+		},
+		{
+			name:  "keyword matches wildcards literally",
+			query: repository.ProductListingQuery{Keyword: "100%", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_hotpot"},
+		},
+		{
+			name:  "region, status and price range",
+			query: repository.ProductListingQuery{Region: "广州", SalesStatus: &onSale, MinPrice: &minPrice, MaxPrice: &maxPrice, Sort: repository.ListingSortPrice},
+			want:  []string{"DT_fish", "DT_hotpot"},
+		},
+		{
+			name:  "monitored by user",
+			query: repository.ProductListingQuery{UserID: "client-1", MonitorStatus: &monitored, Sort: repository.ListingSortPrice},
+			want:  []string{"DT_fish"},
+		},
+		{
+			name:  "favorites and tags of user",
+			query: repository.ProductListingQuery{UserID: "client-1", FavoriteOnly: true, Tag: "聚餐", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_hotpot"},
+		},
+		{
+			name:  "keyword matches characters in order",
+			query: repository.ProductListingQuery{Keyword: "烤鱼", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_fish"},
+		},
+		{
+			name:  "keyword matches shop names",
+			query: repository.ProductListingQuery{Keyword: "好利来", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_cake"},
+		},
+		{
+			name:  "other masters of the same shops",
+			query: repository.ProductListingQuery{SharedShopOf: "DT_fish", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_hotpot"},
+		},
+		{
+			name:  "keyword searches every term",
+			query: repository.ProductListingQuery{Keyword: "牛肉 火锅", Sort: repository.ListingSortPrice},
+			want:  []string{"DT_hotpot"},
+		},
+		{
+			name:  "whole matches rank first",
+			query: repository.ProductListingQuery{Keyword: "牛肉", Sort: repository.ListingSortRelevance, Descending: true},
+			want:  []string{"DT_noodle", "DT_hotpot"},
+		},
+		{
+			name:  "biggest drop first",
+			query: repository.ProductListingQuery{Sort: repository.ListingSortDropRate, Descending: true},
+			want:  []string{"DT_fish", "DT_hotpot", "DT_noodle", "DT_cake", "DT_tea"},
+		},
+		{
+			name:  "deepest discount first",
+			query: repository.ProductListingQuery{Sort: repository.ListingSortDiscount, Descending: true},
+			want:  []string{"DT_fish", "DT_hotpot", "DT_noodle", "DT_cake", "DT_tea"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.List(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := listingIDs(page); !equalStrings(got, tt.want) {
+				t.Fatalf("List() = %v, want %v", got, tt.want)
+			}
+			if page.Total != len(tt.want) || page.NextCursor != nil {
+				t.Fatalf("unexpected total %d or cursor %+v", page.Total, page.NextCursor)
+			}
+		})
+	}
+
+	page, err := repo.List(ctx, repository.ProductListingQuery{Sort: repository.ListingSortDropRate, Descending: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if fish := page.Products[0]; fish.OriginalPrice != 100 || fish.Price != 50 {
+		t.Fatalf("expected highest trend price as original price, got %+v", fish)
+	}
+
+	// A higher recorded price or a price rise raises the original price
+	pool := repo.(*productListingRepository).db
+	if _, err := pool.ExecContext(ctx, `
+		INSERT INTO product_price_trend (activity_id, price, record_date) VALUES ('DT_cake', 40.0, '2026-03-06');
+		UPDATE master_product SET price = 120.0 WHERE id = 'DT_noodle';
+	`); err != nil {
+		t.Fatalf("record prices: %v", err)
+	}
+	page, err = repo.List(ctx, repository.ProductListingQuery{Sort: repository.ListingSortDiscount, Descending: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	assertDiscountOrder(t, page, true)
+	ascending, err := repo.List(ctx, repository.ProductListingQuery{Sort: repository.ListingSortDiscount})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	assertDiscountOrder(t, ascending, false)
+	if got, want := listingIDs(page), []string{"DT_cake", "DT_fish", "DT_hotpot", "DT_noodle", "DT_tea"}; !equalStrings(got, want) {
+		t.Fatalf("List() after price changes = %v, want %v", got, want)
+	}
+	if cake := page.Products[0]; cake.OriginalPrice != 40 {
+		t.Fatalf("expected the new trend price as original price, got %+v", cake)
+	}
+	if noodle := page.Products[3]; noodle.OriginalPrice != 120 {
+		t.Fatalf("expected the raised price as original price, got %+v", noodle)
+	}
+
+	if price, err := repo.OriginalPrice(ctx, "DT_cake"); err != nil || price != 40 {
+		t.Fatalf("OriginalPrice(DT_cake) = %v, %v, want 40", price, err)
+	}
+	if price, err := repo.OriginalPrice(ctx, "DT_missing"); err != nil || price != 0 {
+		t.Fatalf("OriginalPrice(DT_missing) = %v, %v, want 0", price, err)
+	}
+}
+
+func TestProductListingRepository_CursorPagination(t *testing.T) {
+	repo := NewProductListingRepository(setupProductListingDB(t))
+	ctx := context.Background()
+
+	for _, sort := range []string{repository.ListingSortUpdateTime, repository.ListingSortPrice, repository.ListingSortDiscount} {
+		query := repository.ProductListingQuery{Sort: sort, Descending: true}
+		all, err := repo.List(ctx, query)
+		if err != nil {
+			t.Fatalf("List(%s) error = %v", sort, err)
+		}
+
+		query.Limit = 2
+		var paged []string
+		for pages := 0; ; pages++ {
+			if pages > 5 {
+				t.Fatalf("pagination by %s did not terminate", sort)
+			}
+			page, err := repo.List(ctx, query)
+			if err != nil {
+				t.Fatalf("List(%s) page %d error = %v", sort, pages, err)
+			}
+			if page.Total != 5 {
+				t.Fatalf("Total = %d, want 5", page.Total)
+			}
+			paged = append(paged, listingIDs(page)...)
+			if page.NextCursor == nil {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+
+		if want := listingIDs(all); !equalStrings(paged, want) {
+			t.Fatalf("pages by %s = %v, want %v", sort, paged, want)
+		}
+	}
+}
+
+func setupProductListingDB(t *testing.T) *dbinfra.Pool {
+	t.Helper()
+
+	sqlDB, err := sql.Open("sqlite", "file:"+t.TempDir()+"/listing.db?mode=rwc")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	mustExecLegacy(t, sqlDB, `
+		CREATE TABLE master_product (
+			id TEXT PRIMARY KEY,
+			region TEXT NOT NULL,
+			standard_title TEXT NOT NULL,
+			price REAL,
+			status INTEGER,
+			trust_score INTEGER DEFAULT 0,
+			create_time TEXT NOT NULL DEFAULT (datetime('now')),
+			update_time TEXT NOT NULL DEFAULT (datetime('now')),
+			platform TEXT DEFAULT '探探糖'
+		);
+
+		CREATE TABLE product (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			activity_id TEXT NOT NULL UNIQUE,
+			title TEXT,
+			shop_name TEXT
+		);
+
+		CREATE TABLE product_price_trend (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			activity_id TEXT NOT NULL,
+			price REAL NOT NULL,
+			record_date TEXT NOT NULL,
+			UNIQUE(activity_id, record_date)
+		);
+
+		CREATE TABLE notification_config (
+			activity_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			target_price REAL NOT NULL,
+			PRIMARY KEY (activity_id, user_id)
+		);
+
+		CREATE TABLE blocked_product (
+			activity_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			PRIMARY KEY (activity_id, user_id)
+		);
+
+		CREATE TABLE product_annotation (
+			activity_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			favorite INTEGER NOT NULL DEFAULT 0,
+			tags TEXT NOT NULL DEFAULT '[]',
+			note TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (activity_id, user_id)
+		);
+	`)
+	for _, name := range []string{"015_product_search.sql", "019_master_original_price.sql", "020_product_search_chars.sql"} {
+		migration, err := os.ReadFile("../db/migrations/" + name)
+		if err != nil {
+			t.Fatalf("read migration %s: %v", name, err)
+		}
+		mustExecLegacy(t, sqlDB, string(migration))
+	}
+
+	mustExecLegacy(t, sqlDB, `
+		INSERT INTO master_product (id, region, platform, standard_title, price, status, update_time)
+		VALUES
+			('DT_hotpot', '广州', 'DT', '100%牛肉火锅四人餐', 68.0, 1, '2026-03-05 00:00:00'),
+			('DT_fish', '广州', 'DT', '烤全鱼双人餐', 50.0, 1, '2026-03-04 00:00:00'),
+			('DT_cake', '广州', 'DT', '生日蛋糕', 10.0, 0, '2026-03-03 00:00:00'),
+			('DT_noodle', '深圳', 'DT', '牛肉面', 90.0, 1, '2026-03-02 00:00:00'),
+			('DT_tea', '深圳', 'DT', '烤奶茶', 15.0, 1, '2026-03-01 00:00:00')
+	`)
+	mustExecLegacy(t, sqlDB, `
+		INSERT INTO product_price_trend (activity_id, price, record_date)
+		VALUES
+			('DT_hotpot', 80.0, '2026-03-01'),
+			('DT_hotpot', 68.0, '2026-03-05'),
+			('DT_fish', 100.0, '2026-03-01'),
+			('DT_fish', 50.0, '2026-03-04'),
+			('DT_noodle', 95.0, '2026-03-01')
+	`)
+	mustExecLegacy(t, sqlDB, `
+		INSERT INTO master_shop (master_id, shop_name)
+		VALUES ('DT_cake', '好利来'), ('DT_fish', '川味小馆'), ('DT_hotpot', '川味小馆');
+		INSERT INTO blocked_product (activity_id, user_id) VALUES ('DT_tea', 'client-1');
+		INSERT INTO notification_config (activity_id, user_id, target_price)
+		VALUES ('DT_fish', 'client-1', 45.0), ('DT_hotpot', 'client-2', 60.0);
+		INSERT INTO product_annotation (activity_id, user_id, favorite, tags)
+		VALUES
+			('DT_hotpot', 'client-1', 1, '["聚餐","周末"]'),
+			('DT_fish', 'client-1', 1, '["周末"]'),
+			('DT_cake', 'client-2', 1, '["聚餐"]');
+	`)
+
+	return &dbinfra.Pool{DB: sqlDB}
+}
+
+// assertDiscountOrder checks a page sorted by discount against the discount
+// values returned to clients
+func assertDiscountOrder(t *testing.T, page *repository.ProductListingPage, descending bool) {
+	t.Helper()
+
+	discounts := make([]float64, len(page.Products))
+	for i, p := range page.Products {
+		product := dto.FromMasterEntity(p.MasterProduct)
+		product.ApplyReferencePrice(p.OriginalPrice)
+		discounts[i] = product.Discount
+	}
+	for i := 1; i < len(discounts); i++ {
+		if descending && discounts[i] > discounts[i-1] || !descending && discounts[i] < discounts[i-1] {
+			t.Fatalf("discounts %v of %v are out of order", discounts, listingIDs(page))
+		}
+	}
+}
+
+func listingIDs(page *repository.ProductListingPage) []string {
+	ids := make([]string, 0, len(page.Products))
+	for _, p := range page.Products {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -71,6 +71,24 @@ func (r *productRepository) FindByFilter(ctx context.Context, filter repository.
 	return result, nil
 }
 
+// FindByShop finds up to limit other products of the same shop, newest first
+func (r *productRepository) FindByShop(ctx context.Context, shopName, excludeActivityID string, limit int) ([]*entity.Product, error) {
+	products, err := r.db.ListProductsByShop(ctx, db.ListProductsByShopParams{
+		ShopName:   sqlNullString(shopName),
+		ActivityID: excludeActivityID,
+		Limit:      int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list products by shop: %w", err)
+	}
+
+	result := make([]*entity.Product, len(products))
+	for i, p := range products {
+		result[i] = convertDBProductToEntity(&p)
+	}
+	return result, nil
+}
+
 // Create creates a new product
 func (r *productRepository) Create(ctx context.Context, product *entity.Product) error {
 	params := db.CreateProductParams{
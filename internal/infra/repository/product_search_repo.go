@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+type productSearchRepository struct {
+	db *db.Pool
+}
+
+// NewProductSearchRepository creates a new product search repository
+func NewProductSearchRepository(db *db.Pool) repository.ProductSearchRepository {
+	return &productSearchRepository{db: db}
+}
+
+func (r *productSearchRepository) Search(ctx context.Context, terms []string, limit int) ([]*repository.SearchHit, error) {
+	if len(terms) == 0 {
+		return []*repository.SearchHit{}, nil
+	}
+
+	matchesSQL, args := searchMatchesSQL(terms)
+	// Bare columns of an aggregate query come from the row holding the MAX
+	query := `
+		WITH matches AS (` + matchesSQL + `)
+		SELECT activity_id, source, title, shop_name, MAX(relevance) AS relevance
+		FROM matches
+		GROUP BY activity_id
+		ORDER BY relevance DESC, activity_id
+		LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search products: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []*repository.SearchHit{}
+	for rows.Next() {
+		var hit repository.SearchHit
+		if err := rows.Scan(&hit.ActivityID, &hit.Source, &hit.Title, &hit.ShopName, &hit.Relevance); err != nil {
+			return nil, fmt.Errorf("scan search hit: %w", err)
+		}
+		hits = append(hits, &hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search hits: %w", err)
+	}
+	return hits, nil
+}
+
+// searchFallbackLimit bounds the rows of the LIKE fallback, which scans the whole index
+const searchFallbackLimit = 200
+
+// searchMatchesSQL builds a query over product_search returning activity_id,
+// source, title, shop_name and relevance for rows matching every term.
+//
+// Titles and shop names are indexed character by character, so the full-text
+// query narrows rows down to those holding every letter and digit of the terms.
+// Rows whose title or shop name holds the characters of each term in order
+// (烤鱼 in 烤全鱼) are kept, scored by how closely the terms matched and then by
+// bm25 with titles weighted above shop names. Only when the index has no hits,
+// as for terms of punctuation alone, a bounded LIKE scan takes its place.
+func searchMatchesSQL(terms []string) (string, []interface{}) {
+	var scores, conditions, tokens []string
+	var scoreArgs, conditionArgs []interface{}
+	for _, term := range terms {
+		whole := "%" + escapeLike(term) + "%"
+		chars := "%"
+		for _, r := range term {
+			chars += escapeLike(string(r)) + "%"
+			// unicode61 indexes letters and digits, other characters only separate them
+			if unicode.IsLetter(r) || unicode.IsNumber(r) {
+				tokens = append(tokens, `"`+string(r)+`"`)
+			}
+		}
+		scores = append(scores, `CASE
+					WHEN title LIKE ? ESCAPE '\' THEN 3
+					WHEN shop_name LIKE ? ESCAPE '\' THEN 2
+					WHEN title LIKE ? ESCAPE '\' THEN 1
+					ELSE 0 END`)
+		scoreArgs = append(scoreArgs, whole, whole, chars)
+		conditions = append(conditions, `(title LIKE ? ESCAPE '\' OR shop_name LIKE ? ESCAPE '\')`)
+		conditionArgs = append(conditionArgs, chars, chars)
+	}
+	score := `(` + strings.Join(scores, " + ") + `) * 10.0`
+	where := strings.Join(conditions, " AND ")
+
+	fallback := `
+				SELECT activity_id, source, title, shop_name,
+					` + score + ` - length(title) * 0.01 AS relevance
+				FROM product_search
+				WHERE ` + where
+	fallbackArgs := append(append([]interface{}{}, scoreArgs...), conditionArgs...)
+	if len(tokens) == 0 {
+		return `
+			SELECT * FROM (` + fallback + `
+				LIMIT ?)`, append(fallbackArgs, searchFallbackLimit)
+	}
+
+	match := strings.Join(tokens, " ")
+	args := append(append(append([]interface{}{}, scoreArgs...), match), conditionArgs...)
+	args = append(args, fallbackArgs...)
+	args = append(args, match, searchFallbackLimit)
+	return `
+			SELECT activity_id, source, title, shop_name,
+				` + score + ` - bm25(product_search, 10.0, 1.0) AS relevance
+			FROM product_search
+			WHERE product_search MATCH ? AND ` + where + `
+			UNION ALL
+			SELECT * FROM (` + fallback + `
+					AND NOT EXISTS (SELECT 1 FROM product_search WHERE product_search MATCH ?)
+				LIMIT ?)`, args
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"kbfood/internal/domain/repository"
+)
+
+func TestProductSearchRepository_Search(t *testing.T) {
+	pool := setupProductListingDB(t)
+	repo := NewProductSearchRepository(pool)
+	ctx := context.Background()
+
+	mustExecLegacy(t, pool.DB, `
+		INSERT INTO product (activity_id, title, shop_name)
+		VALUES
+			('XC_1', '招牌酸菜鱼', '老王烤鱼店'),
+			('DT_fish', '烤全鱼', '')
+	`)
+
+	hits, err := repo.Search(ctx, []string{"烤全鱼"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := searchHitIDs(hits); !equalStrings(got, []string{"DT_fish"}) {
+		t.Fatalf("Search(烤全鱼) = %v, want one hit per activity ID", got)
+	}
+
+	// A whole match in a shop name ranks above in-order characters in a title
+	hits, err = repo.Search(ctx, []string{"烤鱼"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := searchHitIDs(hits); !equalStrings(got, []string{"XC_1", "DT_fish"}) {
+		t.Fatalf("Search(烤鱼) = %v", got)
+	}
+	if hits[0].Source != repository.SearchSourceProduct || hits[0].ShopName != "老王烤鱼店" {
+		t.Fatalf("unexpected hit %+v", hits[0])
+	}
+
+	// Terms shorter than three characters are matched through the index too
+	hits, err = repo.Search(ctx, []string{"鱼"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := searchHitIDs(hits); !equalStrings(got, []string{"DT_fish", "XC_1"}) {
+		t.Fatalf("Search(鱼) = %v", got)
+	}
+
+	// Masters are found by the shops selling them
+	mustExecLegacy(t, pool.DB, `INSERT INTO master_shop (master_id, shop_name) VALUES ('DT_noodle', '老王烤鱼店')`)
+	hits, err = repo.Search(ctx, []string{"老王"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := searchHitIDs(hits); !equalStrings(got, []string{"DT_noodle", "XC_1"}) {
+		t.Fatalf("Search(老王) = %v", got)
+	}
+	if hits[0].Source != repository.SearchSourceMaster || hits[0].ShopName != "老王烤鱼店" {
+		t.Fatalf("unexpected hit %+v", hits[0])
+	}
+
+	// Terms without indexed characters fall back to LIKE
+	hits, err = repo.Search(ctx, []string{"%"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := searchHitIDs(hits); !equalStrings(got, []string{"DT_hotpot"}) {
+		t.Fatalf("Search(%%) = %v", got)
+	}
+
+	// Triggers keep the index in sync with the product and shop tables
+	mustExecLegacy(t, pool.DB, `
+		UPDATE master_product SET standard_title = '香辣烤鱼' WHERE id = 'DT_cake';
+		DELETE FROM product WHERE activity_id = 'XC_1';
+		DELETE FROM master_shop WHERE master_id = 'DT_noodle';
+	`)
+	hits, err = repo.Search(ctx, []string{"香辣烤鱼"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := searchHitIDs(hits); !equalStrings(got, []string{"DT_cake"}) {
+		t.Fatalf("Search(香辣烤鱼) = %v", got)
+	}
+	hits, err = repo.Search(ctx, []string{"酸菜鱼"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(hits) != 0 {
+		t.Fatalf("expected deleted product to leave the index, got %v", searchHitIDs(hits))
+	}
+	hits, err = repo.Search(ctx, []string{"老王"}, 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(hits) != 0 {
+		t.Fatalf("expected deleted shops to leave the index, got %v", searchHitIDs(hits))
+	}
+}
+
+func searchHitIDs(hits []*repository.SearchHit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ActivityID)
+	}
+	return ids
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type savedSearchRepository struct {
+	db *db.Queries
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(db *db.Queries) repository.SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) FindByID(ctx context.Context, id int64, userID string) (*entity.SavedSearch, error) {
+	search, err := r.db.GetSavedSearch(ctx, db.GetSavedSearchParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get saved search: %w", err)
+	}
+	return convertDBSavedSearchToEntity(&search), nil
+}
+
+func (r *savedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*entity.SavedSearch, error) {
+	searches, err := r.db.ListSavedSearchesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list saved searches by user: %w", err)
+	}
+
+	result := make([]*entity.SavedSearch, len(searches))
+	for i, s := range searches {
+		result[i] = convertDBSavedSearchToEntity(&s)
+	}
+	return result, nil
+}
+
+func (r *savedSearchRepository) ListAll(ctx context.Context) ([]*entity.SavedSearch, error) {
+	searches, err := r.db.ListAllSavedSearches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list all saved searches: %w", err)
+	}
+
+	result := make([]*entity.SavedSearch, len(searches))
+	for i, s := range searches {
+		result[i] = convertDBSavedSearchToEntity(&s)
+	}
+	return result, nil
+}
+
+func (r *savedSearchRepository) Create(ctx context.Context, search *entity.SavedSearch) error {
+	created, err := r.db.CreateSavedSearch(ctx, db.CreateSavedSearchParams{
+		UserID:   search.UserID,
+		Name:     search.Name,
+		Keyword:  search.Keyword,
+		Region:   search.Region,
+		Platform: search.Platform,
+		MaxPrice: sqlNullFloat64FromFloat(search.MaxPrice),
+	})
+	if err != nil {
+		return fmt.Errorf("create saved search: %w", err)
+	}
+
+	search.ID = created.ID
+	search.CreateTime = parseSQLiteTime(created.CreateTime)
+	search.UpdateTime = parseSQLiteTime(created.UpdateTime)
+	return nil
+}
+
+func (r *savedSearchRepository) Update(ctx context.Context, search *entity.SavedSearch) error {
+	err := r.db.UpdateSavedSearch(ctx, db.UpdateSavedSearchParams{
+		Name:     search.Name,
+		Keyword:  search.Keyword,
+		Region:   search.Region,
+		Platform: search.Platform,
+		MaxPrice: sqlNullFloat64FromFloat(search.MaxPrice),
+		ID:       search.ID,
+		UserID:   search.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("update saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *savedSearchRepository) Delete(ctx context.Context, id int64, userID string) error {
+	search, err := r.FindByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if search == nil {
+		return nil
+	}
+
+	if err := r.db.DeleteSavedSearchMatches(ctx, id); err != nil {
+		return fmt.Errorf("delete saved search matches: %w", err)
+	}
+	if err := r.db.DeleteSavedSearch(ctx, db.DeleteSavedSearchParams{
+		ID:     id,
+		UserID: userID,
+	}); err != nil {
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *savedSearchRepository) RecordMatch(ctx context.Context, searchID int64, activityID string) (bool, error) {
+	rows, err := r.db.CreateSavedSearchMatch(ctx, db.CreateSavedSearchMatchParams{
+		SearchID:   searchID,
+		ActivityID: activityID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("record saved search match: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// convertDBSavedSearchToEntity converts db.SavedSearch to entity.SavedSearch
+func convertDBSavedSearchToEntity(s *db.SavedSearch) *entity.SavedSearch {
+	return &entity.SavedSearch{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Name:       s.Name,
+		Keyword:    s.Keyword,
+		Region:     s.Region,
+		Platform:   s.Platform,
+		MaxPrice:   float64FromNull(s.MaxPrice),
+		CreateTime: parseSQLiteTime(s.CreateTime),
+		UpdateTime: parseSQLiteTime(s.UpdateTime),
+	}
+}
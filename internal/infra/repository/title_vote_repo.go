@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type titleVoteRepository struct {
+	db *db.Queries
+}
+
+// NewTitleVoteRepository creates a new title vote repository
+func NewTitleVoteRepository(db *db.Queries) repository.TitleVoteRepository {
+	return &titleVoteRepository{db: db}
+}
+
+func (r *titleVoteRepository) AddVotes(ctx context.Context, masterID string, votes map[string]int) error {
+	// Sorted for a stable write order
+	titles := make([]string, 0, len(votes))
+	for title := range votes {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	for _, title := range titles {
+		if votes[title] <= 0 {
+			continue
+		}
+		err := r.db.AddMasterTitleVotes(ctx, db.AddMasterTitleVotesParams{
+			MasterID: masterID,
+			Title:    title,
+			Votes:    int64(votes[title]),
+		})
+		if err != nil {
+			return fmt.Errorf("add master title votes: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *titleVoteRepository) ListByMaster(ctx context.Context, masterID string) ([]*entity.TitleVote, error) {
+	votes, err := r.db.ListMasterTitleVotes(ctx, masterID)
+	if err != nil {
+		return nil, fmt.Errorf("list master title votes: %w", err)
+	}
+
+	result := make([]*entity.TitleVote, len(votes))
+	for i, v := range votes {
+		result[i] = &entity.TitleVote{
+			MasterID: v.MasterID,
+			Title:    v.Title,
+			Votes:    int(v.Votes),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+type userDataTransactor struct {
+	db *db.Pool
+}
+
+// NewUserDataTransactor creates a transactor for the data of a user
+func NewUserDataTransactor(db *db.Pool) repository.UserDataTransactor {
+	return &userDataTransactor{db: db}
+}
+
+func (t *userDataTransactor) InTx(ctx context.Context, fn func(repos repository.UserDataRepositories) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin user data tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	queries := tx.Queries()
+	if err := fn(repository.UserDataRepositories{
+		Settings:      NewUserSettingsRepository(queries),
+		Notifications: NewNotificationRepository(queries),
+		Blocked:       NewBlockedRepository(queries),
+		SavedSearches: NewSavedSearchRepository(queries),
+		Annotations:   NewProductAnnotationRepository(queries),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit user data tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	dbinfra "kbfood/internal/infra/db"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestUserDataTransactor_RollsBackFailedImport(t *testing.T) {
+	ctx := context.Background()
+	pool := setupUserDataDB(t)
+	notifications := NewNotificationRepository(pool.Queries())
+	if err := notifications.Upsert(ctx, &entity.NotificationConfig{ActivityID: "DT_OLD", UserID: "u1", TargetPrice: 5}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	errImport := errors.New("import failed")
+	err := NewUserDataTransactor(pool).InTx(ctx, func(repos repository.UserDataRepositories) error {
+		if err := repos.Notifications.Delete(ctx, "DT_OLD", "u1"); err != nil {
+			return err
+		}
+		if err := repos.Notifications.Upsert(ctx, &entity.NotificationConfig{ActivityID: "DT_NEW", UserID: "u1", TargetPrice: 8}); err != nil {
+			return err
+		}
+		return errImport
+	})
+	if !errors.Is(err, errImport) {
+		t.Fatalf("InTx() error = %v, want the error of fn", err)
+	}
+	assertUserNotifications(t, notifications, "DT_OLD")
+
+	err = NewUserDataTransactor(pool).InTx(ctx, func(repos repository.UserDataRepositories) error {
+		return repos.Notifications.Upsert(ctx, &entity.NotificationConfig{ActivityID: "DT_NEW", UserID: "u1", TargetPrice: 8})
+	})
+	if err != nil {
+		t.Fatalf("InTx() error = %v", err)
+	}
+	assertUserNotifications(t, notifications, "DT_OLD", "DT_NEW")
+}
+
+func assertUserNotifications(t *testing.T, notifications repository.NotificationRepository, want ...string) {
+	t.Helper()
+	configs, err := notifications.ListByUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("ListByUser() error = %v", err)
+	}
+	var got []string
+	for _, config := range configs {
+		got = append(got, config.ActivityID)
+	}
+	if !equalStrings(got, want) {
+		t.Fatalf("notifications = %v, want %v", got, want)
+	}
+}
+
+func setupUserDataDB(t *testing.T) *dbinfra.Pool {
+	t.Helper()
+
+	sqlDB, err := sql.Open("sqlite", "file:"+t.TempDir()+"/user_data.db?mode=rwc")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	pool := &dbinfra.Pool{DB: sqlDB}
+	if err := pool.RunMigrations(dbinfra.Migrations()); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	return pool
+}
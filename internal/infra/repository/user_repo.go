@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	db "kbfood/internal/infra/db/sqlc"
+)
+
+type userRepository struct {
+	db *db.Queries
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *db.Queries) repository.UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	user, err := r.db.GetUser(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return convertDBUserToEntity(&user), nil
+}
+
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	user, err := r.db.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user by username: %w", err)
+	}
+	return convertDBUserToEntity(&user), nil
+}
+
+func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
+	err := r.db.CreateUser(ctx, db.CreateUserParams{
+		ID:           user.ID,
+		Username:     user.Username,
+		PasswordHash: user.PasswordHash,
+		Role:         user.Role,
+	})
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, username, role string) (bool, error) {
+	rows, err := r.db.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Role:     role,
+		Username: username,
+	})
+	if err != nil {
+		return false, fmt.Errorf("update user role: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// convertDBUserToEntity converts db.AppUser to entity.User
+func convertDBUserToEntity(u *db.AppUser) *entity.User {
+	return &entity.User{
+		ID:           u.ID,
+		Username:     u.Username,
+		PasswordHash: u.PasswordHash,
+		Role:         u.Role,
+		CreateTime:   parseSQLiteTime(u.CreateTime),
+		UpdateTime:   parseSQLiteTime(u.UpdateTime),
+	}
+}
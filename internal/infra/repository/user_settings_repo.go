@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"kbfood/internal/domain/entity"
 	"kbfood/internal/domain/repository"
@@ -42,11 +43,83 @@ func (r *userSettingsRepository) Upsert(ctx context.Context, settings *entity.Us
 	return nil
 }
 
+func (r *userSettingsRepository) UpdatePreferences(ctx context.Context, userID string, prefs entity.NotificationPreferences) error {
+	err := r.db.UpsertUserPreferences(ctx, db.UpsertUserPreferencesParams{
+		UserID:         userID,
+		Timezone:       prefs.Timezone,
+		QuietStart:     prefs.QuietStart,
+		QuietEnd:       prefs.QuietEnd,
+		MaxPerHour:     int64(prefs.MaxPerHour),
+		MaxPerDay:      int64(prefs.MaxPerDay),
+		BarkLevel:      prefs.BarkLevel,
+		BarkSound:      prefs.BarkSound,
+		DigestEnabled:  boolToInt64(prefs.DigestEnabled),
+		DigestTime:     prefs.DigestTime,
+		BarkGroup:      prefs.BarkGroup,
+		BarkIcon:       prefs.BarkIcon,
+		BarkArchive:    boolToInt64(prefs.BarkArchive),
+		BarkEncryptKey: prefs.BarkEncryptKey,
+		BarkEncryptIv:  prefs.BarkEncryptIV,
+	})
+	if err != nil {
+		return fmt.Errorf("update user preferences: %w", err)
+	}
+	return nil
+}
+
+func (r *userSettingsRepository) ListDigestSubscribers(ctx context.Context) ([]*entity.UserSettings, error) {
+	settings, err := r.db.ListDigestSubscribers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list digest subscribers: %w", err)
+	}
+
+	result := make([]*entity.UserSettings, len(settings))
+	for i, s := range settings {
+		result[i] = convertDBUserSettingsToEntity(&s)
+	}
+	return result, nil
+}
+
+func (r *userSettingsRepository) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	err := r.db.UpdateUserLastDigestTime(ctx, db.UpdateUserLastDigestTimeParams{
+		LastDigestTime: sqlNullStringFromTime(sentAt),
+		UserID:         userID,
+	})
+	if err != nil {
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+	return nil
+}
+
 // convertDBUserSettingsToEntity converts db.UserSetting to entity.UserSettings
 func convertDBUserSettingsToEntity(s *db.UserSetting) *entity.UserSettings {
+	var lastDigest *time.Time
+	if s.LastDigestTime.Valid && s.LastDigestTime.String != "" {
+		if t := parseSQLiteTime(s.LastDigestTime.String); !t.IsZero() {
+			lastDigest = &t
+		}
+	}
+
 	return &entity.UserSettings{
-		UserID:     s.UserID,
-		BarkKey:    s.BarkKey,
+		UserID:  s.UserID,
+		BarkKey: s.BarkKey,
+		Preferences: entity.NotificationPreferences{
+			Timezone:       s.Timezone,
+			QuietStart:     s.QuietStart,
+			QuietEnd:       s.QuietEnd,
+			MaxPerHour:     int(s.MaxPerHour),
+			MaxPerDay:      int(s.MaxPerDay),
+			BarkLevel:      s.BarkLevel,
+			BarkSound:      s.BarkSound,
+			DigestEnabled:  s.DigestEnabled != 0,
+			DigestTime:     s.DigestTime,
+			BarkGroup:      s.BarkGroup,
+			BarkIcon:       s.BarkIcon,
+			BarkArchive:    s.BarkArchive != 0,
+			BarkEncryptKey: s.BarkEncryptKey,
+			BarkEncryptIV:  s.BarkEncryptIv,
+		},
+		LastDigest: lastDigest,
 		CreateTime: parseSQLiteTime(s.CreateTime),
 		UpdateTime: parseSQLiteTime(s.UpdateTime),
 	}
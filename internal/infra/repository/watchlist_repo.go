@@ -0,0 +1,464 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+
+	db "kbfood/internal/infra/db"
+)
+
+// watchlistItemColumns selects a watchlist item for scanWatchlistItem
+const watchlistItemColumns = `i.watchlist_id, i.activity_id, i.rule_type, i.target_price, i.drop_percent,
+	i.drop_amount, i.reference_price, i.last_status, i.last_notify_time, i.create_time, i.update_time`
+
+// watchlistColumns selects a watchlist with its member count
+const watchlistColumns = `w.id, w.name, w.invite_code, w.create_time, w.update_time,
+	(SELECT COUNT(*) FROM watchlist_member c WHERE c.watchlist_id = w.id)`
+
+type watchlistRepository struct {
+	db *db.Pool
+}
+
+// NewWatchlistRepository creates a new watchlist repository
+func NewWatchlistRepository(db *db.Pool) repository.WatchlistRepository {
+	return &watchlistRepository{db: db}
+}
+
+func (r *watchlistRepository) Create(ctx context.Context, watchlist *entity.Watchlist, ownerUserID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin watchlist tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var createTime, updateTime string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO watchlist (name, invite_code)
+		VALUES (?, ?)
+		RETURNING id, create_time, update_time
+	`, watchlist.Name, watchlist.InviteCode).Scan(&watchlist.ID, &createTime, &updateTime)
+	if err != nil {
+		return fmt.Errorf("create watchlist: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO watchlist_member (watchlist_id, user_id, role)
+		VALUES (?, ?, ?)
+	`, watchlist.ID, ownerUserID, entity.WatchlistRoleOwner); err != nil {
+		return fmt.Errorf("add watchlist owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit watchlist tx: %w", err)
+	}
+
+	watchlist.Role = entity.WatchlistRoleOwner
+	watchlist.MemberCount = 1
+	watchlist.CreateTime = parseSQLiteTime(createTime)
+	watchlist.UpdateTime = parseSQLiteTime(updateTime)
+	return nil
+}
+
+func (r *watchlistRepository) FindByID(ctx context.Context, id int64) (*entity.Watchlist, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+watchlistColumns+` FROM watchlist w WHERE w.id = ?`, id)
+	watchlist, err := scanWatchlist(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get watchlist: %w", err)
+	}
+	return watchlist, nil
+}
+
+func (r *watchlistRepository) FindByInviteCode(ctx context.Context, inviteCode string) (*entity.Watchlist, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+watchlistColumns+` FROM watchlist w WHERE w.invite_code = ?`, inviteCode)
+	watchlist, err := scanWatchlist(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get watchlist by invite code: %w", err)
+	}
+	return watchlist, nil
+}
+
+func (r *watchlistRepository) ListByMember(ctx context.Context, userID string) ([]*entity.Watchlist, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+watchlistColumns+`, m.role
+		FROM watchlist w
+		JOIN watchlist_member m ON m.watchlist_id = w.id
+		WHERE m.user_id = ?
+		ORDER BY w.id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	var watchlists []*entity.Watchlist
+	for rows.Next() {
+		var watchlist entity.Watchlist
+		var createTime, updateTime string
+		if err := rows.Scan(
+			&watchlist.ID,
+			&watchlist.Name,
+			&watchlist.InviteCode,
+			&createTime,
+			&updateTime,
+			&watchlist.MemberCount,
+			&watchlist.Role,
+		); err != nil {
+			return nil, fmt.Errorf("scan watchlist: %w", err)
+		}
+		watchlist.CreateTime = parseSQLiteTime(createTime)
+		watchlist.UpdateTime = parseSQLiteTime(updateTime)
+		watchlists = append(watchlists, &watchlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate watchlists: %w", err)
+	}
+	return watchlists, nil
+}
+
+func (r *watchlistRepository) Update(ctx context.Context, watchlist *entity.Watchlist) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE watchlist
+		SET name = ?, invite_code = ?, update_time = datetime('now')
+		WHERE id = ?
+	`, watchlist.Name, watchlist.InviteCode, watchlist.ID)
+	if err != nil {
+		return fmt.Errorf("update watchlist: %w", err)
+	}
+	return nil
+}
+
+func (r *watchlistRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin watchlist tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM watchlist_item WHERE watchlist_id = ?`, id); err != nil {
+		return fmt.Errorf("delete watchlist items: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM watchlist_member WHERE watchlist_id = ?`, id); err != nil {
+		return fmt.Errorf("delete watchlist members: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM watchlist WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete watchlist: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit watchlist tx: %w", err)
+	}
+	return nil
+}
+
+func (r *watchlistRepository) FindMember(ctx context.Context, watchlistID int64, userID string) (*entity.WatchlistMember, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT m.id, m.watchlist_id, m.user_id, COALESCE(u.username, ''), m.role, m.create_time
+		FROM watchlist_member m
+		LEFT JOIN app_user u ON u.id = m.user_id
+		WHERE m.watchlist_id = ? AND m.user_id = ?
+	`, watchlistID, userID)
+
+	member, err := scanWatchlistMember(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get watchlist member: %w", err)
+	}
+	return member, nil
+}
+
+func (r *watchlistRepository) ListMembers(ctx context.Context, watchlistID int64) ([]*entity.WatchlistMember, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.watchlist_id, m.user_id, COALESCE(u.username, ''), m.role, m.create_time
+		FROM watchlist_member m
+		LEFT JOIN app_user u ON u.id = m.user_id
+		WHERE m.watchlist_id = ?
+		ORDER BY m.role = 'owner' DESC, m.id
+	`, watchlistID)
+	if err != nil {
+		return nil, fmt.Errorf("list watchlist members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*entity.WatchlistMember
+	for rows.Next() {
+		member, err := scanWatchlistMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan watchlist member: %w", err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate watchlist members: %w", err)
+	}
+	return members, nil
+}
+
+func (r *watchlistRepository) AddMember(ctx context.Context, member *entity.WatchlistMember) (bool, error) {
+	var createTime string
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO watchlist_member (watchlist_id, user_id, role)
+		VALUES (?, ?, ?)
+		ON CONFLICT(watchlist_id, user_id) DO NOTHING
+		RETURNING id, create_time
+	`, member.WatchlistID, member.UserID, member.Role).Scan(&member.ID, &createTime)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("add watchlist member: %w", err)
+	}
+
+	member.CreateTime = parseSQLiteTime(createTime)
+	return true, nil
+}
+
+func (r *watchlistRepository) RemoveMember(ctx context.Context, watchlistID, memberID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM watchlist_member
+		WHERE watchlist_id = ? AND id = ?
+	`, watchlistID, memberID)
+	if err != nil {
+		return false, fmt.Errorf("remove watchlist member: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("remove watchlist member: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *watchlistRepository) ListItems(ctx context.Context, watchlistID int64) ([]*entity.WatchlistItem, error) {
+	return r.queryItems(ctx, "list watchlist items", `
+		SELECT `+watchlistItemColumns+`
+		FROM watchlist_item i
+		WHERE i.watchlist_id = ?
+		ORDER BY i.id
+	`, watchlistID)
+}
+
+func (r *watchlistRepository) ListItemsByMember(ctx context.Context, userID string) ([]*entity.WatchlistItem, error) {
+	return r.queryItems(ctx, "list watchlist items by member", `
+		SELECT `+watchlistItemColumns+`
+		FROM watchlist_item i
+		JOIN watchlist_member m ON m.watchlist_id = i.watchlist_id
+		WHERE m.user_id = ?
+		ORDER BY i.id
+	`, userID)
+}
+
+func (r *watchlistRepository) ListItemsByActivityID(ctx context.Context, activityID string) ([]*entity.WatchlistItem, error) {
+	return r.queryItems(ctx, "list watchlist items by activity", `
+		SELECT `+watchlistItemColumns+`
+		FROM watchlist_item i
+		WHERE i.activity_id = ?
+		ORDER BY i.id
+	`, activityID)
+}
+
+func (r *watchlistRepository) ListAllItems(ctx context.Context) ([]*entity.WatchlistItem, error) {
+	return r.queryItems(ctx, "list all watchlist items", `
+		SELECT `+watchlistItemColumns+`
+		FROM watchlist_item i
+		ORDER BY i.id
+	`)
+}
+
+func (r *watchlistRepository) FindItem(ctx context.Context, watchlistID int64, activityID string) (*entity.WatchlistItem, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+watchlistItemColumns+`
+		FROM watchlist_item i
+		WHERE i.watchlist_id = ? AND i.activity_id = ?
+	`, watchlistID, activityID)
+
+	item, err := scanWatchlistItem(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get watchlist item: %w", err)
+	}
+	return item, nil
+}
+
+func (r *watchlistRepository) SaveItem(ctx context.Context, item *entity.WatchlistItem) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO watchlist_item (
+			watchlist_id, activity_id, rule_type, target_price, drop_percent, drop_amount,
+			reference_price, last_status, last_notify_time
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (watchlist_id, activity_id) DO UPDATE
+		SET rule_type = excluded.rule_type,
+			target_price = excluded.target_price,
+			drop_percent = excluded.drop_percent,
+			drop_amount = excluded.drop_amount,
+			reference_price = excluded.reference_price,
+			last_status = excluded.last_status,
+			last_notify_time = excluded.last_notify_time,
+			update_time = datetime('now')
+	`,
+		item.WatchlistID,
+		item.ActivityID,
+		item.EffectiveRuleType(),
+		item.TargetPrice,
+		sqlNullFloat64FromFloat(item.DropPercent),
+		sqlNullFloat64FromFloat(item.DropAmount),
+		sqlNullFloat64FromPtr(item.ReferencePrice),
+		sqlNullInt64FromPtr(item.LastStatus),
+		sqlNullStringFromTimePtr(item.LastNotifyTime),
+	)
+	if err != nil {
+		return fmt.Errorf("save watchlist item: %w", err)
+	}
+	return nil
+}
+
+func (r *watchlistRepository) DeleteItem(ctx context.Context, watchlistID int64, activityID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM watchlist_item
+		WHERE watchlist_id = ? AND activity_id = ?
+	`, watchlistID, activityID)
+	if err != nil {
+		return fmt.Errorf("delete watchlist item: %w", err)
+	}
+	return nil
+}
+
+func (r *watchlistRepository) UpdateItemNotifyTime(ctx context.Context, watchlistID int64, activityID string, price float64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE watchlist_item
+		SET last_notify_time = datetime('now'),
+			reference_price = ?,
+			update_time = datetime('now')
+		WHERE watchlist_id = ? AND activity_id = ?
+	`, price, watchlistID, activityID)
+	if err != nil {
+		return fmt.Errorf("update watchlist item notify time: %w", err)
+	}
+	return nil
+}
+
+func (r *watchlistRepository) UpdateItemLastStatus(ctx context.Context, watchlistID int64, activityID string, status int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE watchlist_item
+		SET last_status = ?
+		WHERE watchlist_id = ? AND activity_id = ?
+	`, status, watchlistID, activityID)
+	if err != nil {
+		return fmt.Errorf("update watchlist item last status: %w", err)
+	}
+	return nil
+}
+
+// queryItems runs a query selecting watchlistItemColumns
+func (r *watchlistRepository) queryItems(ctx context.Context, op, query string, args ...any) ([]*entity.WatchlistItem, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var items []*entity.WatchlistItem
+	for rows.Next() {
+		item, err := scanWatchlistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan watchlist item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate watchlist items: %w", err)
+	}
+	return items, nil
+}
+
+// scanWatchlist scans a watchlist row selected with watchlistColumns
+func scanWatchlist(row interface{ Scan(dest ...any) error }) (*entity.Watchlist, error) {
+	var watchlist entity.Watchlist
+	var createTime, updateTime string
+	if err := row.Scan(
+		&watchlist.ID,
+		&watchlist.Name,
+		&watchlist.InviteCode,
+		&createTime,
+		&updateTime,
+		&watchlist.MemberCount,
+	); err != nil {
+		return nil, err
+	}
+
+	watchlist.CreateTime = parseSQLiteTime(createTime)
+	watchlist.UpdateTime = parseSQLiteTime(updateTime)
+	return &watchlist, nil
+}
+
+// scanWatchlistMember scans a member row joined with the account username
+func scanWatchlistMember(row interface{ Scan(dest ...any) error }) (*entity.WatchlistMember, error) {
+	var member entity.WatchlistMember
+	var createTime string
+	if err := row.Scan(
+		&member.ID,
+		&member.WatchlistID,
+		&member.UserID,
+		&member.Username,
+		&member.Role,
+		&createTime,
+	); err != nil {
+		return nil, err
+	}
+
+	member.CreateTime = parseSQLiteTime(createTime)
+	return &member, nil
+}
+
+// scanWatchlistItem scans an item row selected with watchlistItemColumns
+func scanWatchlistItem(row interface{ Scan(dest ...any) error }) (*entity.WatchlistItem, error) {
+	var item entity.WatchlistItem
+	var dropPercent, dropAmount, referencePrice sql.NullFloat64
+	var lastStatus sql.NullInt64
+	var lastNotifyTime sql.NullString
+	var createTime, updateTime string
+	if err := row.Scan(
+		&item.WatchlistID,
+		&item.ActivityID,
+		&item.RuleType,
+		&item.TargetPrice,
+		&dropPercent,
+		&dropAmount,
+		&referencePrice,
+		&lastStatus,
+		&lastNotifyTime,
+		&createTime,
+		&updateTime,
+	); err != nil {
+		return nil, err
+	}
+
+	item.DropPercent = float64FromNull(dropPercent)
+	item.DropAmount = float64FromNull(dropAmount)
+	item.ReferencePrice = float64PtrFromNull(referencePrice)
+	item.LastStatus = intPtrFromNull(lastStatus)
+	if lastNotifyTime.Valid && lastNotifyTime.String != "" {
+		if t := parseSQLiteTime(lastNotifyTime.String); !t.IsZero() {
+			item.LastNotifyTime = &t
+		}
+	}
+	item.CreateTime = parseSQLiteTime(createTime)
+	item.UpdateTime = parseSQLiteTime(updateTime)
+	return &item, nil
+}
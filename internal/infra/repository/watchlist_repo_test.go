@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+)
+
+func TestWatchlistRepository_Items(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWatchlistRepository(setupUserDataDB(t))
+
+	lunch := &entity.Watchlist{Name: "午餐", InviteCode: "lunch"}
+	if err := repo.Create(ctx, lunch, "client-alice"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	dinner := &entity.Watchlist{Name: "晚餐", InviteCode: "dinner"}
+	if err := repo.Create(ctx, dinner, "client-carol"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := repo.AddMember(ctx, &entity.WatchlistMember{WatchlistID: lunch.ID, UserID: "client-bob", Role: entity.WatchlistRoleMember}); err != nil {
+		t.Fatalf("AddMember() error = %v", err)
+	}
+
+	for _, item := range []*entity.WatchlistItem{
+		{WatchlistID: lunch.ID, NotificationConfig: entity.NotificationConfig{ActivityID: "DT_1", TargetPrice: 20}},
+		{WatchlistID: lunch.ID, NotificationConfig: entity.NotificationConfig{ActivityID: "DT_2", RuleType: entity.RuleTypePercentDrop, DropPercent: 10}},
+		{WatchlistID: dinner.ID, NotificationConfig: entity.NotificationConfig{ActivityID: "DT_2", TargetPrice: 50}},
+	} {
+		if err := repo.SaveItem(ctx, item); err != nil {
+			t.Fatalf("SaveItem() error = %v", err)
+		}
+	}
+
+	items, err := repo.ListItemsByMember(ctx, "client-bob")
+	if err != nil {
+		t.Fatalf("ListItemsByMember() error = %v", err)
+	}
+	if len(items) != 2 || items[0].ActivityID != "DT_1" || items[1].ActivityID != "DT_2" || items[1].DropPercent != 10 {
+		t.Fatalf("items of member = %+v, want DT_1 and DT_2 of the lunch list", items)
+	}
+
+	watching, err := repo.ListItemsByActivityID(ctx, "DT_2")
+	if err != nil {
+		t.Fatalf("ListItemsByActivityID() error = %v", err)
+	}
+	if len(watching) != 2 {
+		t.Fatalf("items watching DT_2 = %d, want 2", len(watching))
+	}
+
+	if err := repo.UpdateItemNotifyTime(ctx, lunch.ID, "DT_1", 18.5); err != nil {
+		t.Fatalf("UpdateItemNotifyTime() error = %v", err)
+	}
+	item, err := repo.FindItem(ctx, lunch.ID, "DT_1")
+	if err != nil {
+		t.Fatalf("FindItem() error = %v", err)
+	}
+	if item == nil || item.LastNotifyTime == nil || item.ReferencePrice == nil || *item.ReferencePrice != 18.5 {
+		t.Fatalf("item after alert = %+v", item)
+	}
+
+	if err := repo.Delete(ctx, lunch.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	all, err := repo.ListAllItems(ctx)
+	if err != nil {
+		t.Fatalf("ListAllItems() error = %v", err)
+	}
+	if len(all) != 1 || all[0].WatchlistID != dinner.ID {
+		t.Fatalf("items after deleting the list = %+v, want only the dinner item", all)
+	}
+}
@@ -7,8 +7,7 @@ import (
 
 	"kbfood/internal/domain/repository"
 	"kbfood/internal/domain/service"
-
-	"github.com/rs/zerolog/log"
+	applog "kbfood/internal/pkg/logger"
 )
 
 // PromoteCandidatesJob promotes candidates from the candidate pool to master products
@@ -30,6 +29,8 @@ func (j *PromoteCandidatesJob) Name() string {
 
 // Run executes the job
 func (j *PromoteCandidatesJob) Run(ctx context.Context) error {
+	logger := applog.LoggerFromContext(ctx)
+
 	if j.cleaningService == nil {
 		return fmt.Errorf("cleaningService not initialized")
 	}
@@ -45,11 +46,11 @@ func (j *PromoteCandidatesJob) Run(ctx context.Context) error {
 	}
 
 	if total > 0 {
-		log.Info().
+		logger.Info().
 			Int("count", total).
 			Msg("Candidates promoted")
 	} else {
-		log.Debug().Msg("No candidates to promote")
+		logger.Debug().Msg("No candidates to promote")
 	}
 
 	return nil
@@ -85,6 +86,84 @@ func (j *PriceCheckJob) Run(ctx context.Context) error {
 	return nil
 }
 
+// DeliverHeldNotificationsJob delivers alerts held by quiet hours or frequency caps
+type DeliverHeldNotificationsJob struct {
+	notificationService *service.NotificationService
+}
+
+// NewDeliverHeldNotificationsJob creates a new deliver held notifications job
+func NewDeliverHeldNotificationsJob(notificationService *service.NotificationService) *DeliverHeldNotificationsJob {
+	return &DeliverHeldNotificationsJob{
+		notificationService: notificationService,
+	}
+}
+
+// Name returns the job name
+func (j *DeliverHeldNotificationsJob) Name() string {
+	return "deliver-held-notifications"
+}
+
+// Run executes the job
+func (j *DeliverHeldNotificationsJob) Run(ctx context.Context) error {
+	logger := applog.LoggerFromContext(ctx)
+
+	if j.notificationService == nil {
+		return fmt.Errorf("notificationService not initialized")
+	}
+
+	sent, err := j.notificationService.DeliverHeld(ctx)
+	if err != nil {
+		return fmt.Errorf("deliver held notifications job failed: %w", err)
+	}
+
+	if sent > 0 {
+		logger.Info().
+			Int("count", sent).
+			Msg("Held notifications delivered")
+	}
+
+	return nil
+}
+
+// DigestJob sends daily digests to users in digest mode
+type DigestJob struct {
+	digestService *service.DigestService
+}
+
+// NewDigestJob creates a new digest job
+func NewDigestJob(digestService *service.DigestService) *DigestJob {
+	return &DigestJob{
+		digestService: digestService,
+	}
+}
+
+// Name returns the job name
+func (j *DigestJob) Name() string {
+	return "daily-digest"
+}
+
+// Run executes the job
+func (j *DigestJob) Run(ctx context.Context) error {
+	logger := applog.LoggerFromContext(ctx)
+
+	if j.digestService == nil {
+		return fmt.Errorf("digestService not initialized")
+	}
+
+	sent, err := j.digestService.SendDue(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("daily digest job failed: %w", err)
+	}
+
+	if sent > 0 {
+		logger.Info().
+			Int("count", sent).
+			Msg("Daily digests sent")
+	}
+
+	return nil
+}
+
 // CleanupJob cleans up expired data
 type CleanupJob struct {
 	prodRepo repository.ProductRepository
@@ -104,6 +183,8 @@ func (j *CleanupJob) Name() string {
 
 // Run executes the job
 func (j *CleanupJob) Run(ctx context.Context) error {
+	logger := applog.LoggerFromContext(ctx)
+
 	if j.prodRepo == nil {
 		return fmt.Errorf("product repository not initialized")
 	}
@@ -113,7 +194,7 @@ func (j *CleanupJob) Run(ctx context.Context) error {
 	// TODO: Implement delete products older than threshold
 	// This requires a FindByUpdateTime repository method
 
-	log.Info().
+	logger.Info().
 		Time("threshold", threshold).
 		Msg("Cleanup completed")
 
@@ -139,6 +220,8 @@ func (j *RecordTrendsJob) Name() string {
 
 // Run executes the job
 func (j *RecordTrendsJob) Run(ctx context.Context) error {
+	logger := applog.LoggerFromContext(ctx)
+
 	if j.cleaningService == nil {
 		return fmt.Errorf("cleaningService not initialized")
 	}
@@ -149,11 +232,11 @@ func (j *RecordTrendsJob) Run(ctx context.Context) error {
 	}
 
 	if count > 0 {
-		log.Info().
+		logger.Info().
 			Int("count", count).
 			Msg("Price trends recorded")
 	} else {
-		log.Debug().Msg("No trends to record")
+		logger.Debug().Msg("No trends to record")
 	}
 
 	return nil
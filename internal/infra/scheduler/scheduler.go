@@ -6,6 +6,10 @@ import (
 	"sync"
 	"time"
 
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/metrics"
+	"kbfood/internal/pkg/tracing"
+
 	"github.com/rs/zerolog/log"
 	"github.com/robfig/cron/v3"
 )
@@ -85,35 +89,13 @@ func (s *Scheduler) RegisterJobWithTimeWindow(job Job, cronExpr string) error {
 // wrapJob wraps a job with logging
 func (s *Scheduler) wrapJob(job Job) func() {
 	return func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
-
-		log.Info().
-			Str("job", job.Name()).
-			Msg("Job started")
-
-		start := time.Now()
-
-		if err := job.Run(ctx); err != nil {
-			log.Error().Err(err).
-				Str("job", job.Name()).
-				Dur("duration", time.Since(start)).
-				Msg("Job failed")
-		} else {
-			log.Info().
-				Str("job", job.Name()).
-				Dur("duration", time.Since(start)).
-				Msg("Job completed")
-		}
+		runJob(job)
 	}
 }
 
 // wrapJobWithTimeWindow wraps a job with time window check
 func (s *Scheduler) wrapJobWithTimeWindow(job Job) func() {
 	return func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
-
 		if !s.timeWindow.IsActiveNow() {
 			log.Debug().
 				Str("job", job.Name()).
@@ -122,23 +104,41 @@ func (s *Scheduler) wrapJobWithTimeWindow(job Job) func() {
 			return
 		}
 
-		log.Info().
-			Str("job", job.Name()).
-			Msg("Job started")
-
-		start := time.Now()
+		runJob(job)
+	}
+}
 
-		if err := job.Run(ctx); err != nil {
-			log.Error().Err(err).
-				Str("job", job.Name()).
-				Dur("duration", time.Since(start)).
-				Msg("Job failed")
-		} else {
-			log.Info().
-				Str("job", job.Name()).
-				Dur("duration", time.Since(start)).
-				Msg("Job completed")
-		}
+// runJob runs a job with a timeout, logging and recording its duration and
+// outcome. Each run gets a run ID and a root span, so that the fetches,
+// repository calls and notifications it causes can be tied back to it.
+func runJob(job Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ctx = applog.JobContext(ctx, job.Name())
+	ctx, span := tracing.Start(ctx, "job "+job.Name(), tracing.KindInternal,
+		tracing.String("job.name", job.Name()),
+		tracing.String("job.run_id", applog.RequestIDFromContext(ctx)),
+	)
+	defer span.End()
+
+	logger := applog.LoggerFromContext(ctx)
+	logger.Info().Msg("Job started")
+
+	start := time.Now()
+
+	if err := job.Run(ctx); err != nil {
+		span.RecordError(err)
+		metrics.JobDuration.With(job.Name(), metrics.StatusFailure).ObserveSince(start)
+		logger.Error().Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("Job failed")
+	} else {
+		metrics.JobDuration.With(job.Name(), metrics.StatusSuccess).ObserveSince(start)
+		metrics.JobLastSuccess.With(job.Name()).SetToTime(time.Now())
+		logger.Info().
+			Dur("duration", time.Since(start)).
+			Msg("Job completed")
 	}
 }
 
@@ -10,8 +10,7 @@ import (
 	"kbfood/internal/domain/repository"
 	"kbfood/internal/domain/service"
 	"kbfood/internal/infra/platform"
-
-	"github.com/rs/zerolog/log"
+	applog "kbfood/internal/pkg/logger"
 )
 
 // SyncJob synchronizes products from TanTanTang platform
@@ -44,6 +43,8 @@ func (j *SyncJob) Name() string {
 
 // Run executes the sync job
 func (j *SyncJob) Run(ctx context.Context) error {
+	logger := applog.LoggerFromContext(ctx)
+
 	startTime := time.Now()
 
 	if j.client == nil {
@@ -70,7 +71,7 @@ func (j *SyncJob) Run(ctx context.Context) error {
 		// TanTanTangClient.FetchProducts handles pagination internally
 		products, err := j.client.FetchProducts(ctx, region)
 		if err != nil {
-			log.Error().Err(err).
+			logger.Error().Err(err).
 				Str("region", region).
 				Msg("Failed to fetch products")
 			lastErr = err
@@ -78,39 +79,43 @@ func (j *SyncJob) Run(ctx context.Context) error {
 		}
 
 		if len(products) == 0 {
-			log.Warn().
+			logger.Warn().
 				Str("region", region).
 				Msg("No products fetched from API")
 			continue
 		}
 
 		if products == nil {
-			log.Warn().
+			logger.Warn().
 				Str("region", region).
 				Msg("No products fetched")
 			continue
 		}
 
+		inputs := make([]*entity.DTInputDTO, 0, len(products))
 		for _, p := range products {
 			// Nil check for individual products
 			if p == nil {
-				log.Warn().Msg("Skipping nil product")
+				logger.Warn().Msg("Skipping nil product")
 				continue
 			}
 
 			// Convert PlatformProductDTO to DTInputDTO for processing
-			input := &entity.DTInputDTO{
+			inputs = append(inputs, &entity.DTInputDTO{
 				Title:     p.Title,
+				ShopName:  p.ShopName,
 				Price:     p.CurrentPrice,
 				Status:    p.SalesStatus,
 				CrawlTime: p.ActivityCreateTime.Unix(),
 				Region:    region,
-			}
+			})
+		}
 
-			_, err := j.cleaningService.ProcessIncomingItem(ctx, input, region)
+		_, errs := j.cleaningService.ProcessIncomingItems(ctx, inputs)
+		for i, err := range errs {
 			if err != nil {
-				log.Error().Err(err).
-					Str("title", p.Title).
+				logger.Error().Err(err).
+					Str("title", inputs[i].Title).
 					Str("region", region).
 					Msg("Failed to process product")
 			} else {
@@ -119,7 +124,7 @@ func (j *SyncJob) Run(ctx context.Context) error {
 		}
 	}
 
-	log.Info().
+	logger.Info().
 		Int("totalProducts", totalProducts).
 		Msg("Sync job completed")
 
@@ -131,6 +136,8 @@ func (j *SyncJob) Run(ctx context.Context) error {
 
 // recordStatus records the sync status to the database
 func (j *SyncJob) recordStatus(ctx context.Context, startTime time.Time, productCount int, err error) {
+	logger := applog.LoggerFromContext(ctx)
+
 	if j.syncStatusRepo == nil {
 		return
 	}
@@ -148,6 +155,6 @@ func (j *SyncJob) recordStatus(ctx context.Context, startTime time.Time, product
 	}
 
 	if recordErr := j.syncStatusRepo.Upsert(ctx, status); recordErr != nil {
-		log.Error().Err(recordErr).Msg("Failed to record sync status")
+		logger.Error().Err(recordErr).Msg("Failed to record sync status")
 	}
 }
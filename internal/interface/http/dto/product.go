@@ -1,30 +1,55 @@
 package dto
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
 	"time"
 
 	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
 )
 
+// ErrInvalidCursor is returned for a malformed or foreign page cursor
+var ErrInvalidCursor = errors.New("无效的分页游标")
+
 // ProductDTO represents a product response
 type ProductDTO struct {
-	ID                 int64     `json:"id"`
-	ActivityID         string    `json:"activityId"`
-	Platform           string    `json:"platform"`
-	Region             string    `json:"region"`
-	Title              string    `json:"title"`
-	ShopName           string    `json:"shopName"`
-	OriginalPrice      float64   `json:"originalPrice"`
-	CurrentPrice       float64   `json:"currentPrice"`
-	SalesStatus        int       `json:"salesStatus"`
-	SalesStatusText    string    `json:"salesStatusText"`
-	ActivityCreateTime time.Time `json:"activityCreateTime"`
-	CreateTime         time.Time `json:"createTime"`
-	UpdateTime         time.Time `json:"updateTime"`
-	Discount           float64   `json:"discount,omitempty"`
-	DropRate           float64   `json:"dropRate,omitempty"`
-	HasNotification    bool      `json:"hasNotification,omitempty"`
-	TargetPrice        *float64  `json:"targetPrice,omitempty"`
+	ID                 int64            `json:"id"`
+	ActivityID         string           `json:"activityId"`
+	Platform           string           `json:"platform"`
+	Region             string           `json:"region"`
+	Title              string           `json:"title"`
+	ShopName           string           `json:"shopName"`
+	OriginalPrice      float64          `json:"originalPrice"`
+	CurrentPrice       float64          `json:"currentPrice"`
+	SalesStatus        int              `json:"salesStatus"`
+	SalesStatusText    string           `json:"salesStatusText"`
+	ActivityCreateTime time.Time        `json:"activityCreateTime"`
+	CreateTime         time.Time        `json:"createTime"`
+	UpdateTime         time.Time        `json:"updateTime"`
+	Discount           float64          `json:"discount,omitempty"`
+	DropRate           float64          `json:"dropRate,omitempty"`
+	HasNotification    bool             `json:"hasNotification,omitempty"`
+	TargetPrice        *float64         `json:"targetPrice,omitempty"`
+	Notification       *NotificationDTO `json:"notification,omitempty"`
+	Favorite           bool             `json:"favorite,omitempty"`
+	Tags               []string         `json:"tags,omitempty"`
+	Note               string           `json:"note,omitempty"`
+	Highlight          *HighlightDTO    `json:"highlight,omitempty"`
+}
+
+// HighlightDTO holds HTML-escaped text with search matches wrapped in <mark> tags
+type HighlightDTO struct {
+	Title    string `json:"title"`
+	ShopName string `json:"shopName,omitempty"`
+}
+
+// TagDTO represents a user's tag with the number of products carrying it
+type TagDTO struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
 }
 
 // PriceTrendDTO represents a price trend point
@@ -35,9 +60,13 @@ type PriceTrendDTO struct {
 
 // NotificationDTO represents a notification config response
 type NotificationDTO struct {
-	ActivityID     string  `json:"activityId"`
-	TargetPrice    float64 `json:"targetPrice"`
-	LastNotifyTime *string `json:"lastNotifyTime,omitempty"`
+	ActivityID     string     `json:"activityId"`
+	RuleType       string     `json:"ruleType"`
+	TargetPrice    float64    `json:"targetPrice"`
+	DropPercent    float64    `json:"dropPercent,omitempty"`
+	DropAmount     float64    `json:"dropAmount,omitempty"`
+	LastNotifyTime *string    `json:"lastNotifyTime,omitempty"`
+	SnoozeUntil    *time.Time `json:"snoozeUntil,omitempty"`
 }
 
 // FromEntity converts a Product entity to DTO
@@ -114,13 +143,62 @@ func FromMasterEntities(products []*entity.MasterProduct) []ProductDTO {
 // FromEntityWithNotification converts a Product entity to DTO with notification info
 func FromEntityWithNotification(p *entity.Product, noti *entity.NotificationConfig) ProductDTO {
 	dto := FromEntity(p)
-	if noti != nil {
-		dto.HasNotification = true
-		dto.TargetPrice = &noti.TargetPrice
-	}
+	dto.ApplyNotification(noti)
 	return dto
 }
 
+// ApplyNotification attaches notification info to the product DTO
+func (d *ProductDTO) ApplyNotification(noti *entity.NotificationConfig) {
+	if noti == nil {
+		return
+	}
+	d.HasNotification = true
+	if noti.EffectiveRuleType() == entity.RuleTypeTargetPrice {
+		targetPrice := noti.TargetPrice
+		d.TargetPrice = &targetPrice
+	}
+	notification := FromNotificationEntity(noti)
+	d.Notification = &notification
+}
+
+// ApplyReferencePrice sets the original price of a master product DTO to its
+// highest recorded price and derives the discount and drop rate from it
+func (d *ProductDTO) ApplyReferencePrice(originalPrice float64) {
+	if originalPrice <= d.CurrentPrice {
+		return
+	}
+	d.OriginalPrice = originalPrice
+	d.Discount = (1 - d.CurrentPrice/originalPrice) * 10
+	d.DropRate = (1 - d.CurrentPrice/originalPrice) * 100
+}
+
+// ApplyHighlight marks where the search terms match the title and shop name.
+// Without terms, or when neither matches, no highlight is attached.
+func (d *ProductDTO) ApplyHighlight(terms []string) {
+	if len(terms) == 0 {
+		return
+	}
+	title, titleMatched := entity.HighlightSearchTerms(d.Title, terms)
+	shopName, shopMatched := entity.HighlightSearchTerms(d.ShopName, terms)
+	if !titleMatched && !shopMatched {
+		return
+	}
+	d.Highlight = &HighlightDTO{Title: title}
+	if shopMatched {
+		d.Highlight.ShopName = shopName
+	}
+}
+
+// ApplyAnnotation attaches the user's favorite star, tags and note to the product DTO
+func (d *ProductDTO) ApplyAnnotation(annotation *entity.ProductAnnotation) {
+	if annotation == nil {
+		return
+	}
+	d.Favorite = annotation.Favorite
+	d.Tags = annotation.Tags
+	d.Note = annotation.Note
+}
+
 // FromEntities converts multiple Product entities to DTOs
 func FromEntities(products []*entity.Product) []ProductDTO {
 	result := make([]ProductDTO, 0, len(products))
@@ -167,7 +245,38 @@ func FromNotificationEntity(n *entity.NotificationConfig) NotificationDTO {
 
 	return NotificationDTO{
 		ActivityID:     n.ActivityID,
+		RuleType:       n.EffectiveRuleType(),
 		TargetPrice:    n.TargetPrice,
+		DropPercent:    n.DropPercent,
+		DropAmount:     n.DropAmount,
 		LastNotifyTime: lastNotifyTime,
+		SnoozeUntil:    n.SnoozeUntil,
+	}
+}
+
+// EncodeListingCursor encodes a listing cursor as an opaque URL-safe string
+func EncodeListingCursor(cursor *repository.ListingCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeListingCursor decodes a cursor produced by EncodeListingCursor for the given sort key
+func DecodeListingCursor(s, sort string) (*repository.ListingCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor repository.ListingCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if sort != repository.ListingSortUpdateTime {
+		if _, err := strconv.ParseFloat(cursor.Value, 64); err != nil {
+			return nil, ErrInvalidCursor
+		}
 	}
+	return &cursor, nil
 }
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+// ProductDetailDTO aggregates everything known about one product
+type ProductDetailDTO struct {
+	Product      ProductDTO       `json:"product"`
+	Source       string           `json:"source"` // master or product
+	Blocked      bool             `json:"blocked"`
+	TrustScore   int              `json:"trustScore"`
+	LastSeenTime time.Time        `json:"lastSeenTime"`
+	TrendSummary *TrendSummaryDTO `json:"trendSummary,omitempty"`
+	TitleVotes   []TitleVoteDTO   `json:"titleVotes"`
+	Related      []ProductDTO     `json:"related"`
+}
+
+// TrendSummaryDTO represents a summary of a product's price history
+type TrendSummaryDTO struct {
+	Points       int     `json:"points"`
+	FirstPrice   float64 `json:"firstPrice"`
+	FirstDate    string  `json:"firstDate"`
+	LatestPrice  float64 `json:"latestPrice"`
+	LatestDate   string  `json:"latestDate"`
+	LowestPrice  float64 `json:"lowestPrice"`
+	LowestDate   string  `json:"lowestDate"`
+	HighestPrice float64 `json:"highestPrice"`
+	ChangeRate   float64 `json:"changeRate"`
+}
+
+// TitleVoteDTO represents a raw title seen for a master product and its count
+type TitleVoteDTO struct {
+	Title string `json:"title"`
+	Votes int    `json:"votes"`
+}
+
+// FromTrendSummary converts a trend summary to DTO, nil stays nil
+func FromTrendSummary(s *entity.TrendSummary) *TrendSummaryDTO {
+	if s == nil {
+		return nil
+	}
+	return &TrendSummaryDTO{
+		Points:       s.Points,
+		FirstPrice:   s.FirstPrice,
+		FirstDate:    s.FirstDate.Format("2006-01-02"),
+		LatestPrice:  s.LatestPrice,
+		LatestDate:   s.LatestDate.Format("2006-01-02"),
+		LowestPrice:  s.LowestPrice,
+		LowestDate:   s.LowestDate.Format("2006-01-02"),
+		HighestPrice: s.HighestPrice,
+		ChangeRate:   s.ChangeRate,
+	}
+}
+
+// FromTitleVotes converts title votes to DTOs
+func FromTitleVotes(votes []*entity.TitleVote) []TitleVoteDTO {
+	result := make([]TitleVoteDTO, 0, len(votes))
+	for _, v := range votes {
+		if v != nil {
+			result = append(result, TitleVoteDTO{Title: v.Title, Votes: v.Votes})
+		}
+	}
+	return result
+}
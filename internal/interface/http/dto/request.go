@@ -0,0 +1,90 @@
+package dto
+
+// CredentialsRequest is the body of sign-up and login requests
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateTokenRequest is the body of a personal API token request
+type CreateTokenRequest struct {
+	Name string `json:"name"`
+}
+
+// SetRoleRequest is the body of an account role change
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// PairDeviceRequest is the body of a device pairing request
+type PairDeviceRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// BarkKeyRequest carries a Bark device key, or a full Bark URL containing it
+type BarkKeyRequest struct {
+	BarkKey string `json:"barkKey"`
+}
+
+// NotificationRuleRequest creates a price watch on a product
+type NotificationRuleRequest struct {
+	ActivityID  string  `json:"activityId"`
+	RuleType    string  `json:"ruleType,omitempty"`
+	TargetPrice float64 `json:"targetPrice,omitempty"`
+	DropPercent float64 `json:"dropPercent,omitempty"`
+	DropAmount  float64 `json:"dropAmount,omitempty"`
+}
+
+// UpdateNotificationRequest changes the fields of a price watch that are present.
+// An empty snoozeUntil clears the snooze.
+type UpdateNotificationRequest struct {
+	RuleType    *string  `json:"ruleType,omitempty"`
+	TargetPrice *float64 `json:"targetPrice,omitempty"`
+	DropPercent *float64 `json:"dropPercent,omitempty"`
+	DropAmount  *float64 `json:"dropAmount,omitempty"`
+	SnoozeUntil *string  `json:"snoozeUntil,omitempty"` // YYYY-MM-DD or RFC3339
+}
+
+// UpdateWatchlistItemRequest changes the fields of a watchlist item rule that are present
+type UpdateWatchlistItemRequest struct {
+	RuleType    *string  `json:"ruleType,omitempty"`
+	TargetPrice *float64 `json:"targetPrice,omitempty"`
+	DropPercent *float64 `json:"dropPercent,omitempty"`
+	DropAmount  *float64 `json:"dropAmount,omitempty"`
+}
+
+// UpdateAnnotationRequest changes the fields of a product annotation that are present
+type UpdateAnnotationRequest struct {
+	Favorite *bool     `json:"favorite,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	Note     *string   `json:"note,omitempty"`
+}
+
+// SavedSearchRequest creates a saved search subscription
+type SavedSearchRequest struct {
+	Name     string  `json:"name"`
+	Keyword  string  `json:"keyword,omitempty"`
+	Region   string  `json:"region,omitempty"`
+	Platform string  `json:"platform,omitempty"`
+	MaxPrice float64 `json:"maxPrice,omitempty"`
+}
+
+// UpdateSavedSearchRequest changes the fields of a saved search that are present
+type UpdateSavedSearchRequest struct {
+	Name     *string  `json:"name,omitempty"`
+	Keyword  *string  `json:"keyword,omitempty"`
+	Region   *string  `json:"region,omitempty"`
+	Platform *string  `json:"platform,omitempty"`
+	MaxPrice *float64 `json:"maxPrice,omitempty"`
+}
+
+// WatchlistNameRequest names a new or renamed watchlist
+type WatchlistNameRequest struct {
+	Name string `json:"name"`
+}
+
+// JoinWatchlistRequest is the body of a request to join a watchlist by invite code
+type JoinWatchlistRequest struct {
+	InviteCode string `json:"inviteCode"`
+}
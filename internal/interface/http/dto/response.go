@@ -21,6 +21,8 @@ type Meta struct {
 	Total int `json:"total,omitempty"`
 	Page  int `json:"page,omitempty"`
 	Limit int `json:"limit,omitempty"`
+	// NextCursor fetches the following page of a cursor-paginated list
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // Success creates a success response
@@ -48,18 +50,29 @@ func Error(code int, message string) Response {
 	}
 }
 
-// FromAppError creates an error response from AppError
-func FromAppError(err *errors.AppError) Response {
+// FromAppError creates an error response from AppError. The code is the
+// error code clients branch on, not the HTTP status. Chinese clients get the
+// specific message of the error, English clients the message of the code;
+// server errors always get the message of the code, so causes are not leaked.
+func FromAppError(err *errors.AppError, lang string) Response {
+	message := err.Message
+	if message == "" || lang != errors.LangZH || err.Code.HTTPStatus() >= http.StatusInternalServerError {
+		message = err.Code.LocalizedMessage(lang)
+	}
 	return Response{
-		Code:    err.Code.HTTPStatus(),
-		Message: err.Message,
+		Code:    int(err.Code),
+		Message: message,
 	}
 }
 
-// WriteJSON writes a JSON response
+// WriteJSON writes a JSON response with resp.Code as the HTTP status
 func WriteJSON(w http.ResponseWriter, resp Response) {
+	writeJSON(w, resp.Code, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp Response) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.Code)
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
 	}
@@ -72,9 +85,6 @@ func WriteError(w http.ResponseWriter, code int, message string) {
 
 // WriteErr writes an error response from an error
 func WriteErr(w http.ResponseWriter, err error) {
-	if appErr, ok := err.(*errors.AppError); ok {
-		WriteJSON(w, FromAppError(appErr))
-	} else {
-		WriteError(w, http.StatusInternalServerError, "Internal server error")
-	}
+	appErr := errors.Ensure(err, errors.Unknown, "")
+	writeJSON(w, appErr.Code.HTTPStatus(), FromAppError(appErr, errors.LangZH))
 }
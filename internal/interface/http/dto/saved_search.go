@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"kbfood/internal/domain/entity"
+)
+
+// SavedSearchDTO represents a saved search response
+type SavedSearchDTO struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Keyword    string    `json:"keyword"`
+	Region     string    `json:"region"`
+	Platform   string    `json:"platform"`
+	MaxPrice   float64   `json:"maxPrice,omitempty"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// FromSavedSearch converts a SavedSearch entity to DTO
+func FromSavedSearch(s *entity.SavedSearch) SavedSearchDTO {
+	if s == nil {
+		return SavedSearchDTO{}
+	}
+	return SavedSearchDTO{
+		ID:         s.ID,
+		Name:       s.Name,
+		Keyword:    s.Keyword,
+		Region:     s.Region,
+		Platform:   s.Platform,
+		MaxPrice:   s.MaxPrice,
+		CreateTime: s.CreateTime,
+		UpdateTime: s.UpdateTime,
+	}
+}
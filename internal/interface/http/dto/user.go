@@ -0,0 +1,38 @@
+package dto
+
+import "kbfood/internal/domain/entity"
+
+// AuthResponse is returned on sign-up and login with the new session token
+type AuthResponse struct {
+	User  *entity.User `json:"user"`
+	Token string       `json:"token"`
+}
+
+// ClaimClientResponse reports which anonymous client's data was claimed
+type ClaimClientResponse struct {
+	ClientID string `json:"clientId"`
+}
+
+// CreateTokenResponse holds a new personal API token. The secret is only returned once.
+type CreateTokenResponse struct {
+	Token  string            `json:"token"`
+	Detail *entity.AuthToken `json:"detail"`
+}
+
+// RoleDTO represents the role of an account
+type RoleDTO struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// UserSettingsDTO represents the Bark settings of a user
+type UserSettingsDTO struct {
+	BarkKey string `json:"barkKey"`
+}
+
+// TestNotificationDTO reports the result of a test push
+type TestNotificationDTO struct {
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+	TestedAt string `json:"testedAt"`
+}
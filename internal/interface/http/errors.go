@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+	applog "kbfood/internal/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// errorHandler renders the errors returned by handlers and middleware. AppErrors
+// keep their code and the HTTP status of the code; framework errors such as
+// unknown routes are mapped by status, and any other error is a server error
+// with code 10000. Unknown paths outside the API are served from the frontend.
+func errorHandler(frontend fs.FS) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		appErr, status := classifyError(err)
+		if status == http.StatusNotFound && isFrontendRequest(c.Request()) && serveFrontend(c, frontend) {
+			return
+		}
+
+		if status >= http.StatusInternalServerError {
+			applog.LoggerFromContext(c.Request().Context()).Error().Err(err).
+				Int("code", int(appErr.Code)).
+				Str("method", c.Request().Method).
+				Str("path", middleware.LoggedPath(c)).
+				Msg("Request failed")
+		}
+
+		if c.Request().Method == http.MethodHead {
+			_ = c.NoContent(status)
+			return
+		}
+		lang := apperrors.Language(c.Request().Header.Get("Accept-Language"))
+		_ = c.JSON(status, dto.FromAppError(appErr, lang))
+	}
+}
+
+// classifyError returns the AppError and HTTP status of an error
+func classifyError(err error) (*apperrors.AppError, int) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr, appErr.Code.HTTPStatus()
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return apperrors.Wrap(apperrors.CodeForStatus(httpErr.Code), "", err), httpErr.Code
+	}
+	return apperrors.Wrap(apperrors.Unknown, "", err), http.StatusInternalServerError
+}
+
+// isFrontendRequest reports whether a request for an unknown path may be for
+// a file of the frontend or a client-side route
+func isFrontendRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	for _, prefix := range []string{"/api", "/assets", "/feeds", "/health", "/ready", "/metrics"} {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		lang        string
+		wantStatus  int
+		wantCode    apperrors.ErrorCode
+		wantMessage string
+	}{
+		{
+			name:        "app error keeps its message",
+			err:         apperrors.New(apperrors.InvalidInput, "价格区间无效"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    apperrors.InvalidInput,
+			wantMessage: "价格区间无效",
+		},
+		{
+			name:        "english clients get the message of the code",
+			err:         apperrors.ErrMissingUserID,
+			lang:        "en-US,en;q=0.9",
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    apperrors.MissingUserID,
+			wantMessage: apperrors.MissingUserID.Message(),
+		},
+		{
+			name:        "database errors do not leak their cause",
+			err:         apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch products", errors.New("database is locked")),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    apperrors.ErrDatabase,
+			wantMessage: apperrors.ErrDatabase.LocalizedMessage(apperrors.LangZH),
+		},
+		{
+			name:        "framework errors are mapped by status",
+			err:         echo.ErrNotFound,
+			wantStatus:  http.StatusNotFound,
+			wantCode:    apperrors.NotFound,
+			wantMessage: apperrors.NotFound.LocalizedMessage(apperrors.LangZH),
+		},
+		{
+			name:        "plain errors are server errors",
+			err:         errors.New("boom"),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    apperrors.Unknown,
+			wantMessage: apperrors.Unknown.LocalizedMessage(apperrors.LangZH),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = errorHandler(fstest.MapFS{})
+			e.GET("/api/fail", func(c echo.Context) error { return tt.err })
+
+			req := httptest.NewRequest(http.MethodGet, "/api/fail", nil)
+			if tt.lang != "" {
+				req.Header.Set("Accept-Language", tt.lang)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var resp dto.Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Code != int(tt.wantCode) {
+				t.Errorf("code = %d, want %d", resp.Code, tt.wantCode)
+			}
+			if resp.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", resp.Message, tt.wantMessage)
+			}
+		})
+	}
+}
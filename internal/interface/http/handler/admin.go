@@ -10,6 +10,7 @@ import (
 
 	"kbfood/internal/infra/platform"
 	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
@@ -43,7 +44,7 @@ func (h *SyncHandler) TriggerSync(c echo.Context) error {
 
 	if err := h.syncJob.Run(ctx); err != nil {
 		log.Error().Err(err).Msg("Manual sync failed")
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "sync operation failed"))
+		return apperrors.Ensure(err, apperrors.Unknown, "sync operation failed")
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(map[string]string{
@@ -96,11 +97,11 @@ func (h *SyncHandler) TestNotification(c echo.Context) error {
 	}
 
 	if err := c.Bind(&params); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid request format"))
+		return apperrors.ErrInvalidRequest
 	}
 
 	if params.BarkKey == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Bark key is required"))
+		return apperrors.New(apperrors.InvalidInput, "Bark Key 不能为空")
 	}
 
 	// Build Bark URL
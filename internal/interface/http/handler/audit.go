@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// defaultAuditLimit is the number of audit log entries returned by default
+const defaultAuditLimit = 100
+
+// maxAuditLimit caps the number of audit log entries per request
+const maxAuditLimit = 1000
+
+// AuditHandler handles admin audit log requests
+type AuditHandler struct {
+	auditLogRepo repository.AuditLogRepository
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditLogRepo repository.AuditLogRepository) *AuditHandler {
+	return &AuditHandler{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// ListAuditLogs handles GET /api/admin/audit
+func (h *AuditHandler) ListAuditLogs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit := defaultAuditLimit
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return apperrors.New(apperrors.InvalidInput, "limit 必须是正整数")
+		}
+		limit = min(parsed, maxAuditLimit)
+	}
+
+	logs, err := h.auditLogRepo.ListRecent(ctx, limit)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to list audit logs", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(logs))
+}
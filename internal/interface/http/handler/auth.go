@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuthHandler handles account and token requests
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// SignUp handles POST /api/auth/signup
+func (h *AuthHandler) SignUp(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var params dto.CredentialsRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	params.Username = strings.TrimSpace(params.Username)
+	if err := entity.ValidateCredentials(params.Username, params.Password); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	user, token, err := h.authService.SignUp(ctx, params.Username, params.Password)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to sign up")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.AuthResponse{
+		User:  user,
+		Token: token,
+	}))
+}
+
+// Login handles POST /api/auth/login
+func (h *AuthHandler) Login(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var params dto.CredentialsRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	user, token, err := h.authService.Login(ctx, params.Username, params.Password)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to log in")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.AuthResponse{
+		User:  user,
+		Token: token,
+	}))
+}
+
+// Logout handles POST /api/auth/logout
+func (h *AuthHandler) Logout(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	token := middleware.GetAuthToken(c)
+	if token == "" {
+		return c.JSON(http.StatusOK, dto.Success(nil))
+	}
+
+	if err := h.authService.Logout(ctx, token); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to log out")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// Me handles GET /api/auth/me
+func (h *AuthHandler) Me(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if !middleware.IsAuthenticated(c) {
+		return apperrors.ErrLoginRequired
+	}
+
+	user, err := h.authService.GetUser(ctx, middleware.GetUserID(c))
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to get user")
+	}
+	if user == nil {
+		return apperrors.ErrLoginRequired
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(user))
+}
+
+// ClaimClient handles POST /api/auth/claim
+// Moves the data of the anonymous client ID sent in the X-User-ID header of the
+// same request onto the account. Other client IDs cannot be named, so only the
+// device that holds an anonymous ID can claim it.
+func (h *AuthHandler) ClaimClient(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if !middleware.IsAuthenticated(c) {
+		return apperrors.ErrLoginRequired
+	}
+
+	clientID := strings.TrimSpace(c.Request().Header.Get(middleware.UserIDHeader))
+	if clientID == "" || strings.HasPrefix(clientID, entity.UserIDPrefix) {
+		return apperrors.New(apperrors.InvalidInput, "无效的匿名标识")
+	}
+
+	if err := h.authService.ClaimClient(ctx, middleware.GetUserID(c), clientID); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to claim client data")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.ClaimClientResponse{
+		ClientID: clientID,
+	}))
+}
+
+// ListTokens handles GET /api/auth/tokens
+func (h *AuthHandler) ListTokens(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if !middleware.IsAuthenticated(c) {
+		return apperrors.ErrLoginRequired
+	}
+
+	tokens, err := h.authService.ListTokens(ctx, middleware.GetUserID(c))
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to list tokens")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(tokens))
+}
+
+// CreateToken handles POST /api/auth/tokens
+// The token secret is only returned in this response.
+func (h *AuthHandler) CreateToken(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if !middleware.IsAuthenticated(c) {
+		return apperrors.ErrLoginRequired
+	}
+
+	var params dto.CreateTokenRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		return apperrors.New(apperrors.InvalidInput, "令牌名称不能为空")
+	}
+
+	secret, token, err := h.authService.CreateAPIToken(ctx, middleware.GetUserID(c), params.Name)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to create token")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.CreateTokenResponse{
+		Token:  secret,
+		Detail: token,
+	}))
+}
+
+// RevokeToken handles DELETE /api/auth/tokens/:id
+func (h *AuthHandler) RevokeToken(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if !middleware.IsAuthenticated(c) {
+		return apperrors.ErrLoginRequired
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的令牌 ID")
+	}
+
+	revoked, err := h.authService.RevokeToken(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to revoke token")
+	}
+	if !revoked {
+		return apperrors.New(apperrors.NotFound, "令牌不存在")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// SetRole handles PUT /api/admin/users/:username/role
+func (h *AuthHandler) SetRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var params dto.SetRoleRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+	if !entity.IsValidRole(params.Role) {
+		return apperrors.New(apperrors.InvalidInput, "无效的角色")
+	}
+
+	username := c.Param("username")
+	if user := middleware.GetUser(c); user != nil && user.Username == username && params.Role != entity.RoleAdmin {
+		return apperrors.New(apperrors.InvalidInput, "不能取消自己的管理员权限")
+	}
+
+	if err := h.authService.SetRole(ctx, username, params.Role); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to set role")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.RoleDTO{
+		Username: username,
+		Role:     params.Role,
+	}))
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubClientClaimRepo struct {
+	claims map[string]string
+}
+
+func (s *stubClientClaimRepo) IsClaimed(ctx context.Context, clientID string) (bool, error) {
+	_, ok := s.claims[clientID]
+	return ok, nil
+}
+
+func (s *stubClientClaimRepo) Claim(ctx context.Context, clientID, userID string) (bool, error) {
+	if _, ok := s.claims[clientID]; ok {
+		return false, nil
+	}
+	s.claims[clientID] = userID
+	return true, nil
+}
+
+func TestAuthHandler_ClaimClientOnlyClaimsTheRequestingClient(t *testing.T) {
+	claims := &stubClientClaimRepo{claims: make(map[string]string)}
+	h := NewAuthHandler(service.NewAuthService(nil, nil, claims, time.Hour, nil))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/claim", strings.NewReader(`{"clientId":"victim-client"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middleware.UserIDHeader, "own-client")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(middleware.UserIDContextKey, "user_alice")
+	c.Set(middleware.AuthenticatedContextKey, true)
+
+	if err := h.ClaimClient(c); err != nil {
+		t.Fatalf("ClaimClient() error = %v", err)
+	}
+	if claims.claims["own-client"] != "user_alice" {
+		t.Errorf("expected the X-User-ID of the request to be claimed, got %v", claims.claims)
+	}
+	if _, ok := claims.claims["victim-client"]; ok {
+		t.Error("a client ID named in the body must not be claimed")
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DeviceHandler handles device pairing requests
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler(deviceService *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+	}
+}
+
+// CreatePairingCode handles POST /api/devices/pairing-code
+// Returns a six-digit code that another device can enter to share this user.
+// Accounts cannot be shared this way, their other devices log in instead.
+func (h *DeviceHandler) CreatePairingCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if middleware.IsAuthenticated(c) {
+		return apperrors.New(apperrors.InvalidInput, "账号无需配对，请在其他设备上登录同一账号")
+	}
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	code, err := h.deviceService.CreatePairingCode(ctx, userID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to create pairing code")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(code))
+}
+
+// Pair handles POST /api/devices/pair
+// Binds the anonymous client ID of the request to the user who created the code.
+func (h *DeviceHandler) Pair(c echo.Context) error {
+	ctx := c.Request().Context()
+	clientID := middleware.GetClientID(c)
+
+	if middleware.IsAuthenticated(c) {
+		return apperrors.New(apperrors.InvalidInput, "已登录账号的设备请直接登录同一账号")
+	}
+	if clientID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	var params dto.PairDeviceRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	name := params.Name
+	if name == "" {
+		name = c.Request().UserAgent()
+	}
+
+	device, err := h.deviceService.Pair(ctx, clientID, params.Code, name)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to pair device")
+	}
+
+	device.Current = true
+	return c.JSON(http.StatusOK, dto.Success(device))
+}
+
+// ListDevices handles GET /api/devices
+func (h *DeviceHandler) ListDevices(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	devices, err := h.deviceService.ListDevices(ctx, userID, middleware.GetClientID(c))
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to list devices")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(devices))
+}
+
+// RevokeDevice handles DELETE /api/devices/:id
+func (h *DeviceHandler) RevokeDevice(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的设备 ID")
+	}
+
+	revoked, err := h.deviceService.RevokeDevice(ctx, userID, id)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to revoke device")
+	}
+	if !revoked {
+		return apperrors.New(apperrors.NotFound, "设备不存在")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
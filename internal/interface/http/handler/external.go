@@ -7,6 +7,7 @@ import (
 	"kbfood/internal/domain/entity"
 	"kbfood/internal/domain/service"
 	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
@@ -40,6 +41,12 @@ type DTPlatformItem struct {
 	Region    string  `json:"region"`
 }
 
+// DTPlatformPushResponse reports how many pushed items were received and promoted
+type DTPlatformPushResponse struct {
+	Received int `json:"received"`
+	Promoted int `json:"promoted"`
+}
+
 // HandleDTPush handles POST /api/external/dt/push
 func (h *ExternalHandler) HandleDTPush(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -47,37 +54,40 @@ func (h *ExternalHandler) HandleDTPush(c echo.Context) error {
 	var req DTPlatformPushRequest
 	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
 		log.Error().Err(err).Msg("Failed to decode DT push request")
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid request body"))
+		return apperrors.ErrInvalidRequest
 	}
 
 	if len(req.Items) == 0 {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "items array is required and must not be empty"))
+		return apperrors.New(apperrors.InvalidInput, "items 不能为空")
 	}
 
 	if len(req.Items) > 1000 {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "too many items in request (max 1000)"))
+		return apperrors.New(apperrors.InvalidInput, "items 最多 1000 条")
 	}
 
-	promotedCount := 0
-	for _, item := range req.Items {
-		input := &entity.DTInputDTO{
+	inputs := make([]*entity.DTInputDTO, len(req.Items))
+	for i, item := range req.Items {
+		inputs[i] = &entity.DTInputDTO{
 			Title:     item.Title,
 			Price:     item.Price,
 			Status:    item.Status,
 			CrawlTime: item.CrawlTime,
 			Region:    item.Region,
 		}
+	}
 
-		promoted, err := h.cleaningService.ProcessIncomingItem(ctx, input, item.Region)
-		if err != nil {
-			log.Error().Err(err).
+	promotedCount := 0
+	promoted, errs := h.cleaningService.ProcessIncomingItems(ctx, inputs)
+	for i, item := range req.Items {
+		if errs[i] != nil {
+			log.Error().Err(errs[i]).
 				Str("title", item.Title).
 				Str("region", item.Region).
 				Msg("Failed to process DT item")
 			continue
 		}
 
-		if promoted != nil {
+		if promoted[i] != nil {
 			promotedCount++
 		}
 	}
@@ -87,8 +97,8 @@ func (h *ExternalHandler) HandleDTPush(c echo.Context) error {
 		Int("promoted", promotedCount).
 		Msg("DT push processed")
 
-	return c.JSON(http.StatusOK, dto.Success(map[string]interface{}{
-		"received": len(req.Items),
-		"promoted": promotedCount,
+	return c.JSON(http.StatusOK, dto.Success(DTPlatformPushResponse{
+		Received: len(req.Items),
+		Promoted: promotedCount,
 	}))
 }
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// atomContentType is the media type of Atom feeds
+const atomContentType = "application/atom+xml; charset=utf-8"
+
+// feedMaxAge is how long feed readers and proxies may cache a feed before revalidating
+const feedMaxAge = 5 * time.Minute
+
+// feedFilterParams are the query parameters that filter feed entries, in the
+// order they appear in feed IDs
+var feedFilterParams = []string{"region", "platform", "keyword"}
+
+// FeedHandler serves Atom feeds of price drops and new products, and manages
+// the private feed tokens of users
+type FeedHandler struct {
+	feedService *service.FeedService
+	publicURL   string
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(feedService *service.FeedService, publicURL string) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		publicURL:   strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Drops handles GET /feeds/drops.atom
+func (h *FeedHandler) Drops(c echo.Context) error {
+	changes, err := h.feedService.Drops(c.Request().Context(), feedQuery(c))
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to load price drops")
+	}
+	return h.serveFeed(c, "KbFood 降价", changes, true)
+}
+
+// NewArrivals handles GET /feeds/new.atom
+func (h *FeedHandler) NewArrivals(c echo.Context) error {
+	changes, err := h.feedService.NewArrivals(c.Request().Context(), feedQuery(c))
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to load new products")
+	}
+	return h.serveFeed(c, "KbFood 新品", changes, true)
+}
+
+// WatchedDrops handles GET /feeds/user/:token/drops.atom
+func (h *FeedHandler) WatchedDrops(c echo.Context) error {
+	changes, err := h.feedService.WatchedDrops(c.Request().Context(), c.Param("token"), feedQuery(c))
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to load price drops")
+	}
+	return h.serveFeed(c, "KbFood 我的关注降价", changes, false)
+}
+
+// GetFeedToken handles GET /api/user/feed
+// Returns the private feed token of the user, creating it on first use.
+func (h *FeedHandler) GetFeedToken(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	token, err := h.feedService.Token(c.Request().Context(), userID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to get feed token")
+	}
+	return c.JSON(http.StatusOK, dto.Success(token))
+}
+
+// RotateFeedToken handles POST /api/user/feed
+// Issues a new private feed token; the earlier feed URL stops working.
+func (h *FeedHandler) RotateFeedToken(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	token, err := h.feedService.RotateToken(c.Request().Context(), userID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to rotate feed token")
+	}
+	return c.JSON(http.StatusOK, dto.Success(token))
+}
+
+// serveFeed renders the changes as an Atom feed. The ETag and Last-Modified
+// headers let feed readers revalidate with conditional requests.
+func (h *FeedHandler) serveFeed(c echo.Context, title string, changes []*entity.ProductChange, public bool) error {
+	base := h.baseURL(c)
+	self := base + c.Request().URL.Path
+	if query := canonicalFeedQuery(c); query != "" {
+		self += "?" + query
+	}
+
+	// Entries are newest first; an empty feed is dated at the epoch so that it stays cacheable
+	updated := time.Unix(0, 0).UTC()
+	if len(changes) > 0 {
+		updated = changes[0].CreateTime.UTC()
+	}
+
+	feed := atomFeed{
+		ID:      self,
+		Title:   title,
+		Updated: updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: self},
+			{Rel: "alternate", Type: "text/html", Href: base + "/"},
+		},
+		Author:  atomPerson{Name: "KbFood"},
+		Entries: make([]atomEntry, 0, len(changes)),
+	}
+	for _, change := range changes {
+		feed.Entries = append(feed.Entries, feedEntry(base, change))
+	}
+
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	if err := xml.NewEncoder(&body).Encode(feed); err != nil {
+		return apperrors.Wrap(apperrors.Unknown, "Failed to render feed", err)
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	cacheControl := "private"
+	if public {
+		cacheControl = "public"
+	}
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, atomContentType)
+	header.Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	header.Set(echo.HeaderCacheControl, fmt.Sprintf("%s, max-age=%d", cacheControl, int(feedMaxAge.Seconds())))
+
+	// ServeContent answers If-None-Match and If-Modified-Since with 304 Not Modified
+	var modified time.Time
+	if len(changes) > 0 {
+		modified = updated
+	}
+	http.ServeContent(c.Response(), c.Request(), "", modified, bytes.NewReader(body.Bytes()))
+	return nil
+}
+
+// baseURL returns the public URL, or the URL the request was sent to if none is configured
+func (h *FeedHandler) baseURL(c echo.Context) string {
+	if h.publicURL != "" {
+		return h.publicURL
+	}
+	return c.Scheme() + "://" + c.Request().Host
+}
+
+// feedQuery returns the entry filters of the request
+func feedQuery(c echo.Context) repository.ProductChangeQuery {
+	return repository.ProductChangeQuery{
+		Region:   strings.TrimSpace(c.QueryParam("region")),
+		Platform: strings.TrimSpace(c.QueryParam("platform")),
+		Keyword:  strings.TrimSpace(c.QueryParam("keyword")),
+		Limit:    repository.MaxFeedEntries,
+	}
+}
+
+// canonicalFeedQuery returns the filter parameters of the request in a fixed
+// order, so that the feed ID does not depend on how the URL was written
+func canonicalFeedQuery(c echo.Context) string {
+	var parts []string
+	for _, name := range feedFilterParams {
+		if value := strings.TrimSpace(c.QueryParam(name)); value != "" {
+			parts = append(parts, name+"="+url.QueryEscape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// feedEntry converts a recorded change to an Atom entry. The entry ID is
+// derived from the change ID only, so it never changes.
+func feedEntry(base string, change *entity.ProductChange) atomEntry {
+	title := fmt.Sprintf("【%s %s ¥%.2f】%s", change.Platform, change.Region, change.Price, change.Title)
+	summary := fmt.Sprintf("%s %s 新上架，价格 ¥%.2f", change.Platform, change.Region, change.Price)
+	if change.OldPrice != nil {
+		oldPrice := *change.OldPrice
+		summary = fmt.Sprintf("%s %s 价格从 ¥%.2f 变为 ¥%.2f", change.Platform, change.Region, oldPrice, change.Price)
+		if change.IsDrop() && oldPrice > 0 {
+			title += fmt.Sprintf("（降价 %.0f%%）", (1-change.Price/oldPrice)*100)
+		}
+	}
+
+	link := url.Values{}
+	link.Set("keyword", change.Title)
+	link.Set("activityId", change.ActivityID)
+
+	updated := change.CreateTime.UTC().Format(time.RFC3339)
+	entry := atomEntry{
+		ID:        "urn:kbfood:product-change:" + strconv.FormatInt(change.ID, 10),
+		Title:     title,
+		Updated:   updated,
+		Published: updated,
+		Link:      atomLink{Rel: "alternate", Type: "text/html", Href: base + "/?" + link.Encode()},
+		Summary:   summary,
+	}
+	for _, term := range []string{change.Region, change.Platform} {
+		if term != "" {
+			entry.Categories = append(entry.Categories, atomCategory{Term: term})
+		}
+	}
+	return entry
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Link       atomLink       `xml:"link"`
+	Summary    string         `xml:"summary"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
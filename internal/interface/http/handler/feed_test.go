@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubProductChangeRepo struct {
+	repository.ProductChangeRepository
+	changes []*entity.ProductChange
+	query   repository.ProductChangeQuery
+}
+
+func (s *stubProductChangeRepo) List(ctx context.Context, query repository.ProductChangeQuery) ([]*entity.ProductChange, error) {
+	s.query = query
+	return s.changes, nil
+}
+
+func TestFeedHandler_Drops(t *testing.T) {
+	oldPrice := 40.0
+	changes := &stubProductChangeRepo{changes: []*entity.ProductChange{
+		{ID: 7, Kind: entity.ProductChangePrice, ActivityID: "DT_1", Region: "广州", Platform: "探探糖", Title: "双人套餐",
+			Price: 30, OldPrice: &oldPrice, CreateTime: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}}
+	h := NewFeedHandler(service.NewFeedService(changes, nil, nil, nil, ""), "https://kbfood.example.com/")
+
+	e := echo.New()
+	serve := func(header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/feeds/drops.atom?keyword=套餐&region=广州", nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		if err := h.Drops(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Drops() error = %v", err)
+		}
+		return rec
+	}
+
+	rec := serve(nil)
+	if rec.Code != http.StatusOK || rec.Header().Get(echo.HeaderContentType) != atomContentType {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	if changes.query.Region != "广州" || changes.query.Keyword != "套餐" || !changes.query.DropsOnly {
+		t.Errorf("query = %+v", changes.query)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("parse feed: %v\n%s", err, rec.Body.String())
+	}
+	if feed.ID != "https://kbfood.example.com/feeds/drops.atom?region=%E5%B9%BF%E5%B7%9E&keyword=%E5%A5%97%E9%A4%90" {
+		t.Errorf("feed ID = %s", feed.ID)
+	}
+	if feed.Updated != "2026-03-01T12:00:00Z" || len(feed.Entries) != 1 {
+		t.Fatalf("feed = %+v", feed)
+	}
+	entry := feed.Entries[0]
+	if entry.ID != "urn:kbfood:product-change:7" || entry.Title != "【探探糖 广州 ¥30.00】双人套餐（降价 25%）" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Link.Href != "https://kbfood.example.com/?activityId=DT_1&keyword=%E5%8F%8C%E4%BA%BA%E5%A5%97%E9%A4%90" {
+		t.Errorf("entry link = %s", entry.Link.Href)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Last-Modified") != "Sun, 01 Mar 2026 12:00:00 GMT" {
+		t.Fatalf("ETag = %q, Last-Modified = %q", etag, rec.Header().Get("Last-Modified"))
+	}
+	if rec := serve(http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("If-None-Match status = %d, body %d bytes", rec.Code, rec.Body.Len())
+	}
+	if rec := serve(http.Header{"If-Modified-Since": {"Sun, 01 Mar 2026 12:00:00 GMT"}}); rec.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since status = %d", rec.Code)
+	}
+	if rec := serve(http.Header{"If-None-Match": {`"stale"`}}); rec.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match status = %d", rec.Code)
+	}
+}
+
+func TestFeedHandler_WatchedDropsUnknownToken(t *testing.T) {
+	tokens := &stubFeedTokenRepo{}
+	h := NewFeedHandler(service.NewFeedService(&stubProductChangeRepo{}, tokens, nil, nil, ""), "")
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/feeds/user/unknown/drops.atom", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("token")
+	c.SetParamValues("unknown")
+	err := h.WatchedDrops(c)
+	if !apperrors.IsNotFound(err) {
+		t.Errorf("WatchedDrops() error = %v, want a not found error", err)
+	}
+}
+
+type stubFeedTokenRepo struct {
+	repository.FeedTokenRepository
+}
+
+func (s *stubFeedTokenRepo) FindByToken(ctx context.Context, token string) (*entity.FeedToken, error) {
+	return nil, nil
+}
@@ -1,25 +1,37 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"kbfood/internal/domain/entity"
 	"kbfood/internal/domain/repository"
 	"kbfood/internal/interface/http/dto"
 	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
 
 	"github.com/labstack/echo/v4"
 )
 
 // ProductHandler handles product-related requests
 type ProductHandler struct {
-	prodRepo    repository.ProductRepository
-	masterRepo  repository.MasterProductRepository
-	notiRepo    repository.NotificationRepository
-	blockedRepo repository.BlockedRepository
-	trendRepo   repository.TrendRepository
+	prodRepo     repository.ProductRepository
+	masterRepo   repository.MasterProductRepository
+	notiRepo     repository.NotificationRepository
+	blockedRepo  repository.BlockedRepository
+	trendRepo    repository.TrendRepository
+	settingsRepo repository.UserSettingsRepository
+	annoRepo     repository.ProductAnnotationRepository
+	listingRepo  repository.ProductListingRepository
+	searchRepo   repository.ProductSearchRepository
+	voteRepo     repository.TitleVoteRepository
 }
 
 // NewProductHandler creates a new product handler
@@ -29,130 +41,186 @@ func NewProductHandler(
 	notiRepo repository.NotificationRepository,
 	blockedRepo repository.BlockedRepository,
 	trendRepo repository.TrendRepository,
+	settingsRepo repository.UserSettingsRepository,
+	annoRepo repository.ProductAnnotationRepository,
+	listingRepo repository.ProductListingRepository,
+	searchRepo repository.ProductSearchRepository,
+	voteRepo repository.TitleVoteRepository,
 ) *ProductHandler {
 	return &ProductHandler{
-		prodRepo:    prodRepo,
-		masterRepo:  masterRepo,
-		notiRepo:    notiRepo,
-		blockedRepo: blockedRepo,
-		trendRepo:   trendRepo,
+		prodRepo:     prodRepo,
+		masterRepo:   masterRepo,
+		notiRepo:     notiRepo,
+		blockedRepo:  blockedRepo,
+		trendRepo:    trendRepo,
+		settingsRepo: settingsRepo,
+		annoRepo:     annoRepo,
+		listingRepo:  listingRepo,
+		searchRepo:   searchRepo,
+		voteRepo:     voteRepo,
 	}
 }
 
 // QueryProducts handles GET /api/products
+// Filters, sorting and cursor pagination run in SQL. Without limit every match is returned.
 func (h *ProductHandler) QueryProducts(c echo.Context) error {
 	ctx := c.Request().Context()
 	userID := middleware.GetUserID(c)
 
-	// Parse query parameters
-	region := c.QueryParam("region")
-	platform := c.QueryParam("platform")
-	keyword := c.QueryParam("keyword")
-	salesStatusStr := c.QueryParam("salesStatus")
-	monitorStatus := c.QueryParam("monitorStatus")
+	query, err := parseListingQuery(c)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+	query.UserID = userID
 
-	var salesStatus *int
-	if salesStatusStr != "" {
-		val := 0
-		if salesStatusStr == "1" {
-			val = 1
-		}
-		salesStatus = &val
+	page, err := h.listingRepo.List(ctx, query)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch products", err)
 	}
 
-	// Get blocked products list for user
-	var blockedSet map[string]bool
-	if userID != "" {
-		blockedIDs, _ := h.blockedRepo.List(ctx, userID)
-		blockedSet = make(map[string]bool)
-		for _, id := range blockedIDs {
-			blockedSet[id] = true
-		}
-	} else {
-		blockedSet = make(map[string]bool)
+	notificationMap, annotationMap, err := h.loadUserProductState(ctx, userID, len(page.Products))
+	if err != nil {
+		return err
 	}
+	terms := entity.ParseSearchTerms(query.Keyword)
 
-	// Get notification configs for user
-	var notificationMap map[string]*entity.NotificationConfig
-	if userID != "" {
-		notis, _ := h.notiRepo.ListByUser(ctx, userID)
-		notificationMap = make(map[string]*entity.NotificationConfig)
-		for _, n := range notis {
-			notificationMap[n.ActivityID] = n
-		}
-	} else {
-		notificationMap = make(map[string]*entity.NotificationConfig)
+	// Convert to DTOs with notification info
+	result := make([]dto.ProductDTO, 0, len(page.Products))
+	for _, p := range page.Products {
+		productDTO := dto.FromMasterEntity(p.MasterProduct)
+		productDTO.ApplyReferencePrice(p.OriginalPrice)
+		productDTO.ApplyNotification(notificationMap[p.ID])
+		productDTO.ApplyAnnotation(annotationMap[p.ID])
+		productDTO.ApplyHighlight(terms)
+		result = append(result, productDTO)
 	}
 
-	// Fetch master products with platform and region filters
-	var masterProducts []*entity.MasterProduct
-	var err error
+	return c.JSON(http.StatusOK, dto.SuccessWithMeta(result, dto.Meta{
+		Total:      page.Total,
+		Limit:      query.Limit,
+		NextCursor: dto.EncodeListingCursor(page.NextCursor),
+	}))
+}
 
-	if region != "" && platform != "" {
-		masterProducts, err = h.masterRepo.FindByRegionAndPlatform(ctx, region, platform)
-	} else if region != "" {
-		masterProducts, err = h.masterRepo.FindByRegion(ctx, region)
-	} else if platform != "" {
-		masterProducts, err = h.masterRepo.FindByPlatform(ctx, platform)
-	} else {
-		masterProducts, err = h.masterRepo.ListAll(ctx)
+// loadUserProductState loads the user's notification configs and annotations
+// keyed by activity ID, skipping the lookups when there are no products to decorate
+func (h *ProductHandler) loadUserProductState(ctx context.Context, userID string, products int) (map[string]*entity.NotificationConfig, map[string]*entity.ProductAnnotation, error) {
+	notificationMap := make(map[string]*entity.NotificationConfig)
+	annotationMap := make(map[string]*entity.ProductAnnotation)
+	if userID == "" || products == 0 {
+		return notificationMap, annotationMap, nil
 	}
 
+	// Get notification configs for user
+	notis, err := h.notiRepo.ListByUser(ctx, userID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch products"))
+		return nil, nil, apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch notifications", err)
+	}
+	for _, n := range notis {
+		notificationMap[n.ActivityID] = n
 	}
 
-	// Apply filters
-	filtered := make([]*entity.MasterProduct, 0)
-	for _, p := range masterProducts {
-		// Skip blocked products
-		if blockedSet[p.ID] {
-			continue
-		}
+	// Get favorites, tags and notes for user
+	annotations, err := h.annoRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch annotations", err)
+	}
+	for _, a := range annotations {
+		annotationMap[a.ActivityID] = a
+	}
+	return notificationMap, annotationMap, nil
+}
 
-		// Filter by keyword
-		if keyword != "" && !containsIgnoreCase(p.StandardTitle, keyword) {
-			continue
-		}
+// parseListingQuery reads the filter, sort and page parameters of a product listing
+func parseListingQuery(c echo.Context) (repository.ProductListingQuery, error) {
+	query := repository.ProductListingQuery{
+		Region:       c.QueryParam("region"),
+		Platform:     c.QueryParam("platform"),
+		Keyword:      c.QueryParam("keyword"),
+		FavoriteOnly: c.QueryParam("favorite") == "1",
+		Tag:          strings.TrimSpace(c.QueryParam("tag")),
+		Sort:         repository.ListingSortUpdateTime,
+	}
+	if len(entity.ParseSearchTerms(query.Keyword)) > 0 {
+		// Keyword searches list the best matches first unless asked otherwise
+		query.Sort = repository.ListingSortRelevance
+	}
 
-		// Filter by sales status
-		if salesStatus != nil && p.Status != *salesStatus {
-			continue
+	if salesStatusStr := c.QueryParam("salesStatus"); salesStatusStr != "" {
+		val := 0
+		if salesStatusStr == "1" {
+			val = 1
 		}
+		query.SalesStatus = &val
+	}
 
-		// Filter by monitor status
-		_, hasNotification := notificationMap[p.ID]
-		if monitorStatus == "1" && !hasNotification {
-			continue
-		}
-		if monitorStatus == "0" && hasNotification {
-			continue
-		}
+	switch c.QueryParam("monitorStatus") {
+	case "1":
+		monitored := 1
+		query.MonitorStatus = &monitored
+	case "0":
+		unmonitored := 0
+		query.MonitorStatus = &unmonitored
+	}
 
-		filtered = append(filtered, p)
+	var err error
+	if query.MinPrice, err = parsePriceParam(c.QueryParam("minPrice")); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = parsePriceParam(c.QueryParam("maxPrice")); err != nil {
+		return query, err
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return query, errors.New("最低价不能高于最高价")
 	}
 
-	// Convert to DTOs with notification info
-	result := make([]dto.ProductDTO, 0, len(filtered))
-	for _, p := range filtered {
-		productDTO := dto.FromMasterEntity(p)
-		if noti, exists := notificationMap[p.ID]; exists {
-			productDTO.HasNotification = true
-			productDTO.TargetPrice = &noti.TargetPrice
+	if sort := c.QueryParam("sort"); sort != "" {
+		if !repository.IsValidListingSort(sort) {
+			return query, errors.New("不支持的排序方式")
+		}
+		query.Sort = sort
+	}
+	switch c.QueryParam("order") {
+	case "":
+		// Cheapest first for price; newest, biggest discount or drop, or best match first otherwise
+		query.Descending = query.Sort != repository.ListingSortPrice
+	case "asc":
+		query.Descending = false
+	case "desc":
+		query.Descending = true
+	default:
+		return query, errors.New("排序方向只能是 asc 或 desc")
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > repository.MaxListingLimit {
+			return query, fmt.Errorf("limit 必须在 1 到 %d 之间", repository.MaxListingLimit)
+		}
+		query.Limit = limit
+	}
+	if cursor := c.QueryParam("cursor"); cursor != "" {
+		if query.Limit == 0 {
+			return query, errors.New("使用游标分页时必须指定 limit")
+		}
+		if query.Cursor, err = dto.DecodeListingCursor(cursor, query.Sort); err != nil {
+			return query, err
 		}
-		result = append(result, productDTO)
 	}
 
-	return c.JSON(http.StatusOK, dto.Success(result))
+	return query, nil
 }
 
-// containsIgnoreCase checks if s contains substr (case-insensitive)
-// Uses strings.ToLower which properly handles UTF-8 multi-byte characters
-func containsIgnoreCase(s, substr string) bool {
-	if len(substr) == 0 {
-		return true
+// parsePriceParam parses an optional non-negative price query parameter
+func parsePriceParam(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
 	}
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+	price, err := strconv.ParseFloat(s, 64)
+	if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return nil, errors.New("价格区间无效")
+	}
+	return &price, nil
 }
 
 // GetPriceTrend handles GET /api/products/:activityId/trend
@@ -161,12 +229,12 @@ func (h *ProductHandler) GetPriceTrend(c echo.Context) error {
 	activityID := c.Param("activityId")
 
 	if activityID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "activityId is required"))
+		return apperrors.ErrMissingActivityID
 	}
 
 	trends, err := h.trendRepo.FindByActivityID(ctx, activityID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch price trends"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch price trends", err)
 	}
 
 	result := dto.FromTrendEntities(trends)
@@ -180,14 +248,14 @@ func (h *ProductHandler) BlockProduct(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if activityID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "activityId is required"))
+		return apperrors.ErrMissingActivityID
 	}
 	if userID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "用户标识缺失，请刷新页面后重试"))
+		return apperrors.ErrMissingUserID
 	}
 
 	if err := h.blockedRepo.Create(ctx, activityID, userID); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to block product"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to block product", err)
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(nil))
@@ -200,14 +268,14 @@ func (h *ProductHandler) UnblockProduct(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if activityID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "activityId is required"))
+		return apperrors.ErrMissingActivityID
 	}
 	if userID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "用户标识缺失，请刷新页面后重试"))
+		return apperrors.ErrMissingUserID
 	}
 
 	if err := h.blockedRepo.Delete(ctx, activityID, userID); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to unblock product"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to unblock product", err)
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(nil))
@@ -225,7 +293,7 @@ func (h *ProductHandler) GetBlockedProducts(c echo.Context) error {
 	// Get blocked activity IDs for user
 	blockedIDs, err := h.blockedRepo.List(ctx, userID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch blocked products"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch blocked products", err)
 	}
 
 	if len(blockedIDs) == 0 {
@@ -236,7 +304,10 @@ func (h *ProductHandler) GetBlockedProducts(c echo.Context) error {
 	result := make([]dto.ProductDTO, 0)
 	for _, activityID := range blockedIDs {
 		master, err := h.masterRepo.FindByID(ctx, activityID)
-		if err == nil && master != nil {
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch blocked products", err)
+		}
+		if master != nil {
 			result = append(result, dto.FromMasterEntity(master))
 		}
 	}
@@ -250,7 +321,7 @@ func (h *ProductHandler) ClearPlatform(c echo.Context) error {
 	platform := c.Param("platform")
 
 	if err := h.prodRepo.DeleteByPlatform(ctx, platform); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to clear platform data"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to clear platform data", err)
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(nil))
@@ -262,33 +333,37 @@ func (h *ProductHandler) CreateNotification(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if userID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "用户标识缺失，请刷新页面后重试"))
+		return apperrors.ErrMissingUserID
 	}
 
-	var params struct {
-		ActivityID  string  `json:"activityId"`
-		TargetPrice float64 `json:"targetPrice"`
-	}
+	var params dto.NotificationRuleRequest
 
 	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid request body"))
+		return apperrors.ErrInvalidRequest
 	}
 
 	if params.ActivityID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "activityId is required"))
-	}
-	if params.TargetPrice <= 0 {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "targetPrice must be positive"))
+		return apperrors.ErrMissingActivityID
 	}
 
 	config := &entity.NotificationConfig{
 		ActivityID:  params.ActivityID,
 		UserID:      userID,
+		RuleType:    params.RuleType,
 		TargetPrice: params.TargetPrice,
+		DropPercent: params.DropPercent,
+		DropAmount:  params.DropAmount,
 	}
+	config.RuleType = config.EffectiveRuleType()
+
+	if err := config.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	seedRuleState(ctx, h.masterRepo, h.prodRepo, config)
 
 	if err := h.notiRepo.Upsert(ctx, config); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to create notification"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to create notification", err)
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(nil))
@@ -301,40 +376,122 @@ func (h *ProductHandler) UpdateNotification(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if activityID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "activityId is required"))
+		return apperrors.ErrMissingActivityID
 	}
 	if userID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "用户标识缺失，请刷新页面后重试"))
+		return apperrors.ErrMissingUserID
 	}
 
-	var params struct {
-		TargetPrice float64 `json:"targetPrice"`
-	}
+	var params dto.UpdateNotificationRequest
 
 	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid request body"))
-	}
-
-	if params.TargetPrice <= 0 {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "targetPrice must be positive"))
+		return apperrors.ErrInvalidRequest
 	}
 
 	config, err := h.notiRepo.FindByActivityID(ctx, activityID, userID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, dto.Error(404, "Notification not found"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch notification", err)
 	}
 	if config == nil {
-		return c.JSON(http.StatusNotFound, dto.Error(404, "Notification not found"))
+		return apperrors.New(apperrors.NotFound, "提醒不存在")
+	}
+
+	ruleChanged := false
+	if params.RuleType != nil && *params.RuleType != config.EffectiveRuleType() {
+		config.RuleType = *params.RuleType
+		ruleChanged = true
+	}
+	if params.TargetPrice != nil {
+		config.TargetPrice = *params.TargetPrice
+	}
+	if params.DropPercent != nil {
+		config.DropPercent = *params.DropPercent
+	}
+	if params.DropAmount != nil {
+		config.DropAmount = *params.DropAmount
+	}
+	if params.SnoozeUntil != nil {
+		snoozeUntil, err := h.parseSnoozeUntil(ctx, userID, *params.SnoozeUntil)
+		if err != nil {
+			return apperrors.New(apperrors.InvalidInput, "snoozeUntil 格式无效，应为 YYYY-MM-DD 或 RFC3339")
+		}
+		config.SnoozeUntil = snoozeUntil
+	}
+	config.RuleType = config.EffectiveRuleType()
+
+	if err := config.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	if ruleChanged {
+		seedRuleState(ctx, h.masterRepo, h.prodRepo, config)
 	}
 
-	config.TargetPrice = params.TargetPrice
 	if err := h.notiRepo.Upsert(ctx, config); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to update notification"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to update notification", err)
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(nil))
 }
 
+// parseSnoozeUntil parses a snooze deadline. A date means the watch resumes at
+// midnight of that day in the user's timezone; an empty value clears the snooze.
+func (h *ProductHandler) parseSnoozeUntil(ctx context.Context, userID, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	loc := time.Local
+	if h.settingsRepo != nil {
+		if settings, err := h.settingsRepo.Get(ctx, userID); err == nil && settings != nil {
+			loc = settings.Preferences.Location()
+		}
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// seedRuleState records the product's current price and status as the
+// starting point for rules that compare against a previous observation
+func seedRuleState(
+	ctx context.Context,
+	masterRepo repository.MasterProductRepository,
+	prodRepo repository.ProductRepository,
+	config *entity.NotificationConfig,
+) {
+	config.ReferencePrice = nil
+	config.LastStatus = nil
+
+	var (
+		price  float64
+		status int
+		found  bool
+	)
+	if master, err := masterRepo.FindByID(ctx, config.ActivityID); err == nil && master != nil {
+		price, status, found = master.Price, master.Status, true
+	} else if product, err := prodRepo.FindByActivityID(ctx, config.ActivityID); err == nil && product != nil {
+		price, status, found = product.CurrentPrice, product.SalesStatus, true
+	}
+	if !found {
+		return
+	}
+
+	switch config.EffectiveRuleType() {
+	case entity.RuleTypeDropSinceLastAlert:
+		config.ReferencePrice = &price
+	case entity.RuleTypeBackInStock:
+		config.LastStatus = &status
+	}
+}
+
 // DeleteNotification handles DELETE /api/products/notifications/:activityId
 func (h *ProductHandler) DeleteNotification(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -342,14 +499,14 @@ func (h *ProductHandler) DeleteNotification(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if activityID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "activityId is required"))
+		return apperrors.ErrMissingActivityID
 	}
 	if userID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "用户标识缺失，请刷新页面后重试"))
+		return apperrors.ErrMissingUserID
 	}
 
 	if err := h.notiRepo.Delete(ctx, activityID, userID); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to delete notification"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to delete notification", err)
 	}
 
 	return c.JSON(http.StatusOK, dto.Success(nil))
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetAnnotation handles GET /api/products/:activityId/annotation
+func (h *ProductHandler) GetAnnotation(c echo.Context) error {
+	ctx := c.Request().Context()
+	activityID := c.Param("activityId")
+	userID := middleware.GetUserID(c)
+
+	if activityID == "" {
+		return apperrors.ErrMissingActivityID
+	}
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	annotation, err := h.findAnnotation(ctx, activityID, userID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to get annotation", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(annotation))
+}
+
+// UpdateAnnotation handles PUT /api/products/:activityId/annotation
+// Only the fields present in the body are changed; clearing every field removes the annotation.
+func (h *ProductHandler) UpdateAnnotation(c echo.Context) error {
+	ctx := c.Request().Context()
+	activityID := c.Param("activityId")
+	userID := middleware.GetUserID(c)
+
+	if activityID == "" {
+		return apperrors.ErrMissingActivityID
+	}
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	var params dto.UpdateAnnotationRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	annotation, err := h.findAnnotation(ctx, activityID, userID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to get annotation", err)
+	}
+
+	if params.Favorite != nil {
+		annotation.Favorite = *params.Favorite
+	}
+	if params.Tags != nil {
+		annotation.Tags = *params.Tags
+	}
+	if params.Note != nil {
+		annotation.Note = *params.Note
+	}
+	annotation.Normalize()
+
+	if err := annotation.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	if err := h.saveAnnotation(ctx, annotation); err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to save annotation", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(annotation))
+}
+
+// FavoriteProduct handles POST /api/products/:activityId/favorite
+func (h *ProductHandler) FavoriteProduct(c echo.Context) error {
+	return h.setFavorite(c, true)
+}
+
+// UnfavoriteProduct handles DELETE /api/products/:activityId/favorite
+func (h *ProductHandler) UnfavoriteProduct(c echo.Context) error {
+	return h.setFavorite(c, false)
+}
+
+// ListTags handles GET /api/products/tags
+// Returns the user's tags, most used first.
+func (h *ProductHandler) ListTags(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return c.JSON(http.StatusOK, dto.Success([]dto.TagDTO{}))
+	}
+
+	annotations, err := h.annoRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to list tags", err)
+	}
+
+	counts := make(map[string]int)
+	for _, a := range annotations {
+		for _, tag := range a.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]dto.TagDTO, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, dto.TagDTO{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return c.JSON(http.StatusOK, dto.Success(result))
+}
+
+// setFavorite stars or unstars a product, keeping its tags and note
+func (h *ProductHandler) setFavorite(c echo.Context, favorite bool) error {
+	ctx := c.Request().Context()
+	activityID := c.Param("activityId")
+	userID := middleware.GetUserID(c)
+
+	if activityID == "" {
+		return apperrors.ErrMissingActivityID
+	}
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	annotation, err := h.findAnnotation(ctx, activityID, userID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to get annotation", err)
+	}
+	annotation.Favorite = favorite
+
+	if err := h.saveAnnotation(ctx, annotation); err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to update favorite", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(annotation))
+}
+
+// findAnnotation returns the stored annotation of a product, or an empty one
+func (h *ProductHandler) findAnnotation(ctx context.Context, activityID, userID string) (*entity.ProductAnnotation, error) {
+	annotation, err := h.annoRepo.Find(ctx, activityID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if annotation == nil {
+		annotation = &entity.ProductAnnotation{ActivityID: activityID, UserID: userID, Tags: []string{}}
+	}
+	return annotation, nil
+}
+
+// saveAnnotation stores the annotation, or deletes it once it marks nothing
+func (h *ProductHandler) saveAnnotation(ctx context.Context, annotation *entity.ProductAnnotation) error {
+	if annotation.IsEmpty() {
+		return h.annoRepo.Delete(ctx, annotation.ActivityID, annotation.UserID)
+	}
+	return h.annoRepo.Upsert(ctx, annotation)
+}
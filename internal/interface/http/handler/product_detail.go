@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// relatedProductLimit is the number of related products on a detail page
+const relatedProductLimit = 6
+
+// GetProduct handles GET /api/products/:activityId
+// Looks the product up in the master catalog first, then among platform products.
+func (h *ProductHandler) GetProduct(c echo.Context) error {
+	ctx := c.Request().Context()
+	activityID := c.Param("activityId")
+	userID := middleware.GetUserID(c)
+
+	if activityID == "" {
+		return apperrors.ErrMissingActivityID
+	}
+
+	detail := dto.ProductDetailDTO{
+		TitleVotes: []dto.TitleVoteDTO{},
+		Related:    []dto.ProductDTO{},
+	}
+
+	master, err := h.masterRepo.FindByID(ctx, activityID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch product", err)
+	}
+
+	if master != nil {
+		detail.Source = repository.SearchSourceMaster
+		detail.Product = dto.FromMasterEntity(master)
+		detail.TrustScore = master.TrustScore
+		detail.LastSeenTime = master.UpdateTime
+
+		votes, err := h.voteRepo.ListByMaster(ctx, activityID)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch title votes", err)
+		}
+		detail.TitleVotes = dto.FromTitleVotes(votes)
+
+		// The stored original price, as in the product list
+		originalPrice, err := h.listingRepo.OriginalPrice(ctx, activityID)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch original price", err)
+		}
+		detail.Product.ApplyReferencePrice(originalPrice)
+
+		related, err := h.relatedMasters(ctx, userID, master)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch related products", err)
+		}
+		for _, p := range related {
+			relatedDTO := dto.FromMasterEntity(p.MasterProduct)
+			relatedDTO.ApplyReferencePrice(p.OriginalPrice)
+			detail.Related = append(detail.Related, relatedDTO)
+		}
+	} else {
+		product, err := h.prodRepo.FindByActivityID(ctx, activityID)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch product", err)
+		}
+		if product == nil {
+			return apperrors.ErrProductNotFound
+		}
+
+		detail.Source = repository.SearchSourceProduct
+		detail.Product = dto.FromEntity(product)
+		detail.LastSeenTime = product.UpdateTime
+
+		if product.ShopName != "" {
+			related, err := h.prodRepo.FindByShop(ctx, product.ShopName, activityID, relatedProductLimit)
+			if err != nil {
+				return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch related products", err)
+			}
+			related, err = h.withoutBlocked(ctx, userID, related)
+			if err != nil {
+				return err
+			}
+			detail.Related = dto.FromEntities(related)
+		}
+	}
+
+	trends, err := h.trendRepo.FindByActivityID(ctx, activityID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch price trends", err)
+	}
+	if summary := entity.SummarizeTrends(trends); summary != nil {
+		detail.TrendSummary = dto.FromTrendSummary(summary)
+	}
+
+	if userID != "" {
+		noti, err := h.notiRepo.FindByActivityID(ctx, activityID, userID)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch notification", err)
+		}
+		detail.Product.ApplyNotification(noti)
+
+		annotation, err := h.annoRepo.Find(ctx, activityID, userID)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch annotation", err)
+		}
+		detail.Product.ApplyAnnotation(annotation)
+
+		if detail.Blocked, err = h.blockedRepo.Exists(ctx, activityID, userID); err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch block state", err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(detail))
+}
+
+// relatedMasters lists the newest other masters sold by the shops of a master.
+// Shops are recorded as synced items match the master, so a master without
+// shops, or whose shops sell nothing else, falls back to the newest masters of
+// its region and platform.
+func (h *ProductHandler) relatedMasters(ctx context.Context, userID string, master *entity.MasterProduct) ([]*repository.ProductListing, error) {
+	query := repository.ProductListingQuery{
+		UserID:       userID,
+		SharedShopOf: master.ID,
+		Sort:         repository.ListingSortUpdateTime,
+		Descending:   true,
+		Limit:        relatedProductLimit,
+	}
+	related, err := h.listingRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(related.Products) > 0 {
+		return related.Products, nil
+	}
+
+	query.SharedShopOf = ""
+	query.Region = master.Region
+	query.Platform = master.Platform
+	query.Limit = relatedProductLimit + 1
+	related, err = h.listingRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*repository.ProductListing, 0, relatedProductLimit)
+	for _, p := range related.Products {
+		if p.ID != master.ID && len(result) < relatedProductLimit {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// withoutBlocked drops the products the user has blocked
+func (h *ProductHandler) withoutBlocked(ctx context.Context, userID string, products []*entity.Product) ([]*entity.Product, error) {
+	blocked, err := h.blockedSet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.Product, 0, len(products))
+	for _, p := range products {
+		if !blocked[p.ActivityID] {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// blockedSet returns the activity IDs the user has blocked, empty without a user
+func (h *ProductHandler) blockedSet(ctx context.Context, userID string) (map[string]bool, error) {
+	if userID == "" {
+		return map[string]bool{}, nil
+	}
+
+	blockedIDs, err := h.blockedRepo.List(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrDatabase, "Failed to fetch blocked products", err)
+	}
+	blocked := make(map[string]bool, len(blockedIDs))
+	for _, id := range blockedIDs {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubMasterProductRepo struct {
+	repository.MasterProductRepository
+	master *entity.MasterProduct
+}
+
+func (s *stubMasterProductRepo) FindByID(ctx context.Context, id string) (*entity.MasterProduct, error) {
+	return s.master, nil
+}
+
+type stubDetailListingRepo struct {
+	repository.ProductListingRepository
+	originalPrice float64
+}
+
+func (s *stubDetailListingRepo) List(ctx context.Context, query repository.ProductListingQuery) (*repository.ProductListingPage, error) {
+	return &repository.ProductListingPage{Products: []*repository.ProductListing{}}, nil
+}
+
+func (s *stubDetailListingRepo) OriginalPrice(ctx context.Context, masterID string) (float64, error) {
+	return s.originalPrice, nil
+}
+
+type stubDetailTrendRepo struct {
+	repository.TrendRepository
+	trends []*entity.PriceTrend
+}
+
+func (s *stubDetailTrendRepo) FindByActivityID(ctx context.Context, activityID string) ([]*entity.PriceTrend, error) {
+	return s.trends, nil
+}
+
+type stubDetailVoteRepo struct {
+	repository.TitleVoteRepository
+}
+
+func (s *stubDetailVoteRepo) ListByMaster(ctx context.Context, masterID string) ([]*entity.TitleVote, error) {
+	return nil, nil
+}
+
+type stubProductRepo struct {
+	repository.ProductRepository
+}
+
+func (s *stubProductRepo) FindByActivityID(ctx context.Context, activityID string) (*entity.Product, error) {
+	return nil, nil
+}
+
+func TestProductHandler_GetProductNotFound(t *testing.T) {
+	h := NewProductHandler(&stubProductRepo{}, &stubMasterProductRepo{}, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/products/DT_missing", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("activityId")
+	c.SetParamValues("DT_missing")
+
+	err := h.GetProduct(c)
+	if !errors.Is(err, apperrors.ErrProductNotFound) {
+		t.Fatalf("GetProduct() error = %v, want ErrProductNotFound", err)
+	}
+	if !apperrors.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestProductHandler_GetProductUsesStoredOriginalPrice(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	master := &entity.MasterProduct{ID: "DT_1", Region: "广州", StandardTitle: "双人套餐", Price: 60, UpdateTime: now}
+	// The stored original price is what the product list shows, not the trend summary
+	trends := &stubDetailTrendRepo{trends: []*entity.PriceTrend{
+		{ActivityID: "DT_1", Price: 100, RecordDate: now.AddDate(0, 0, -1)},
+		{ActivityID: "DT_1", Price: 60, RecordDate: now},
+	}}
+	h := NewProductHandler(&stubProductRepo{}, &stubMasterProductRepo{master: master}, nil, nil, trends, nil, nil,
+		&stubDetailListingRepo{originalPrice: 80}, nil, &stubDetailVoteRepo{})
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/products/DT_1", nil), rec)
+	c.SetParamNames("activityId")
+	c.SetParamValues("DT_1")
+	if err := h.GetProduct(c); err != nil {
+		t.Fatalf("GetProduct() error = %v", err)
+	}
+
+	var body struct {
+		Data dto.ProductDetailDTO `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if product := body.Data.Product; product.OriginalPrice != 80 || product.DropRate != 25 {
+		t.Fatalf("product = %+v, want the stored original price of 80", product)
+	}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SavedSearchHandler handles saved search subscription requests
+type SavedSearchHandler struct {
+	savedSearchService *service.SavedSearchService
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(savedSearchService *service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+	}
+}
+
+// ListSearches handles GET /api/user/searches
+func (h *SavedSearchHandler) ListSearches(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return c.JSON(http.StatusOK, dto.Success([]dto.SavedSearchDTO{}))
+	}
+
+	searches, err := h.savedSearchService.List(ctx, userID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to list saved searches")
+	}
+
+	result := make([]dto.SavedSearchDTO, len(searches))
+	for i, s := range searches {
+		result[i] = dto.FromSavedSearch(s)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(result))
+}
+
+// GetSearch handles GET /api/user/searches/:id
+func (h *SavedSearchHandler) GetSearch(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的订阅 ID")
+	}
+
+	search, err := h.savedSearchService.Get(ctx, userID, id)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to get saved search")
+	}
+	if search == nil {
+		return apperrors.New(apperrors.NotFound, "订阅不存在")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.FromSavedSearch(search)))
+}
+
+// CreateSearch handles POST /api/user/searches
+func (h *SavedSearchHandler) CreateSearch(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	var params dto.SavedSearchRequest
+
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	search := &entity.SavedSearch{
+		UserID:   userID,
+		Name:     params.Name,
+		Keyword:  params.Keyword,
+		Region:   params.Region,
+		Platform: params.Platform,
+		MaxPrice: params.MaxPrice,
+	}
+	search.Normalize()
+
+	if err := search.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	if err := h.savedSearchService.Create(ctx, search); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to create saved search")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.FromSavedSearch(search)))
+}
+
+// UpdateSearch handles PUT /api/user/searches/:id
+func (h *SavedSearchHandler) UpdateSearch(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的订阅 ID")
+	}
+
+	var params dto.UpdateSavedSearchRequest
+
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	search, err := h.savedSearchService.Get(ctx, userID, id)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to get saved search")
+	}
+	if search == nil {
+		return apperrors.New(apperrors.NotFound, "订阅不存在")
+	}
+
+	if params.Name != nil {
+		search.Name = *params.Name
+	}
+	if params.Keyword != nil {
+		search.Keyword = *params.Keyword
+	}
+	if params.Region != nil {
+		search.Region = *params.Region
+	}
+	if params.Platform != nil {
+		search.Platform = *params.Platform
+	}
+	if params.MaxPrice != nil {
+		search.MaxPrice = *params.MaxPrice
+	}
+	search.Normalize()
+
+	if err := search.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	if err := h.savedSearchService.Update(ctx, search); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to update saved search")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.FromSavedSearch(search)))
+}
+
+// DeleteSearch handles DELETE /api/user/searches/:id
+func (h *SavedSearchHandler) DeleteSearch(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的订阅 ID")
+	}
+
+	if err := h.savedSearchService.Delete(ctx, userID, id); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to delete saved search")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
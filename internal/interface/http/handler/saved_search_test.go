@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubSavedSearchRepo struct {
+	repository.SavedSearchRepository
+	search *entity.SavedSearch
+}
+
+func (s *stubSavedSearchRepo) FindByID(ctx context.Context, id int64, userID string) (*entity.SavedSearch, error) {
+	if s.search != nil && s.search.ID == id && s.search.UserID == userID {
+		return s.search, nil
+	}
+	return nil, nil
+}
+
+func TestSavedSearchHandler_GetSearch(t *testing.T) {
+	repo := &stubSavedSearchRepo{search: &entity.SavedSearch{ID: 3, UserID: "client-1", Name: "烤鱼", Keyword: "烤鱼", Region: "广州"}}
+	h := NewSavedSearchHandler(service.NewSavedSearchService(repo, nil, nil))
+
+	e := echo.New()
+	get := func(userID, id string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/user/searches/"+id, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(middleware.UserIDContextKey, userID)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return rec, h.GetSearch(c)
+	}
+
+	rec, err := get("client-1", "3")
+	if err != nil {
+		t.Fatalf("GetSearch() error = %v", err)
+	}
+	var resp struct {
+		Data dto.SavedSearchDTO `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Data.ID != 3 || resp.Data.Keyword != "烤鱼" || resp.Data.Region != "广州" {
+		t.Errorf("search = %+v", resp.Data)
+	}
+
+	if _, err := get("client-2", "3"); !apperrors.IsNotFound(err) {
+		t.Errorf("GetSearch() of another user's search error = %v, want not found", err)
+	}
+	if _, err := get("client-1", "abc"); !apperrors.IsInvalidInput(err) {
+		t.Errorf("GetSearch() with an invalid ID error = %v, want invalid input", err)
+	}
+}
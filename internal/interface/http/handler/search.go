@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// defaultSearchLimit is the number of search results returned without limit
+const defaultSearchLimit = 20
+
+// Search handles GET /api/search
+// Searches titles and shop names of catalog and platform products, best match first.
+func (h *ProductHandler) Search(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	terms := entity.ParseSearchTerms(c.QueryParam("q"))
+	if len(terms) == 0 {
+		return apperrors.New(apperrors.InvalidInput, "请输入搜索关键词")
+	}
+
+	limit := defaultSearchLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		val, err := strconv.Atoi(limitStr)
+		if err != nil || val < 1 || val > repository.MaxSearchLimit {
+			return apperrors.New(apperrors.InvalidInput, fmt.Sprintf("limit 必须在 1 到 %d 之间", repository.MaxSearchLimit))
+		}
+		limit = val
+	}
+
+	hits, err := h.searchRepo.Search(ctx, terms, limit)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to search products", err)
+	}
+
+	blockedSet, err := h.blockedSet(ctx, userID)
+	if err != nil {
+		return err
+	}
+	notificationMap, annotationMap, err := h.loadUserProductState(ctx, userID, len(hits))
+	if err != nil {
+		return err
+	}
+
+	result := make([]dto.ProductDTO, 0, len(hits))
+	for _, hit := range hits {
+		if blockedSet[hit.ActivityID] {
+			continue
+		}
+
+		var productDTO dto.ProductDTO
+		switch hit.Source {
+		case repository.SearchSourceMaster:
+			master, err := h.masterRepo.FindByID(ctx, hit.ActivityID)
+			if err != nil || master == nil {
+				continue
+			}
+			productDTO = dto.FromMasterEntity(master)
+		default:
+			product, err := h.prodRepo.FindByActivityID(ctx, hit.ActivityID)
+			if err != nil || product == nil {
+				continue
+			}
+			productDTO = dto.FromEntity(product)
+		}
+
+		productDTO.ApplyNotification(notificationMap[hit.ActivityID])
+		productDTO.ApplyAnnotation(annotationMap[hit.ActivityID])
+		productDTO.ApplyHighlight(terms)
+		result = append(result, productDTO)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(result))
+}
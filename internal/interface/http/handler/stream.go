@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultStreamHeartbeat is the interval of keep-alive messages on idle streams
+const DefaultStreamHeartbeat = 15 * time.Second
+
+// streamRetry is the reconnection delay suggested to EventSource clients
+const streamRetry = 3 * time.Second
+
+// StreamHandler serves live product updates as Server-Sent Events
+type StreamHandler struct {
+	stream    *service.StreamService
+	notiRepo  repository.NotificationRepository
+	heartbeat time.Duration
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(
+	stream *service.StreamService,
+	notiRepo repository.NotificationRepository,
+	heartbeat time.Duration,
+) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultStreamHeartbeat
+	}
+	return &StreamHandler{
+		stream:    stream,
+		notiRepo:  notiRepo,
+		heartbeat: heartbeat,
+	}
+}
+
+// Stream handles GET /api/stream
+// Pushes price, status and new product events until the client disconnects.
+// Clients resume with the Last-Event-ID header, or the lastEventId parameter.
+func (h *StreamHandler) Stream(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	filter := entity.StreamFilter{
+		Region:      c.QueryParam("region"),
+		Platform:    c.QueryParam("platform"),
+		WatchedOnly: c.QueryParam("watched") == "1",
+	}
+	if filter.WatchedOnly {
+		if userID == "" {
+			return apperrors.ErrMissingUserID
+		}
+		watched, err := h.watchedIDs(ctx, userID)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrDatabase, "Failed to load watched products", err)
+		}
+		filter.Watched = watched
+	}
+
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("lastEventId")
+	}
+
+	backlog, sub := h.stream.Listen(lastEventID)
+	defer sub.Close()
+
+	res := c.Response()
+	// The stream outlives the server's write timeout
+	if err := http.NewResponseController(res).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Warn().Err(err).Msg("failed to clear stream write deadline")
+	}
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no") // disable nginx proxy buffering
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", streamRetry.Milliseconds()); err != nil {
+		return nil
+	}
+	// skipped is the ID of the latest event filtered out since the last one sent
+	skipped := ""
+	for _, e := range backlog {
+		if !filter.Matches(e) {
+			skipped = e.ID
+			continue
+		}
+		if err := writeStreamEvent(res, e); err != nil {
+			return nil
+		}
+		skipped = ""
+	}
+	res.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case e, ok := <-sub.Events():
+			if !ok {
+				// Fell behind or shutting down; the client resumes from the buffer
+				return nil
+			}
+			if !filter.Matches(e) {
+				skipped = e.ID
+				continue
+			}
+			if err := writeStreamEvent(res, e); err != nil {
+				return nil
+			}
+			skipped = ""
+			res.Flush()
+
+		case <-ticker.C:
+			if filter.WatchedOnly {
+				if watched, err := h.watchedIDs(ctx, userID); err == nil {
+					filter.Watched = watched
+				}
+			}
+			// An ID without data moves the client's resume point past filtered events
+			heartbeat := ": ping\n\n"
+			if skipped != "" {
+				heartbeat = "id: " + skipped + "\n\n"
+				skipped = ""
+			}
+			if _, err := io.WriteString(res, heartbeat); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// watchedIDs returns the activity IDs the user has alerts for
+func (h *StreamHandler) watchedIDs(ctx context.Context, userID string) (map[string]bool, error) {
+	configs, err := h.notiRepo.ListByUser(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("userId", userID).Msg("failed to list watched products")
+		return nil, err
+	}
+
+	watched := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		watched[config.ActivityID] = true
+	}
+	return watched, nil
+}
+
+// writeStreamEvent writes an event in the Server-Sent Events format
+func writeStreamEvent(w io.Writer, e entity.StreamEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", e.ID, data)
+	return err
+}
@@ -3,26 +3,41 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
 	"kbfood/internal/domain/entity"
 	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/infra/external"
 	"kbfood/internal/interface/http/dto"
 	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 )
 
 // UserHandler handles user-related requests
 type UserHandler struct {
 	userSettingsRepo repository.UserSettingsRepository
+	digestService    *service.DigestService
+	userDataService  *service.UserDataService
+	barkURL          string
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userSettingsRepo repository.UserSettingsRepository) *UserHandler {
+func NewUserHandler(
+	userSettingsRepo repository.UserSettingsRepository,
+	digestService *service.DigestService,
+	userDataService *service.UserDataService,
+	barkURL string,
+) *UserHandler {
 	return &UserHandler{
 		userSettingsRepo: userSettingsRepo,
+		digestService:    digestService,
+		userDataService:  userDataService,
+		barkURL:          barkURL,
 	}
 }
 
@@ -32,22 +47,20 @@ func (h *UserHandler) SaveSettings(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if userID == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "用户标识缺失，请刷新页面后重试"))
+		return apperrors.ErrMissingUserID
 	}
 
-	var params struct {
-		BarkKey string `json:"barkKey"`
-	}
+	var params dto.BarkKeyRequest
 
 	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid request body"))
+		return apperrors.ErrInvalidRequest
 	}
 
 	// Normalize Bark key - extract device key from URL if needed
-	barkKey := normalizeBarkKey(params.BarkKey)
+	barkKey := entity.NormalizeBarkKey(params.BarkKey)
 
 	if barkKey == "" {
-		return c.JSON(http.StatusBadRequest, dto.Error(400, "Bark Key 不能为空"))
+		return apperrors.New(apperrors.InvalidInput, "Bark Key 不能为空")
 	}
 
 	settings := &entity.UserSettings{
@@ -56,11 +69,11 @@ func (h *UserHandler) SaveSettings(c echo.Context) error {
 	}
 
 	if err := h.userSettingsRepo.Upsert(ctx, settings); err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to save settings"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to save settings", err)
 	}
 
-	return c.JSON(http.StatusOK, dto.Success(map[string]string{
-		"barkKey": barkKey,
+	return c.JSON(http.StatusOK, dto.Success(dto.UserSettingsDTO{
+		BarkKey: barkKey,
 	}))
 }
 
@@ -70,60 +83,174 @@ func (h *UserHandler) GetSettings(c echo.Context) error {
 	userID := middleware.GetUserID(c)
 
 	if userID == "" {
-		return c.JSON(http.StatusOK, dto.Success(map[string]string{
-			"barkKey": "",
-		}))
+		return c.JSON(http.StatusOK, dto.Success(dto.UserSettingsDTO{}))
 	}
 
 	settings, err := h.userSettingsRepo.Get(ctx, userID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to get settings"))
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to get settings", err)
 	}
 
 	if settings == nil {
-		return c.JSON(http.StatusOK, dto.Success(map[string]string{
-			"barkKey": "",
-		}))
+		return c.JSON(http.StatusOK, dto.Success(dto.UserSettingsDTO{}))
 	}
 
-	return c.JSON(http.StatusOK, dto.Success(map[string]string{
-		"barkKey": settings.BarkKey,
+	return c.JSON(http.StatusOK, dto.Success(dto.UserSettingsDTO{
+		BarkKey: settings.BarkKey,
 	}))
 }
 
-// normalizeBarkKey extracts device key from full URL or returns key as-is
-func normalizeBarkKey(input string) string {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return ""
-	}
-
-	// If full URL, extract the last non-empty path segment as device key.
-	if strings.HasPrefix(strings.ToLower(input), "http") {
-		parsed, err := url.Parse(input)
-		if err == nil {
-			path := strings.Trim(parsed.Path, "/")
-			if path == "" {
-				return ""
-			}
-
-			parts := strings.Split(path, "/")
-			for i := len(parts) - 1; i >= 0; i-- {
-				if seg := strings.TrimSpace(parts[i]); seg != "" {
-					return seg
-				}
-			}
-			return ""
-		}
-
-		trimmed := strings.Trim(input, "/")
-		parts := strings.Split(trimmed, "/")
-		for i := len(parts) - 1; i >= 0; i-- {
-			if seg := strings.TrimSpace(parts[i]); seg != "" {
-				return seg
-			}
-		}
-		return ""
-	}
-	return input
+// GetPreferences handles GET /api/user/preferences
+func (h *UserHandler) GetPreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return c.JSON(http.StatusOK, dto.Success(entity.NotificationPreferences{}))
+	}
+
+	settings, err := h.userSettingsRepo.Get(ctx, userID)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to get preferences", err)
+	}
+
+	if settings == nil {
+		return c.JSON(http.StatusOK, dto.Success(entity.NotificationPreferences{}))
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(settings.Preferences))
+}
+
+// SavePreferences handles PUT /api/user/preferences
+func (h *UserHandler) SavePreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	var prefs entity.NotificationPreferences
+	if err := json.NewDecoder(c.Request().Body).Decode(&prefs); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	prefs.Timezone = strings.TrimSpace(prefs.Timezone)
+	prefs.QuietStart = strings.TrimSpace(prefs.QuietStart)
+	prefs.QuietEnd = strings.TrimSpace(prefs.QuietEnd)
+	prefs.BarkSound = strings.TrimSpace(prefs.BarkSound)
+	prefs.BarkIcon = strings.TrimSpace(prefs.BarkIcon)
+
+	if err := prefs.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	if err := h.userSettingsRepo.UpdatePreferences(ctx, userID, prefs); err != nil {
+		return apperrors.Wrap(apperrors.ErrDatabase, "Failed to save preferences", err)
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(prefs))
+}
+
+// GetDigest handles GET /api/user/digest
+func (h *UserHandler) GetDigest(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	digest, err := h.digestService.Build(ctx, userID, time.Now())
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to build digest")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(digest))
+}
+
+// ExportData handles GET /api/user/export
+// The document is returned as is, without the response envelope, so that the
+// downloaded file can be posted to /api/user/import unchanged.
+func (h *UserHandler) ExportData(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	doc, err := h.userDataService.Export(ctx, userID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to export user data")
+	}
+
+	filename := "kbfood-export-" + doc.ExportedAt.Format("20060102") + ".json"
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	return c.JSON(http.StatusOK, doc)
+}
+
+// ImportData handles POST /api/user/import?mode=merge|replace
+func (h *UserHandler) ImportData(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	mode := c.QueryParam("mode")
+	if mode == "" {
+		mode = entity.ImportModeMerge
+	}
+	if !entity.IsValidImportMode(mode) {
+		return apperrors.New(apperrors.InvalidInput, "mode 只能是 merge 或 replace")
+	}
+
+	var doc entity.UserDataExport
+	if err := json.NewDecoder(c.Request().Body).Decode(&doc); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+	if err := doc.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	summary, err := h.userDataService.Import(ctx, userID, &doc, mode)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to import user data")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(summary))
+}
+
+// TestNotification handles POST /api/user/test-notification
+// Sends a test push to the given Bark device key through the configured Bark server.
+func (h *UserHandler) TestNotification(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var params dto.BarkKeyRequest
+
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	barkKey := entity.NormalizeBarkKey(params.BarkKey)
+	if barkKey == "" {
+		return apperrors.New(apperrors.InvalidInput, "Bark Key 不能为空")
+	}
+
+	client := external.NewBarkClientWithURL(h.barkURL, barkKey)
+	if err := client.Send(ctx, "测试通知", "美食监控配置成功！您可以收到价格提醒了。", ""); err != nil {
+		log.Warn().Err(err).Msg("Test notification failed")
+		return c.JSON(http.StatusOK, dto.Success(dto.TestNotificationDTO{
+			Success:  false,
+			Error:    err.Error(),
+			TestedAt: time.Now().Format("2006-01-02 15:04:05"),
+		}))
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(dto.TestNotificationDTO{
+		Success:  true,
+		TestedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}))
 }
@@ -0,0 +1,373 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/middleware"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WatchlistHandler handles shared watchlist requests
+type WatchlistHandler struct {
+	watchlistService *service.WatchlistService
+	prodRepo         repository.ProductRepository
+	masterRepo       repository.MasterProductRepository
+}
+
+// NewWatchlistHandler creates a new watchlist handler
+func NewWatchlistHandler(
+	watchlistService *service.WatchlistService,
+	prodRepo repository.ProductRepository,
+	masterRepo repository.MasterProductRepository,
+) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistService: watchlistService,
+		prodRepo:         prodRepo,
+		masterRepo:       masterRepo,
+	}
+}
+
+// ListWatchlists handles GET /api/watchlists
+func (h *WatchlistHandler) ListWatchlists(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	watchlists, err := h.watchlistService.List(ctx, userID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to list watchlists")
+	}
+	if watchlists == nil {
+		watchlists = []*entity.Watchlist{}
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(watchlists))
+}
+
+// CreateWatchlist handles POST /api/watchlists
+func (h *WatchlistHandler) CreateWatchlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	var params dto.WatchlistNameRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	probe := &entity.Watchlist{Name: params.Name}
+	probe.Normalize()
+	if err := probe.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	watchlist, err := h.watchlistService.Create(ctx, userID, params.Name)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to create watchlist")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(watchlist))
+}
+
+// JoinWatchlist handles POST /api/watchlists/join
+func (h *WatchlistHandler) JoinWatchlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+
+	var params dto.JoinWatchlistRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	watchlist, err := h.watchlistService.Join(ctx, userID, params.InviteCode)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to join watchlist")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(watchlist))
+}
+
+// GetWatchlist handles GET /api/watchlists/:id
+func (h *WatchlistHandler) GetWatchlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	detail, err := h.watchlistService.Get(ctx, userID, id)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to get watchlist")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(detail))
+}
+
+// RenameWatchlist handles PUT /api/watchlists/:id
+func (h *WatchlistHandler) RenameWatchlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	var params dto.WatchlistNameRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	probe := &entity.Watchlist{Name: params.Name}
+	probe.Normalize()
+	if err := probe.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	watchlist, err := h.watchlistService.Rename(ctx, userID, id, params.Name)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to rename watchlist")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(watchlist))
+}
+
+// DeleteWatchlist handles DELETE /api/watchlists/:id
+func (h *WatchlistHandler) DeleteWatchlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	if err := h.watchlistService.Delete(ctx, userID, id); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to delete watchlist")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// RotateInvite handles POST /api/watchlists/:id/invite
+// Replaces the invite code; earlier invite links stop working.
+func (h *WatchlistHandler) RotateInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	watchlist, err := h.watchlistService.RotateInvite(ctx, userID, id)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to rotate invite link")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(watchlist))
+}
+
+// LeaveWatchlist handles POST /api/watchlists/:id/leave
+func (h *WatchlistHandler) LeaveWatchlist(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	if err := h.watchlistService.Leave(ctx, userID, id); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to leave watchlist")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// RemoveMember handles DELETE /api/watchlists/:id/members/:memberId
+func (h *WatchlistHandler) RemoveMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+	memberID, err := strconv.ParseInt(c.Param("memberId"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的成员 ID")
+	}
+
+	removed, err := h.watchlistService.RemoveMember(ctx, userID, id, memberID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to remove member")
+	}
+	if !removed {
+		return apperrors.New(apperrors.NotFound, "成员不存在")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// SaveItem handles POST /api/watchlists/:id/items
+// Adds a product to the list, or replaces the rule if it is already on it.
+func (h *WatchlistHandler) SaveItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	var params dto.NotificationRuleRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+	if params.ActivityID == "" {
+		return apperrors.ErrMissingActivityID
+	}
+
+	item := &entity.WatchlistItem{
+		NotificationConfig: entity.NotificationConfig{
+			ActivityID:  params.ActivityID,
+			RuleType:    params.RuleType,
+			TargetPrice: params.TargetPrice,
+			DropPercent: params.DropPercent,
+			DropAmount:  params.DropAmount,
+		},
+	}
+	item.RuleType = item.EffectiveRuleType()
+
+	if err := item.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	seedRuleState(ctx, h.masterRepo, h.prodRepo, &item.NotificationConfig)
+
+	if err := h.watchlistService.SaveItem(ctx, userID, id, item); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to save watchlist item")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(item))
+}
+
+// UpdateItem handles PUT /api/watchlists/:id/items/:activityId
+func (h *WatchlistHandler) UpdateItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+	activityID := c.Param("activityId")
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	var params dto.UpdateWatchlistItemRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+		return apperrors.ErrInvalidRequest
+	}
+
+	item, err := h.watchlistService.GetItem(ctx, userID, id, activityID)
+	if err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to get watchlist item")
+	}
+	if item == nil {
+		return apperrors.New(apperrors.NotFound, "清单商品不存在")
+	}
+
+	ruleChanged := false
+	if params.RuleType != nil && *params.RuleType != item.EffectiveRuleType() {
+		item.RuleType = *params.RuleType
+		ruleChanged = true
+	}
+	if params.TargetPrice != nil {
+		item.TargetPrice = *params.TargetPrice
+	}
+	if params.DropPercent != nil {
+		item.DropPercent = *params.DropPercent
+	}
+	if params.DropAmount != nil {
+		item.DropAmount = *params.DropAmount
+	}
+	item.RuleType = item.EffectiveRuleType()
+
+	if err := item.Validate(); err != nil {
+		return apperrors.New(apperrors.InvalidInput, err.Error())
+	}
+
+	if ruleChanged {
+		seedRuleState(ctx, h.masterRepo, h.prodRepo, &item.NotificationConfig)
+	}
+
+	if err := h.watchlistService.SaveItem(ctx, userID, id, item); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to update watchlist item")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(item))
+}
+
+// DeleteItem handles DELETE /api/watchlists/:id/items/:activityId
+func (h *WatchlistHandler) DeleteItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	if userID == "" {
+		return apperrors.ErrMissingUserID
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperrors.New(apperrors.InvalidInput, "无效的清单 ID")
+	}
+
+	if err := h.watchlistService.DeleteItem(ctx, userID, id, c.Param("activityId")); err != nil {
+		return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to delete watchlist item")
+	}
+
+	return c.JSON(http.StatusOK, dto.Success(nil))
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// maxAuditBodySize limits how much of a request body is stored in the audit log
+const maxAuditBodySize = 4096
+
+// RequireRole only lets accounts with the given role through
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user := GetUser(c)
+			if user == nil {
+				return apperrors.ErrLoginRequired
+			}
+			if user.Role != role {
+				return apperrors.New(apperrors.Forbidden, "没有权限执行此操作")
+			}
+			return next(c)
+		}
+	}
+}
+
+// AuditLogger records who triggered each request and with what parameters.
+// It must run after RequireRole so that only authorized actions are recorded.
+func AuditLogger(auditLogs repository.AuditLogRepository) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			body := readAuditBody(c.Request())
+
+			err := next(c)
+
+			status := responseStatus(c, err)
+
+			entry := &entity.AuditLog{
+				UserID: GetUserID(c),
+				Action: c.Request().Method + " " + c.Path(),
+				Params: auditParams(c, body),
+				Status: status,
+			}
+			if user := GetUser(c); user != nil {
+				entry.Username = user.Username
+			}
+
+			// The request context may already be canceled once the handler returns
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request().Context()), 5*time.Second)
+			defer cancel()
+			if logErr := auditLogs.Create(ctx, entry); logErr != nil {
+				log.Error().Err(logErr).
+					Str("action", entry.Action).
+					Str("userId", entry.UserID).
+					Msg("failed to write audit log")
+			}
+
+			return err
+		}
+	}
+}
+
+// readAuditBody reads the start of the request body and restores it for the handler
+func readAuditBody(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBodySize+1))
+	if err != nil {
+		return nil
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	if len(body) > maxAuditBodySize {
+		body = body[:maxAuditBodySize]
+	}
+	return body
+}
+
+// auditParams encodes the path params, query and body of the request as JSON
+func auditParams(c echo.Context, body []byte) string {
+	params := make(map[string]interface{})
+
+	if names := c.ParamNames(); len(names) > 0 {
+		pathParams := make(map[string]string, len(names))
+		for _, name := range names {
+			pathParams[name] = c.Param(name)
+		}
+		params["path"] = pathParams
+	}
+	if query := c.QueryParams(); len(query) > 0 {
+		params["query"] = query
+	}
+	if len(body) > 0 {
+		if json.Valid(body) {
+			params["body"] = json.RawMessage(body)
+		} else {
+			params["body"] = string(body)
+		}
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubAuditLogRepository struct {
+	logs []*entity.AuditLog
+}
+
+func (s *stubAuditLogRepository) Create(ctx context.Context, log *entity.AuditLog) error {
+	s.logs = append(s.logs, log)
+	return nil
+}
+
+func (s *stubAuditLogRepository) ListRecent(ctx context.Context, limit int) ([]*entity.AuditLog, error) {
+	return s.logs, nil
+}
+
+// renderAppErrors is a minimal error handler that responds with the HTTP
+// status of the error, as the router's error handler does
+func renderAppErrors(err error, c echo.Context) {
+	_ = c.JSON(responseStatus(c, err), dto.FromAppError(apperrors.Ensure(err, apperrors.Unknown, ""), apperrors.LangZH))
+}
+
+func newAdminTestServer(audit *stubAuditLogRepository, user *entity.User) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = renderAppErrors
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if user != nil {
+				c.Set(UserIDContextKey, user.ID)
+				c.Set(AuthenticatedContextKey, true)
+				c.Set(UserContextKey, user)
+			}
+			return next(c)
+		}
+	})
+	e.DELETE("/api/products/platform/:platform", func(c echo.Context) error {
+		body, _ := io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, string(body))
+	}, RequireRole(entity.RoleAdmin), AuditLogger(audit))
+	return e
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *entity.User
+		wantCode int
+	}{
+		{name: "anonymous", wantCode: http.StatusUnauthorized},
+		{name: "regular user", user: &entity.User{ID: "u_1", Role: entity.RoleUser}, wantCode: http.StatusForbidden},
+		{name: "admin", user: &entity.User{ID: "u_2", Role: entity.RoleAdmin}, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &stubAuditLogRepository{}
+			e := newAdminTestServer(audit, tt.user)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/products/platform/DT", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			wantLogs := 0
+			if tt.wantCode == http.StatusOK {
+				wantLogs = 1
+			}
+			if len(audit.logs) != wantLogs {
+				t.Fatalf("audit logs = %d, want %d", len(audit.logs), wantLogs)
+			}
+		})
+	}
+}
+
+func TestAuditLogger_RecordsActorAndParams(t *testing.T) {
+	audit := &stubAuditLogRepository{}
+	admin := &entity.User{ID: "u_admin", Username: "root", Role: entity.RoleAdmin}
+	e := newAdminTestServer(audit, admin)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/products/platform/DT?dryRun=1", strings.NewReader(`{"reason":"cleanup"}`))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Body.String() != `{"reason":"cleanup"}` {
+		t.Fatalf("handler body = %q, want the original request body", rec.Body.String())
+	}
+	if len(audit.logs) != 1 {
+		t.Fatalf("audit logs = %d, want 1", len(audit.logs))
+	}
+
+	entry := audit.logs[0]
+	if entry.UserID != "u_admin" || entry.Username != "root" {
+		t.Fatalf("actor = %s/%s, want u_admin/root", entry.UserID, entry.Username)
+	}
+	if entry.Action != "DELETE /api/products/platform/:platform" {
+		t.Fatalf("Action = %q", entry.Action)
+	}
+	if entry.Status != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", entry.Status)
+	}
+
+	var params struct {
+		Path  map[string]string   `json:"path"`
+		Query map[string][]string `json:"query"`
+		Body  map[string]string   `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(entry.Params), &params); err != nil {
+		t.Fatalf("Params is not JSON: %v", err)
+	}
+	if params.Path["platform"] != "DT" || params.Query["dryRun"][0] != "1" || params.Body["reason"] != "cleanup" {
+		t.Fatalf("unexpected params: %s", entry.Params)
+	}
+}
@@ -13,7 +13,7 @@ func CORS() echo.MiddlewareFunc {
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
 		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-Requested-With", "X-User-ID"},
-		ExposeHeaders:    []string{"Link"},
+		ExposeHeaders:    []string{"Link", "Retry-After", "X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           300, // 5 minutes
 	})
@@ -3,35 +3,27 @@ package middleware
 import (
 	"time"
 
+	applog "kbfood/internal/pkg/logger"
+
 	"github.com/labstack/echo/v4"
-	"github.com/rs/zerolog/log"
 )
 
-// Logger returns a logger middleware
+// Logger returns a logger middleware. Requests are logged with the request
+// logger, which carries the request ID added by logger.RequestIDHandler.
 func Logger() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
 
-			// Get request ID from header or generate one
-			requestID := c.Request().Header.Get("X-Request-ID")
-			if requestID == "" {
-				requestID = c.Response().Header().Get(echo.HeaderXRequestID)
-			}
-			if requestID == "" {
-				requestID = "unknown"
-			}
-
 			// Call next handler
 			err := next(c)
 
 			// Log after request completes
-			log.Info().
-				Str("request_id", requestID).
+			applog.LoggerFromContext(c.Request().Context()).Info().
 				Str("method", c.Request().Method).
-				Str("path", c.Request().URL.Path).
+				Str("path", LoggedPath(c)).
 				Str("query", c.Request().URL.RawQuery).
-				Int("status", c.Response().Status).
+				Int("status", responseStatus(c, err)).
 				Int64("size", c.Response().Size).
 				Dur("duration", time.Since(start)).
 				Msg("HTTP request")
@@ -40,3 +32,18 @@ func Logger() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// secretPathParams are the path parameters holding credentials, such as the
+// token of private feeds
+var secretPathParams = map[string]bool{"token": true}
+
+// LoggedPath returns the request path for logs and spans. Paths holding a
+// credential are replaced by their route, e.g. /feeds/user/:token/drops.atom.
+func LoggedPath(c echo.Context) string {
+	for _, name := range c.ParamNames() {
+		if secretPathParams[name] {
+			return c.Path()
+		}
+	}
+	return c.Request().URL.Path
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "kbfood/internal/pkg/errors"
+	"kbfood/internal/pkg/metrics"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Metrics returns a middleware that counts requests and observes their
+// latency by route pattern, so that path parameters do not create a series
+// per product or token
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := routeOf(c)
+			method := c.Request().Method
+
+			metrics.HTTPRequests.With(method, route, strconv.Itoa(responseStatus(c, err))).Inc()
+			metrics.HTTPRequestDuration.With(method, route).ObserveSince(start)
+			return err
+		}
+	}
+}
+
+// routeOf returns the route pattern of the request, e.g. /api/products/:id
+func routeOf(c echo.Context) string {
+	if route := c.Path(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// responseStatus returns the status of the response, or the status the
+// error handler will write if the handler returned an error
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code.HTTPStatus()
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
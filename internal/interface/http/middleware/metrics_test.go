@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kbfood/internal/pkg/metrics"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMetrics_RecordsRoutePattern(t *testing.T) {
+	e := echo.New()
+	e.Use(Metrics())
+	e.GET("/api/metrics-test/:id", func(c echo.Context) error {
+		if c.Param("id") == "missing" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	ok := metrics.HTTPRequests.With(http.MethodGet, "/api/metrics-test/:id", "204")
+	notFound := metrics.HTTPRequests.With(http.MethodGet, "/api/metrics-test/:id", "404")
+	latency := metrics.HTTPRequestDuration.With(http.MethodGet, "/api/metrics-test/:id")
+	okBefore, notFoundBefore, latencyBefore := ok.Value(), notFound.Value(), latency.Count()
+
+	for _, path := range []string{"/api/metrics-test/1", "/api/metrics-test/2", "/api/metrics-test/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := ok.Value() - okBefore; got != 2 {
+		t.Errorf("204 requests = %v, want 2", got)
+	}
+	if got := notFound.Value() - notFoundBefore; got != 1 {
+		t.Errorf("404 requests = %v, want 1", got)
+	}
+	if got := latency.Count() - latencyBefore; got != 3 {
+		t.Errorf("latency observations = %d, want 3", got)
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// idleBucketTTL is how long an untouched bucket is kept before it is swept
+const idleBucketTTL = 10 * time.Minute
+
+// RateLimitRule configures a token bucket: Burst requests at once, refilled at
+// RequestsPerMinute. A zero RequestsPerMinute disables the limit.
+type RateLimitRule struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter keeps in-memory token buckets per client
+type RateLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter for the rule
+func NewRateLimiter(rule RateLimitRule) *RateLimiter {
+	burst := rule.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    float64(rule.RequestsPerMinute) / 60,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key. If none is left it returns false
+// and how long until the next token is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that have not been used for a while. Their buckets
+// would be full again anyway. Caller must hold the lock.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleBucketTTL {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit limits requests per client with a token bucket. Clients are keyed by
+// account for token-authenticated requests and by IP otherwise, since anonymous
+// user IDs are chosen by the client and can be rotated freely.
+func RateLimit(rule RateLimitRule) echo.MiddlewareFunc {
+	if rule.RequestsPerMinute <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	limiter := NewRateLimiter(rule)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			allowed, retryAfter := limiter.Allow(rateLimitKey(c))
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				c.Response().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				return apperrors.New(apperrors.RateLimited, "请求过于频繁，请稍后再试")
+			}
+			return next(c)
+		}
+	}
+}
+
+// rateLimitKey identifies the client of a request
+func rateLimitKey(c echo.Context) string {
+	if IsAuthenticated(c) {
+		return "user:" + GetUserID(c)
+	}
+	return "ip:" + c.RealIP()
+}
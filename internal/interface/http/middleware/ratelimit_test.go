@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"kbfood/internal/interface/http/dto"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRateLimiter_BurstThenRefill(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(RateLimitRule{RequestsPerMinute: 6, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("ip:1.2.3.4"); !ok {
+			t.Fatalf("request %d within burst was limited", i+1)
+		}
+	}
+
+	ok, retryAfter := limiter.Allow("ip:1.2.3.4")
+	if ok {
+		t.Fatal("request beyond burst was allowed")
+	}
+	if retryAfter != 10*time.Second {
+		t.Fatalf("retryAfter = %v, want 10s", retryAfter)
+	}
+
+	if ok, _ := limiter.Allow("ip:5.6.7.8"); !ok {
+		t.Fatal("other clients must have their own bucket")
+	}
+
+	now = now.Add(10 * time.Second)
+	if ok, _ := limiter.Allow("ip:1.2.3.4"); !ok {
+		t.Fatal("request after refill was limited")
+	}
+}
+
+func TestRateLimit_RespondsWith429AndRetryAfter(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = renderAppErrors
+	e.GET("/api/admin/sync", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimit(RateLimitRule{RequestsPerMinute: 1, Burst: 1}))
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/sync", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", rec.Code)
+	}
+
+	rec := send()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "60" {
+		t.Fatalf("Retry-After = %q, want 60", got)
+	}
+
+	var resp dto.Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Code != int(apperrors.RateLimited) {
+		t.Fatalf("response code = %d, want %d", resp.Code, apperrors.RateLimited)
+	}
+}
+
+func TestRateLimit_ZeroRuleDisablesLimit(t *testing.T) {
+	e := echo.New()
+	e.GET("/api/status", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimit(RateLimitRule{}))
+
+	for i := 0; i < 100; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i+1, rec.Code)
+		}
+	}
+}
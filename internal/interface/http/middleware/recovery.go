@@ -17,7 +17,7 @@ func Recovery() echo.MiddlewareFunc {
 				if r := recover(); r != nil {
 					// Log the panic
 					log.Error().
-						Str("path", c.Request().URL.Path).
+						Str("path", LoggedPath(c)).
 						Str("method", c.Request().Method).
 						Str("stack", string(debug.Stack())).
 						Msg(fmt.Sprintf("panic: %v", r))
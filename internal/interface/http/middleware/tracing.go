@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/tracing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// Tracing returns a middleware that starts a server span for each request.
+// Spans of repository calls, platform fetches and notifications started from
+// the request context become its children, and the trace ID is added to the
+// request logger.
+func Tracing() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := routeOf(c)
+			ctx, span := tracing.Start(req.Context(), req.Method+" "+route, tracing.KindServer,
+				tracing.String("http.request.method", req.Method),
+				tracing.String("http.route", route),
+				tracing.String("url.path", LoggedPath(c)),
+				tracing.String("request.id", applog.RequestIDFromContext(req.Context())),
+			)
+			if span == nil {
+				return next(c)
+			}
+			defer span.End()
+
+			zerolog.Ctx(ctx).UpdateContext(func(l zerolog.Context) zerolog.Context {
+				return l.Str("trace_id", span.TraceID())
+			})
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			status := responseStatus(c, err)
+			span.SetAttributes(tracing.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(tracing.StatusError, http.StatusText(status))
+				span.RecordError(err)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/tracing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestTracing_RequestIDAndSpan(t *testing.T) {
+	shutdown, err := tracing.Init(tracing.Config{Exporter: tracing.ExporterStdout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(context.Background())
+
+	e := echo.New()
+	e.Use(echo.WrapMiddleware(applog.Middleware))
+	e.Use(echo.WrapMiddleware(applog.RequestIDHandler))
+	e.Use(Tracing())
+
+	var requestID string
+	var span *tracing.Span
+	e.GET("/api/products/:id", func(c echo.Context) error {
+		requestID = applog.RequestIDFromContext(c.Request().Context())
+		span = tracing.SpanFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/products/DT_1", nil))
+
+	if requestID == "" || rec.Header().Get(applog.RequestIDHeader) != requestID {
+		t.Errorf("request ID = %q, X-Request-ID = %q", requestID, rec.Header().Get(applog.RequestIDHeader))
+	}
+	if span == nil || span.TraceID() == "" {
+		t.Error("handler context has no span")
+	}
+}
+
+func TestRequestIDHandler_ReusesIncomingID(t *testing.T) {
+	e := echo.New()
+	e.Use(echo.WrapMiddleware(applog.Middleware))
+	e.Use(echo.WrapMiddleware(applog.RequestIDHandler))
+
+	var requestID string
+	e.GET("/api/products", func(c echo.Context) error {
+		requestID = applog.RequestIDFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   string
+	}{
+		{
+			name:   "request ID header",
+			header: map[string]string{applog.RequestIDHeader: "gateway-42", applog.TraceparentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			want:   "gateway-42",
+		},
+		{
+			name:   "trace ID of traceparent",
+			header: map[string]string{applog.TraceparentHeader: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+			want:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:   "unsafe ID is replaced",
+			header: map[string]string{applog.RequestIDHeader: "id\r\nX-Injected: 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Header().Get(applog.RequestIDHeader) != requestID {
+				t.Fatalf("X-Request-ID = %q, request ID = %q", rec.Header().Get(applog.RequestIDHeader), requestID)
+			}
+			if tt.want != "" && requestID != tt.want {
+				t.Errorf("request ID = %q, want %q", requestID, tt.want)
+			}
+			if tt.want == "" && (requestID == "" || requestID == tt.header[applog.RequestIDHeader]) {
+				t.Errorf("request ID = %q, want a generated ID", requestID)
+			}
+		})
+	}
+}
+
+func TestLoggedPath_HidesFeedTokens(t *testing.T) {
+	e := echo.New()
+	var path string
+	capture := func(c echo.Context) error {
+		path = LoggedPath(c)
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.GET("/feeds/user/:token/drops.atom", capture)
+	e.GET("/api/products/:id", capture)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/feeds/user/secret-token/drops.atom", nil))
+	if path != "/feeds/user/:token/drops.atom" {
+		t.Errorf("LoggedPath() = %q, want the route without the token", path)
+	}
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/products/DT_1", nil))
+	if path != "/api/products/DT_1" {
+		t.Errorf("LoggedPath() = %q, want the request path", path)
+	}
+}
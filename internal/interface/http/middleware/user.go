@@ -1,13 +1,18 @@
 package middleware
 
 import (
+	"context"
+	"net/http"
 	"strings"
 
+	"kbfood/internal/domain/entity"
+	apperrors "kbfood/internal/pkg/errors"
+
 	"github.com/labstack/echo/v4"
 )
 
 const (
-	// UserIDHeader is the HTTP header for user identification
+	// UserIDHeader is the HTTP header for anonymous user identification
 	UserIDHeader = "X-User-ID"
 	// LegacyUserIDHeader carries the legacy Bark-key-derived user identifier.
 	LegacyUserIDHeader = "X-Legacy-User-ID"
@@ -15,13 +20,82 @@ const (
 	UserIDContextKey = "userID"
 	// LegacyUserIDContextKey is the context key for the legacy user ID.
 	LegacyUserIDContextKey = "legacyUserID"
+	// AuthenticatedContextKey is set when the user ID comes from a verified token
+	AuthenticatedContextKey = "authenticated"
+	// AuthTokenContextKey is the context key for the bearer token of the request
+	AuthTokenContextKey = "authToken"
+	// UserContextKey is the context key for the authenticated account
+	UserContextKey = "user"
+	// ClientIDContextKey is the context key for the anonymous X-User-ID of the request
+	ClientIDContextKey = "clientID"
 )
 
-// UserExtractor extracts user ID from request header and adds to context
-func UserExtractor() echo.MiddlewareFunc {
+// Authenticator resolves bearer tokens to accounts
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*entity.User, error)
+	IsClientClaimed(ctx context.Context, clientID string) (bool, error)
+}
+
+// DeviceResolver resolves paired anonymous client IDs to their shared user ID
+type DeviceResolver interface {
+	ResolveDevice(ctx context.Context, clientID string) (string, error)
+}
+
+// UserExtractor derives the user ID of the request. A bearer token always
+// identifies an account; without one the anonymous X-User-ID header is only
+// trusted when allowAnonymous is set and the ID has not been claimed by an account.
+// A paired client ID acts as the anonymous user it is paired to.
+func UserExtractor(auth Authenticator, devices DeviceResolver, allowAnonymous bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			c.Set(UserIDContextKey, "")
+			c.Set(LegacyUserIDContextKey, "")
+			c.Set(ClientIDContextKey, "")
+
+			if token := bearerToken(c.Request()); token != "" && auth != nil {
+				user, err := auth.Authenticate(c.Request().Context(), token)
+				if err != nil {
+					// An invalid token is an Unauthorized AppError
+					return apperrors.Ensure(err, apperrors.ErrDatabase, "Failed to authenticate token")
+				}
+				c.Set(UserIDContextKey, user.ID)
+				c.Set(AuthenticatedContextKey, true)
+				c.Set(AuthTokenContextKey, token)
+				c.Set(UserContextKey, user)
+				return next(c)
+			}
+
+			if !allowAnonymous {
+				return next(c)
+			}
+
 			userID := strings.TrimSpace(c.Request().Header.Get(UserIDHeader))
+			if strings.HasPrefix(userID, entity.UserIDPrefix) {
+				// Account IDs can only be used with a token
+				userID = ""
+			}
+			if userID != "" && auth != nil {
+				claimed, err := auth.IsClientClaimed(c.Request().Context(), userID)
+				if err != nil {
+					return apperrors.Wrap(apperrors.ErrDatabase, "Failed to check client claim", err)
+				}
+				if claimed {
+					return apperrors.New(apperrors.Unauthorized, "该匿名标识已绑定账号，请登录")
+				}
+			}
+
+			c.Set(ClientIDContextKey, userID)
+			if userID != "" && devices != nil {
+				pairedUserID, err := devices.ResolveDevice(c.Request().Context(), userID)
+				if err != nil {
+					return apperrors.Wrap(apperrors.ErrDatabase, "Failed to resolve paired device", err)
+				}
+				// Accounts are only reachable with a token, never through a pairing
+				if pairedUserID != "" && !strings.HasPrefix(pairedUserID, entity.UserIDPrefix) {
+					userID = pairedUserID
+				}
+			}
+
 			legacyUserID := strings.TrimSpace(c.Request().Header.Get(LegacyUserIDHeader))
 			c.Set(UserIDContextKey, userID)
 			c.Set(LegacyUserIDContextKey, legacyUserID)
@@ -45,3 +119,43 @@ func GetLegacyUserID(c echo.Context) string {
 	}
 	return ""
 }
+
+// GetClientID retrieves the anonymous client ID the request was sent with. It
+// differs from the user ID when the client is paired to another user.
+func GetClientID(c echo.Context) string {
+	if id, ok := c.Get(ClientIDContextKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// IsAuthenticated returns true if the user ID comes from a verified token
+func IsAuthenticated(c echo.Context) bool {
+	authenticated, _ := c.Get(AuthenticatedContextKey).(bool)
+	return authenticated
+}
+
+// GetUser retrieves the authenticated account, or nil for anonymous requests
+func GetUser(c echo.Context) *entity.User {
+	if user, ok := c.Get(UserContextKey).(*entity.User); ok {
+		return user
+	}
+	return nil
+}
+
+// GetAuthToken retrieves the bearer token of an authenticated request
+func GetAuthToken(c echo.Context) string {
+	if token, ok := c.Get(AuthTokenContextKey).(string); ok {
+		return token
+	}
+	return ""
+}
+
+// bearerToken extracts the token from the Authorization header
+func bearerToken(r *http.Request) string {
+	header := strings.TrimSpace(r.Header.Get(echo.HeaderAuthorization))
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
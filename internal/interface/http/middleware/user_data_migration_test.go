@@ -110,6 +110,12 @@ func setupMigrationTestDB(t *testing.T) *dbinfra.Pool {
 			last_notify_time TEXT,
 			create_time TEXT NOT NULL DEFAULT (datetime('now')),
 			update_time TEXT NOT NULL DEFAULT (datetime('now')),
+			rule_type TEXT NOT NULL DEFAULT 'target_price',
+			drop_percent REAL,
+			drop_amount REAL,
+			reference_price REAL,
+			last_status INTEGER,
+			snooze_until TEXT,
 			PRIMARY KEY (activity_id, user_id)
 		);
 
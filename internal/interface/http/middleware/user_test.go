@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type stubAuthenticator struct {
+	claimed map[string]bool
+}
+
+func (s *stubAuthenticator) Authenticate(ctx context.Context, token string) (*entity.User, error) {
+	if token == "kbf_valid" {
+		return &entity.User{ID: "u_alice"}, nil
+	}
+	return nil, service.ErrInvalidToken
+}
+
+func (s *stubAuthenticator) IsClientClaimed(ctx context.Context, clientID string) (bool, error) {
+	return s.claimed[clientID], nil
+}
+
+type stubDeviceResolver struct {
+	paired map[string]string
+}
+
+func (s *stubDeviceResolver) ResolveDevice(ctx context.Context, clientID string) (string, error) {
+	return s.paired[clientID], nil
+}
+
+func runUserExtractor(t *testing.T, allowAnonymous bool, headers map[string]string) (int, string, bool) {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var userID string
+	var authenticated bool
+	auth := &stubAuthenticator{claimed: map[string]bool{"client-claimed": true}}
+	devices := &stubDeviceResolver{paired: map[string]string{"client-phone": "client-laptop", "client-tablet": "u_alice"}}
+	handler := UserExtractor(auth, devices, allowAnonymous)(func(c echo.Context) error {
+		userID = GetUserID(c)
+		authenticated = IsAuthenticated(c)
+		return c.NoContent(http.StatusOK)
+	})
+	if err := handler(c); err != nil {
+		return responseStatus(c, err), userID, authenticated
+	}
+	return rec.Code, userID, authenticated
+}
+
+func TestUserExtractor(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowAnonymous    bool
+		headers           map[string]string
+		wantCode          int
+		wantUserID        string
+		wantAuthenticated bool
+	}{
+		{
+			name:              "bearer token identifies the account",
+			allowAnonymous:    true,
+			headers:           map[string]string{"Authorization": "Bearer kbf_valid", UserIDHeader: "client-123"},
+			wantCode:          http.StatusOK,
+			wantUserID:        "u_alice",
+			wantAuthenticated: true,
+		},
+		{
+			name:           "invalid token is rejected",
+			allowAnonymous: true,
+			headers:        map[string]string{"Authorization": "Bearer kbf_invalid"},
+			wantCode:       http.StatusUnauthorized,
+		},
+		{
+			name:           "anonymous client ID is trusted when allowed",
+			allowAnonymous: true,
+			headers:        map[string]string{UserIDHeader: "client-123"},
+			wantCode:       http.StatusOK,
+			wantUserID:     "client-123",
+		},
+		{
+			name:           "anonymous client ID is ignored when disabled",
+			allowAnonymous: false,
+			headers:        map[string]string{UserIDHeader: "client-123"},
+			wantCode:       http.StatusOK,
+		},
+		{
+			name:           "account ID cannot be spoofed through the header",
+			allowAnonymous: true,
+			headers:        map[string]string{UserIDHeader: "u_alice"},
+			wantCode:       http.StatusOK,
+		},
+		{
+			name:           "paired client ID acts as the shared user",
+			allowAnonymous: true,
+			headers:        map[string]string{UserIDHeader: "client-phone"},
+			wantCode:       http.StatusOK,
+			wantUserID:     "client-laptop",
+		},
+		{
+			name:           "client paired to an account does not act as the account",
+			allowAnonymous: true,
+			headers:        map[string]string{UserIDHeader: "client-tablet"},
+			wantCode:       http.StatusOK,
+			wantUserID:     "client-tablet",
+		},
+		{
+			name:           "claimed client ID requires login",
+			allowAnonymous: true,
+			headers:        map[string]string{UserIDHeader: "client-claimed"},
+			wantCode:       http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, userID, authenticated := runUserExtractor(t, tt.allowAnonymous, tt.headers)
+			if code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", code, tt.wantCode)
+			}
+			if userID != tt.wantUserID {
+				t.Fatalf("userID = %q, want %q", userID, tt.wantUserID)
+			}
+			if authenticated != tt.wantAuthenticated {
+				t.Fatalf("authenticated = %v, want %v", authenticated, tt.wantAuthenticated)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package http
+
+import (
+	"net/http"
+	"sync"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/dto"
+	"kbfood/internal/interface/http/handler"
+	"kbfood/internal/interface/http/middleware"
+	"kbfood/internal/interface/http/openapi"
+	apperrors "kbfood/internal/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// apiInfo describes the API in the OpenAPI document
+var apiInfo = openapi.Info{
+	Title:   "KbFood API",
+	Version: "1.0.0",
+	Description: "美食价格监控服务的 HTTP 接口。成功响应的数据放在 data 字段中，失败时返回错误码 code 与 message，" +
+		"错误码见 internal/pkg/errors，如 10001 参数无效、10002 资源不存在、10003 未登录；message 随 Accept-Language 返回中文或英文。" +
+		"请求以 Authorization: Bearer <令牌> 识别账号，或以 X-User-ID 头识别匿名客户端。",
+}
+
+// errorBody is the body of failed responses; code is an error code of
+// internal/pkg/errors rather than the HTTP status
+type errorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// listingSorts are the sort keys of the product list
+var listingSorts = []string{
+	repository.ListingSortUpdateTime,
+	repository.ListingSortPrice,
+	repository.ListingSortDiscount,
+	repository.ListingSortDropRate,
+	repository.ListingSortRelevance,
+}
+
+// idParam documents the numeric id path parameter
+var idParam = openapi.Param{Name: "id", In: openapi.InPath, Type: openapi.TypeInteger}
+
+// apiRoutes documents every route registered by Router. Router tests check
+// that both list the same routes and handlers.
+var apiRoutes = []openapi.Route{
+	// Health checks
+	{Method: http.MethodGet, Path: "/health", Handler: "(*HealthHandler).Health", ID: "Health", Tag: "system",
+		Summary: "健康检查", Data: map[string]string{}, Raw: true},
+	{Method: http.MethodGet, Path: "/ready", Handler: "(*HealthHandler).Ready", ID: "Ready", Tag: "system",
+		Summary: "就绪检查（含数据库连通性）", Data: map[string]string{}, Raw: true},
+	{Method: http.MethodGet, Path: "/api/status", Handler: "(*StatusHandler).GetStatus", ID: "GetStatus", Tag: "system",
+		Summary: "获取同步状态", Data: handler.SystemStatusResponse{}},
+	{Method: http.MethodGet, Path: "/api/openapi.json", Handler: "http.serveOpenAPI", ID: "GetOpenAPI", Tag: "system",
+		Summary: "获取本接口文档", Data: map[string]interface{}{}, Raw: true},
+
+	// Accounts
+	{Method: http.MethodPost, Path: "/api/auth/signup", Handler: "(*AuthHandler).SignUp", ID: "SignUp", Tag: "auth",
+		Summary: "注册账号", Body: dto.CredentialsRequest{}, Data: dto.AuthResponse{}},
+	{Method: http.MethodPost, Path: "/api/auth/login", Handler: "(*AuthHandler).Login", ID: "Login", Tag: "auth",
+		Summary: "登录", Body: dto.CredentialsRequest{}, Data: dto.AuthResponse{}},
+	{Method: http.MethodPost, Path: "/api/auth/logout", Handler: "(*AuthHandler).Logout", ID: "Logout", Tag: "auth",
+		Summary: "退出登录"},
+	{Method: http.MethodGet, Path: "/api/auth/me", Handler: "(*AuthHandler).Me", ID: "Me", Tag: "auth",
+		Summary: "获取当前账号", Data: entity.User{}},
+	{Method: http.MethodPost, Path: "/api/auth/claim", Handler: "(*AuthHandler).ClaimClient", ID: "ClaimClient", Tag: "auth",
+		Summary: "将匿名标识下的数据迁入账号", Data: dto.ClaimClientResponse{}},
+	{Method: http.MethodGet, Path: "/api/auth/tokens", Handler: "(*AuthHandler).ListTokens", ID: "ListTokens", Tag: "auth",
+		Summary: "获取会话与 API 令牌", Data: []*entity.AuthToken{}},
+	{Method: http.MethodPost, Path: "/api/auth/tokens", Handler: "(*AuthHandler).CreateToken", ID: "CreateToken", Tag: "auth",
+		Summary: "创建 API 令牌", Body: dto.CreateTokenRequest{}, Data: dto.CreateTokenResponse{}},
+	{Method: http.MethodDelete, Path: "/api/auth/tokens/:id", Handler: "(*AuthHandler).RevokeToken", ID: "RevokeToken", Tag: "auth",
+		Summary: "吊销令牌", Params: []openapi.Param{idParam}},
+
+	// User settings
+	{Method: http.MethodGet, Path: "/api/user/settings", Handler: "(*UserHandler).GetSettings", ID: "GetSettings", Tag: "user",
+		Summary: "获取 Bark 设置", Data: dto.UserSettingsDTO{}},
+	{Method: http.MethodPost, Path: "/api/user/settings", Handler: "(*UserHandler).SaveSettings", ID: "SaveSettings", Tag: "user",
+		Summary: "保存 Bark 设置", Body: dto.BarkKeyRequest{}, Data: dto.UserSettingsDTO{}},
+	{Method: http.MethodGet, Path: "/api/user/preferences", Handler: "(*UserHandler).GetPreferences", ID: "GetPreferences", Tag: "user",
+		Summary: "获取推送偏好", Data: entity.NotificationPreferences{}},
+	{Method: http.MethodPut, Path: "/api/user/preferences", Handler: "(*UserHandler).SavePreferences", ID: "SavePreferences", Tag: "user",
+		Summary: "更新推送偏好", Body: entity.NotificationPreferences{}, Data: entity.NotificationPreferences{}},
+	{Method: http.MethodGet, Path: "/api/user/digest", Handler: "(*UserHandler).GetDigest", ID: "GetDigest", Tag: "user",
+		Summary: "获取今日摘要", Data: entity.Digest{}},
+	{Method: http.MethodPost, Path: "/api/user/test-notification", Handler: "(*UserHandler).TestNotification", ID: "TestNotification", Tag: "user",
+		Summary: "向自己的 Bark Key 发送测试推送", Body: dto.BarkKeyRequest{}, Data: dto.TestNotificationDTO{}},
+	{Method: http.MethodGet, Path: "/api/user/export", Handler: "(*UserHandler).ExportData", ID: "ExportData", Tag: "user",
+		Summary: "导出个人数据", Data: entity.UserDataExport{}, Raw: true},
+	{Method: http.MethodPost, Path: "/api/user/import", Handler: "(*UserHandler).ImportData", ID: "ImportData", Tag: "user",
+		Summary: "导入个人数据", Body: entity.UserDataExport{}, Data: entity.UserDataImportSummary{},
+		Params: []openapi.Param{{Name: "mode", Description: "merge（默认）或 replace", Enum: []string{entity.ImportModeMerge, entity.ImportModeReplace}}}},
+	{Method: http.MethodGet, Path: "/api/user/feed", Handler: "(*FeedHandler).GetFeedToken", ID: "GetFeedToken", Tag: "user",
+		Summary: "获取私人订阅源地址，首次调用时生成", Data: entity.FeedToken{}},
+	{Method: http.MethodPost, Path: "/api/user/feed", Handler: "(*FeedHandler).RotateFeedToken", ID: "RotateFeedToken", Tag: "user",
+		Summary: "重新生成私人订阅源地址，旧地址失效", Data: entity.FeedToken{}},
+
+	// Saved searches
+	{Method: http.MethodGet, Path: "/api/user/searches", Handler: "(*SavedSearchHandler).ListSearches", ID: "ListSearches", Tag: "searches",
+		Summary: "获取订阅搜索", Data: []dto.SavedSearchDTO{}},
+	{Method: http.MethodPost, Path: "/api/user/searches", Handler: "(*SavedSearchHandler).CreateSearch", ID: "CreateSearch", Tag: "searches",
+		Summary: "创建订阅搜索", Body: dto.SavedSearchRequest{}, Data: dto.SavedSearchDTO{}},
+	{Method: http.MethodGet, Path: "/api/user/searches/:id", Handler: "(*SavedSearchHandler).GetSearch", ID: "GetSearch", Tag: "searches",
+		Summary: "获取单个订阅搜索", Params: []openapi.Param{idParam}, Data: dto.SavedSearchDTO{}},
+	{Method: http.MethodPut, Path: "/api/user/searches/:id", Handler: "(*SavedSearchHandler).UpdateSearch", ID: "UpdateSearch", Tag: "searches",
+		Summary: "更新订阅搜索", Params: []openapi.Param{idParam}, Body: dto.UpdateSavedSearchRequest{}, Data: dto.SavedSearchDTO{}},
+	{Method: http.MethodDelete, Path: "/api/user/searches/:id", Handler: "(*SavedSearchHandler).DeleteSearch", ID: "DeleteSearch", Tag: "searches",
+		Summary: "删除订阅搜索", Params: []openapi.Param{idParam}},
+
+	// Devices
+	{Method: http.MethodGet, Path: "/api/devices", Handler: "(*DeviceHandler).ListDevices", ID: "ListDevices", Tag: "devices",
+		Summary: "获取已配对设备", Data: []*entity.Device{}},
+	{Method: http.MethodPost, Path: "/api/devices/pairing-code", Handler: "(*DeviceHandler).CreatePairingCode", ID: "CreatePairingCode", Tag: "devices",
+		Summary: "生成设备配对码", Data: entity.PairingCode{}},
+	{Method: http.MethodPost, Path: "/api/devices/pair", Handler: "(*DeviceHandler).Pair", ID: "PairDevice", Tag: "devices",
+		Summary: "输入配对码，与其他设备共享数据", Body: dto.PairDeviceRequest{}, Data: entity.Device{}},
+	{Method: http.MethodDelete, Path: "/api/devices/:id", Handler: "(*DeviceHandler).RevokeDevice", ID: "RevokeDevice", Tag: "devices",
+		Summary: "移除已配对设备", Params: []openapi.Param{idParam}},
+
+	// Shared watchlists
+	{Method: http.MethodGet, Path: "/api/watchlists", Handler: "(*WatchlistHandler).ListWatchlists", ID: "ListWatchlists", Tag: "watchlists",
+		Summary: "获取已加入的共享清单", Data: []*entity.Watchlist{}},
+	{Method: http.MethodPost, Path: "/api/watchlists", Handler: "(*WatchlistHandler).CreateWatchlist", ID: "CreateWatchlist", Tag: "watchlists",
+		Summary: "创建共享清单", Body: dto.WatchlistNameRequest{}, Data: entity.Watchlist{}},
+	{Method: http.MethodPost, Path: "/api/watchlists/join", Handler: "(*WatchlistHandler).JoinWatchlist", ID: "JoinWatchlist", Tag: "watchlists",
+		Summary: "通过邀请码加入清单", Body: dto.JoinWatchlistRequest{}, Data: entity.Watchlist{}},
+	{Method: http.MethodGet, Path: "/api/watchlists/:id", Handler: "(*WatchlistHandler).GetWatchlist", ID: "GetWatchlist", Tag: "watchlists",
+		Summary: "获取清单商品与成员", Params: []openapi.Param{idParam}, Data: service.WatchlistDetail{}},
+	{Method: http.MethodPut, Path: "/api/watchlists/:id", Handler: "(*WatchlistHandler).RenameWatchlist", ID: "RenameWatchlist", Tag: "watchlists",
+		Summary: "重命名清单（创建者）", Params: []openapi.Param{idParam}, Body: dto.WatchlistNameRequest{}, Data: entity.Watchlist{}},
+	{Method: http.MethodDelete, Path: "/api/watchlists/:id", Handler: "(*WatchlistHandler).DeleteWatchlist", ID: "DeleteWatchlist", Tag: "watchlists",
+		Summary: "删除清单（创建者）", Params: []openapi.Param{idParam}},
+	{Method: http.MethodPost, Path: "/api/watchlists/:id/invite", Handler: "(*WatchlistHandler).RotateInvite", ID: "RotateInvite", Tag: "watchlists",
+		Summary: "重置邀请链接（创建者）", Params: []openapi.Param{idParam}, Data: entity.Watchlist{}},
+	{Method: http.MethodPost, Path: "/api/watchlists/:id/leave", Handler: "(*WatchlistHandler).LeaveWatchlist", ID: "LeaveWatchlist", Tag: "watchlists",
+		Summary: "退出清单", Params: []openapi.Param{idParam}},
+	{Method: http.MethodDelete, Path: "/api/watchlists/:id/members/:memberId", Handler: "(*WatchlistHandler).RemoveMember", ID: "RemoveMember", Tag: "watchlists",
+		Summary: "移除成员（创建者）", Params: []openapi.Param{idParam, {Name: "memberId", In: openapi.InPath, Type: openapi.TypeInteger}}},
+	{Method: http.MethodPost, Path: "/api/watchlists/:id/items", Handler: "(*WatchlistHandler).SaveItem", ID: "SaveWatchlistItem", Tag: "watchlists",
+		Summary: "向清单添加商品", Params: []openapi.Param{idParam}, Body: dto.NotificationRuleRequest{}, Data: entity.WatchlistItem{}},
+	{Method: http.MethodPut, Path: "/api/watchlists/:id/items/:activityId", Handler: "(*WatchlistHandler).UpdateItem", ID: "UpdateWatchlistItem", Tag: "watchlists",
+		Summary: "修改清单商品的提醒规则", Params: []openapi.Param{idParam}, Body: dto.UpdateWatchlistItemRequest{}, Data: entity.WatchlistItem{}},
+	{Method: http.MethodDelete, Path: "/api/watchlists/:id/items/:activityId", Handler: "(*WatchlistHandler).DeleteItem", ID: "DeleteWatchlistItem", Tag: "watchlists",
+		Summary: "从清单移除商品", Params: []openapi.Param{idParam}},
+
+	// Administration
+	{Method: http.MethodPost, Path: "/api/admin/sync", Handler: "(*SyncHandler).TriggerSync", ID: "TriggerSync", Tag: "admin",
+		Summary: "手动触发同步（管理员）", Data: map[string]string{}},
+	{Method: http.MethodGet, Path: "/api/admin/test-api", Handler: "(*SyncHandler).TestAPI", ID: "TestAPI", Tag: "admin",
+		Summary: "测试上游接口（管理员）", Data: map[string]interface{}{},
+		Params: []openapi.Param{{Name: "region", Description: "地区，默认广州"}}},
+	{Method: http.MethodPost, Path: "/api/admin/test-notification", Handler: "(*SyncHandler).TestNotification", ID: "AdminTestNotification", Tag: "admin",
+		Summary: "测试推送通知（管理员）", Body: dto.BarkKeyRequest{}, Data: map[string]interface{}{}},
+	{Method: http.MethodGet, Path: "/api/admin/audit", Handler: "(*AuditHandler).ListAuditLogs", ID: "ListAuditLogs", Tag: "admin",
+		Summary: "查看管理操作审计日志（管理员）", Data: []*entity.AuditLog{},
+		Params: []openapi.Param{{Name: "limit", Type: openapi.TypeInteger, Description: "条数，默认 100"}}},
+	{Method: http.MethodPut, Path: "/api/admin/users/:username/role", Handler: "(*AuthHandler).SetRole", ID: "SetRole", Tag: "admin",
+		Summary: "设置账号角色（管理员）", Body: dto.SetRoleRequest{}, Data: dto.RoleDTO{}},
+
+	// Products
+	{Method: http.MethodGet, Path: "/api/search", Handler: "(*ProductHandler).Search", ID: "Search", Tag: "products",
+		Summary: "全文搜索商品标题与店铺名", Data: []dto.ProductDTO{},
+		Params: []openapi.Param{
+			{Name: "q", Required: true, Description: "关键词，多个词用空格分隔"},
+			{Name: "limit", Type: openapi.TypeInteger, Description: "条数，默认 20，最大 50"},
+		}},
+	{Method: http.MethodGet, Path: "/api/stream", Handler: "(*StreamHandler).Stream", ID: "Stream", Tag: "products",
+		Summary: "订阅价格、售卖状态与新商品的实时更新（Server-Sent Events）", Data: entity.StreamEvent{}, Stream: true,
+		Params: []openapi.Param{
+			{Name: "region", Description: "地区"},
+			{Name: "platform", Description: "平台"},
+			{Name: "watched", Description: "1 只推送已设置提醒的商品", Enum: []string{"1"}},
+			{Name: "lastEventId", Description: "从该事件之后继续推送，与 Last-Event-ID 头相同"},
+		}},
+	{Method: http.MethodGet, Path: "/api/products", Handler: "(*ProductHandler).QueryProducts", ID: "QueryProducts", Tag: "products",
+		Summary: "获取商品列表", Data: []dto.ProductDTO{}, Paged: true,
+		Params: []openapi.Param{
+			{Name: "region", Description: "地区"},
+			{Name: "platform", Description: "平台"},
+			{Name: "keyword", Description: "关键词"},
+			{Name: "salesStatus", Type: openapi.TypeInteger, Description: "1 在售，0 售罄"},
+			{Name: "monitorStatus", Description: "1 已设置提醒，0 未设置", Enum: []string{"1", "0"}},
+			{Name: "favorite", Description: "1 只看收藏", Enum: []string{"1"}},
+			{Name: "tag", Description: "按标签筛选"},
+			{Name: "minPrice", Type: openapi.TypeNumber, Description: "最低价"},
+			{Name: "maxPrice", Type: openapi.TypeNumber, Description: "最高价"},
+			{Name: "sort", Description: "排序字段；discount 与 drop_rate 按返回的 discount / dropRate 字段排序", Enum: listingSorts},
+			{Name: "order", Description: "排序方向，默认价格从低到高，其余从高到低", Enum: []string{"asc", "desc"}},
+			{Name: "limit", Type: openapi.TypeInteger, Description: "每页条数，最大 200，不传返回全部"},
+			{Name: "cursor", Description: "上一页返回的 meta.nextCursor"},
+		}},
+	{Method: http.MethodGet, Path: "/api/products/blocked", Handler: "(*ProductHandler).GetBlockedProducts", ID: "GetBlockedProducts", Tag: "products",
+		Summary: "获取已屏蔽的商品", Data: []dto.ProductDTO{}},
+	{Method: http.MethodGet, Path: "/api/products/tags", Handler: "(*ProductHandler).ListTags", ID: "ListTags", Tag: "products",
+		Summary: "获取自己用过的标签", Data: []dto.TagDTO{}},
+	{Method: http.MethodGet, Path: "/api/products/:activityId", Handler: "(*ProductHandler).GetProduct", ID: "GetProduct", Tag: "products",
+		Summary: "获取商品详情", Data: dto.ProductDetailDTO{}},
+	{Method: http.MethodGet, Path: "/api/products/:activityId/trend", Handler: "(*ProductHandler).GetPriceTrend", ID: "GetPriceTrend", Tag: "products",
+		Summary: "获取价格趋势", Data: []dto.PriceTrendDTO{}},
+	{Method: http.MethodPost, Path: "/api/products/:activityId/block", Handler: "(*ProductHandler).BlockProduct", ID: "BlockProduct", Tag: "products",
+		Summary: "屏蔽商品"},
+	{Method: http.MethodPost, Path: "/api/products/unblock/:activityId", Handler: "(*ProductHandler).UnblockProduct", ID: "UnblockProduct", Tag: "products",
+		Summary: "取消屏蔽商品"},
+	{Method: http.MethodPost, Path: "/api/products/:activityId/favorite", Handler: "(*ProductHandler).FavoriteProduct", ID: "FavoriteProduct", Tag: "products",
+		Summary: "收藏商品", Data: entity.ProductAnnotation{}},
+	{Method: http.MethodDelete, Path: "/api/products/:activityId/favorite", Handler: "(*ProductHandler).UnfavoriteProduct", ID: "UnfavoriteProduct", Tag: "products",
+		Summary: "取消收藏", Data: entity.ProductAnnotation{}},
+	{Method: http.MethodGet, Path: "/api/products/:activityId/annotation", Handler: "(*ProductHandler).GetAnnotation", ID: "GetAnnotation", Tag: "products",
+		Summary: "获取商品的收藏、标签与备注", Data: entity.ProductAnnotation{}},
+	{Method: http.MethodPut, Path: "/api/products/:activityId/annotation", Handler: "(*ProductHandler).UpdateAnnotation", ID: "UpdateAnnotation", Tag: "products",
+		Summary: "修改商品的收藏、标签与备注", Body: dto.UpdateAnnotationRequest{}, Data: entity.ProductAnnotation{}},
+	{Method: http.MethodDelete, Path: "/api/products/platform/:platform", Handler: "(*ProductHandler).ClearPlatform", ID: "ClearPlatform", Tag: "admin",
+		Summary: "清空平台数据（管理员）"},
+
+	// Price alerts
+	{Method: http.MethodPost, Path: "/api/products/notifications", Handler: "(*ProductHandler).CreateNotification", ID: "CreateNotification", Tag: "notifications",
+		Summary: "设置价格提醒", Body: dto.NotificationRuleRequest{}},
+	{Method: http.MethodPut, Path: "/api/products/notifications/:activityId", Handler: "(*ProductHandler).UpdateNotification", ID: "UpdateNotification", Tag: "notifications",
+		Summary: "更新价格提醒", Body: dto.UpdateNotificationRequest{}},
+	{Method: http.MethodDelete, Path: "/api/products/notifications/:activityId", Handler: "(*ProductHandler).DeleteNotification", ID: "DeleteNotification", Tag: "notifications",
+		Summary: "删除价格提醒"},
+
+	// External platform webhooks
+	{Method: http.MethodPost, Path: "/api/external/dt/push", Handler: "(*ExternalHandler).HandleDTPush", ID: "PushDTItems", Tag: "external",
+		Summary: "推送探探糖商品", Body: handler.DTPlatformPushRequest{}, Data: handler.DTPlatformPushResponse{}},
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  *openapi.Document
+	openAPIErr  error
+)
+
+// OpenAPIDocument returns the OpenAPI document of the routes registered by Router
+func OpenAPIDocument() (*openapi.Document, error) {
+	openAPIOnce.Do(func() {
+		openAPIDoc, openAPIErr = openapi.Build(apiInfo, apiRoutes, dto.Response{}, errorBody{})
+		if openAPIErr != nil {
+			return
+		}
+		openAPIDoc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", Description: "登录会话令牌或个人 API 令牌"},
+			"clientId":   {Type: "apiKey", In: "header", Name: middleware.UserIDHeader, Description: "匿名客户端标识"},
+		}
+		openAPIDoc.Security = []map[string][]string{{"bearerAuth": {}}, {"clientId": {}}, {}}
+	})
+	return openAPIDoc, openAPIErr
+}
+
+// serveOpenAPI handles GET /api/openapi.json
+func serveOpenAPI(c echo.Context) error {
+	doc, err := OpenAPIDocument()
+	if err != nil {
+		return apperrors.Wrap(apperrors.Unknown, "Failed to build API document", err)
+	}
+	return c.JSON(http.StatusOK, doc)
+}
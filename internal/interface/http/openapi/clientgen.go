@@ -0,0 +1,458 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names
+var initialisms = map[string]bool{
+	"api": true, "http": true, "id": true, "ip": true, "iv": true, "json": true, "uri": true, "url": true,
+}
+
+// GenerateClient renders Go source of a client for the document's operations.
+// Component schemas become types and operations become methods of Client named
+// after their operation IDs. Operations whose responses always carry meta
+// also return it.
+//
+// The target package defines Client and its request methods:
+//
+//	func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, raw bool) (*Meta, error)
+//	func (c *Client) stream(ctx context.Context, path string, query url.Values, handle func(decode func(interface{}) error) error) error
+func GenerateClient(doc *Document, pkg string) ([]byte, error) {
+	g := &clientGenerator{doc: doc}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		if name != EnvelopeSchema && name != ErrorSchema {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.writeType(name, doc.Components.Schemas[name])
+	}
+
+	refs := doc.Operations()
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].Operation.OperationID < refs[j].Operation.OperationID
+	})
+	for _, ref := range refs {
+		if err := g.writeMethod(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by TestGeneratedClient from the OpenAPI document. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg)
+	code := g.buf.String()
+	for _, imp := range []string{"context", "net/url", "strconv", "time"} {
+		short := imp[strings.LastIndex(imp, "/")+1:]
+		if imp == "context" || strings.Contains(code, short+".") {
+			fmt.Fprintf(&out, "\t%q\n", imp)
+		}
+	}
+	out.WriteString(")\n")
+	out.WriteString(code)
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format client: %w", err)
+	}
+	return source, nil
+}
+
+type clientGenerator struct {
+	doc *Document
+	buf bytes.Buffer
+}
+
+func (g *clientGenerator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// writeType writes the Go type of a component schema
+func (g *clientGenerator) writeType(name string, s *Schema) {
+	if s.Description != "" {
+		g.printf("\n// %s %s\n", name, s.Description)
+	} else {
+		g.printf("\n// %s is the %s schema of the API\n", name, name)
+	}
+	if s.Type != "object" || len(s.Properties) == 0 {
+		g.printf("type %s %s\n", name, g.goType(s))
+		return
+	}
+
+	required := make(map[string]bool)
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	g.printf("type %s struct {\n", name)
+	for _, prop := range propertyOrder(s) {
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		g.printf("\t%s %s `json:%q`\n", goName(prop), g.goType(s.Properties[prop]), tag)
+	}
+	g.printf("}\n")
+}
+
+// writeMethod writes the Client method of an operation
+func (g *clientGenerator) writeMethod(ref OperationRef) error {
+	op := ref.Operation
+	name := op.OperationID
+
+	var args, queryFields []string
+	args = append(args, "ctx context.Context")
+	pathExpr := ""
+	literal := ""
+	for _, segment := range strings.Split(strings.TrimPrefix(ref.Path, "/"), "/") {
+		literal += "/"
+		if !strings.HasPrefix(segment, "{") {
+			literal += segment
+			continue
+		}
+		param := findParameter(op, strings.Trim(segment, "{}"), InPath)
+		if param == nil {
+			return fmt.Errorf("%s: path parameter %s is not documented", name, segment)
+		}
+		arg := goArgName(param.Name)
+		pathExpr += fmt.Sprintf("%q + ", literal)
+		literal = ""
+		if param.Schema.Type == TypeInteger {
+			args = append(args, arg+" int64")
+			pathExpr += fmt.Sprintf("strconv.FormatInt(%s, 10) + ", arg)
+		} else {
+			args = append(args, arg+" string")
+			pathExpr += fmt.Sprintf("url.PathEscape(%s) + ", arg)
+		}
+	}
+	if literal != "" {
+		pathExpr += fmt.Sprintf("%q", literal)
+	} else {
+		pathExpr = strings.TrimSuffix(pathExpr, " + ")
+	}
+
+	for _, param := range op.Parameters {
+		if param.In == InQuery {
+			queryFields = append(queryFields, param.Name)
+		}
+	}
+	if len(queryFields) > 0 {
+		g.writeParamsType(name, op)
+		args = append(args, "params "+name+"Params")
+	}
+
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		args = append(args, "body "+g.goType(op.RequestBody.Content["application/json"].Schema))
+		bodyArg = "body"
+	}
+
+	if response := op.Responses["200"]; response != nil && response.Content[EventStream] != nil {
+		return g.writeStreamMethod(ref, args, pathExpr, len(queryFields) > 0, response.Content[EventStream].Schema)
+	}
+
+	data, paged, raw := g.responseData(op)
+	resultType := ""
+	if data != nil {
+		resultType = g.goType(data)
+	}
+
+	g.printf("\n// %s calls %s %s", name, ref.Method, ref.Path)
+	if op.Summary != "" {
+		g.printf(": %s", op.Summary)
+	}
+	g.printf("\n")
+
+	returns := "error"
+	switch {
+	case resultType == "":
+	case paged:
+		returns = fmt.Sprintf("(%s, *Meta, error)", resultType)
+	case isNamedStruct(resultType):
+		returns = fmt.Sprintf("(*%s, error)", resultType)
+	default:
+		returns = fmt.Sprintf("(%s, error)", resultType)
+	}
+	g.printf("func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	queryArg := "nil"
+	if len(queryFields) > 0 {
+		queryArg = "query"
+		g.printf("\tquery := url.Values{}\n")
+		for _, param := range op.Parameters {
+			if param.In == InQuery {
+				g.writeQueryParam(param)
+			}
+		}
+	}
+
+	call := fmt.Sprintf("c.do(ctx, %q, %s, %s, %s, %%s, %t)", ref.Method, pathExpr, queryArg, bodyArg, raw)
+	switch {
+	case resultType == "":
+		g.printf("\t_, err := "+call+"\n\treturn err\n", "nil")
+	case paged:
+		g.printf("\tvar out %s\n", resultType)
+		g.printf("\tmeta, err := "+call+"\n", "&out")
+		g.printf("\tif err != nil {\n\t\treturn %s, nil, err\n\t}\n\treturn out, meta, nil\n", zeroValue(resultType))
+	case isNamedStruct(resultType):
+		g.printf("\tvar out %s\n", resultType)
+		g.printf("\tif _, err := "+call+"; err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n", "&out")
+	default:
+		g.printf("\tvar out %s\n", resultType)
+		g.printf("\tif _, err := "+call+"; err != nil {\n\t\treturn %s, err\n\t}\n\treturn out, nil\n", "&out", zeroValue(resultType))
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// writeStreamMethod writes the Client method of an event stream operation,
+// which passes each event to a handler until the context is done
+func (g *clientGenerator) writeStreamMethod(ref OperationRef, args []string, pathExpr string, hasQuery bool, event *Schema) error {
+	op := ref.Operation
+	if ref.Method != "GET" || op.RequestBody != nil {
+		return fmt.Errorf("%s: event streams must be GET requests without a body", op.OperationID)
+	}
+	eventType := g.goType(event)
+
+	g.printf("\n// %s calls %s %s", op.OperationID, ref.Method, ref.Path)
+	if op.Summary != "" {
+		g.printf(": %s", op.Summary)
+	}
+	g.printf("\n// It passes each event to handle, resuming after dropped connections, until ctx\n")
+	g.printf("// is done or handle returns an error.\n")
+	args = append(args, "handle func("+eventType+") error")
+	g.printf("func (c *Client) %s(%s) error {\n", op.OperationID, strings.Join(args, ", "))
+
+	queryArg := "nil"
+	if hasQuery {
+		queryArg = "query"
+		g.printf("\tquery := url.Values{}\n")
+		for _, param := range op.Parameters {
+			if param.In == InQuery {
+				g.writeQueryParam(param)
+			}
+		}
+	}
+	g.printf("\treturn c.stream(ctx, %s, %s, func(decode func(interface{}) error) error {\n", pathExpr, queryArg)
+	g.printf("\t\tvar event %s\n", eventType)
+	g.printf("\t\tif err := decode(&event); err != nil {\n\t\t\treturn err\n\t\t}\n")
+	g.printf("\t\treturn handle(event)\n\t})\n}\n")
+	return nil
+}
+
+// writeParamsType writes the struct holding the query parameters of an operation
+func (g *clientGenerator) writeParamsType(name string, op *Operation) {
+	g.printf("\n// %sParams holds the query parameters of %s. Zero values are not sent.\n", name, name)
+	g.printf("type %sParams struct {\n", name)
+	for _, param := range op.Parameters {
+		if param.In != InQuery {
+			continue
+		}
+		g.printf("\t%s %s", goName(param.Name), queryFieldType(param))
+		if param.Description != "" {
+			g.printf(" // %s", param.Description)
+		}
+		g.printf("\n")
+	}
+	g.printf("}\n")
+}
+
+// writeQueryParam writes the statements adding a query parameter to query
+func (g *clientGenerator) writeQueryParam(param *Parameter) {
+	field := "params." + goName(param.Name)
+	switch param.Schema.Type {
+	case TypeInteger:
+		g.printf("\tif %s != nil {\n\t\tquery.Set(%q, strconv.Itoa(*%s))\n\t}\n", field, param.Name, field)
+	case TypeNumber:
+		g.printf("\tif %s != nil {\n\t\tquery.Set(%q, strconv.FormatFloat(*%s, 'f', -1, 64))\n\t}\n", field, param.Name, field)
+	case TypeBoolean:
+		g.printf("\tif %s {\n\t\tquery.Set(%q, \"true\")\n\t}\n", field, param.Name)
+	default:
+		g.printf("\tif %s != \"\" {\n\t\tquery.Set(%q, %s)\n\t}\n", field, param.Name, field)
+	}
+}
+
+// responseData returns the schema of the data of a successful response, whether
+// the response always carries meta, and whether the data is the whole body
+// rather than wrapped in the envelope
+func (g *clientGenerator) responseData(op *Operation) (data *Schema, paged, raw bool) {
+	response := op.Responses["200"]
+	if response == nil || response.Content["application/json"] == nil {
+		return nil, false, true
+	}
+	schema := response.Content["application/json"].Schema
+	if len(schema.AllOf) == 0 || refName(schema.AllOf[0]) != EnvelopeSchema {
+		return schema, false, true
+	}
+	if len(schema.AllOf) < 2 {
+		return nil, false, false
+	}
+	part := schema.AllOf[1]
+	for _, name := range part.Required {
+		if name == "meta" {
+			paged = true
+		}
+	}
+	return part.Properties["data"], paged, false
+}
+
+// goType returns the Go type of values of the schema
+func (g *clientGenerator) goType(s *Schema) string {
+	if s.Ref != "" {
+		return refName(s)
+	}
+	if len(s.AllOf) == 1 {
+		t := g.goType(s.AllOf[0])
+		if s.Nullable {
+			return "*" + t
+		}
+		return t
+	}
+
+	var t string
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			t = "time.Time"
+		case "byte":
+			t = "[]byte"
+		default:
+			t = "string"
+		}
+	case "integer":
+		t = "int"
+		if s.Format == "int64" {
+			t = "int64"
+		}
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		t = "[]" + g.goType(s.Items)
+	case "object":
+		t = "map[string]interface{}"
+		if s.AdditionalProperties != nil {
+			t = "map[string]" + g.goType(s.AdditionalProperties)
+		}
+	default:
+		return "interface{}"
+	}
+	if s.Nullable {
+		t = "*" + t
+	}
+	return t
+}
+
+// findParameter returns the parameter of an operation by name and location
+func findParameter(op *Operation, name, in string) *Parameter {
+	for _, param := range op.Parameters {
+		if param.Name == name && param.In == in {
+			return param
+		}
+	}
+	return nil
+}
+
+// queryFieldType returns the Go type of a query parameter field
+func queryFieldType(param *Parameter) string {
+	switch param.Schema.Type {
+	case TypeInteger:
+		return "*int"
+	case TypeNumber:
+		return "*float64"
+	case TypeBoolean:
+		return "bool"
+	}
+	return "string"
+}
+
+// propertyOrder returns the property names of an object schema in declaration order
+func propertyOrder(s *Schema) []string {
+	if len(s.order) == len(s.Properties) {
+		return s.order
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isNamedStruct reports whether a generated Go type is a component struct
+func isNamedStruct(t string) bool {
+	return t != "" && unicode.IsUpper(rune(t[0]))
+}
+
+// zeroValue returns the zero value expression of a generated Go type
+func zeroValue(t string) string {
+	switch t {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "int", "int64", "float64":
+		return "0"
+	}
+	return "nil"
+}
+
+// goName converts a JSON name such as activityId to an exported Go name such as ActivityID
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// goArgName converts a parameter name such as activityId to an argument name such as activityID
+func goArgName(name string) string {
+	words := splitWords(name)
+	var b strings.Builder
+	b.WriteString(strings.ToLower(words[0]))
+	for _, word := range words[1:] {
+		b.WriteString(goName(word))
+	}
+	return b.String()
+}
+
+// splitWords splits a camelCase, snake_case or kebab-case name into words
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(current) > 0:
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
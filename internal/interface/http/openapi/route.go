@@ -0,0 +1,265 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Parameter types
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// Parameter locations
+const (
+	InQuery = "query"
+	InPath  = "path"
+)
+
+// EventStream is the media type of Server-Sent Events responses
+const EventStream = "text/event-stream"
+
+// Envelope component names
+const (
+	EnvelopeSchema = "Envelope"
+	ErrorSchema    = "Error"
+)
+
+// Route documents one registered route
+type Route struct {
+	Method string
+	Path   string // Echo path, e.g. /api/products/:activityId
+	// Handler is the end of the Echo route name, e.g. (*ProductHandler).GetProduct
+	Handler string
+	ID      string // operation ID, also the name of the client method
+	Tag     string
+	Summary string
+	// Params lists the query parameters, and path parameters that are not strings
+	Params []Param
+	Body   interface{} // value of the request body type, nil for none
+	Data   interface{} // value of the response data type, nil for none
+	Paged  bool        // the response carries pagination meta
+	Raw    bool        // Data is the whole response body instead of the envelope's data
+	Stream bool        // the response is an event stream whose events carry Data values
+}
+
+// Param documents a query or path parameter
+type Param struct {
+	Name        string
+	In          string // InQuery if empty
+	Type        string // TypeString if empty
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// Build returns the document of the routes. envelope is a value of the type
+// wrapping the data of every response that is not Raw; its data field is
+// replaced by the Data type of each route. failure is a value of the error
+// response type.
+func Build(info Info, routes []Route, envelope, failure interface{}) (*Document, error) {
+	registry := newSchemaRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: registry.schemas,
+		},
+	}
+
+	envelopeSchema := registry.structSchema(reflect.TypeOf(envelope))
+	delete(envelopeSchema.Properties, "data")
+	envelopeSchema.order = without(envelopeSchema.order, "data")
+	envelopeSchema.Required = without(envelopeSchema.Required, "data")
+	registry.schemas[EnvelopeSchema] = envelopeSchema
+
+	errorSchema := registry.structSchema(reflect.TypeOf(failure))
+	registry.schemas[ErrorSchema] = errorSchema
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, route := range routes {
+		if seen[route.ID] {
+			errs = append(errs, fmt.Errorf("duplicate operation ID %s", route.ID))
+		}
+		seen[route.ID] = true
+
+		path, op, err := route.operation(registry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		method := strings.ToLower(route.Method)
+		if item[method] != nil {
+			errs = append(errs, fmt.Errorf("duplicate route %s %s", route.Method, route.Path))
+		}
+		item[method] = op
+	}
+
+	errs = append(errs, registry.errs...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return doc, nil
+}
+
+// operation converts the route to an OpenAPI path and operation
+func (r Route) operation(registry *schemaRegistry) (string, *Operation, error) {
+	op := &Operation{
+		OperationID: r.ID,
+		Summary:     r.Summary,
+		Responses:   make(map[string]*Response),
+	}
+	if r.ID == "" {
+		return "", nil, fmt.Errorf("route %s %s has no operation ID", r.Method, r.Path)
+	}
+	if r.Tag != "" {
+		op.Tags = []string{r.Tag}
+	}
+
+	pathParams := make(map[string]Param)
+	for _, p := range r.Params {
+		if p.In == InPath {
+			pathParams[p.Name] = p
+		}
+	}
+
+	// Path parameters in the order they appear in the path, then query parameters
+	segments := strings.Split(r.Path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := segment[1:]
+		p, ok := pathParams[name]
+		if !ok {
+			p = Param{Name: name, In: InPath}
+		}
+		delete(pathParams, name)
+		p.Required = true
+		op.Parameters = append(op.Parameters, p.parameter())
+		segments[i] = "{" + name + "}"
+	}
+	if len(pathParams) > 0 {
+		return "", nil, fmt.Errorf("route %s %s documents a path parameter it does not have", r.Method, r.Path)
+	}
+	for _, p := range r.Params {
+		if p.In != InPath {
+			op.Parameters = append(op.Parameters, p.parameter())
+		}
+	}
+
+	if r.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: registry.schemaOf(reflect.TypeOf(r.Body))},
+			},
+		}
+	}
+
+	var data *Schema
+	if r.Data != nil {
+		data = registry.schemaOf(reflect.TypeOf(r.Data))
+	}
+	success := &Response{Description: http.StatusText(http.StatusOK)}
+	switch {
+	case r.Stream:
+		success.Content = map[string]*MediaType{EventStream: {Schema: data}}
+	case r.Raw:
+		if data != nil {
+			success.Content = map[string]*MediaType{"application/json": {Schema: data}}
+		}
+	default:
+		body := &Schema{AllOf: []*Schema{{Ref: refPrefix + EnvelopeSchema}}}
+		if data != nil {
+			part := &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"data": data},
+				Required:   []string{"data"},
+				order:      []string{"data"},
+			}
+			if r.Paged {
+				part.Required = append(part.Required, "meta")
+			}
+			body.AllOf = append(body.AllOf, part)
+		}
+		success.Content = map[string]*MediaType{"application/json": {Schema: body}}
+	}
+	op.Responses["200"] = success
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: refPrefix + ErrorSchema}},
+		},
+	}
+
+	return strings.Join(segments, "/"), op, nil
+}
+
+// parameter converts the parameter to its OpenAPI form
+func (p Param) parameter() *Parameter {
+	typ := p.Type
+	if typ == "" {
+		typ = TypeString
+	}
+	in := p.In
+	if in == "" {
+		in = InQuery
+	}
+	return &Parameter{
+		Name:        p.Name,
+		In:          in,
+		Description: p.Description,
+		Required:    p.Required,
+		Schema:      &Schema{Type: typ, Enum: p.Enum},
+	}
+}
+
+// Operations returns the paths and methods of the document's operations in a stable order
+func (d *Document) Operations() []OperationRef {
+	var refs []OperationRef
+	for path, item := range d.Paths {
+		for method, op := range item {
+			refs = append(refs, OperationRef{Path: path, Method: strings.ToUpper(method), Operation: op})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Path != refs[j].Path {
+			return refs[i].Path < refs[j].Path
+		}
+		return refs[i].Method < refs[j].Method
+	})
+	return refs
+}
+
+// OperationRef is an operation with its path and upper-case method
+type OperationRef struct {
+	Path      string
+	Method    string
+	Operation *Operation
+}
+
+// without returns names without name
+func without(names []string, name string) []string {
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaRegistry converts Go types to schemas. Named struct types become
+// component schemas named after the type, without a DTO suffix.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	types   map[string]reflect.Type
+	errs    []error
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		types:   make(map[string]reflect.Type),
+	}
+}
+
+// schemaOf returns the schema of the JSON encoding of values of type t
+func (r *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	if t == nil || t == rawMessageType {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(r.schemaOf(t.Elem()))
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: refPrefix + r.component(t)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	}
+
+	r.errs = append(r.errs, fmt.Errorf("unsupported type %s", t))
+	return &Schema{}
+}
+
+// component registers the named struct type t and returns its component name
+func (r *schemaRegistry) component(t reflect.Type) string {
+	name := strings.TrimSuffix(t.Name(), "DTO")
+	if existing, ok := r.types[name]; ok {
+		if existing != t {
+			r.errs = append(r.errs, fmt.Errorf("schema %s is used by both %s and %s", name, existing, t))
+		}
+		return name
+	}
+
+	// Register before walking the fields so recursive types terminate
+	r.types[name] = t
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+// structSchema returns the object schema of a struct type
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON fields of struct type t to s, flattening embedded structs
+func (r *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		if _, exists := s.Properties[name]; !exists {
+			s.order = append(s.order, name)
+		}
+		s.Properties[name] = r.schemaOf(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// nullable returns a copy of s that also accepts null. References cannot carry
+// siblings in OpenAPI 3.0, so they are wrapped in allOf.
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	if s.Type == "" && s.AllOf == nil {
+		return s
+	}
+	c := *s
+	c.Nullable = true
+	return &c
+}
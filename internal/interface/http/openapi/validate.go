@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ValidateResponse checks a successful response body of the operation at the
+// OpenAPI path and method against the documented schema
+func (d *Document) ValidateResponse(method, path string, body []byte) error {
+	op := d.Paths[path][strings.ToLower(method)]
+	if op == nil {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+	response := op.Responses["200"]
+	if response == nil || response.Content["application/json"] == nil {
+		return fmt.Errorf("%s %s documents no JSON response", method, path)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return d.Validate(response.Content["application/json"].Schema, value)
+}
+
+// ValidateEvent checks the data of one event of the event stream operation at
+// the OpenAPI path and method against the documented schema
+func (d *Document) ValidateEvent(method, path string, data []byte) error {
+	op := d.Paths[path][strings.ToLower(method)]
+	if op == nil {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+	response := op.Responses["200"]
+	if response == nil || response.Content[EventStream] == nil {
+		return fmt.Errorf("%s %s documents no event stream", method, path)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("decode event: %w", err)
+	}
+	return d.Validate(response.Content[EventStream].Schema, value)
+}
+
+// Validate checks that a value decoded from JSON conforms to the schema.
+// Objects must not carry properties their schema does not declare.
+func (d *Document) Validate(schema *Schema, value interface{}) error {
+	return d.validate(schema, value, "$")
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, at string) error {
+	if schema.Ref != "" {
+		resolved := d.Resolve(schema)
+		if resolved == nil {
+			return fmt.Errorf("%s: unknown schema %s", at, schema.Ref)
+		}
+		return d.validate(resolved, value, at)
+	}
+
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && len(schema.AllOf) == 0) {
+			return nil
+		}
+		return fmt.Errorf("%s: must not be null", at)
+	}
+
+	if len(schema.AllOf) > 0 {
+		return d.validate(d.mergeAllOf(schema), value, at)
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", at)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing property %s", at, name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property := schema.Properties[name]
+			if property == nil {
+				property = schema.AdditionalProperties
+			}
+			if property == nil {
+				if len(schema.Properties) == 0 {
+					continue
+				}
+				return fmt.Errorf("%s: undocumented property %s", at, name)
+			}
+			if err := d.validate(property, object[name], at+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", at)
+		}
+		for i, item := range items {
+			if err := d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", at)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: expected date-time, got %q", at, s)
+			}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: expected integer", at)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected number", at)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", at)
+		}
+	default:
+		return fmt.Errorf("%s: unknown schema type %s", at, schema.Type)
+	}
+	return nil
+}
+
+// mergeAllOf combines the parts of an allOf schema into one schema, so that
+// the properties of every part are known when checking for undocumented ones
+func (d *Document) mergeAllOf(schema *Schema) *Schema {
+	if len(schema.AllOf) == 1 {
+		merged := *d.Resolve(schema.AllOf[0])
+		merged.Nullable = merged.Nullable || schema.Nullable
+		return &merged
+	}
+
+	merged := &Schema{Type: "object", Properties: make(map[string]*Schema), Nullable: schema.Nullable}
+	for _, part := range schema.AllOf {
+		part = d.Resolve(part)
+		if len(part.AllOf) > 0 {
+			part = d.mergeAllOf(part)
+		}
+		for name, property := range part.Properties {
+			merged.Properties[name] = property
+		}
+		merged.Required = append(merged.Required, part.Required...)
+	}
+	return merged
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEnvelope struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+	Meta    *testMeta   `json:"meta,omitempty"`
+}
+
+type testMeta struct {
+	Total int `json:"total"`
+}
+
+type testError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type testItemDTO struct {
+	ID       int64      `json:"id"`
+	Name     string     `json:"name"`
+	Tags     []string   `json:"tags"`
+	Note     *string    `json:"note"`
+	SeenTime *time.Time `json:"seenTime,omitempty"`
+}
+
+func testDocument(t *testing.T) *Document {
+	t.Helper()
+	doc, err := Build(Info{Title: "test", Version: "1"}, []Route{
+		{Method: http.MethodGet, Path: "/items", ID: "ListItems", Data: []testItemDTO{}, Paged: true},
+		{Method: http.MethodGet, Path: "/items/:id", ID: "GetItem", Data: testItemDTO{},
+			Params: []Param{{Name: "id", In: InPath, Type: TypeInteger}}},
+	}, testEnvelope{}, testError{})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return doc
+}
+
+func TestBuild(t *testing.T) {
+	doc := testDocument(t)
+
+	item := doc.Components.Schemas["testItem"]
+	if item == nil {
+		t.Fatalf("components = %v, want testItem without the DTO suffix", doc.Components.Schemas)
+	}
+	if got := strings.Join(item.Required, ","); got != "id,name,tags,note" {
+		t.Errorf("testItem required = %s", got)
+	}
+	if _, ok := doc.Components.Schemas[EnvelopeSchema].Properties["data"]; ok {
+		t.Error("Envelope documents data, want it left to each operation")
+	}
+
+	op := doc.Paths["/items/{id}"]["get"]
+	if op == nil || len(op.Parameters) != 1 || op.Parameters[0].Schema.Type != TypeInteger || !op.Parameters[0].Required {
+		t.Fatalf("GET /items/{id} = %+v", op)
+	}
+}
+
+func TestBuildRejectsUnknownPathParam(t *testing.T) {
+	_, err := Build(Info{}, []Route{
+		{Method: http.MethodGet, Path: "/items", ID: "ListItems", Params: []Param{{Name: "id", In: InPath}}},
+	}, testEnvelope{}, testError{})
+	if err == nil {
+		t.Error("Build() succeeded with a path parameter the path does not have")
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc := testDocument(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		wantErr string
+	}{
+		{"item", "/items/{id}", `{"code":200,"message":"success","data":{"id":1,"name":"a","tags":[],"note":null,"seenTime":"2026-03-01T12:00:00+08:00"}}`, ""},
+		{"page", "/items", `{"code":200,"message":"success","data":[{"id":1,"name":"a","tags":["x"],"note":"n"}],"meta":{"total":1}}`, ""},
+		{"missing meta", "/items", `{"code":200,"message":"success","data":[]}`, "missing property meta"},
+		{"null array", "/items/{id}", `{"code":200,"message":"success","data":{"id":1,"name":"a","tags":null,"note":null}}`, "$.data.tags: must not be null"},
+		{"undocumented property", "/items/{id}", `{"code":200,"message":"success","data":{"id":1,"name":"a","tags":[],"note":null,"extra":1}}`, "undocumented property extra"},
+		{"fractional integer", "/items/{id}", `{"code":200,"message":"success","data":{"id":1.5,"name":"a","tags":[],"note":null}}`, "$.data.id: expected integer"},
+		{"bad date-time", "/items/{id}", `{"code":200,"message":"success","data":{"id":1,"name":"a","tags":[],"note":null,"seenTime":"2026-03-01"}}`, "expected date-time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := doc.ValidateResponse(http.MethodGet, tt.path, []byte(tt.body))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateResponse() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateResponse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
+	"kbfood/internal/domain/service"
+	"kbfood/internal/interface/http/handler"
+	"kbfood/internal/interface/http/middleware"
+	"kbfood/internal/interface/http/openapi"
+	"kbfood/pkg/client"
+
+	"github.com/labstack/echo/v4"
+)
+
+var update = flag.Bool("update", false, "rewrite pkg/client/api_gen.go from the OpenAPI document")
+
+// generatedClientPath is the generated part of the public Go client
+const generatedClientPath = "../../../pkg/client/api_gen.go"
+
+type stubListingRepo struct {
+	repository.ProductListingRepository
+	page *repository.ProductListingPage
+}
+
+func (s *stubListingRepo) List(ctx context.Context, query repository.ProductListingQuery) (*repository.ProductListingPage, error) {
+	return s.page, nil
+}
+
+type stubTrendRepo struct {
+	repository.TrendRepository
+	trends []*entity.PriceTrend
+}
+
+func (s *stubTrendRepo) FindByActivityID(ctx context.Context, activityID string) ([]*entity.PriceTrend, error) {
+	return s.trends, nil
+}
+
+type stubNotificationRepo struct {
+	repository.NotificationRepository
+	configs []*entity.NotificationConfig
+}
+
+func (s *stubNotificationRepo) ListByUser(ctx context.Context, userID string) ([]*entity.NotificationConfig, error) {
+	return s.configs, nil
+}
+
+type stubAnnotationRepo struct {
+	repository.ProductAnnotationRepository
+	annotations []*entity.ProductAnnotation
+}
+
+func (s *stubAnnotationRepo) Find(ctx context.Context, activityID string, userID string) (*entity.ProductAnnotation, error) {
+	for _, a := range s.annotations {
+		if a.ActivityID == activityID {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubAnnotationRepo) ListByUser(ctx context.Context, userID string) ([]*entity.ProductAnnotation, error) {
+	return s.annotations, nil
+}
+
+// newTestRouter returns the router with product and stream handlers backed by
+// stubs and zero values for every other handler
+func newTestRouter(stream *service.StreamService) *echo.Echo {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	referencePrice := 39.9
+	listing := &stubListingRepo{page: &repository.ProductListingPage{
+		Products: []*repository.ProductListing{
+			{
+				MasterProduct: &entity.MasterProduct{
+					ID: "act-1", Region: "广州", Platform: "探探糖", StandardTitle: "双人套餐",
+					Price: 29.9, Status: entity.SalesStatusOnSale, TrustScore: 3, CreateTime: now, UpdateTime: now,
+				},
+				OriginalPrice: 49.9,
+			},
+			{
+				MasterProduct: &entity.MasterProduct{
+					ID: "act-2", Region: "广州", Platform: "探探糖", StandardTitle: "单人餐",
+					Price: 9.9, CreateTime: now, UpdateTime: now,
+				},
+			},
+		},
+		Total:      3,
+		NextCursor: &repository.ListingCursor{Value: "2026-03-01", ID: "act-2"},
+	}}
+	trends := &stubTrendRepo{trends: []*entity.PriceTrend{
+		{ID: 1, ActivityID: "act-1", Price: 29.9, RecordDate: now, CreateTime: now},
+	}}
+	notifications := &stubNotificationRepo{configs: []*entity.NotificationConfig{
+		{ActivityID: "act-1", UserID: "client-1", RuleType: entity.RuleTypeTargetPrice, TargetPrice: 25, ReferencePrice: &referencePrice, CreateTime: now, UpdateTime: now},
+	}}
+	annotations := &stubAnnotationRepo{annotations: []*entity.ProductAnnotation{
+		{ActivityID: "act-1", UserID: "client-1", Favorite: true, Tags: []string{"午餐"}, Note: "周末去", UpdateTime: now},
+	}}
+	productHandler := handler.NewProductHandler(nil, nil, notifications, nil, trends, nil, annotations, listing, nil, nil)
+	streamHandler := handler.NewStreamHandler(stream, notifications, 20*time.Millisecond)
+
+	return Router(productHandler, &handler.ExternalHandler{}, &handler.SyncHandler{}, &handler.StatusHandler{},
+		&handler.UserHandler{}, &handler.SavedSearchHandler{}, &handler.AuthHandler{}, &handler.AuditHandler{},
+		&handler.DeviceHandler{}, &handler.WatchlistHandler{}, streamHandler, &handler.FeedHandler{}, nil, nil, true, false, nil, RateLimits{}, nil, fstest.MapFS{})
+}
+
+func TestOpenAPIDocumentCoversRouter(t *testing.T) {
+	if _, err := OpenAPIDocument(); err != nil {
+		t.Fatalf("OpenAPIDocument() error = %v", err)
+	}
+
+	documented := make(map[string]openapi.Route)
+	for _, route := range apiRoutes {
+		documented[route.Method+" "+route.Path] = route
+	}
+
+	methods := map[string]bool{
+		http.MethodGet: true, http.MethodPost: true, http.MethodPut: true,
+		http.MethodPatch: true, http.MethodDelete: true,
+	}
+	registered := make(map[string]bool)
+	for _, route := range newTestRouter(service.NewStreamService(10)).Routes() {
+		if !methods[route.Method] {
+			continue
+		}
+		if !strings.HasPrefix(route.Path, "/api/") && route.Path != "/health" && route.Path != "/ready" {
+			// Static frontend files, Atom feeds and the metrics endpoint
+			continue
+		}
+		// Routes with a trailing slash are aliases of the route without one
+		key := route.Method + " " + strings.TrimSuffix(route.Path, "/")
+		registered[key] = true
+
+		doc, ok := documented[key]
+		if !ok {
+			t.Errorf("route %s is not documented in apiRoutes", key)
+			continue
+		}
+		if !strings.HasSuffix(strings.TrimSuffix(route.Name, "-fm"), doc.Handler) {
+			t.Errorf("route %s is handled by %s, documented as %s", key, route.Name, doc.Handler)
+		}
+	}
+
+	for key := range documented {
+		if !registered[key] {
+			t.Errorf("documented route %s is not registered by Router", key)
+		}
+	}
+}
+
+func TestOpenAPIResponsesMatchHandlers(t *testing.T) {
+	doc, err := OpenAPIDocument()
+	if err != nil {
+		t.Fatalf("OpenAPIDocument() error = %v", err)
+	}
+	e := newTestRouter(service.NewStreamService(10))
+
+	tests := []struct {
+		target string
+		path   string // OpenAPI path of the operation
+	}{
+		{"/api/products?limit=2&sort=price", "/api/products"},
+		{"/api/products/", "/api/products"},
+		{"/api/products/act-1/trend", "/api/products/{activityId}/trend"},
+		{"/api/products/act-1/annotation", "/api/products/{activityId}/annotation"},
+		{"/api/products/act-2/annotation", "/api/products/{activityId}/annotation"},
+		{"/api/products/tags", "/api/products/tags"},
+		{"/api/openapi.json", "/api/openapi.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set(middleware.UserIDHeader, "client-1")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+			}
+			if err := doc.ValidateResponse(http.MethodGet, tt.path, rec.Body.Bytes()); err != nil {
+				t.Errorf("response does not match the document: %v\nbody = %s", err, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGeneratedClient(t *testing.T) {
+	doc, err := OpenAPIDocument()
+	if err != nil {
+		t.Fatalf("OpenAPIDocument() error = %v", err)
+	}
+	generated, err := openapi.GenerateClient(doc, "client")
+	if err != nil {
+		t.Fatalf("GenerateClient() error = %v", err)
+	}
+
+	path := filepath.FromSlash(generatedClientPath)
+	if *update {
+		if err := os.WriteFile(path, generated, 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		return
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if !bytes.Equal(current, generated) {
+		t.Errorf("%s is out of date; run go test ./internal/interface/http -run TestGeneratedClient -update", path)
+	}
+}
+
+func TestClientCallsRouter(t *testing.T) {
+	server := httptest.NewServer(newTestRouter(service.NewStreamService(10)))
+	defer server.Close()
+	c := client.New(server.URL, client.WithUserID("client-1"))
+	ctx := context.Background()
+
+	limit := 2
+	products, meta, err := c.QueryProducts(ctx, client.QueryProductsParams{Limit: &limit, Sort: "price"})
+	if err != nil {
+		t.Fatalf("QueryProducts() error = %v", err)
+	}
+	if len(products) != 2 || products[0].ActivityID != "act-1" {
+		t.Errorf("QueryProducts() products = %+v", products)
+	}
+	if meta == nil || meta.Total != 3 || meta.NextCursor == "" {
+		t.Errorf("QueryProducts() meta = %+v", meta)
+	}
+
+	annotation, err := c.GetAnnotation(ctx, "act-1")
+	if err != nil {
+		t.Fatalf("GetAnnotation() error = %v", err)
+	}
+	if !annotation.Favorite || len(annotation.Tags) != 1 || annotation.Tags[0] != "午餐" {
+		t.Errorf("GetAnnotation() = %+v", annotation)
+	}
+
+	_, err = c.Search(ctx, client.SearchParams{Q: " "})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message == "" {
+		t.Errorf("Search() without keywords error = %v", err)
+	}
+}
+
+// publishWhenListening publishes the events once the stream has a subscriber
+func publishWhenListening(t *testing.T, stream *service.StreamService, events ...entity.StreamEvent) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for stream.SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Error("no stream subscriber connected")
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, e := range events {
+		stream.Publish(e)
+	}
+}
+
+func TestStreamEventsMatchDocument(t *testing.T) {
+	doc, err := OpenAPIDocument()
+	if err != nil {
+		t.Fatalf("OpenAPIDocument() error = %v", err)
+	}
+	stream := service.NewStreamService(10)
+	server := httptest.NewServer(newTestRouter(stream))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/stream?watched=1", nil)
+	req.Header.Set(middleware.UserIDHeader, "client-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/stream error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get(echo.HeaderContentType) != openapi.EventStream {
+		t.Fatalf("status = %d, content type = %s", resp.StatusCode, resp.Header.Get(echo.HeaderContentType))
+	}
+
+	price, status := 25.0, entity.SalesStatusOnSale
+	go publishWhenListening(t, stream,
+		// Not watched by client-1
+		entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: "act-2", Region: "广州", Price: &price},
+		entity.StreamEvent{Type: entity.StreamEventStatus, ActivityID: "act-1", Region: "广州", Platform: "探探糖", Title: "双人套餐", Status: &status, OldStatus: new(int)},
+		entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: "act-2", Region: "广州", Price: &price},
+	)
+
+	// The watched event is sent, and the heartbeat after the filtered one carries its ID
+	var events, ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && len(ids) < 2 {
+		field, value, _ := strings.Cut(scanner.Text(), ": ")
+		switch field {
+		case "data":
+			events = append(events, value)
+		case "id":
+			ids = append(ids, value)
+		}
+	}
+	if len(events) != 1 || len(ids) != 2 || !strings.HasSuffix(ids[0], "-2") || !strings.HasSuffix(ids[1], "-3") {
+		t.Fatalf("events = %v, ids = %v", events, ids)
+	}
+	if err := doc.ValidateEvent(http.MethodGet, "/api/stream", []byte(events[0])); err != nil {
+		t.Errorf("event does not match the document: %v\nevent = %s", err, events[0])
+	}
+}
+
+func TestClientStream(t *testing.T) {
+	stream := service.NewStreamService(10)
+	server := httptest.NewServer(newTestRouter(stream))
+	defer server.Close()
+	c := client.New(server.URL, client.WithUserID("client-1"))
+
+	price := 25.0
+	go publishWhenListening(t, stream,
+		entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: "act-3", Region: "佛山", Price: &price},
+		entity.StreamEvent{Type: entity.StreamEventPrice, ActivityID: "act-1", Region: "广州", Price: &price},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stop := errors.New("stop")
+	var received []client.StreamEvent
+	err := c.Stream(ctx, client.StreamParams{Region: "广州"}, func(e client.StreamEvent) error {
+		received = append(received, e)
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("Stream() error = %v, want the handler's error", err)
+	}
+	if len(received) != 1 || received[0].ActivityID != "act-1" || received[0].Price == nil || *received[0].Price != 25 {
+		t.Errorf("Stream() received %+v", received)
+	}
+
+	// Rejected requests are not retried
+	anonymous := client.New(server.URL)
+	err = anonymous.Stream(ctx, client.StreamParams{Watched: "1"}, func(client.StreamEvent) error { return nil })
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Stream() without a user error = %v, want 400", err)
+	}
+}
@@ -1,15 +1,28 @@
 package http
 
 import (
-	"strings"
+	"io/fs"
 
+	"kbfood/internal/domain/entity"
+	"kbfood/internal/domain/repository"
 	"kbfood/internal/infra/db"
 	"kbfood/internal/interface/http/handler"
 	"kbfood/internal/interface/http/middleware"
+	applog "kbfood/internal/pkg/logger"
+	"kbfood/internal/pkg/metrics"
 
 	"github.com/labstack/echo/v4"
 )
 
+// RateLimits holds the per-client rate limits of the route groups.
+// Admin, external and pairing limits apply on top of the default API limit.
+type RateLimits struct {
+	Default  middleware.RateLimitRule
+	Admin    middleware.RateLimitRule
+	External middleware.RateLimitRule
+	Pairing  middleware.RateLimitRule
+}
+
 // Router returns the HTTP router
 func Router(
 	productHandler *handler.ProductHandler,
@@ -17,7 +30,21 @@ func Router(
 	syncHandler *handler.SyncHandler,
 	statusHandler *handler.StatusHandler,
 	userHandler *handler.UserHandler,
+	savedSearchHandler *handler.SavedSearchHandler,
+	authHandler *handler.AuthHandler,
+	auditHandler *handler.AuditHandler,
+	deviceHandler *handler.DeviceHandler,
+	watchlistHandler *handler.WatchlistHandler,
+	streamHandler *handler.StreamHandler,
+	feedHandler *handler.FeedHandler,
+	authenticator middleware.Authenticator,
+	deviceResolver middleware.DeviceResolver,
+	allowAnonymous bool,
+	legacyMigration bool,
+	auditLogRepo repository.AuditLogRepository,
+	rateLimits RateLimits,
 	database *db.Pool,
+	frontend fs.FS,
 ) *echo.Echo {
 	e := echo.New()
 
@@ -27,48 +54,143 @@ func Router(
 	// Hide Echo banner
 	e.HideBanner = true
 
+	// Only trust X-Forwarded-For from private networks (the nginx proxy), so
+	// clients cannot pick their own IP to dodge rate limits
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
+
 	// Global middleware
 	e.Use(middleware.Recovery())
+	// Request-scoped logger with a request ID, returned in X-Request-ID
+	e.Use(echo.WrapMiddleware(applog.Middleware))
+	e.Use(echo.WrapMiddleware(applog.RequestIDHandler))
+	e.Use(middleware.Metrics())
+	e.Use(middleware.Tracing())
 	e.Use(middleware.Logger())
 	e.Use(middleware.CORS())
-	e.Use(middleware.UserExtractor())
-	e.Use(middleware.UserDataMigrator(database))
+	e.Use(middleware.UserExtractor(authenticator, deviceResolver, allowAnonymous))
+	if legacyMigration {
+		// Per-request fallback for legacy clients; -migrate-legacy-users does this in bulk
+		e.Use(middleware.UserDataMigrator(database))
+	}
 
 	// Health check (no auth required)
 	e.GET("/health", healthHandler.Health)
 	e.GET("/ready", healthHandler.Ready)
 
+	// Prometheus scrape endpoint; nginx does not proxy it, so it is only
+	// reachable on the backend port
+	e.GET("/metrics", echo.WrapHandler(metrics.Default.Handler()))
+
+	// Admin-only actions are recorded in the audit log
+	adminOnly := []echo.MiddlewareFunc{
+		middleware.RequireRole(entity.RoleAdmin),
+		middleware.RateLimit(rateLimits.Admin),
+		middleware.AuditLogger(auditLogRepo),
+	}
+
 	// API routes
-	api := e.Group("/api")
+	api := e.Group("/api", middleware.RateLimit(rateLimits.Default))
 	{
 		// System status
 		api.GET("/status", statusHandler.GetStatus)
+		api.GET("/openapi.json", serveOpenAPI)
+
+		// Account routes
+		auth := api.Group("/auth")
+		{
+			auth.POST("/signup", authHandler.SignUp)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/logout", authHandler.Logout)
+			auth.GET("/me", authHandler.Me)
+			auth.POST("/claim", authHandler.ClaimClient)
+			auth.GET("/tokens", authHandler.ListTokens)
+			auth.POST("/tokens", authHandler.CreateToken)
+			auth.DELETE("/tokens/:id", authHandler.RevokeToken)
+		}
 
 		// User routes
 		user := api.Group("/user")
 		{
 			user.GET("/settings", userHandler.GetSettings)
 			user.POST("/settings", userHandler.SaveSettings)
+			user.GET("/preferences", userHandler.GetPreferences)
+			user.PUT("/preferences", userHandler.SavePreferences)
+			user.GET("/digest", userHandler.GetDigest)
+			user.POST("/test-notification", userHandler.TestNotification)
+			user.GET("/export", userHandler.ExportData)
+			user.POST("/import", userHandler.ImportData)
+
+			// Private Atom feed token
+			user.GET("/feed", feedHandler.GetFeedToken)
+			user.POST("/feed", feedHandler.RotateFeedToken)
+
+			// Saved search subscriptions
+			user.GET("/searches", savedSearchHandler.ListSearches)
+			user.POST("/searches", savedSearchHandler.CreateSearch)
+			user.GET("/searches/:id", savedSearchHandler.GetSearch)
+			user.PUT("/searches/:id", savedSearchHandler.UpdateSearch)
+			user.DELETE("/searches/:id", savedSearchHandler.DeleteSearch)
+		}
+
+		// Device pairing routes. Pairing codes are short, so entering them is
+		// limited more strictly to prevent guessing.
+		devices := api.Group("/devices")
+		{
+			devices.GET("", deviceHandler.ListDevices)
+			devices.POST("/pairing-code", deviceHandler.CreatePairingCode)
+			devices.POST("/pair", deviceHandler.Pair, middleware.RateLimit(rateLimits.Pairing))
+			devices.DELETE("/:id", deviceHandler.RevokeDevice)
 		}
 
-		// Admin routes (for manual operations)
-		admin := api.Group("/admin")
+		// Shared watchlist routes
+		watchlists := api.Group("/watchlists")
+		{
+			watchlists.GET("", watchlistHandler.ListWatchlists)
+			watchlists.POST("", watchlistHandler.CreateWatchlist)
+			watchlists.POST("/join", watchlistHandler.JoinWatchlist)
+			watchlists.GET("/:id", watchlistHandler.GetWatchlist)
+			watchlists.PUT("/:id", watchlistHandler.RenameWatchlist)
+			watchlists.DELETE("/:id", watchlistHandler.DeleteWatchlist)
+			watchlists.POST("/:id/invite", watchlistHandler.RotateInvite)
+			watchlists.POST("/:id/leave", watchlistHandler.LeaveWatchlist)
+			watchlists.DELETE("/:id/members/:memberId", watchlistHandler.RemoveMember)
+			watchlists.POST("/:id/items", watchlistHandler.SaveItem)
+			watchlists.PUT("/:id/items/:activityId", watchlistHandler.UpdateItem)
+			watchlists.DELETE("/:id/items/:activityId", watchlistHandler.DeleteItem)
+		}
+
+		// Admin routes (for manual operations, admin role required)
+		admin := api.Group("/admin", adminOnly...)
 		{
 			admin.POST("/sync", syncHandler.TriggerSync)
 			admin.GET("/test-api", syncHandler.TestAPI)
 			admin.POST("/test-notification", syncHandler.TestNotification)
+			admin.GET("/audit", auditHandler.ListAuditLogs)
+			admin.PUT("/users/:username/role", authHandler.SetRole)
 		}
 
+		// Full-text product search
+		api.GET("/search", productHandler.Search)
+
+		// Live product updates (Server-Sent Events)
+		api.GET("/stream", streamHandler.Stream)
+
 		// Product routes
 		products := api.Group("/products")
 		{
 			products.GET("", productHandler.QueryProducts)
 			products.GET("/", productHandler.QueryProducts)
 			products.GET("/blocked", productHandler.GetBlockedProducts)
+			products.GET("/tags", productHandler.ListTags)
+			products.GET("/:activityId", productHandler.GetProduct)
 			products.GET("/:activityId/trend", productHandler.GetPriceTrend)
 			products.POST("/:activityId/block", productHandler.BlockProduct)
 			products.POST("/unblock/:activityId", productHandler.UnblockProduct)
-			products.DELETE("/platform/:platform", productHandler.ClearPlatform)
+			products.POST("/:activityId/favorite", productHandler.FavoriteProduct)
+			products.DELETE("/:activityId/favorite", productHandler.UnfavoriteProduct)
+			products.GET("/:activityId/annotation", productHandler.GetAnnotation)
+			products.PUT("/:activityId/annotation", productHandler.UpdateAnnotation)
+			products.DELETE("/platform/:platform", productHandler.ClearPlatform, adminOnly...)
 
 			// Notification routes - support both with and without trailing slash
 			products.POST("/notifications", productHandler.CreateNotification)
@@ -78,50 +200,26 @@ func Router(
 		}
 
 		// External platform routes (webhooks)
-		external := api.Group("/external")
+		external := api.Group("/external", middleware.RateLimit(rateLimits.External))
 		{
 			external.POST("/dt/push", externalHandler.HandleDTPush)
 		}
 	}
 
-	// Serve frontend static files
-	// "static" directory contains the built frontend (from Docker build or npm run build)
-	staticDir := "static"
-
-	// Serve static assets (JS, CSS, images, etc.)
-	e.Static("/assets", staticDir+"/assets")
-
-	// Serve specific static files
-	e.File("/vite.svg", staticDir+"/vite.svg")
-
-	// SPA fallback: serve index.html for all unmatched routes
-	// This handles client-side routing (React Router, etc.)
-	e.HTTPErrorHandler = func(err error, c echo.Context) {
-		he, ok := err.(*echo.HTTPError)
-		if !ok {
-			he = &echo.HTTPError{Code: 500, Message: err.Error()}
-		}
+	// Atom feeds for feed readers; private feeds are identified by the token in the URL
+	feeds := e.Group("/feeds", middleware.RateLimit(rateLimits.Default))
+	{
+		feeds.GET("/drops.atom", feedHandler.Drops)
+		feeds.GET("/new.atom", feedHandler.NewArrivals)
+		feeds.GET("/user/:token/drops.atom", feedHandler.WatchedDrops)
+	}
 
-		// Check if it's a 404 error for non-API routes
-		if he.Code == 404 {
-			path := c.Request().URL.Path
-			// Don't serve index.html for API or health routes that don't exist
-			if !strings.HasPrefix(path, "/api") &&
-				!strings.HasPrefix(path, "/health") &&
-				!strings.HasPrefix(path, "/ready") {
-				// Serve index.html for SPA routing
-				if fileErr := c.File(staticDir + "/index.html"); fileErr == nil {
-					return
-				}
-			}
-		}
+	// Hashed frontend assets (JS, CSS, images, etc.) are cached forever
+	e.GET("/assets/*", serveAssets(frontend))
 
-		// Default error handler
-		c.JSON(he.Code, map[string]interface{}{
-			"code":    he.Code,
-			"message": he.Message,
-		})
-	}
+	// Errors are rendered as {code, message}; other frontend files and
+	// client-side routes (React Router, etc.) are served by the error handler
+	e.HTTPErrorHandler = errorHandler(frontend)
 
 	return e
 }
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cache-Control values of the frontend files. Vite puts a content hash in the
+// names of the files under /assets, so a name always has the same content;
+// index.html and the files copied from public/ keep their names across builds.
+const (
+	immutableCacheControl  = "public, max-age=31536000, immutable"
+	revalidateCacheControl = "no-cache"
+)
+
+// frontendIndex is the entry point of the single-page app
+const frontendIndex = "index.html"
+
+// serveAssets handles GET /assets/*, the hashed JS, CSS and image files
+func serveAssets(frontend fs.FS) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name, err := url.PathUnescape(c.Param("*"))
+		if err != nil {
+			return echo.ErrNotFound
+		}
+		name = path.Join("assets", name)
+		if !strings.HasPrefix(name, "assets/") || !isFile(frontend, name) {
+			return echo.ErrNotFound
+		}
+		c.Response().Header().Set(echo.HeaderCacheControl, immutableCacheControl)
+		return echo.StaticFileHandler(name, frontend)(c)
+	}
+}
+
+// serveFrontend serves the file of the frontend at the request path, or
+// index.html for client-side routes. It reports false if the frontend has
+// neither, for example when it was not built into the binary.
+func serveFrontend(c echo.Context, frontend fs.FS) bool {
+	if frontend == nil {
+		return false
+	}
+
+	name := strings.TrimPrefix(path.Clean(c.Request().URL.Path), "/")
+	if name == "" || !isFile(frontend, name) {
+		name = frontendIndex
+	}
+	if !isFile(frontend, name) {
+		return false
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, revalidateCacheControl)
+	return echo.StaticFileHandler(name, frontend)(c) == nil
+}
+
+// isFile reports whether name is a regular file of fsys
+func isFile(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestFrontendRoutes(t *testing.T) {
+	frontend := fstest.MapFS{
+		"index.html":          {Data: []byte("<html>app</html>")},
+		"vite.svg":            {Data: []byte("<svg/>")},
+		"assets/index-3f.js":  {Data: []byte("console.log(1)")},
+		"assets/index-3f.css": {Data: []byte("body{}")},
+	}
+	e := echo.New()
+	e.GET("/assets/*", serveAssets(frontend))
+	e.HTTPErrorHandler = errorHandler(frontend)
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		wantStatus       int
+		wantCacheControl string
+		wantBody         string
+	}{
+		{name: "hashed asset", path: "/assets/index-3f.js", wantStatus: http.StatusOK, wantCacheControl: immutableCacheControl, wantBody: "console.log(1)"},
+		{name: "missing asset", path: "/assets/index-00.js", wantStatus: http.StatusNotFound},
+		{name: "public file", path: "/vite.svg", wantStatus: http.StatusOK, wantCacheControl: revalidateCacheControl, wantBody: "<svg/>"},
+		{name: "root", path: "/", wantStatus: http.StatusOK, wantCacheControl: revalidateCacheControl, wantBody: "app"},
+		{name: "client-side route", path: "/watchlists/3", wantStatus: http.StatusOK, wantCacheControl: revalidateCacheControl, wantBody: "app"},
+		{name: "unknown API route", path: "/api/unknown", wantStatus: http.StatusNotFound},
+		{name: "non-GET request", method: http.MethodPost, path: "/watchlists/3", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get(echo.HeaderCacheControl); got != tt.wantCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCacheControl)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
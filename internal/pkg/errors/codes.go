@@ -1,5 +1,7 @@
 package errors
 
+import "strings"
+
 // ErrorCode represents application-specific error codes
 type ErrorCode int
 
@@ -9,12 +11,19 @@ const (
 
 	// Input validation errors (10xxx)
 	InvalidInput ErrorCode = 10001
-	NotFound    ErrorCode = 10002
+	NotFound     ErrorCode = 10002
+
+	// Request errors (10xxx)
+	Unauthorized  ErrorCode = 10003
+	Forbidden     ErrorCode = 10004
+	Conflict      ErrorCode = 10005
+	RateLimited   ErrorCode = 10006
+	MissingUserID ErrorCode = 10007
 
 	// Price validation errors (20xxx)
-	ErrPriceBelowMin      ErrorCode = 20001
-	ErrPriceDropExceeded  ErrorCode = 20002
-	ErrPriceRiseExceeded  ErrorCode = 20003
+	ErrPriceBelowMin     ErrorCode = 20001
+	ErrPriceDropExceeded ErrorCode = 20002
+	ErrPriceRiseExceeded ErrorCode = 20003
 
 	// Platform API errors (30xxx)
 	ErrPlatformAPI     ErrorCode = 30001
@@ -22,9 +31,9 @@ const (
 	ErrPlatformAuth    ErrorCode = 30003
 
 	// Database errors (40xxx)
-	ErrDatabase      ErrorCode = 40001
-	ErrDuplicate     ErrorCode = 40002
-	ErrForeignKey     ErrorCode = 40003
+	ErrDatabase   ErrorCode = 40001
+	ErrDuplicate  ErrorCode = 40002
+	ErrForeignKey ErrorCode = 40003
 
 	// Notification errors (50xxx)
 	ErrNotificationSend ErrorCode = 50001
@@ -33,15 +42,25 @@ const (
 // HTTPStatus returns the appropriate HTTP status code for an error code
 func (c ErrorCode) HTTPStatus() int {
 	switch c {
-	case InvalidInput:
+	case InvalidInput, MissingUserID:
 		return 400
+	case Unauthorized:
+		return 401
+	case Forbidden:
+		return 403
 	case NotFound:
 		return 404
+	case Conflict, ErrDuplicate, ErrForeignKey:
+		return 409
+	case RateLimited:
+		return 429
+	case ErrPriceBelowMin, ErrPriceDropExceeded, ErrPriceRiseExceeded:
+		return 422
 	case ErrPlatformAuth:
 		return 401
-	case ErrDatabase, ErrDuplicate, ErrForeignKey:
+	case ErrDatabase:
 		return 500
-	case ErrPlatformAPI, ErrPlatformTimeout:
+	case ErrPlatformAPI, ErrPlatformTimeout, ErrNotificationSend:
 		return 502
 	default:
 		return 500
@@ -55,6 +74,16 @@ func (c ErrorCode) Message() string {
 		return "Invalid input"
 	case NotFound:
 		return "Resource not found"
+	case Unauthorized:
+		return "Authentication required"
+	case Forbidden:
+		return "Permission denied"
+	case Conflict:
+		return "Conflicts with the current state"
+	case RateLimited:
+		return "Too many requests, please try again later"
+	case MissingUserID:
+		return "Missing user ID, please reload the page and try again"
 	case ErrPriceBelowMin:
 		return "Price is below minimum threshold"
 	case ErrPriceDropExceeded:
@@ -79,3 +108,78 @@ func (c ErrorCode) Message() string {
 		return "Unknown error"
 	}
 }
+
+// CodeForStatus returns the error code of an HTTP error status, for errors
+// such as unknown routes that are raised by the framework rather than the app
+func CodeForStatus(status int) ErrorCode {
+	switch status {
+	case 400:
+		return InvalidInput
+	case 401:
+		return Unauthorized
+	case 403:
+		return Forbidden
+	case 404:
+		return NotFound
+	case 409:
+		return Conflict
+	case 429:
+		return RateLimited
+	}
+	if status < 500 {
+		return InvalidInput
+	}
+	return Unknown
+}
+
+// messagesZH are the Chinese messages of the error codes
+var messagesZH = map[ErrorCode]string{
+	Unknown:              "服务器内部错误，请稍后再试",
+	InvalidInput:         "请求参数无效",
+	NotFound:             "资源不存在",
+	Unauthorized:         "请先登录",
+	Forbidden:            "没有权限执行此操作",
+	Conflict:             "操作与当前状态冲突",
+	RateLimited:          "请求过于频繁，请稍后再试",
+	MissingUserID:        "用户标识缺失，请刷新页面后重试",
+	ErrPriceBelowMin:     "价格低于最低阈值",
+	ErrPriceDropExceeded: "单次降价幅度过大",
+	ErrPriceRiseExceeded: "单次涨价幅度过大",
+	ErrPlatformAPI:       "平台接口错误",
+	ErrPlatformTimeout:   "平台接口超时",
+	ErrPlatformAuth:      "平台认证失败",
+	ErrDatabase:          "数据存储错误，请稍后再试",
+	ErrDuplicate:         "记录已存在",
+	ErrForeignKey:        "关联的记录不存在",
+	ErrNotificationSend:  "推送发送失败",
+}
+
+// LocalizedMessage returns the message of the error code in lang, "zh" or
+// "en"; other languages get Chinese
+func (c ErrorCode) LocalizedMessage(lang string) string {
+	if lang == LangEN {
+		return c.Message()
+	}
+	if message, ok := messagesZH[c]; ok {
+		return message
+	}
+	return messagesZH[Unknown]
+}
+
+// Languages of error messages
+const (
+	LangZH = "zh"
+	LangEN = "en"
+)
+
+// Language returns the message language preferred by an Accept-Language
+// header: English if the first language is English, Chinese otherwise
+func Language(acceptLanguage string) string {
+	first, _, _ := strings.Cut(acceptLanguage, ",")
+	first, _, _ = strings.Cut(first, ";")
+	first = strings.ToLower(strings.TrimSpace(first))
+	if first == "en" || strings.HasPrefix(first, "en-") {
+		return LangEN
+	}
+	return LangZH
+}
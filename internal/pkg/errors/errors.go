@@ -51,6 +51,20 @@ func Wrapf(code ErrorCode, format string, cause error, args ...interface{}) *App
 	}
 }
 
+// Ensure returns the AppError in the chain of err, so that errors already
+// classified by a service keep their code, or wraps err with code and message.
+// It returns nil for a nil error.
+func Ensure(err error, code ErrorCode, message string) *AppError {
+	if err == nil {
+		return nil
+	}
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Wrap(code, message, err)
+}
+
 // IsNotFound checks if an error is a "not found" error
 func IsNotFound(err error) bool {
 	var appErr *AppError
@@ -65,8 +79,14 @@ func IsInvalidInput(err error) bool {
 
 // Predefined errors for common cases
 var (
-	ErrProductNotFound   = New(NotFound, "Product not found")
-	ErrInvalidPrice      = New(InvalidInput, "Invalid price value")
-	ErrInvalidDateRange  = New(InvalidInput, "Invalid date range")
-	ErrInvalidPagination = New(InvalidInput, "Invalid pagination parameters")
+	ErrProductNotFound   = New(NotFound, "商品不存在")
+	ErrInvalidPrice      = New(InvalidInput, "价格无效")
+	ErrInvalidDateRange  = New(InvalidInput, "日期范围无效")
+	ErrInvalidPagination = New(InvalidInput, "分页参数无效")
+
+	// Errors of every handler that needs a user or a valid body
+	ErrMissingUserID     = New(MissingUserID, "用户标识缺失，请刷新页面后重试")
+	ErrMissingActivityID = New(InvalidInput, "缺少商品 activityId")
+	ErrLoginRequired     = New(Unauthorized, "请先登录")
+	ErrInvalidRequest    = New(InvalidInput, "请求内容格式错误")
 )
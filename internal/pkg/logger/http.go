@@ -3,16 +3,25 @@ package logger
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/rs/xid"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/hlog"
+	"github.com/rs/zerolog/log"
 )
 
 // RequestIDHeader is the header name for request ID
 const RequestIDHeader = "X-Request-ID"
 
-// Middleware returns a logging middleware for chi
+// TraceparentHeader is the W3C trace context header
+const TraceparentHeader = "traceparent"
+
+// maxRequestIDLength bounds request IDs taken from request headers
+const maxRequestIDLength = 128
+
+// Middleware adds a copy of the global logger to the request context
 func Middleware(next http.Handler) http.Handler {
 	return hlog.NewHandler(Logger)(next)
 }
@@ -31,24 +40,94 @@ func AccessHandler(next http.Handler) http.Handler {
 	})(next)
 }
 
-// RequestIDHandler adds a request ID to the context
+// RequestIDHandler adds a request ID to the context and to the logger of the
+// request, and returns it in the X-Request-ID response header. The ID is taken
+// from the X-Request-ID request header, or else from the trace ID of the W3C
+// traceparent header, so that it matches the logs of the caller; a new ID is
+// generated otherwise. Middleware must run first so that the request has a
+// logger to add the ID to.
 func RequestIDHandler(next http.Handler) http.Handler {
-	return hlog.RequestIDHandler(RequestIDHeader, "req-id")(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := incomingRequestID(r)
+		if id == "" {
+			generated := xid.New()
+			ctx = hlog.CtxWithID(ctx, generated)
+			id = generated.String()
+		}
+		ctx = context.WithValue(ctx, requestIDKey{}, id)
+
+		zerolog.Ctx(ctx).UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("request_id", id)
+		})
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type requestIDKey struct{}
+
+// incomingRequestID returns the request ID sent by the caller, or "" if there
+// is none or it is not safe to log and echo back
+func incomingRequestID(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); validRequestID(id) {
+		return id
+	}
+	// version-traceid-parentid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+	parts := strings.Split(r.Header.Get(TraceparentHeader), "-")
+	if len(parts) == 4 && len(parts[1]) == 32 && validRequestID(parts[1]) &&
+		parts[1] != strings.Repeat("0", 32) {
+		return strings.ToLower(parts[1])
+	}
+	return ""
+}
+
+// validRequestID reports whether id is non-empty, bounded and made of
+// letters, digits and the punctuation common in IDs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// JobContext returns a context for one run of a scheduler job, carrying a new
+// run ID and a logger that adds the job name and run ID to every entry
+func JobContext(ctx context.Context, job string) context.Context {
+	id := xid.New()
+	ctx = hlog.CtxWithID(ctx, id)
+	l := LoggerFromContext(ctx).With().
+		Str("job", job).
+		Str("run_id", id.String()).
+		Logger()
+	return l.WithContext(ctx)
 }
 
-// RequestIDFromContext returns the request ID from the context
+// RequestIDFromContext returns the request ID, or the run ID of a scheduler job, from the context
 func RequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
 	if id, ok := hlog.IDFromCtx(ctx); ok {
 		return id.String()
 	}
 	return ""
 }
 
-// LoggerFromContext returns a logger from the context
-func LoggerFromContext(ctx context.Context) zerolog.Logger {
-	l := zerolog.Ctx(ctx)
-	if l == nil {
-		return Logger
+// LoggerFromContext returns the logger of the request or job run in the
+// context, or the global logger if there is none
+func LoggerFromContext(ctx context.Context) *zerolog.Logger {
+	// zerolog.Ctx returns a disabled logger, not nil, for a context without one
+	if l := zerolog.Ctx(ctx); l.GetLevel() != zerolog.Disabled {
+		return l
 	}
-	return *l
+	return &log.Logger
 }
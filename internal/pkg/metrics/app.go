@@ -0,0 +1,58 @@
+package metrics
+
+// Application metrics, registered in the Default registry
+
+var (
+	// HTTPRequests counts handled requests by route pattern and response status
+	HTTPRequests = Default.NewCounterVec("kbfood_http_requests_total",
+		"HTTP requests handled, by method, route and status.", "method", "route", "status")
+	// HTTPRequestDuration observes request latencies by route pattern
+	HTTPRequestDuration = Default.NewHistogramVec("kbfood_http_request_duration_seconds",
+		"HTTP request latency in seconds, by method and route.", DefaultBuckets, "method", "route")
+
+	// PlatformFetchDuration observes how long fetching all products of a region takes
+	PlatformFetchDuration = Default.NewHistogramVec("kbfood_platform_fetch_duration_seconds",
+		"Duration of fetching the products of a region from a platform, in seconds.", DefaultBuckets, "platform")
+	// PlatformFetchPages counts fetched product pages
+	PlatformFetchPages = Default.NewCounterVec("kbfood_platform_fetch_pages_total",
+		"Product pages fetched from a platform.", "platform")
+	// PlatformFetchErrors counts failed page fetches by the error type reported by the platform
+	PlatformFetchErrors = Default.NewCounterVec("kbfood_platform_fetch_errors_total",
+		"Failed product page fetches, by platform and error type.", "platform", "errorType")
+	// PlatformProductsFetched counts products returned by a platform
+	PlatformProductsFetched = Default.NewCounterVec("kbfood_platform_products_fetched_total",
+		"Products fetched from a platform.", "platform")
+
+	// CandidatePoolSize is the number of candidates waiting for promotion
+	CandidatePoolSize = Default.NewGauge("kbfood_candidate_pool_size",
+		"Candidate products waiting to be promoted.")
+	// Promotions counts promoted candidates by whether a master product was created or updated
+	Promotions = Default.NewCounterVec("kbfood_promotions_total",
+		"Candidates promoted to master products, by outcome.", "outcome")
+
+	// PriceRejections counts price updates rejected by the validator, by error code
+	PriceRejections = Default.NewCounterVec("kbfood_price_validator_rejections_total",
+		"Price updates rejected by the price validator, by error code.", "code")
+
+	// Notifications counts price notifications by channel and whether they were sent
+	Notifications = Default.NewCounterVec("kbfood_notifications_total",
+		"Price notifications, by channel and status.", "channel", "status")
+
+	// JobDuration observes scheduler job run durations by job and outcome
+	JobDuration = Default.NewHistogramVec("kbfood_job_duration_seconds",
+		"Scheduler job run duration in seconds, by job and status.", DefaultBuckets, "job", "status")
+	// JobLastSuccess is the Unix time of the last successful run of a job
+	JobLastSuccess = Default.NewGaugeVec("kbfood_job_last_success_timestamp_seconds",
+		"Unix time of the last successful run of a scheduler job.", "job")
+)
+
+// Outcome and status label values
+const (
+	PromotionCreated = "created"
+	PromotionMerged  = "merged"
+
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
@@ -0,0 +1,349 @@
+// Package metrics is a small in-process metrics registry that exposes
+// counters, gauges and histograms in the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric types
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// ContentType is the media type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are histogram buckets in seconds for request and job latencies
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Default is the registry of the application metrics served at /metrics
+var Default = NewRegistry()
+
+// Registry holds metric families and writes them in the Prometheus text format
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// NewCounterVec registers a counter with the given label names
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.register(name, help, typeCounter, nil, labels)}
+}
+
+// NewGaugeVec registers a gauge with the given label names
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(name, help, typeGauge, nil, labels)}
+}
+
+// NewHistogramVec registers a histogram with the given upper bucket bounds and label names
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	return &HistogramVec{f: r.register(name, help, typeHistogram, bounds, labels)}
+}
+
+// NewCounter registers a counter without labels
+func (r *Registry) NewCounter(name, help string) *Counter {
+	return r.NewCounterVec(name, help).With()
+}
+
+// NewGauge registers a gauge without labels
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return r.NewGaugeVec(name, help).With()
+}
+
+// register adds a family, panicking on duplicate names like the registration
+// of an HTTP route does, since it is a programming error
+func (r *Registry) register(name, help, typ string, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.families[name]; ok {
+		panic("metrics: duplicate metric " + name)
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// WriteText writes every metric in the Prometheus text format, sorted by name
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry in the Prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WriteText(w)
+	})
+}
+
+// family is a metric with all its labeled series
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64 // histograms only
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// with returns the series of the label values, creating it on first use
+func (f *family) with(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels, got %d values", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	all := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		all = append(all, s)
+	}
+	f.mu.Unlock()
+	if len(all) == 0 {
+		return
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].values, "\xff") < strings.Join(all[j].values, "\xff")
+	})
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, s := range all {
+		s.mu.Lock()
+		if f.typ != typeHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, labelText(f.labels, s.values, "", ""), formatFloat(s.value))
+			s.mu.Unlock()
+			continue
+		}
+
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, labelText(f.labels, s.values, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, labelText(f.labels, s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, labelText(f.labels, s.values, "", ""), formatFloat(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, labelText(f.labels, s.values, "", ""), s.count)
+		s.mu.Unlock()
+	}
+}
+
+// series is the value of one combination of label values. For histograms,
+// value is the sum of the observations.
+type series struct {
+	values []string
+
+	mu     sync.Mutex
+	value  float64
+	counts []uint64 // observations per bucket, not cumulative
+	count  uint64
+}
+
+func (s *series) add(v float64) {
+	s.mu.Lock()
+	s.value += v
+	s.mu.Unlock()
+}
+
+func (s *series) set(v float64) {
+	s.mu.Lock()
+	s.value = v
+	s.mu.Unlock()
+}
+
+func (s *series) get() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct{ f *family }
+
+// With returns the counter of the label values, in the order the labels were registered
+func (v *CounterVec) With(values ...string) *Counter {
+	return &Counter{s: v.f.with(values)}
+}
+
+// Counter is a value that only goes up
+type Counter struct{ s *series }
+
+// Inc adds one
+func (c *Counter) Inc() { c.s.add(1) }
+
+// Add adds v, which must not be negative
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	c.s.add(v)
+}
+
+// Value returns the current value
+func (c *Counter) Value() float64 { return c.s.get() }
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct{ f *family }
+
+// With returns the gauge of the label values, in the order the labels were registered
+func (v *GaugeVec) With(values ...string) *Gauge {
+	return &Gauge{s: v.f.with(values)}
+}
+
+// Gauge is a value that goes up and down
+type Gauge struct{ s *series }
+
+// Set sets the value
+func (g *Gauge) Set(v float64) { g.s.set(v) }
+
+// SetToTime sets the value to t in Unix seconds
+func (g *Gauge) SetToTime(t time.Time) { g.s.set(float64(t.UnixNano()) / 1e9) }
+
+// Add adds v, which may be negative
+func (g *Gauge) Add(v float64) { g.s.add(v) }
+
+// Value returns the current value
+func (g *Gauge) Value() float64 { return g.s.get() }
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct{ f *family }
+
+// With returns the histogram of the label values, in the order the labels were registered
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return &Histogram{s: v.f.with(values), buckets: v.f.buckets}
+}
+
+// Histogram counts observations in buckets
+type Histogram struct {
+	s       *series
+	buckets []float64
+}
+
+// Observe records a value
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.buckets, v)
+	h.s.mu.Lock()
+	if i < len(h.buckets) {
+		h.s.counts[i]++
+	}
+	h.s.count++
+	h.s.value += v
+	h.s.mu.Unlock()
+}
+
+// ObserveSince records the seconds elapsed since start
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.count
+}
+
+// labelText formats the label pairs of a series, with an extra pair if extraName is set
+func labelText(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(values[i]))
+		b.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extraName)
+		b.WriteString(`="`)
+		b.WriteString(extraValue)
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
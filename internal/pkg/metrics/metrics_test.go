@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("test_requests_total", "Requests handled.", "route", "status")
+	pool := r.NewGauge("test_pool_size", "Pool size.")
+	latency := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+	r.NewCounterVec("test_unused_total", "Never incremented.", "route")
+
+	requests.With("/api/products/:id", "200").Add(2)
+	requests.With(`/say"hi"`, "500").Inc()
+	requests.With("/api/products/:id", "200").Add(-1) // ignored
+	pool.Set(42)
+	latency.With("/api").Observe(0.05)
+	latency.With("/api").Observe(0.5)
+	latency.With("/api").Observe(3)
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	want := `# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{route="/api",le="0.1"} 1
+test_latency_seconds_bucket{route="/api",le="1"} 2
+test_latency_seconds_bucket{route="/api",le="+Inf"} 3
+test_latency_seconds_sum{route="/api"} 3.55
+test_latency_seconds_count{route="/api"} 3
+# HELP test_pool_size Pool size.
+# TYPE test_pool_size gauge
+test_pool_size 42
+# HELP test_requests_total Requests handled.
+# TYPE test_requests_total counter
+test_requests_total{route="/api/products/:id",status="200"} 2
+test_requests_total{route="/say\"hi\"",status="500"} 1
+`
+	if b.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "Test.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Header().Get("Content-Type") != ContentType || !strings.Contains(rec.Body.String(), "test_total 1\n") {
+		t.Errorf("content type = %q, body = %q", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	r := NewRegistry()
+	vec := r.NewCounterVec("test_total", "Test.", "route")
+
+	for name, fn := range map[string]func(){
+		"duplicate name":    func() { r.NewGauge("test_total", "Again.") },
+		"wrong label count": func() { vec.With("/a", "extra") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("did not panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
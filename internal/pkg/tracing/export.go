@@ -0,0 +1,315 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Batching limits; spans beyond maxQueueSize are dropped rather than
+// slowing down requests when the collector is unreachable
+const (
+	maxQueueSize   = 2048
+	maxBatchSize   = 256
+	exportInterval = 5 * time.Second
+	exportTimeout  = 10 * time.Second
+)
+
+// Exporter sends finished spans to a backend
+type Exporter interface {
+	ExportSpans(ctx context.Context, spans []SpanData) error
+}
+
+// StdoutExporter writes each span as a line of OTLP JSON
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutExporter creates an exporter writing to w, or to stdout if w is nil
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &StdoutExporter{w: w}
+}
+
+// ExportSpans implements Exporter
+func (e *StdoutExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	enc := json.NewEncoder(e.w)
+	for _, span := range spans {
+		if err := enc.Encode(toOTLPSpan(span)); err != nil {
+			return fmt.Errorf("write span: %w", err)
+		}
+	}
+	return nil
+}
+
+// OTLPExporter posts spans to the /v1/traces endpoint of an OTLP/HTTP
+// collector using the JSON encoding
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter creates an exporter for the collector at endpoint, e.g. http://localhost:4318
+func NewOTLPExporter(endpoint, serviceName string) *OTLPExporter {
+	if endpoint == "" {
+		endpoint = "http://localhost:4318"
+	}
+	if serviceName == "" {
+		serviceName = "kbfood"
+	}
+	return &OTLPExporter{
+		url:         strings.TrimRight(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// ExportSpans implements Exporter
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	converted := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		converted = append(converted, toOTLPSpan(span))
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{toOTLPAttribute(String("service.name", e.serviceName))}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "kbfood"},
+			Spans: converted,
+		}},
+	}}})
+	if err != nil {
+		return fmt.Errorf("encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// batchProcessor queues finished spans and exports them in batches from a
+// background goroutine
+type batchProcessor struct {
+	exporter Exporter
+
+	mu      sync.Mutex
+	queue   []SpanData
+	dropped int
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newBatchProcessor(exporter Exporter) *batchProcessor {
+	p := &batchProcessor{
+		exporter: exporter,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *batchProcessor) enqueue(span SpanData) {
+	p.mu.Lock()
+	if len(p.queue) >= maxQueueSize {
+		p.dropped++
+		p.mu.Unlock()
+		return
+	}
+	p.queue = append(p.queue, span)
+	full := len(p.queue) >= maxBatchSize
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *batchProcessor) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.wake:
+		case <-p.stop:
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		p.flush(ctx)
+		cancel()
+	}
+}
+
+// flush exports all queued spans
+func (p *batchProcessor) flush(ctx context.Context) {
+	for {
+		p.mu.Lock()
+		n := min(len(p.queue), maxBatchSize)
+		batch := p.queue[:n:n]
+		p.queue = p.queue[n:]
+		dropped := p.dropped
+		p.dropped = 0
+		p.mu.Unlock()
+
+		if dropped > 0 {
+			log.Warn().Int("dropped", dropped).Msg("Trace queue full, spans dropped")
+		}
+		if n == 0 {
+			return
+		}
+		if err := p.exporter.ExportSpans(ctx, batch); err != nil {
+			log.Warn().Err(err).Int("spans", n).Msg("Failed to export spans")
+			return
+		}
+	}
+}
+
+// shutdown stops the background export and exports the remaining spans
+func (p *batchProcessor) shutdown(ctx context.Context) error {
+	close(p.stop)
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	p.flush(ctx)
+	return ctx.Err()
+}
+
+// OTLP/HTTP JSON encoding, see opentelemetry-proto trace/v1/trace.proto
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is an AnyValue; 64-bit integers are encoded as strings
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func toOTLPSpan(span SpanData) otlpSpan {
+	out := otlpSpan{
+		TraceID:           span.TraceID.String(),
+		SpanID:            hexSpanID(span.SpanID),
+		ParentSpanID:      span.ParentSpanID.String(),
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+		Status:            otlpStatus{Code: span.Status, Message: span.StatusMessage},
+	}
+	for _, attr := range span.Attributes {
+		out.Attributes = append(out.Attributes, toOTLPAttribute(attr))
+	}
+	return out
+}
+
+// hexSpanID formats a span ID, including the zero ID that String leaves empty
+func hexSpanID(id SpanID) string {
+	if s := id.String(); s != "" {
+		return s
+	}
+	return strings.Repeat("0", 16)
+}
+
+func toOTLPAttribute(attr Attribute) otlpAttribute {
+	var value otlpValue
+	switch v := attr.Value.(type) {
+	case string:
+		value.StringValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		value.IntValue = &s
+	case bool:
+		value.BoolValue = &v
+	case float64:
+		value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		value.StringValue = &s
+	}
+	return otlpAttribute{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,237 @@
+// Package tracing records OpenTelemetry-style spans and exports them to
+// stdout or to an OTLP/HTTP collector. Tracing is off until Init installs an
+// exporter; until then Start returns nil spans, whose methods do nothing.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Exporter names accepted by Init
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// SpanKind describes the relationship of a span to its caller
+type SpanKind int
+
+// Span kinds, numbered as in OTLP
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// StatusCode is the outcome of a span, numbered as in OTLP
+type StatusCode int
+
+// Span status codes
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// TraceID identifies a trace, shared by all its spans
+type TraceID [16]byte
+
+// String returns the lowercase hex form used in OTLP JSON and logs
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the lowercase hex form, or "" for the zero ID of root span parents
+func (id SpanID) String() string {
+	if id == (SpanID{}) {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// Attribute is a key-value pair describing a span
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Float64 returns a floating point attribute
+func Float64(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Span is an operation within a trace. A nil span is valid and records nothing.
+type Span struct {
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanData is the exported form of a finished span
+type SpanData struct {
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentSpanID  SpanID
+	Name          string
+	Kind          SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+	s.mu.Unlock()
+}
+
+// SetStatus sets the outcome of the span
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Status = code
+	s.data.StatusMessage = message
+	s.mu.Unlock()
+}
+
+// RecordError marks the span as failed with the error message; a nil error is ignored
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and hands it to the exporter. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	data.Attributes = append([]Attribute(nil), s.data.Attributes...)
+	s.mu.Unlock()
+
+	if p := current(); p != nil {
+		p.enqueue(data)
+	}
+}
+
+// TraceID returns the trace ID of the span, or "" for a nil span
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.data.TraceID.String()
+}
+
+type spanKey struct{}
+
+// Start starts a span as a child of the span in ctx, or as the root of a new
+// trace, and returns a context carrying it. It returns a nil span when
+// tracing is off.
+func Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if current() == nil {
+		return ctx, nil
+	}
+
+	span := &Span{data: SpanData{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: attrs,
+	}}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentSpanID = parent.data.SpanID
+	} else {
+		_, _ = rand.Read(span.data.TraceID[:])
+	}
+	_, _ = rand.Read(span.data.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the current span of ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Config holds tracing configuration
+type Config struct {
+	// Exporter is "none", "stdout" or "otlp"
+	Exporter string
+	// Endpoint is the base URL of the OTLP/HTTP collector, e.g. http://localhost:4318
+	Endpoint    string
+	ServiceName string
+}
+
+var (
+	providerMu sync.RWMutex
+	provider   *batchProcessor
+)
+
+func current() *batchProcessor {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+	return provider
+}
+
+// Init installs the exporter selected by cfg. The returned function flushes
+// pending spans and stops exporting; call it on shutdown.
+func Init(cfg Config) (func(context.Context) error, error) {
+	var exporter Exporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter = NewStdoutExporter(nil)
+	case ExporterOTLP:
+		exporter = NewOTLPExporter(cfg.Endpoint, cfg.ServiceName)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	p := newBatchProcessor(exporter)
+	providerMu.Lock()
+	provider = p
+	providerMu.Unlock()
+
+	return func(ctx context.Context) error {
+		providerMu.Lock()
+		if provider == p {
+			provider = nil
+		}
+		providerMu.Unlock()
+		return p.shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *memoryExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// useExporter installs exporter for the test and returns a function that flushes it
+func useExporter(t *testing.T, exporter Exporter) func() {
+	t.Helper()
+	p := newBatchProcessor(exporter)
+	providerMu.Lock()
+	provider = p
+	providerMu.Unlock()
+
+	var once sync.Once
+	flush := func() {
+		once.Do(func() {
+			providerMu.Lock()
+			provider = nil
+			providerMu.Unlock()
+			if err := p.shutdown(context.Background()); err != nil {
+				t.Errorf("shutdown: %v", err)
+			}
+		})
+	}
+	t.Cleanup(flush)
+	return flush
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "noop", KindInternal)
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatalf("Start() without exporter = %v, want nil span", span)
+	}
+	// Methods of nil spans do nothing
+	span.SetAttributes(String("key", "value"))
+	span.RecordError(errors.New("failed"))
+	span.End()
+}
+
+func TestStart_ChildSpans(t *testing.T) {
+	exporter := &memoryExporter{}
+	flush := useExporter(t, exporter)
+
+	ctx, root := Start(context.Background(), "job sync", KindInternal, String("job.name", "sync"))
+	_, child := Start(ctx, "db SELECT", KindClient)
+	child.RecordError(errors.New("database is locked"))
+	child.End()
+	child.End() // exported once
+	root.End()
+	flush()
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(exporter.spans))
+	}
+	gotChild, gotRoot := exporter.spans[0], exporter.spans[1]
+	if gotChild.TraceID != gotRoot.TraceID || gotChild.ParentSpanID != gotRoot.SpanID || gotRoot.ParentSpanID != (SpanID{}) {
+		t.Errorf("child %+v is not a child of root %+v", gotChild, gotRoot)
+	}
+	if gotChild.Status != StatusError || gotChild.StatusMessage != "database is locked" {
+		t.Errorf("child status = %d %q", gotChild.Status, gotChild.StatusMessage)
+	}
+	if root.TraceID() != gotRoot.TraceID.String() || len(root.TraceID()) != 32 {
+		t.Errorf("TraceID() = %q", root.TraceID())
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body []byte
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %s with content type %q", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer collector.Close()
+
+	flush := useExporter(t, NewOTLPExporter(collector.URL+"/", "kbfood-test"))
+	_, span := Start(context.Background(), "platform.fetch", KindClient, String("region", "广州"), Int("page", 2))
+	span.End()
+	flush()
+
+	var request otlpRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		t.Fatalf("parse request: %v\n%s", err, body)
+	}
+	resource := request.ResourceSpans[0]
+	if *resource.Resource.Attributes[0].Value.StringValue != "kbfood-test" {
+		t.Errorf("resource = %+v", resource.Resource)
+	}
+	got := resource.ScopeSpans[0].Spans[0]
+	if got.Name != "platform.fetch" || got.Kind != KindClient || len(got.TraceID) != 32 || len(got.SpanID) != 16 || got.ParentSpanID != "" {
+		t.Errorf("span = %+v", got)
+	}
+	if len(got.Attributes) != 2 || *got.Attributes[0].Value.StringValue != "广州" || *got.Attributes[1].Value.IntValue != "2" {
+		t.Errorf("attributes = %+v", got.Attributes)
+	}
+}
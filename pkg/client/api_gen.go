@@ -0,0 +1,1187 @@
+// Code generated by TestGeneratedClient from the OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuditLog is the AuditLog schema of the API
+type AuditLog struct {
+	ID         int64     `json:"id"`
+	UserID     string    `json:"userId"`
+	Username   string    `json:"username"`
+	Action     string    `json:"action"`
+	Params     string    `json:"params"`
+	Status     int       `json:"status"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// AuthResponse is the AuthResponse schema of the API
+type AuthResponse struct {
+	User  *User  `json:"user"`
+	Token string `json:"token"`
+}
+
+// AuthToken is the AuthToken schema of the API
+type AuthToken struct {
+	ID           int64      `json:"id"`
+	Kind         string     `json:"kind"`
+	Name         string     `json:"name"`
+	ExpireTime   *time.Time `json:"expireTime,omitempty"`
+	LastUsedTime *time.Time `json:"lastUsedTime,omitempty"`
+	CreateTime   time.Time  `json:"createTime"`
+}
+
+// BarkKeyRequest is the BarkKeyRequest schema of the API
+type BarkKeyRequest struct {
+	BarkKey string `json:"barkKey"`
+}
+
+// ClaimClientResponse is the ClaimClientResponse schema of the API
+type ClaimClientResponse struct {
+	ClientID string `json:"clientId"`
+}
+
+// CreateTokenRequest is the CreateTokenRequest schema of the API
+type CreateTokenRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateTokenResponse is the CreateTokenResponse schema of the API
+type CreateTokenResponse struct {
+	Token  string     `json:"token"`
+	Detail *AuthToken `json:"detail"`
+}
+
+// CredentialsRequest is the CredentialsRequest schema of the API
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// DTPlatformItem is the DTPlatformItem schema of the API
+type DTPlatformItem struct {
+	Title     string  `json:"title"`
+	Price     float64 `json:"price"`
+	Status    int     `json:"status"`
+	CrawlTime int64   `json:"crawlTime"`
+	Region    string  `json:"region"`
+}
+
+// DTPlatformPushRequest is the DTPlatformPushRequest schema of the API
+type DTPlatformPushRequest struct {
+	Items []DTPlatformItem `json:"items"`
+}
+
+// DTPlatformPushResponse is the DTPlatformPushResponse schema of the API
+type DTPlatformPushResponse struct {
+	Received int `json:"received"`
+	Promoted int `json:"promoted"`
+}
+
+// Device is the Device schema of the API
+type Device struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	LastSeenTime *time.Time `json:"lastSeenTime,omitempty"`
+	CreateTime   time.Time  `json:"createTime"`
+	Current      bool       `json:"current"`
+}
+
+// Digest is the Digest schema of the API
+type Digest struct {
+	UserID      string          `json:"userId"`
+	Date        string          `json:"date"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Items       []DigestItem    `json:"items"`
+	NewProducts []DigestProduct `json:"newProducts"`
+}
+
+// DigestItem is the DigestItem schema of the API
+type DigestItem struct {
+	ActivityID   string   `json:"activityId"`
+	Platform     string   `json:"platform"`
+	Region       string   `json:"region"`
+	Title        string   `json:"title"`
+	CurrentPrice float64  `json:"currentPrice"`
+	TodayLow     float64  `json:"todayLow"`
+	RuleType     string   `json:"ruleType"`
+	TargetPrice  *float64 `json:"targetPrice,omitempty"`
+	SalesStatus  int      `json:"salesStatus"`
+	Triggered    bool     `json:"triggered"`
+	Watchlist    string   `json:"watchlist,omitempty"`
+}
+
+// DigestProduct is the DigestProduct schema of the API
+type DigestProduct struct {
+	ActivityID string  `json:"activityId"`
+	Platform   string  `json:"platform"`
+	Region     string  `json:"region"`
+	Title      string  `json:"title"`
+	Price      float64 `json:"price"`
+}
+
+// FeedToken is the FeedToken schema of the API
+type FeedToken struct {
+	Token      string    `json:"token"`
+	URL        string    `json:"url,omitempty"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// Highlight is the Highlight schema of the API
+type Highlight struct {
+	Title    string `json:"title"`
+	ShopName string `json:"shopName,omitempty"`
+}
+
+// JoinWatchlistRequest is the JoinWatchlistRequest schema of the API
+type JoinWatchlistRequest struct {
+	InviteCode string `json:"inviteCode"`
+}
+
+// Meta is the Meta schema of the API
+type Meta struct {
+	Total      int    `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Notification is the Notification schema of the API
+type Notification struct {
+	ActivityID     string     `json:"activityId"`
+	RuleType       string     `json:"ruleType"`
+	TargetPrice    float64    `json:"targetPrice"`
+	DropPercent    float64    `json:"dropPercent,omitempty"`
+	DropAmount     float64    `json:"dropAmount,omitempty"`
+	LastNotifyTime *string    `json:"lastNotifyTime,omitempty"`
+	SnoozeUntil    *time.Time `json:"snoozeUntil,omitempty"`
+}
+
+// NotificationPreferences is the NotificationPreferences schema of the API
+type NotificationPreferences struct {
+	Timezone       string `json:"timezone"`
+	QuietStart     string `json:"quietStart"`
+	QuietEnd       string `json:"quietEnd"`
+	MaxPerHour     int    `json:"maxPerHour"`
+	MaxPerDay      int    `json:"maxPerDay"`
+	BarkLevel      string `json:"barkLevel"`
+	BarkSound      string `json:"barkSound"`
+	DigestEnabled  bool   `json:"digestEnabled"`
+	DigestTime     string `json:"digestTime"`
+	BarkGroup      string `json:"barkGroup"`
+	BarkIcon       string `json:"barkIcon"`
+	BarkArchive    bool   `json:"barkArchive"`
+	BarkEncryptKey string `json:"barkEncryptKey"`
+	BarkEncryptIV  string `json:"barkEncryptIv"`
+}
+
+// NotificationRuleRequest is the NotificationRuleRequest schema of the API
+type NotificationRuleRequest struct {
+	ActivityID  string  `json:"activityId"`
+	RuleType    string  `json:"ruleType,omitempty"`
+	TargetPrice float64 `json:"targetPrice,omitempty"`
+	DropPercent float64 `json:"dropPercent,omitempty"`
+	DropAmount  float64 `json:"dropAmount,omitempty"`
+}
+
+// PairDeviceRequest is the PairDeviceRequest schema of the API
+type PairDeviceRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// PairingCode is the PairingCode schema of the API
+type PairingCode struct {
+	Code       string    `json:"code"`
+	ExpireTime time.Time `json:"expireTime"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// PriceTrend is the PriceTrend schema of the API
+type PriceTrend struct {
+	Date  string  `json:"date"`
+	Price float64 `json:"price"`
+}
+
+// Product is the Product schema of the API
+type Product struct {
+	ID                 int64         `json:"id"`
+	ActivityID         string        `json:"activityId"`
+	Platform           string        `json:"platform"`
+	Region             string        `json:"region"`
+	Title              string        `json:"title"`
+	ShopName           string        `json:"shopName"`
+	OriginalPrice      float64       `json:"originalPrice"`
+	CurrentPrice       float64       `json:"currentPrice"`
+	SalesStatus        int           `json:"salesStatus"`
+	SalesStatusText    string        `json:"salesStatusText"`
+	ActivityCreateTime time.Time     `json:"activityCreateTime"`
+	CreateTime         time.Time     `json:"createTime"`
+	UpdateTime         time.Time     `json:"updateTime"`
+	Discount           float64       `json:"discount,omitempty"`
+	DropRate           float64       `json:"dropRate,omitempty"`
+	HasNotification    bool          `json:"hasNotification,omitempty"`
+	TargetPrice        *float64      `json:"targetPrice,omitempty"`
+	Notification       *Notification `json:"notification,omitempty"`
+	Favorite           bool          `json:"favorite,omitempty"`
+	Tags               []string      `json:"tags,omitempty"`
+	Note               string        `json:"note,omitempty"`
+	Highlight          *Highlight    `json:"highlight,omitempty"`
+}
+
+// ProductAnnotation is the ProductAnnotation schema of the API
+type ProductAnnotation struct {
+	ActivityID string    `json:"activityId"`
+	Favorite   bool      `json:"favorite"`
+	Tags       []string  `json:"tags"`
+	Note       string    `json:"note"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// ProductDetail is the ProductDetail schema of the API
+type ProductDetail struct {
+	Product      Product       `json:"product"`
+	Source       string        `json:"source"`
+	Blocked      bool          `json:"blocked"`
+	TrustScore   int           `json:"trustScore"`
+	LastSeenTime time.Time     `json:"lastSeenTime"`
+	TrendSummary *TrendSummary `json:"trendSummary,omitempty"`
+	TitleVotes   []TitleVote   `json:"titleVotes"`
+	Related      []Product     `json:"related"`
+}
+
+// Role is the Role schema of the API
+type Role struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// SavedSearch is the SavedSearch schema of the API
+type SavedSearch struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Keyword    string    `json:"keyword"`
+	Region     string    `json:"region"`
+	Platform   string    `json:"platform"`
+	MaxPrice   float64   `json:"maxPrice,omitempty"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// SavedSearchRequest is the SavedSearchRequest schema of the API
+type SavedSearchRequest struct {
+	Name     string  `json:"name"`
+	Keyword  string  `json:"keyword,omitempty"`
+	Region   string  `json:"region,omitempty"`
+	Platform string  `json:"platform,omitempty"`
+	MaxPrice float64 `json:"maxPrice,omitempty"`
+}
+
+// SetRoleRequest is the SetRoleRequest schema of the API
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// StreamEvent is the StreamEvent schema of the API
+type StreamEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	ActivityID string    `json:"activityId,omitempty"`
+	Region     string    `json:"region,omitempty"`
+	Platform   string    `json:"platform,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Price      *float64  `json:"price,omitempty"`
+	OldPrice   *float64  `json:"oldPrice,omitempty"`
+	Status     *int      `json:"status,omitempty"`
+	OldStatus  *int      `json:"oldStatus,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// SyncStatus is the SyncStatus schema of the API
+type SyncStatus struct {
+	LastRunTime  string `json:"lastRunTime"`
+	Status       string `json:"status"`
+	ProductCount int    `json:"productCount"`
+	IsHealthy    bool   `json:"isHealthy"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// SystemStatusResponse is the SystemStatusResponse schema of the API
+type SystemStatusResponse struct {
+	Sync       SyncStatus `json:"sync"`
+	ServerTime string     `json:"serverTime"`
+}
+
+// Tag is the Tag schema of the API
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TestNotification is the TestNotification schema of the API
+type TestNotification struct {
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+	TestedAt string `json:"testedAt"`
+}
+
+// TitleVote is the TitleVote schema of the API
+type TitleVote struct {
+	Title string `json:"title"`
+	Votes int    `json:"votes"`
+}
+
+// TrendSummary is the TrendSummary schema of the API
+type TrendSummary struct {
+	Points       int     `json:"points"`
+	FirstPrice   float64 `json:"firstPrice"`
+	FirstDate    string  `json:"firstDate"`
+	LatestPrice  float64 `json:"latestPrice"`
+	LatestDate   string  `json:"latestDate"`
+	LowestPrice  float64 `json:"lowestPrice"`
+	LowestDate   string  `json:"lowestDate"`
+	HighestPrice float64 `json:"highestPrice"`
+	ChangeRate   float64 `json:"changeRate"`
+}
+
+// UpdateAnnotationRequest is the UpdateAnnotationRequest schema of the API
+type UpdateAnnotationRequest struct {
+	Favorite *bool     `json:"favorite,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	Note     *string   `json:"note,omitempty"`
+}
+
+// UpdateNotificationRequest is the UpdateNotificationRequest schema of the API
+type UpdateNotificationRequest struct {
+	RuleType    *string  `json:"ruleType,omitempty"`
+	TargetPrice *float64 `json:"targetPrice,omitempty"`
+	DropPercent *float64 `json:"dropPercent,omitempty"`
+	DropAmount  *float64 `json:"dropAmount,omitempty"`
+	SnoozeUntil *string  `json:"snoozeUntil,omitempty"`
+}
+
+// UpdateSavedSearchRequest is the UpdateSavedSearchRequest schema of the API
+type UpdateSavedSearchRequest struct {
+	Name     *string  `json:"name,omitempty"`
+	Keyword  *string  `json:"keyword,omitempty"`
+	Region   *string  `json:"region,omitempty"`
+	Platform *string  `json:"platform,omitempty"`
+	MaxPrice *float64 `json:"maxPrice,omitempty"`
+}
+
+// UpdateWatchlistItemRequest is the UpdateWatchlistItemRequest schema of the API
+type UpdateWatchlistItemRequest struct {
+	RuleType    *string  `json:"ruleType,omitempty"`
+	TargetPrice *float64 `json:"targetPrice,omitempty"`
+	DropPercent *float64 `json:"dropPercent,omitempty"`
+	DropAmount  *float64 `json:"dropAmount,omitempty"`
+}
+
+// User is the User schema of the API
+type User struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	Role       string    `json:"role"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// UserDataAnnotation is the UserDataAnnotation schema of the API
+type UserDataAnnotation struct {
+	ActivityID string   `json:"activityId"`
+	Favorite   bool     `json:"favorite,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Note       string   `json:"note,omitempty"`
+}
+
+// UserDataExport is the UserDataExport schema of the API
+type UserDataExport struct {
+	Version         int                    `json:"version"`
+	ExportedAt      time.Time              `json:"exportedAt"`
+	Settings        UserDataSettings       `json:"settings"`
+	Notifications   []UserDataNotification `json:"notifications"`
+	BlockedProducts []string               `json:"blockedProducts"`
+	SavedSearches   []UserDataSavedSearch  `json:"savedSearches"`
+	Annotations     []UserDataAnnotation   `json:"annotations"`
+}
+
+// UserDataImportSummary is the UserDataImportSummary schema of the API
+type UserDataImportSummary struct {
+	Mode            string `json:"mode"`
+	Settings        bool   `json:"settings"`
+	Notifications   int    `json:"notifications"`
+	BlockedProducts int    `json:"blockedProducts"`
+	SavedSearches   int    `json:"savedSearches"`
+	Annotations     int    `json:"annotations"`
+	Skipped         int    `json:"skipped"`
+}
+
+// UserDataNotification is the UserDataNotification schema of the API
+type UserDataNotification struct {
+	ActivityID     string     `json:"activityId"`
+	RuleType       string     `json:"ruleType"`
+	TargetPrice    float64    `json:"targetPrice,omitempty"`
+	DropPercent    float64    `json:"dropPercent,omitempty"`
+	DropAmount     float64    `json:"dropAmount,omitempty"`
+	ReferencePrice *float64   `json:"referencePrice,omitempty"`
+	LastStatus     *int       `json:"lastStatus,omitempty"`
+	SnoozeUntil    *time.Time `json:"snoozeUntil,omitempty"`
+}
+
+// UserDataSavedSearch is the UserDataSavedSearch schema of the API
+type UserDataSavedSearch struct {
+	Name     string  `json:"name"`
+	Keyword  string  `json:"keyword"`
+	Region   string  `json:"region"`
+	Platform string  `json:"platform"`
+	MaxPrice float64 `json:"maxPrice,omitempty"`
+}
+
+// UserDataSettings is the UserDataSettings schema of the API
+type UserDataSettings struct {
+	BarkKey     string                   `json:"barkKey"`
+	Preferences *NotificationPreferences `json:"preferences,omitempty"`
+}
+
+// UserSettings is the UserSettings schema of the API
+type UserSettings struct {
+	BarkKey string `json:"barkKey"`
+}
+
+// Watchlist is the Watchlist schema of the API
+type Watchlist struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	InviteCode  string    `json:"inviteCode"`
+	InviteURL   string    `json:"inviteUrl,omitempty"`
+	Role        string    `json:"role"`
+	MemberCount int       `json:"memberCount"`
+	CreateTime  time.Time `json:"createTime"`
+	UpdateTime  time.Time `json:"updateTime"`
+}
+
+// WatchlistDetail is the WatchlistDetail schema of the API
+type WatchlistDetail struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	InviteCode  string             `json:"inviteCode"`
+	InviteURL   string             `json:"inviteUrl,omitempty"`
+	Role        string             `json:"role"`
+	MemberCount int                `json:"memberCount"`
+	CreateTime  time.Time          `json:"createTime"`
+	UpdateTime  time.Time          `json:"updateTime"`
+	Items       []*WatchlistItem   `json:"items"`
+	Members     []*WatchlistMember `json:"members"`
+}
+
+// WatchlistItem is the WatchlistItem schema of the API
+type WatchlistItem struct {
+	WatchlistID    int64      `json:"watchlistId"`
+	ActivityID     string     `json:"activityId"`
+	UserID         string     `json:"userId"`
+	RuleType       string     `json:"ruleType"`
+	TargetPrice    float64    `json:"targetPrice"`
+	DropPercent    float64    `json:"dropPercent"`
+	DropAmount     float64    `json:"dropAmount"`
+	ReferencePrice *float64   `json:"referencePrice"`
+	LastStatus     *int       `json:"lastStatus"`
+	LastNotifyTime *time.Time `json:"lastNotifyTime"`
+	SnoozeUntil    *time.Time `json:"snoozeUntil"`
+	CreateTime     time.Time  `json:"createTime"`
+	UpdateTime     time.Time  `json:"updateTime"`
+}
+
+// WatchlistMember is the WatchlistMember schema of the API
+type WatchlistMember struct {
+	ID         int64     `json:"id"`
+	Username   string    `json:"username"`
+	Role       string    `json:"role"`
+	CreateTime time.Time `json:"createTime"`
+	Current    bool      `json:"current"`
+}
+
+// WatchlistNameRequest is the WatchlistNameRequest schema of the API
+type WatchlistNameRequest struct {
+	Name string `json:"name"`
+}
+
+// AdminTestNotification calls POST /api/admin/test-notification: 测试推送通知（管理员）
+func (c *Client) AdminTestNotification(ctx context.Context, body BarkKeyRequest) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if _, err := c.do(ctx, "POST", "/api/admin/test-notification", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BlockProduct calls POST /api/products/{activityId}/block: 屏蔽商品
+func (c *Client) BlockProduct(ctx context.Context, activityID string) error {
+	_, err := c.do(ctx, "POST", "/api/products/"+url.PathEscape(activityID)+"/block", nil, nil, nil, false)
+	return err
+}
+
+// ClaimClient calls POST /api/auth/claim: 将匿名标识下的数据迁入账号
+func (c *Client) ClaimClient(ctx context.Context) (*ClaimClientResponse, error) {
+	var out ClaimClientResponse
+	if _, err := c.do(ctx, "POST", "/api/auth/claim", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ClearPlatform calls DELETE /api/products/platform/{platform}: 清空平台数据（管理员）
+func (c *Client) ClearPlatform(ctx context.Context, platform string) error {
+	_, err := c.do(ctx, "DELETE", "/api/products/platform/"+url.PathEscape(platform), nil, nil, nil, false)
+	return err
+}
+
+// CreateNotification calls POST /api/products/notifications: 设置价格提醒
+func (c *Client) CreateNotification(ctx context.Context, body NotificationRuleRequest) error {
+	_, err := c.do(ctx, "POST", "/api/products/notifications", nil, body, nil, false)
+	return err
+}
+
+// CreatePairingCode calls POST /api/devices/pairing-code: 生成设备配对码
+func (c *Client) CreatePairingCode(ctx context.Context) (*PairingCode, error) {
+	var out PairingCode
+	if _, err := c.do(ctx, "POST", "/api/devices/pairing-code", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSearch calls POST /api/user/searches: 创建订阅搜索
+func (c *Client) CreateSearch(ctx context.Context, body SavedSearchRequest) (*SavedSearch, error) {
+	var out SavedSearch
+	if _, err := c.do(ctx, "POST", "/api/user/searches", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateToken calls POST /api/auth/tokens: 创建 API 令牌
+func (c *Client) CreateToken(ctx context.Context, body CreateTokenRequest) (*CreateTokenResponse, error) {
+	var out CreateTokenResponse
+	if _, err := c.do(ctx, "POST", "/api/auth/tokens", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWatchlist calls POST /api/watchlists: 创建共享清单
+func (c *Client) CreateWatchlist(ctx context.Context, body WatchlistNameRequest) (*Watchlist, error) {
+	var out Watchlist
+	if _, err := c.do(ctx, "POST", "/api/watchlists", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteNotification calls DELETE /api/products/notifications/{activityId}: 删除价格提醒
+func (c *Client) DeleteNotification(ctx context.Context, activityID string) error {
+	_, err := c.do(ctx, "DELETE", "/api/products/notifications/"+url.PathEscape(activityID), nil, nil, nil, false)
+	return err
+}
+
+// DeleteSearch calls DELETE /api/user/searches/{id}: 删除订阅搜索
+func (c *Client) DeleteSearch(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, "DELETE", "/api/user/searches/"+strconv.FormatInt(id, 10), nil, nil, nil, false)
+	return err
+}
+
+// DeleteWatchlist calls DELETE /api/watchlists/{id}: 删除清单（创建者）
+func (c *Client) DeleteWatchlist(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, "DELETE", "/api/watchlists/"+strconv.FormatInt(id, 10), nil, nil, nil, false)
+	return err
+}
+
+// DeleteWatchlistItem calls DELETE /api/watchlists/{id}/items/{activityId}: 从清单移除商品
+func (c *Client) DeleteWatchlistItem(ctx context.Context, id int64, activityID string) error {
+	_, err := c.do(ctx, "DELETE", "/api/watchlists/"+strconv.FormatInt(id, 10)+"/items/"+url.PathEscape(activityID), nil, nil, nil, false)
+	return err
+}
+
+// ExportData calls GET /api/user/export: 导出个人数据
+func (c *Client) ExportData(ctx context.Context) (*UserDataExport, error) {
+	var out UserDataExport
+	if _, err := c.do(ctx, "GET", "/api/user/export", nil, nil, &out, true); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FavoriteProduct calls POST /api/products/{activityId}/favorite: 收藏商品
+func (c *Client) FavoriteProduct(ctx context.Context, activityID string) (*ProductAnnotation, error) {
+	var out ProductAnnotation
+	if _, err := c.do(ctx, "POST", "/api/products/"+url.PathEscape(activityID)+"/favorite", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAnnotation calls GET /api/products/{activityId}/annotation: 获取商品的收藏、标签与备注
+func (c *Client) GetAnnotation(ctx context.Context, activityID string) (*ProductAnnotation, error) {
+	var out ProductAnnotation
+	if _, err := c.do(ctx, "GET", "/api/products/"+url.PathEscape(activityID)+"/annotation", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBlockedProducts calls GET /api/products/blocked: 获取已屏蔽的商品
+func (c *Client) GetBlockedProducts(ctx context.Context) ([]Product, error) {
+	var out []Product
+	if _, err := c.do(ctx, "GET", "/api/products/blocked", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDigest calls GET /api/user/digest: 获取今日摘要
+func (c *Client) GetDigest(ctx context.Context) (*Digest, error) {
+	var out Digest
+	if _, err := c.do(ctx, "GET", "/api/user/digest", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFeedToken calls GET /api/user/feed: 获取私人订阅源地址，首次调用时生成
+func (c *Client) GetFeedToken(ctx context.Context) (*FeedToken, error) {
+	var out FeedToken
+	if _, err := c.do(ctx, "GET", "/api/user/feed", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPI calls GET /api/openapi.json: 获取本接口文档
+func (c *Client) GetOpenAPI(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if _, err := c.do(ctx, "GET", "/api/openapi.json", nil, nil, &out, true); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetPreferences calls GET /api/user/preferences: 获取推送偏好
+func (c *Client) GetPreferences(ctx context.Context) (*NotificationPreferences, error) {
+	var out NotificationPreferences
+	if _, err := c.do(ctx, "GET", "/api/user/preferences", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPriceTrend calls GET /api/products/{activityId}/trend: 获取价格趋势
+func (c *Client) GetPriceTrend(ctx context.Context, activityID string) ([]PriceTrend, error) {
+	var out []PriceTrend
+	if _, err := c.do(ctx, "GET", "/api/products/"+url.PathEscape(activityID)+"/trend", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetProduct calls GET /api/products/{activityId}: 获取商品详情
+func (c *Client) GetProduct(ctx context.Context, activityID string) (*ProductDetail, error) {
+	var out ProductDetail
+	if _, err := c.do(ctx, "GET", "/api/products/"+url.PathEscape(activityID), nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSearch calls GET /api/user/searches/{id}: 获取单个订阅搜索
+func (c *Client) GetSearch(ctx context.Context, id int64) (*SavedSearch, error) {
+	var out SavedSearch
+	if _, err := c.do(ctx, "GET", "/api/user/searches/"+strconv.FormatInt(id, 10), nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSettings calls GET /api/user/settings: 获取 Bark 设置
+func (c *Client) GetSettings(ctx context.Context) (*UserSettings, error) {
+	var out UserSettings
+	if _, err := c.do(ctx, "GET", "/api/user/settings", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStatus calls GET /api/status: 获取同步状态
+func (c *Client) GetStatus(ctx context.Context) (*SystemStatusResponse, error) {
+	var out SystemStatusResponse
+	if _, err := c.do(ctx, "GET", "/api/status", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWatchlist calls GET /api/watchlists/{id}: 获取清单商品与成员
+func (c *Client) GetWatchlist(ctx context.Context, id int64) (*WatchlistDetail, error) {
+	var out WatchlistDetail
+	if _, err := c.do(ctx, "GET", "/api/watchlists/"+strconv.FormatInt(id, 10), nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Health calls GET /health: 健康检查
+func (c *Client) Health(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if _, err := c.do(ctx, "GET", "/health", nil, nil, &out, true); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImportDataParams holds the query parameters of ImportData. Zero values are not sent.
+type ImportDataParams struct {
+	Mode string // merge（默认）或 replace
+}
+
+// ImportData calls POST /api/user/import: 导入个人数据
+func (c *Client) ImportData(ctx context.Context, params ImportDataParams, body UserDataExport) (*UserDataImportSummary, error) {
+	query := url.Values{}
+	if params.Mode != "" {
+		query.Set("mode", params.Mode)
+	}
+	var out UserDataImportSummary
+	if _, err := c.do(ctx, "POST", "/api/user/import", query, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// JoinWatchlist calls POST /api/watchlists/join: 通过邀请码加入清单
+func (c *Client) JoinWatchlist(ctx context.Context, body JoinWatchlistRequest) (*Watchlist, error) {
+	var out Watchlist
+	if _, err := c.do(ctx, "POST", "/api/watchlists/join", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LeaveWatchlist calls POST /api/watchlists/{id}/leave: 退出清单
+func (c *Client) LeaveWatchlist(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, "POST", "/api/watchlists/"+strconv.FormatInt(id, 10)+"/leave", nil, nil, nil, false)
+	return err
+}
+
+// ListAuditLogsParams holds the query parameters of ListAuditLogs. Zero values are not sent.
+type ListAuditLogsParams struct {
+	Limit *int // 条数，默认 100
+}
+
+// ListAuditLogs calls GET /api/admin/audit: 查看管理操作审计日志（管理员）
+func (c *Client) ListAuditLogs(ctx context.Context, params ListAuditLogsParams) ([]*AuditLog, error) {
+	query := url.Values{}
+	if params.Limit != nil {
+		query.Set("limit", strconv.Itoa(*params.Limit))
+	}
+	var out []*AuditLog
+	if _, err := c.do(ctx, "GET", "/api/admin/audit", query, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDevices calls GET /api/devices: 获取已配对设备
+func (c *Client) ListDevices(ctx context.Context) ([]*Device, error) {
+	var out []*Device
+	if _, err := c.do(ctx, "GET", "/api/devices", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSearches calls GET /api/user/searches: 获取订阅搜索
+func (c *Client) ListSearches(ctx context.Context) ([]SavedSearch, error) {
+	var out []SavedSearch
+	if _, err := c.do(ctx, "GET", "/api/user/searches", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTags calls GET /api/products/tags: 获取自己用过的标签
+func (c *Client) ListTags(ctx context.Context) ([]Tag, error) {
+	var out []Tag
+	if _, err := c.do(ctx, "GET", "/api/products/tags", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTokens calls GET /api/auth/tokens: 获取会话与 API 令牌
+func (c *Client) ListTokens(ctx context.Context) ([]*AuthToken, error) {
+	var out []*AuthToken
+	if _, err := c.do(ctx, "GET", "/api/auth/tokens", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWatchlists calls GET /api/watchlists: 获取已加入的共享清单
+func (c *Client) ListWatchlists(ctx context.Context) ([]*Watchlist, error) {
+	var out []*Watchlist
+	if _, err := c.do(ctx, "GET", "/api/watchlists", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Login calls POST /api/auth/login: 登录
+func (c *Client) Login(ctx context.Context, body CredentialsRequest) (*AuthResponse, error) {
+	var out AuthResponse
+	if _, err := c.do(ctx, "POST", "/api/auth/login", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Logout calls POST /api/auth/logout: 退出登录
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.do(ctx, "POST", "/api/auth/logout", nil, nil, nil, false)
+	return err
+}
+
+// Me calls GET /api/auth/me: 获取当前账号
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var out User
+	if _, err := c.do(ctx, "GET", "/api/auth/me", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PairDevice calls POST /api/devices/pair: 输入配对码，与其他设备共享数据
+func (c *Client) PairDevice(ctx context.Context, body PairDeviceRequest) (*Device, error) {
+	var out Device
+	if _, err := c.do(ctx, "POST", "/api/devices/pair", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PushDTItems calls POST /api/external/dt/push: 推送探探糖商品
+func (c *Client) PushDTItems(ctx context.Context, body DTPlatformPushRequest) (*DTPlatformPushResponse, error) {
+	var out DTPlatformPushResponse
+	if _, err := c.do(ctx, "POST", "/api/external/dt/push", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// QueryProductsParams holds the query parameters of QueryProducts. Zero values are not sent.
+type QueryProductsParams struct {
+	Region        string   // 地区
+	Platform      string   // 平台
+	Keyword       string   // 关键词
+	SalesStatus   *int     // 1 在售，0 售罄
+	MonitorStatus string   // 1 已设置提醒，0 未设置
+	Favorite      string   // 1 只看收藏
+	Tag           string   // 按标签筛选
+	MinPrice      *float64 // 最低价
+	MaxPrice      *float64 // 最高价
+	Sort          string   // 排序字段；discount 与 drop_rate 按返回的 discount / dropRate 字段排序
+	Order         string   // 排序方向，默认价格从低到高，其余从高到低
+	Limit         *int     // 每页条数，最大 200，不传返回全部
+	Cursor        string   // 上一页返回的 meta.nextCursor
+}
+
+// QueryProducts calls GET /api/products: 获取商品列表
+func (c *Client) QueryProducts(ctx context.Context, params QueryProductsParams) ([]Product, *Meta, error) {
+	query := url.Values{}
+	if params.Region != "" {
+		query.Set("region", params.Region)
+	}
+	if params.Platform != "" {
+		query.Set("platform", params.Platform)
+	}
+	if params.Keyword != "" {
+		query.Set("keyword", params.Keyword)
+	}
+	if params.SalesStatus != nil {
+		query.Set("salesStatus", strconv.Itoa(*params.SalesStatus))
+	}
+	if params.MonitorStatus != "" {
+		query.Set("monitorStatus", params.MonitorStatus)
+	}
+	if params.Favorite != "" {
+		query.Set("favorite", params.Favorite)
+	}
+	if params.Tag != "" {
+		query.Set("tag", params.Tag)
+	}
+	if params.MinPrice != nil {
+		query.Set("minPrice", strconv.FormatFloat(*params.MinPrice, 'f', -1, 64))
+	}
+	if params.MaxPrice != nil {
+		query.Set("maxPrice", strconv.FormatFloat(*params.MaxPrice, 'f', -1, 64))
+	}
+	if params.Sort != "" {
+		query.Set("sort", params.Sort)
+	}
+	if params.Order != "" {
+		query.Set("order", params.Order)
+	}
+	if params.Limit != nil {
+		query.Set("limit", strconv.Itoa(*params.Limit))
+	}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+	var out []Product
+	meta, err := c.do(ctx, "GET", "/api/products", query, nil, &out, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, meta, nil
+}
+
+// Ready calls GET /ready: 就绪检查（含数据库连通性）
+func (c *Client) Ready(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if _, err := c.do(ctx, "GET", "/ready", nil, nil, &out, true); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RemoveMember calls DELETE /api/watchlists/{id}/members/{memberId}: 移除成员（创建者）
+func (c *Client) RemoveMember(ctx context.Context, id int64, memberID int64) error {
+	_, err := c.do(ctx, "DELETE", "/api/watchlists/"+strconv.FormatInt(id, 10)+"/members/"+strconv.FormatInt(memberID, 10), nil, nil, nil, false)
+	return err
+}
+
+// RenameWatchlist calls PUT /api/watchlists/{id}: 重命名清单（创建者）
+func (c *Client) RenameWatchlist(ctx context.Context, id int64, body WatchlistNameRequest) (*Watchlist, error) {
+	var out Watchlist
+	if _, err := c.do(ctx, "PUT", "/api/watchlists/"+strconv.FormatInt(id, 10), nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeDevice calls DELETE /api/devices/{id}: 移除已配对设备
+func (c *Client) RevokeDevice(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, "DELETE", "/api/devices/"+strconv.FormatInt(id, 10), nil, nil, nil, false)
+	return err
+}
+
+// RevokeToken calls DELETE /api/auth/tokens/{id}: 吊销令牌
+func (c *Client) RevokeToken(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, "DELETE", "/api/auth/tokens/"+strconv.FormatInt(id, 10), nil, nil, nil, false)
+	return err
+}
+
+// RotateFeedToken calls POST /api/user/feed: 重新生成私人订阅源地址，旧地址失效
+func (c *Client) RotateFeedToken(ctx context.Context) (*FeedToken, error) {
+	var out FeedToken
+	if _, err := c.do(ctx, "POST", "/api/user/feed", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RotateInvite calls POST /api/watchlists/{id}/invite: 重置邀请链接（创建者）
+func (c *Client) RotateInvite(ctx context.Context, id int64) (*Watchlist, error) {
+	var out Watchlist
+	if _, err := c.do(ctx, "POST", "/api/watchlists/"+strconv.FormatInt(id, 10)+"/invite", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SavePreferences calls PUT /api/user/preferences: 更新推送偏好
+func (c *Client) SavePreferences(ctx context.Context, body NotificationPreferences) (*NotificationPreferences, error) {
+	var out NotificationPreferences
+	if _, err := c.do(ctx, "PUT", "/api/user/preferences", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveSettings calls POST /api/user/settings: 保存 Bark 设置
+func (c *Client) SaveSettings(ctx context.Context, body BarkKeyRequest) (*UserSettings, error) {
+	var out UserSettings
+	if _, err := c.do(ctx, "POST", "/api/user/settings", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveWatchlistItem calls POST /api/watchlists/{id}/items: 向清单添加商品
+func (c *Client) SaveWatchlistItem(ctx context.Context, id int64, body NotificationRuleRequest) (*WatchlistItem, error) {
+	var out WatchlistItem
+	if _, err := c.do(ctx, "POST", "/api/watchlists/"+strconv.FormatInt(id, 10)+"/items", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchParams holds the query parameters of Search. Zero values are not sent.
+type SearchParams struct {
+	Q     string // 关键词，多个词用空格分隔
+	Limit *int   // 条数，默认 20，最大 50
+}
+
+// Search calls GET /api/search: 全文搜索商品标题与店铺名
+func (c *Client) Search(ctx context.Context, params SearchParams) ([]Product, error) {
+	query := url.Values{}
+	if params.Q != "" {
+		query.Set("q", params.Q)
+	}
+	if params.Limit != nil {
+		query.Set("limit", strconv.Itoa(*params.Limit))
+	}
+	var out []Product
+	if _, err := c.do(ctx, "GET", "/api/search", query, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetRole calls PUT /api/admin/users/{username}/role: 设置账号角色（管理员）
+func (c *Client) SetRole(ctx context.Context, username string, body SetRoleRequest) (*Role, error) {
+	var out Role
+	if _, err := c.do(ctx, "PUT", "/api/admin/users/"+url.PathEscape(username)+"/role", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SignUp calls POST /api/auth/signup: 注册账号
+func (c *Client) SignUp(ctx context.Context, body CredentialsRequest) (*AuthResponse, error) {
+	var out AuthResponse
+	if _, err := c.do(ctx, "POST", "/api/auth/signup", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StreamParams holds the query parameters of Stream. Zero values are not sent.
+type StreamParams struct {
+	Region      string // 地区
+	Platform    string // 平台
+	Watched     string // 1 只推送已设置提醒的商品
+	LastEventID string // 从该事件之后继续推送，与 Last-Event-ID 头相同
+}
+
+// Stream calls GET /api/stream: 订阅价格、售卖状态与新商品的实时更新（Server-Sent Events）
+// It passes each event to handle, resuming after dropped connections, until ctx
+// is done or handle returns an error.
+func (c *Client) Stream(ctx context.Context, params StreamParams, handle func(StreamEvent) error) error {
+	query := url.Values{}
+	if params.Region != "" {
+		query.Set("region", params.Region)
+	}
+	if params.Platform != "" {
+		query.Set("platform", params.Platform)
+	}
+	if params.Watched != "" {
+		query.Set("watched", params.Watched)
+	}
+	if params.LastEventID != "" {
+		query.Set("lastEventId", params.LastEventID)
+	}
+	return c.stream(ctx, "/api/stream", query, func(decode func(interface{}) error) error {
+		var event StreamEvent
+		if err := decode(&event); err != nil {
+			return err
+		}
+		return handle(event)
+	})
+}
+
+// TestAPIParams holds the query parameters of TestAPI. Zero values are not sent.
+type TestAPIParams struct {
+	Region string // 地区，默认广州
+}
+
+// TestAPI calls GET /api/admin/test-api: 测试上游接口（管理员）
+func (c *Client) TestAPI(ctx context.Context, params TestAPIParams) (map[string]interface{}, error) {
+	query := url.Values{}
+	if params.Region != "" {
+		query.Set("region", params.Region)
+	}
+	var out map[string]interface{}
+	if _, err := c.do(ctx, "GET", "/api/admin/test-api", query, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TestNotification calls POST /api/user/test-notification: 向自己的 Bark Key 发送测试推送
+func (c *Client) TestNotification(ctx context.Context, body BarkKeyRequest) (*TestNotification, error) {
+	var out TestNotification
+	if _, err := c.do(ctx, "POST", "/api/user/test-notification", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TriggerSync calls POST /api/admin/sync: 手动触发同步（管理员）
+func (c *Client) TriggerSync(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if _, err := c.do(ctx, "POST", "/api/admin/sync", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnblockProduct calls POST /api/products/unblock/{activityId}: 取消屏蔽商品
+func (c *Client) UnblockProduct(ctx context.Context, activityID string) error {
+	_, err := c.do(ctx, "POST", "/api/products/unblock/"+url.PathEscape(activityID), nil, nil, nil, false)
+	return err
+}
+
+// UnfavoriteProduct calls DELETE /api/products/{activityId}/favorite: 取消收藏
+func (c *Client) UnfavoriteProduct(ctx context.Context, activityID string) (*ProductAnnotation, error) {
+	var out ProductAnnotation
+	if _, err := c.do(ctx, "DELETE", "/api/products/"+url.PathEscape(activityID)+"/favorite", nil, nil, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAnnotation calls PUT /api/products/{activityId}/annotation: 修改商品的收藏、标签与备注
+func (c *Client) UpdateAnnotation(ctx context.Context, activityID string, body UpdateAnnotationRequest) (*ProductAnnotation, error) {
+	var out ProductAnnotation
+	if _, err := c.do(ctx, "PUT", "/api/products/"+url.PathEscape(activityID)+"/annotation", nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateNotification calls PUT /api/products/notifications/{activityId}: 更新价格提醒
+func (c *Client) UpdateNotification(ctx context.Context, activityID string, body UpdateNotificationRequest) error {
+	_, err := c.do(ctx, "PUT", "/api/products/notifications/"+url.PathEscape(activityID), nil, body, nil, false)
+	return err
+}
+
+// UpdateSearch calls PUT /api/user/searches/{id}: 更新订阅搜索
+func (c *Client) UpdateSearch(ctx context.Context, id int64, body UpdateSavedSearchRequest) (*SavedSearch, error) {
+	var out SavedSearch
+	if _, err := c.do(ctx, "PUT", "/api/user/searches/"+strconv.FormatInt(id, 10), nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateWatchlistItem calls PUT /api/watchlists/{id}/items/{activityId}: 修改清单商品的提醒规则
+func (c *Client) UpdateWatchlistItem(ctx context.Context, id int64, activityID string, body UpdateWatchlistItemRequest) (*WatchlistItem, error) {
+	var out WatchlistItem
+	if _, err := c.do(ctx, "PUT", "/api/watchlists/"+strconv.FormatInt(id, 10)+"/items/"+url.PathEscape(activityID), nil, body, &out, false); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,285 @@
+// Package client is a typed Go client for the KbFood HTTP API.
+//
+// The types and methods in api_gen.go are generated from the server's OpenAPI
+// document, which is also served at /api/openapi.json. Regenerate them after
+// changing a route or DTO with:
+//
+//	go test ./internal/interface/http -run TestGeneratedClient -update
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultStreamRetry is the reconnection delay of event streams until the server suggests one
+const defaultStreamRetry = 3 * time.Second
+
+// Client calls the API of one server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	userID     string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. Event streams stay
+// open indefinitely, so the client should not set a Timeout if they are used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken authenticates requests with a session or personal API token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithUserID identifies an anonymous client by the ID the web app sends as X-User-ID
+func WithUserID(userID string) Option {
+	return func(c *Client) {
+		c.userID = userID
+	}
+}
+
+// New creates a client for the server at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is returned when the server responds with an error status. Code is
+// the error code of the API, such as 10001 for invalid input, to branch on
+// instead of Message.
+type Error struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("kbfood: %d %s", e.StatusCode, e.Message)
+}
+
+// do sends a request and decodes the data of the response into out. Unless
+// raw is set the response is the API envelope, whose meta is returned.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, raw bool) (*Meta, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, responseError(resp.StatusCode, data)
+	}
+
+	if raw {
+		if out != nil {
+			if err := json.Unmarshal(data, out); err != nil {
+				return nil, fmt.Errorf("decode response: %w", err)
+			}
+		}
+		return nil, nil
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+		Meta *Meta           `json:"meta"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return nil, fmt.Errorf("decode response data: %w", err)
+		}
+	}
+	return envelope.Meta, nil
+}
+
+// stream reads the Server-Sent Events of path and passes a decoder of the data
+// of each event to handle. Dropped connections are resumed with the ID of the
+// last event received. It returns when ctx is done, handle returns an error or
+// the server rejects the request.
+func (c *Client) stream(ctx context.Context, path string, query url.Values, handle func(decode func(interface{}) error) error) error {
+	s := &eventStream{retry: defaultStreamRetry}
+	for {
+		err := c.readStream(ctx, path, query, s, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.handleErr != nil {
+			return s.handleErr
+		}
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.retry):
+		}
+	}
+}
+
+// eventStream is the state of an event stream kept across reconnections
+type eventStream struct {
+	lastEventID string
+	retry       time.Duration
+	handleErr   error
+}
+
+// readStream reads one connection of an event stream until it ends
+func (c *Client) readStream(ctx context.Context, path string, query url.Values, s *eventStream, handle func(decode func(interface{}) error) error) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+	c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return responseError(resp.StatusCode, data)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line dispatches the event
+			if len(data) > 0 {
+				payload := []byte(strings.Join(data, "\n"))
+				data = nil
+				err := handle(func(v interface{}) error {
+					if err := json.Unmarshal(payload, v); err != nil {
+						return fmt.Errorf("decode event: %w", err)
+					}
+					return nil
+				})
+				if err != nil {
+					s.handleErr = err
+					return err
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// Comment, e.g. a heartbeat
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "id":
+			s.lastEventID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// authorize adds the configured credentials to a request
+func (c *Client) authorize(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.userID != "" {
+		req.Header.Set("X-User-ID", c.userID)
+	}
+}
+
+// responseError converts an error response to an *Error
+func responseError(statusCode int, body []byte) *Error {
+	apiErr := &Error{StatusCode: statusCode}
+	var failure struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &failure) == nil {
+		apiErr.Code = failure.Code
+		apiErr.Message = failure.Message
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+	return apiErr
+}
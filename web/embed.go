@@ -0,0 +1,23 @@
+// Package web embeds the built frontend into the server binary
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// dist holds the production build of the frontend. It only contains a
+// placeholder until `make frontend` copies frontend/dist into web/dist.
+//
+//go:embed all:dist
+var dist embed.FS
+
+// Dist returns the embedded frontend, rooted at the directory of index.html
+func Dist() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		// Only fails for invalid paths, and "dist" is a valid path
+		panic(err)
+	}
+	return sub
+}